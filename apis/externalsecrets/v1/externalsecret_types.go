@@ -34,6 +34,29 @@ type SecretStoreRef struct {
 	// +optional
 	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
 	Kind string `json:"kind,omitempty"`
+
+	// Fallbacks are the stores tried in order when this store fails,
+	// for example on a connection or an authentication error.
+	// They are not tried when the secret does not exist in this store.
+	// +optional
+	// +kubebuilder:validation:MaxItems=5
+	Fallbacks []SecretStoreFallbackRef `json:"fallbacks,omitempty"`
+}
+
+// SecretStoreFallbackRef defines a SecretStore to fetch the ExternalSecret data from
+// when the referencing store fails.
+type SecretStoreFallbackRef struct {
+	// Name of the SecretStore resource
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+
+	// Kind of the SecretStore resource (SecretStore or ClusterSecretStore)
+	// Defaults to `SecretStore`
+	// +optional
+	// +kubebuilder:validation:Enum=SecretStore;ClusterSecretStore
+	Kind string `json:"kind,omitempty"`
 }
 
 // ExternalSecretCreationPolicy defines rules on how to create the resulting Secret.
@@ -257,6 +280,14 @@ type ExternalSecretTarget struct {
 	// +kubebuilder:default="Retain"
 	DeletionPolicy ExternalSecretDeletionPolicy `json:"deletionPolicy,omitempty"`
 
+	// DriftPolicy defines what happens when the Secret is modified outside of the controller:
+	// - Revert: the Secret is synced again right away
+	// - Report: the Secret is left untouched, and the drift is reported by the Drifted condition
+	// - Ignore: the drift is neither reported nor reverted until the next refresh
+	// Defaults to "Revert", the only policy supported with a manifest target.
+	// +optional
+	DriftPolicy ExternalSecretDriftPolicy `json:"driftPolicy,omitempty"`
+
 	// Template defines a blueprint for the created Secret resource.
 	// +optional
 	Template *ExternalSecretTemplate `json:"template,omitempty"`
@@ -271,6 +302,258 @@ type ExternalSecretTarget struct {
 	// Immutable defines if the final secret will be immutable
 	// +optional
 	Immutable bool `json:"immutable,omitempty"`
+
+	// DryRun fetches and renders the Secret without writing it.
+	// A redacted summary of the changes is written to status.dryRun instead.
+	// Not supported with a manifest target.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// History keeps immutable snapshots of the last rendered versions of the Secret.
+	// Not supported with a manifest target.
+	// +optional
+	History *ExternalSecretHistory `json:"history,omitempty"`
+
+	// RollbackTo pins the Secret to the snapshot of a previous revision listed in status.history.
+	// The provider is not read while it is set. Clear it to resume syncing.
+	// Requires history and is not supported with dryRun.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+
+	// RolloutRestart restarts workloads using the Secret when its data changes.
+	// Not supported with a manifest target.
+	// +optional
+	RolloutRestart *ExternalSecretRolloutRestart `json:"rolloutRestart,omitempty"`
+
+	// Validation declares rules the keys of the rendered Secret must satisfy.
+	// The rules are checked after templating, and the Secret is left untouched when one of them fails.
+	// Not supported with a manifest target.
+	// +optional
+	// +listType=map
+	// +listMapKey=key
+	Validation []ExternalSecretKeyValidation `json:"validation,omitempty"`
+
+	// Rotation keeps the previous value of a key available for a while after it changed.
+	// Not supported with a manifest target or an immutable Secret.
+	// +optional
+	Rotation *ExternalSecretRotation `json:"rotation,omitempty"`
+
+	// Versioning writes every change of the data to a new immutable Secret named after the target and the hash
+	// of its data, like the secretGenerator of kustomize, and points the selected workloads to it.
+	// Requires creationPolicy Owner. Not supported with a manifest target or rotation.
+	// +optional
+	Versioning *ExternalSecretVersioning `json:"versioning,omitempty"`
+}
+
+// ExternalSecretSplitTarget writes the keys of the data fetched for the ExternalSecret which match its selector
+// to a Secret of its own. The Secret is created, owned and deleted independently of the Secret of spec.target.
+type ExternalSecretSplitTarget struct {
+	// The name of the Secret resource to be managed.
+	// Must differ from the name of spec.target and of the other targets.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+
+	// Selector selects the keys of the fetched data written to the Secret.
+	// Every key is selected when it is not set.
+	// +optional
+	Selector *ExternalSecretKeySelector `json:"selector,omitempty"`
+
+	// CreationPolicy defines rules on how to create the Secret.
+	// None is not supported. Defaults to "Owner".
+	// +optional
+	CreationPolicy ExternalSecretCreationPolicy `json:"creationPolicy,omitempty"`
+
+	// DeletionPolicy defines rules on how to delete the Secret when no key is selected.
+	// Defaults to "Retain".
+	// +optional
+	DeletionPolicy ExternalSecretDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Template defines a blueprint for the Secret, including its type and metadata.
+	// Only the selected keys are available to it.
+	// +optional
+	Template *ExternalSecretTemplate `json:"template,omitempty"`
+}
+
+// ExternalSecretKeySelector selects keys of the data fetched for the ExternalSecret.
+// A key is selected if it is listed in keys, or if it matches regexp.
+type ExternalSecretKeySelector struct {
+	// Keys lists the keys to select.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// RegExp selects the keys matching this regular expression.
+	// +optional
+	RegExp string `json:"regexp,omitempty"`
+}
+
+// ExternalSecretRotation keeps the previous value of the keys of the Secret during an overlap period
+// after they changed, so that both the previous and the new credentials can be used while they are rotated.
+// The previous values are kept in companion keys of the Secret, or in a companion Secret.
+type ExternalSecretRotation struct {
+	// OverlapPeriod is how long the previous value of a key is kept after the key changed.
+	OverlapPeriod metav1.Duration `json:"overlapPeriod"`
+
+	// KeySuffix is appended to a key to name the companion key holding its previous value.
+	// Defaults to "_previous". Not used when secretName is set.
+	// +optional
+	// +kubebuilder:validation:MaxLength:=63
+	// +kubebuilder:validation:Pattern:=^[-._a-zA-Z0-9]+$
+	KeySuffix string `json:"keySuffix,omitempty"`
+
+	// SecretName is the name of a companion Secret holding the previous values under the keys of the Secret,
+	// instead of companion keys of the Secret. The companion Secret is owned by the ExternalSecret.
+	// +optional
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	SecretName string `json:"secretName,omitempty"`
+
+	// Keys restricts the rotation to these keys of the Secret. Defaults to every key.
+	// +optional
+	// +listType=set
+	Keys []string `json:"keys,omitempty"`
+}
+
+// ExternalSecretKeyValidation declares the rules of a key of the Secret.
+// Every rule which is set must be satisfied.
+type ExternalSecretKeyValidation struct {
+	// Key of the Secret the rules apply to.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[-._a-zA-Z0-9]+$
+	Key string `json:"key"`
+
+	// Optional skips the rules when the key is missing from the Secret.
+	// A missing key fails the validation otherwise.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+
+	// NonEmpty requires the value not to be empty.
+	// +optional
+	NonEmpty bool `json:"nonEmpty,omitempty"`
+
+	// Regex is a regular expression the value must match, using the Go syntax.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// MinLength is the minimum length of the value in bytes.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinLength *int `json:"minLength,omitempty"`
+
+	// MaxLength is the maximum length of the value in bytes.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxLength *int `json:"maxLength,omitempty"`
+
+	// Certificate requires the value to hold PEM encoded X.509 certificates.
+	// +optional
+	Certificate *ExternalSecretCertificateValidation `json:"certificate,omitempty"`
+
+	// JSON requires the value to be a JSON document.
+	// +optional
+	JSON *ExternalSecretJSONValidation `json:"json,omitempty"`
+
+	// CEL is an expression which must evaluate to true.
+	// The value of the key is available as the string `value`,
+	// and the data of the Secret as the map of strings `data`.
+	// +optional
+	CEL string `json:"cel,omitempty"`
+
+	// Message replaces the description of the failed rule in the condition of the ExternalSecret.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ExternalSecretCertificateValidation configures the validation of PEM encoded certificates.
+// Every certificate of the value must be valid at the time of the validation.
+type ExternalSecretCertificateValidation struct {
+	// MinValidity fails the validation when a certificate expires within this duration, e.g. "720h".
+	// +optional
+	MinValidity *metav1.Duration `json:"minValidity,omitempty"`
+}
+
+// ExternalSecretJSONValidation configures the validation of JSON documents.
+type ExternalSecretJSONValidation struct {
+	// Schema is a JSON schema the document must match.
+	// Draft 2020-12 is assumed when the schema does not declare its $schema.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+}
+
+// ExternalSecretHistory configures the snapshots of the rendered Secret.
+type ExternalSecretHistory struct {
+	// Limit is the number of snapshots to keep.
+	// Defaults to 5
+	// +optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	Limit int `json:"limit,omitempty"`
+}
+
+// ExternalSecretRolloutRestart selects the workloads to restart when the data of the Secret changes.
+// Workloads are restarted like `kubectl rollout restart`, by annotating their pod template.
+// Only workloads in the namespace of the ExternalSecret are restarted.
+type ExternalSecretRolloutRestart struct {
+	// Workloads lists the workloads to restart by name.
+	// +optional
+	Workloads []ExternalSecretWorkloadRef `json:"workloads,omitempty"`
+
+	// Selector restarts the Deployments, StatefulSets and DaemonSets matching the labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ExternalSecretVersioning configures the generations of the Secret written by the controller.
+// Each generation is an immutable Secret named <name>-<hash>. The references to the previous generations
+// in the selected workloads are updated to the current one, which rolls them out.
+// Only workloads in the namespace of the ExternalSecret are updated.
+type ExternalSecretVersioning struct {
+	// Retain is the number of generations of the Secret to keep, including the current one.
+	// Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	Retain int `json:"retain,omitempty"`
+
+	// Workloads lists the workloads whose references to the Secret are updated, by name.
+	// +optional
+	Workloads []ExternalSecretWorkloadRef `json:"workloads,omitempty"`
+
+	// Selector updates the Deployments, StatefulSets, DaemonSets and CronJobs matching the labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ExternalSecretWorkloadKind is the kind of a workload using the Secret.
+// CronJobs are not supported by target.rolloutRestart.
+// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;CronJob
+type ExternalSecretWorkloadKind string
+
+const (
+	// WorkloadKindDeployment is an apps/v1 Deployment.
+	WorkloadKindDeployment ExternalSecretWorkloadKind = "Deployment"
+	// WorkloadKindStatefulSet is an apps/v1 StatefulSet.
+	WorkloadKindStatefulSet ExternalSecretWorkloadKind = "StatefulSet"
+	// WorkloadKindDaemonSet is an apps/v1 DaemonSet.
+	WorkloadKindDaemonSet ExternalSecretWorkloadKind = "DaemonSet"
+	// WorkloadKindCronJob is a batch/v1 CronJob.
+	WorkloadKindCronJob ExternalSecretWorkloadKind = "CronJob"
+)
+
+// ExternalSecretWorkloadRef references a workload in the namespace of the ExternalSecret.
+type ExternalSecretWorkloadRef struct {
+	// Kind of the workload.
+	Kind ExternalSecretWorkloadKind `json:"kind"`
+
+	// Name of the workload.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	Name string `json:"name"`
 }
 
 // ExternalSecretData defines the connection between the Kubernetes Secret key (spec.data.<key>) and the Provider data.
@@ -514,8 +797,21 @@ type FindName struct {
 	RegExp string `json:"regexp,omitempty"`
 }
 
+// ExternalSecretDriftPolicy defines how modifications of the Secret made outside of the controller are handled.
+// +kubebuilder:validation:Enum=Revert;Report;Ignore
+type ExternalSecretDriftPolicy string
+
+const (
+	// DriftPolicyRevert syncs the Secret again as soon as a drift is detected.
+	DriftPolicyRevert ExternalSecretDriftPolicy = "Revert"
+	// DriftPolicyReport reports the drift without updating the Secret.
+	DriftPolicyReport ExternalSecretDriftPolicy = "Report"
+	// DriftPolicyIgnore leaves the drift until the next refresh of the Secret.
+	DriftPolicyIgnore ExternalSecretDriftPolicy = "Ignore"
+)
+
 // ExternalSecretRefreshPolicy defines how and when the ExternalSecret should be refreshed.
-// +kubebuilder:validation:Enum=CreatedOnce;Periodic;OnChange
+// +kubebuilder:validation:Enum=CreatedOnce;Periodic;OnChange;OnExpiry
 type ExternalSecretRefreshPolicy string
 
 const (
@@ -525,8 +821,71 @@ const (
 	RefreshPolicyPeriodic ExternalSecretRefreshPolicy = "Periodic"
 	// RefreshPolicyOnChange only synchronizes when the ExternalSecret's metadata or spec changes.
 	RefreshPolicyOnChange ExternalSecretRefreshPolicy = "OnChange"
+	// RefreshPolicyOnExpiry synchronizes the Secret before the credentials it holds expire.
+	RefreshPolicyOnExpiry ExternalSecretRefreshPolicy = "OnExpiry"
+)
+
+// ExternalSecretExpiryFormat defines how the expiry is read from the value of a key.
+// +kubebuilder:validation:Enum=Auto;Certificate;JWT;Timestamp;Duration;Reported
+type ExternalSecretExpiryFormat string
+
+const (
+	// ExpiryFormatAuto reads the expiry of a PEM encoded certificate or of a JWT,
+	// and uses the expiry reported for the key, whichever is earlier.
+	ExpiryFormatAuto ExternalSecretExpiryFormat = "Auto"
+	// ExpiryFormatCertificate reads the earliest notAfter of the PEM encoded certificates.
+	ExpiryFormatCertificate ExternalSecretExpiryFormat = "Certificate"
+	// ExpiryFormatJWT reads the exp claim of a JWT.
+	ExpiryFormatJWT ExternalSecretExpiryFormat = "JWT"
+	// ExpiryFormatTimestamp reads an RFC 3339 time or a Unix time in seconds,
+	// like a key holding the expiration returned by a generator.
+	ExpiryFormatTimestamp ExternalSecretExpiryFormat = "Timestamp"
+	// ExpiryFormatDuration reads a lifetime from the time of the refresh, as a Go duration or a number of seconds,
+	// like a key holding the duration of a lease.
+	ExpiryFormatDuration ExternalSecretExpiryFormat = "Duration"
+	// ExpiryFormatReported uses the expiry reported by the provider which served the key, like the end of a lease,
+	// or recorded in the state of the generator which generated it. The value of the key is not read.
+	ExpiryFormatReported ExternalSecretExpiryFormat = "Reported"
 )
 
+// ExternalSecretExpirySource defines a key to read the expiry from.
+type ExternalSecretExpirySource struct {
+	// Key is the key of the target Secret holding the expiry.
+	// With the Reported format, it is the key of spec.data or of the generator, before templating.
+	Key string `json:"key"`
+
+	// Format of the value of the key.
+	// +kubebuilder:default="Auto"
+	// +optional
+	Format ExternalSecretExpiryFormat `json:"format,omitempty"`
+}
+
+// ExternalSecretExpiryRefresh configures the refreshes of the OnExpiry refresh policy.
+type ExternalSecretExpiryRefresh struct {
+	// Sources lists the keys to read the expiry from, the earliest expiry is used.
+	// Defaults to every key of the target Secret with the Auto format, along with
+	// every expiry reported by the providers and the generators.
+	// +optional
+	Sources []ExternalSecretExpirySource `json:"sources,omitempty"`
+
+	// RemainingLifetimePercent is the percentage of the remaining lifetime
+	// of the credentials after which they are refreshed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=75
+	// +optional
+	RemainingLifetimePercent *int32 `json:"remainingLifetimePercent,omitempty"`
+
+	// MinInterval is the minimum time between two refreshes, so expired credentials are not refreshed in a loop.
+	// Defaults to 1m.
+	// +optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+
+	// MaxInterval is the maximum time between two refreshes, to also refresh long-lived credentials.
+	// +optional
+	MaxInterval *metav1.Duration `json:"maxInterval,omitempty"`
+}
+
 // ExternalSecretSyncWindowKind defines whether a SyncWindow permits or
 // blocks periodic refreshes.
 // +kubebuilder:validation:Enum=allow;deny
@@ -581,14 +940,29 @@ type ExternalSecretSpec struct {
 	// +optional
 	Target ExternalSecretTarget `json:"target,omitempty"`
 
+	// Targets writes additional Secrets from the data fetched for the ExternalSecret,
+	// each holding the keys matching its selector, rendered with its own template.
+	// The data is fetched once for all of them. Not supported with a manifest target.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=32
+	Targets []ExternalSecretSplitTarget `json:"targets,omitempty"`
+
 	// RefreshPolicy determines how the ExternalSecret should be refreshed:
 	// - CreatedOnce: Creates the Secret only if it does not exist and does not update it thereafter
 	// - Periodic: Synchronizes the Secret from the external source at regular intervals specified by refreshInterval.
 	//   No periodic updates occur if refreshInterval is 0.
 	// - OnChange: Only synchronizes the Secret when the ExternalSecret's metadata or specification changes
+	// - OnExpiry: Synchronizes the Secret after a fraction of the remaining lifetime of the credentials it holds,
+	//   configured by expiryRefresh. Falls back to refreshInterval when no expiry is found.
 	// +optional
 	RefreshPolicy ExternalSecretRefreshPolicy `json:"refreshPolicy,omitempty"`
 
+	// ExpiryRefresh configures how the expiry of the Secret is found for the OnExpiry refresh policy.
+	// +optional
+	ExpiryRefresh *ExternalSecretExpiryRefresh `json:"expiryRefresh,omitempty"`
+
 	// RefreshInterval is the amount of time before the values are read again from the SecretStore provider,
 	// specified as Golang Duration strings.
 	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h"
@@ -660,7 +1034,7 @@ type GeneratorRef struct {
 }
 
 // ExternalSecretConditionType defines a value type for ExternalSecret conditions.
-// +kubebuilder:validation:Enum=Ready;Deleted
+// +kubebuilder:validation:Enum=Ready;Deleted;Drifted
 type ExternalSecretConditionType string
 
 const (
@@ -668,6 +1042,8 @@ const (
 	ExternalSecretReady ExternalSecretConditionType = "Ready"
 	// ExternalSecretDeleted indicates that the external secret has been deleted.
 	ExternalSecretDeleted ExternalSecretConditionType = "Deleted"
+	// ExternalSecretDrifted indicates that the Secret was modified outside of the controller.
+	ExternalSecretDrifted ExternalSecretConditionType = "Drifted"
 )
 
 // ExternalSecretStatusCondition defines a status condition of an ExternalSecret resource.
@@ -700,6 +1076,22 @@ const (
 	// ConditionReasonSecretOwnedByOther indicates that the target secret is owned
 	// by another ExternalSecret.
 	ConditionReasonSecretOwnedByOther = "SecretOwnedByOther"
+	// ConditionReasonSecretDryRun indicates that the secret was rendered
+	// but not written because target.dryRun is set.
+	ConditionReasonSecretDryRun = "SecretDryRun"
+	// ConditionReasonSecretRolledBack indicates that the secret is pinned
+	// to a previous revision because target.rollbackTo is set.
+	ConditionReasonSecretRolledBack = "SecretRolledBack"
+	// ConditionReasonValidationFailed indicates that the rendered secret
+	// did not satisfy the rules of target.validation, so it was not written.
+	ConditionReasonValidationFailed = "ValidationFailed"
+	// ConditionReasonSecretDrifted indicates that the Secret was modified outside of the controller,
+	// and was left untouched because of the Report drift policy.
+	ConditionReasonSecretDrifted = "SecretDrifted"
+	// ConditionReasonDriftReverted indicates that a modification of the Secret was reverted.
+	ConditionReasonDriftReverted = "DriftReverted"
+	// ConditionReasonNoDrift indicates that the Secret matches the data written by the controller.
+	ConditionReasonNoDrift = "NoDrift"
 
 	// ReasonUpdateFailed indicates that the update operation failed.
 	ReasonUpdateFailed = "UpdateFailed"
@@ -713,6 +1105,12 @@ const (
 	ReasonDeleted = "Deleted"
 	// ReasonMissingProviderSecret indicates that the provider secret is missing.
 	ReasonMissingProviderSecret = "MissingProviderSecret"
+	// ReasonRolloutRestarted indicates that workloads have been restarted.
+	ReasonRolloutRestarted = "RolloutRestarted"
+	// ReasonStoreFailover indicates that secrets were served by fallback stores.
+	ReasonStoreFailover = "StoreFailover"
+	// ReasonDrifted indicates that the Secret was modified outside of the controller.
+	ReasonDrifted = "Drifted"
 
 	// ConditionReasonResourceSynced indicates that the secrets was synced.
 	ConditionReasonResourceSynced = "ResourceSynced"
@@ -739,6 +1137,235 @@ type ExternalSecretStatus struct {
 
 	// Binding represents a servicebinding.io Provisioned Service reference to the secret
 	Binding corev1.LocalObjectReference `json:"binding,omitempty"`
+
+	// DryRun summarizes the changes the controller would make to the target Secret
+	// when target.dryRun is set.
+	// +optional
+	DryRun *ExternalSecretDryRunStatus `json:"dryRun,omitempty"`
+
+	// DryRunTargets summarizes the changes the controller would make to the Secrets of spec.targets,
+	// in the same order, when target.dryRun is set.
+	// +optional
+	DryRunTargets []ExternalSecretDryRunStatus `json:"dryRunTargets,omitempty"`
+
+	// History lists the snapshots of the target Secret, newest first,
+	// when target.history is set.
+	// +optional
+	History []ExternalSecretHistoryEntry `json:"history,omitempty"`
+
+	// RolledBackTo is the revision of status.history the target Secret was restored to,
+	// while target.rollbackTo is set.
+	// +optional
+	RolledBackTo *int64 `json:"rolledBackTo,omitempty"`
+
+	// RolloutRestart records the last restart of the workloads in target.rolloutRestart.
+	// +optional
+	RolloutRestart *ExternalSecretRolloutRestartStatus `json:"rolloutRestart,omitempty"`
+
+	// ExpiryTime is the earliest expiry found in the Secret, or reported for its keys, at the last refresh,
+	// when the refresh policy is OnExpiry.
+	// +optional
+	ExpiryTime *metav1.Time `json:"expiryTime,omitempty"`
+
+	// NextRefreshTime is the time of the next refresh, when the refresh policy is OnExpiry.
+	// +optional
+	NextRefreshTime *metav1.Time `json:"nextRefreshTime,omitempty"`
+
+	// ServedBy records the store which served each entry referencing a store with fallbacks,
+	// at the last refresh.
+	// +optional
+	ServedBy []ExternalSecretServedBy `json:"servedBy,omitempty"`
+
+	// Provenance records where each key of the provider data came from, sorted by key.
+	// Values are never recorded. At most 100 keys are recorded.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Provenance []ExternalSecretKeyProvenance `json:"provenance,omitempty"`
+
+	// Rotation lists the keys whose previous value is kept due to target.rotation, sorted by key.
+	// +optional
+	Rotation []ExternalSecretRotatedKey `json:"rotation,omitempty"`
+
+	// Versioning records the generations of the Secret written due to target.versioning.
+	// +optional
+	Versioning *ExternalSecretVersioningStatus `json:"versioning,omitempty"`
+}
+
+// ExternalSecretVersioningStatus records the generations of the Secret and the workloads pointed to them.
+type ExternalSecretVersioningStatus struct {
+	// Generations lists the retained generations of the Secret, newest first.
+	// The first one is the current Secret.
+	// +optional
+	Generations []ExternalSecretSecretGeneration `json:"generations,omitempty"`
+
+	// Workloads lists the workloads whose references were last updated, as kind/name.
+	// +optional
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+// ExternalSecretSecretGeneration references an immutable generation of the Secret.
+type ExternalSecretSecretGeneration struct {
+	// Name of the Secret holding this generation.
+	Name string `json:"name"`
+
+	// DataHash is the hash of the data of this generation.
+	DataHash string `json:"dataHash"`
+
+	// CreatedAt is the time this generation was written.
+	CreatedAt metav1.Time `json:"createdAt"`
+}
+
+// ExternalSecretRotatedKey records a key of the Secret whose previous value is kept during the overlap period.
+type ExternalSecretRotatedKey struct {
+	// Key of the Secret which changed.
+	Key string `json:"key"`
+
+	// PreviousKey is the key of the Secret holding the previous value.
+	// It is not set when the previous value is held by the companion Secret, under Key.
+	// +optional
+	PreviousKey string `json:"previousKey,omitempty"`
+
+	// RotatedAt is the time the key changed.
+	RotatedAt metav1.Time `json:"rotatedAt"`
+
+	// ExpiresAt is the time the previous value is removed.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// ExternalSecretKeyProvenance records the source of a key of the ExternalSecret.
+type ExternalSecretKeyProvenance struct {
+	// Key is the key in the provider data.
+	// It is the key of the target Secret, unless a template renames it.
+	Key string `json:"key"`
+
+	// StoreName is the name of the store the value was fetched from.
+	// +optional
+	StoreName string `json:"storeName,omitempty"`
+
+	// StoreKind is the kind of the store the value was fetched from.
+	// +optional
+	StoreKind string `json:"storeKind,omitempty"`
+
+	// GeneratorName is the name of the generator which generated the value.
+	// +optional
+	GeneratorName string `json:"generatorName,omitempty"`
+
+	// GeneratorKind is the kind of the generator which generated the value.
+	// +optional
+	GeneratorKind string `json:"generatorKind,omitempty"`
+
+	// RemoteKey is the key of the secret in the provider.
+	// +optional
+	RemoteKey string `json:"remoteKey,omitempty"`
+
+	// Property is the property of the remote secret the value was read from.
+	// +optional
+	Property string `json:"property,omitempty"`
+
+	// Version is the version of the remote secret, if the provider reports it.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// LastChangeTime is the time the value of the key was first synced or last changed.
+	// +optional
+	LastChangeTime *metav1.Time `json:"lastChangeTime,omitempty"`
+}
+
+// ExternalSecretServedBy records the store which served an entry of the ExternalSecret.
+type ExternalSecretServedBy struct {
+	// Key is the secretKey of a spec.data entry, or spec.dataFrom[<index>] for a spec.dataFrom entry.
+	Key string `json:"key"`
+
+	// StoreName is the name of the store which served the entry.
+	StoreName string `json:"storeName"`
+
+	// StoreKind is the kind of the store which served the entry.
+	// +optional
+	StoreKind string `json:"storeKind,omitempty"`
+
+	// Fallback is true when the entry was served by a fallback store.
+	// +optional
+	Fallback bool `json:"fallback,omitempty"`
+}
+
+// ExternalSecretRolloutRestartStatus records the last restart of the workloads using the Secret.
+type ExternalSecretRolloutRestartStatus struct {
+	// DataHash is the hash of the data the workloads were last restarted for.
+	DataHash string `json:"dataHash"`
+
+	// RestartedAt is the time the workloads were last restarted.
+	// +optional
+	RestartedAt *metav1.Time `json:"restartedAt,omitempty"`
+
+	// Workloads lists the restarted workloads as kind/name.
+	// +optional
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+// ExternalSecretHistoryEntry references the snapshot of one revision of the target Secret.
+type ExternalSecretHistoryEntry struct {
+	// Revision increases by one for every snapshot.
+	Revision int64 `json:"revision"`
+
+	// SnapshotName is the name of the immutable Secret holding the data of this revision.
+	SnapshotName string `json:"snapshotName"`
+
+	// DataHash is the hash of the data of this revision.
+	DataHash string `json:"dataHash"`
+
+	// CreatedAt is the time the snapshot was taken.
+	CreatedAt metav1.Time `json:"createdAt"`
+}
+
+// ExternalSecretDryRunAction is the operation a dry run would perform on the target Secret.
+// +kubebuilder:validation:Enum=Create;Update;Delete;None
+type ExternalSecretDryRunAction string
+
+const (
+	// DryRunActionCreate means the target Secret would be created.
+	DryRunActionCreate ExternalSecretDryRunAction = "Create"
+	// DryRunActionUpdate means the target Secret would be updated.
+	DryRunActionUpdate ExternalSecretDryRunAction = "Update"
+	// DryRunActionDelete means the target Secret would be deleted.
+	DryRunActionDelete ExternalSecretDryRunAction = "Delete"
+	// DryRunActionNone means the target Secret would be left as it is.
+	DryRunActionNone ExternalSecretDryRunAction = "None"
+)
+
+// ExternalSecretDryRunStatus is a redacted summary of the changes to the target Secret.
+// It only holds key names and hashes, never secret values.
+type ExternalSecretDryRunStatus struct {
+	// Name is the name of the target Secret. With target.versioning,
+	// it is the generation the controller would write.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Action is the operation that would be performed on the target Secret.
+	Action ExternalSecretDryRunAction `json:"action"`
+
+	// AddedKeys lists the keys that would be added to the target Secret.
+	// +optional
+	AddedKeys []string `json:"addedKeys,omitempty"`
+
+	// RemovedKeys lists the keys that would be removed from the target Secret.
+	// +optional
+	RemovedKeys []string `json:"removedKeys,omitempty"`
+
+	// ChangedKeys lists the keys whose value would change.
+	// +optional
+	ChangedKeys []string `json:"changedKeys,omitempty"`
+
+	// MetadataChanged is true if the labels, annotations or type of the target Secret would change.
+	// +optional
+	MetadataChanged bool `json:"metadataChanged,omitempty"`
+
+	// CurrentDataHash is the hash of the data of the existing target Secret.
+	// +optional
+	CurrentDataHash string `json:"currentDataHash,omitempty"`
+
+	// DesiredDataHash is the hash of the data the target Secret would have.
+	// +optional
+	DesiredDataHash string `json:"desiredDataHash,omitempty"`
 }
 
 // ExternalSecret is the Schema for the external-secrets API.
@@ -771,6 +1398,8 @@ const (
 	AnnotationDataHash = "reconcile.external-secrets.io/data-hash"
 	// AnnotationForceSync all ExternalSecrets managed by a ClusterExternalSecret mirror the state and value of this annotation.
 	AnnotationForceSync = "external-secrets.io/force-sync"
+	// AnnotationRestartedAt is set on the pod template of workloads restarted by target.rolloutRestart.
+	AnnotationRestartedAt = "external-secrets.io/restartedAt"
 
 	// LabelManaged all secrets managed by an ExternalSecret will have this label equal to "true".
 	LabelManaged = "reconcile.external-secrets.io/managed"
@@ -780,6 +1409,17 @@ const (
 
 	// LabelOwner points to the owning ExternalSecret resource when CreationPolicy=Owner.
 	LabelOwner = "reconcile.external-secrets.io/created-by"
+
+	// LabelSnapshotOf points to the ExternalSecret resource a history snapshot belongs to.
+	LabelSnapshotOf = "reconcile.external-secrets.io/snapshot-of"
+
+	// LabelRotationOf points to the ExternalSecret resource a companion Secret of target.rotation belongs to.
+	LabelRotationOf = "reconcile.external-secrets.io/rotation-of"
+
+	// LabelStoreCredentials marks the Secrets and ServiceAccounts referenced by stores whose changes
+	// rebuild the provider clients of these stores. It must be equal to "true".
+	LabelStoreCredentials      = "external-secrets.io/store-credentials"
+	LabelStoreCredentialsValue = "true"
 )
 
 // +kubebuilder:object:root=true
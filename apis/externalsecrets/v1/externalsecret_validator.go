@@ -18,8 +18,10 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -61,6 +63,81 @@ func validateExternalSecret(es *ExternalSecret) (admission.Warnings, error) {
 		errs = errors.Join(errs, errors.New("either data or dataFrom should be specified"))
 	}
 
+	if es.Spec.Target.DryRun && es.Spec.Target.Manifest != nil {
+		errs = errors.Join(errs, errors.New("target.dryRun is not supported with target.manifest"))
+	}
+
+	if policy := es.Spec.Target.DriftPolicy; policy != "" && policy != DriftPolicyRevert && es.Spec.Target.Manifest != nil {
+		errs = errors.Join(errs, fmt.Errorf("target.driftPolicy %s is not supported with target.manifest", policy))
+	}
+
+	if es.Spec.Target.History != nil && es.Spec.Target.Manifest != nil {
+		errs = errors.Join(errs, errors.New("target.history is not supported with target.manifest"))
+	}
+
+	if rr := es.Spec.Target.RolloutRestart; rr != nil {
+		if es.Spec.Target.Manifest != nil {
+			errs = errors.Join(errs, errors.New("target.rolloutRestart is not supported with target.manifest"))
+		}
+		if len(rr.Workloads) == 0 && rr.Selector == nil {
+			errs = errors.Join(errs, errors.New("target.rolloutRestart requires workloads or a selector"))
+		}
+		for i, workload := range rr.Workloads {
+			if workload.Kind == WorkloadKindCronJob {
+				errs = errors.Join(errs, fmt.Errorf("target.rolloutRestart.workloads[%d]: kind %s cannot be restarted", i, workload.Kind))
+			}
+		}
+	}
+
+	if len(es.Spec.Target.Validation) > 0 && es.Spec.Target.Manifest != nil {
+		errs = errors.Join(errs, errors.New("target.validation is not supported with target.manifest"))
+	}
+	for i, rules := range es.Spec.Target.Validation {
+		if err := validateKeyValidation(rules); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("target.validation[%d]: %w", i, err))
+		}
+	}
+
+	if err := validateRotation(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if err := validateVersioning(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if err := validateSplitTargets(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if es.Spec.Target.RollbackTo != nil && es.Spec.Target.History == nil {
+		errs = errors.Join(errs, errors.New("target.rollbackTo requires target.history"))
+	}
+
+	if err := validateExpiryRefresh(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if err := validateStoreFallbacks(es.Spec.SecretStoreRef); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("secretStoreRef: %w", err))
+	}
+	for i, ref := range es.Spec.Data {
+		if ref.SourceRef == nil {
+			continue
+		}
+		if err := validateStoreFallbacks(ref.SourceRef.SecretStoreRef); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("data[%d].sourceRef.storeRef: %w", i, err))
+		}
+	}
+	for i, ref := range es.Spec.DataFrom {
+		if ref.SourceRef == nil || ref.SourceRef.SecretStoreRef == nil {
+			continue
+		}
+		if err := validateStoreFallbacks(*ref.SourceRef.SecretStoreRef); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("dataFrom[%d].sourceRef.storeRef: %w", i, err))
+		}
+	}
+
 	if err := validatePrivilegedTemplate(es.Spec.Target.Template); err != nil {
 		errs = errors.Join(errs, err)
 	}
@@ -87,6 +164,146 @@ func validateExternalSecret(es *ExternalSecret) (admission.Warnings, error) {
 	return nil, errs
 }
 
+func validateKeyValidation(rules ExternalSecretKeyValidation) error {
+	var errs error
+	if rules.Regex != "" {
+		if _, err := regexp.Compile(rules.Regex); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("invalid regex: %w", err))
+		}
+	}
+	if rules.MinLength != nil && rules.MaxLength != nil && *rules.MinLength > *rules.MaxLength {
+		errs = errors.Join(errs, errors.New("minLength cannot be greater than maxLength"))
+	}
+	if rules.JSON != nil && rules.JSON.Schema != "" && !json.Valid([]byte(rules.JSON.Schema)) {
+		errs = errors.Join(errs, errors.New("json.schema is not a valid JSON document"))
+	}
+	return errs
+}
+
+func validateExpiryRefresh(es *ExternalSecret) error {
+	expiry := es.Spec.ExpiryRefresh
+	if expiry == nil {
+		return nil
+	}
+	if es.Spec.RefreshPolicy != RefreshPolicyOnExpiry {
+		return errors.New("expiryRefresh requires refreshPolicy=OnExpiry")
+	}
+	if expiry.MinInterval != nil && expiry.MaxInterval != nil && expiry.MinInterval.Duration > expiry.MaxInterval.Duration {
+		return errors.New("expiryRefresh.minInterval cannot be greater than expiryRefresh.maxInterval")
+	}
+	return nil
+}
+
+// validateRotation checks target.rotation against the rest of the target.
+func validateRotation(es *ExternalSecret) error {
+	rotation := es.Spec.Target.Rotation
+	if rotation == nil {
+		return nil
+	}
+	var errs error
+	if rotation.OverlapPeriod.Duration <= 0 {
+		errs = errors.Join(errs, errors.New("target.rotation.overlapPeriod must be positive"))
+	}
+	if es.Spec.Target.Manifest != nil {
+		errs = errors.Join(errs, errors.New("target.rotation is not supported with target.manifest"))
+	}
+	if es.Spec.Target.Immutable {
+		errs = errors.Join(errs, errors.New("target.rotation is not supported with target.immutable"))
+	}
+	targetName := es.Spec.Target.Name
+	if targetName == "" {
+		targetName = es.Name
+	}
+	if rotation.SecretName != "" && rotation.SecretName == targetName {
+		errs = errors.Join(errs, errors.New("target.rotation.secretName must differ from the name of the target"))
+	}
+	return errs
+}
+
+// validateVersioning checks target.versioning against the rest of the target.
+func validateVersioning(es *ExternalSecret) error {
+	if es.Spec.Target.Versioning == nil {
+		return nil
+	}
+	var errs error
+	if es.Spec.Target.Manifest != nil {
+		errs = errors.Join(errs, errors.New("target.versioning is not supported with target.manifest"))
+	}
+	if es.Spec.Target.Rotation != nil {
+		errs = errors.Join(errs, errors.New("target.versioning is not supported with target.rotation"))
+	}
+	if policy := es.Spec.Target.CreationPolicy; policy != "" && policy != CreatePolicyOwner {
+		errs = errors.Join(errs, fmt.Errorf("target.versioning requires creationPolicy=Owner, got %s", policy))
+	}
+	return errs
+}
+
+// validateSplitTargets checks the entries of spec.targets against each other and against spec.target.
+func validateSplitTargets(es *ExternalSecret) error {
+	if len(es.Spec.Targets) == 0 {
+		return nil
+	}
+	var errs error
+	if es.Spec.Target.Manifest != nil {
+		errs = errors.Join(errs, errors.New("targets is not supported with target.manifest"))
+	}
+	targetName := es.Spec.Target.Name
+	if targetName == "" {
+		targetName = es.Name
+	}
+	seen := make(map[string]bool, len(es.Spec.Targets))
+	for i, target := range es.Spec.Targets {
+		if target.Name == targetName {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: name must differ from the name of the target", i))
+		}
+		if seen[target.Name] {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: name %s is used by another target", i, target.Name))
+		}
+		seen[target.Name] = true
+
+		if target.Selector != nil && target.Selector.RegExp != "" {
+			if _, err := regexp.Compile(target.Selector.RegExp); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("targets[%d].selector.regexp: %w", i, err))
+			}
+		}
+		if target.CreationPolicy == CreatePolicyNone {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: creationPolicy=None is not supported", i))
+		}
+		if target.DeletionPolicy == DeletionPolicyDelete && target.CreationPolicy != "" && target.CreationPolicy != CreatePolicyOwner {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: deletionPolicy=Delete requires creationPolicy=Owner", i))
+		}
+		if err := validatePrivilegedTemplate(target.Template); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: %w", i, err))
+		}
+		if err := ValidateSecretTemplateFromTargets(target.Template); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("targets[%d]: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// validateStoreFallbacks rejects fallbacks referencing the store itself or another fallback.
+func validateStoreFallbacks(ref SecretStoreRef) error {
+	if len(ref.Fallbacks) == 0 {
+		return nil
+	}
+	storeKind := func(kind string) string {
+		if kind == "" {
+			return SecretStoreKind
+		}
+		return kind
+	}
+	seen := map[string]bool{storeKind(ref.Kind) + "/" + ref.Name: true}
+	for i, fallback := range ref.Fallbacks {
+		key := storeKind(fallback.Kind) + "/" + fallback.Name
+		if seen[key] {
+			return fmt.Errorf("fallbacks[%d] references %s %q more than once", i, storeKind(fallback.Kind), fallback.Name)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
 func validateSourceRef(ref ExternalSecretDataFromRemoteRef) error {
 	if ref.SourceRef != nil && ref.SourceRef.GeneratorRef == nil && ref.SourceRef.SecretStoreRef == nil {
 		return errors.New("generatorRef or storeRef must be set when using sourceRef in dataFrom")
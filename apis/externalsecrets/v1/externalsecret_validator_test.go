@@ -18,8 +18,10 @@ package v1
 
 import (
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -67,6 +69,242 @@ func TestValidateExternalSecret(t *testing.T) {
 			},
 			expectedErr: "deletionPolicy=Delete must not be used when the controller doesn't own the secret. Please set creationPolicy=Owner",
 		},
+		{
+			name: "dry run with manifest target",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						DryRun:   true,
+						Manifest: &ManifestReference{APIVersion: "v1", Kind: "ConfigMap"},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.dryRun is not supported with target.manifest",
+		},
+		{
+			name: "report drift policy with manifest target",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						DriftPolicy: DriftPolicyReport,
+						Manifest:    &ManifestReference{APIVersion: "v1", Kind: "ConfigMap"},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.driftPolicy Report is not supported with target.manifest",
+		},
+		{
+			name: "rotation without overlap period",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Rotation: &ExternalSecretRotation{},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.rotation.overlapPeriod must be positive",
+		},
+		{
+			name: "rotation into the target",
+			obj: &ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db"},
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Immutable: true,
+						Rotation: &ExternalSecretRotation{
+							OverlapPeriod: metav1.Duration{Duration: time.Hour},
+							SecretName:    "db",
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.rotation is not supported with target.immutable\ntarget.rotation.secretName must differ from the name of the target",
+		},
+		{
+			name: "versioning with rotation and merge",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						CreationPolicy: CreatePolicyMerge,
+						Rotation:       &ExternalSecretRotation{OverlapPeriod: metav1.Duration{Duration: time.Hour}},
+						Versioning:     &ExternalSecretVersioning{Retain: 3},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.versioning is not supported with target.rotation\ntarget.versioning requires creationPolicy=Owner, got Merge",
+		},
+		{
+			name: "rollout restart of a cron job",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						RolloutRestart: &ExternalSecretRolloutRestart{
+							Workloads: []ExternalSecretWorkloadRef{{Kind: WorkloadKindCronJob, Name: "backup"}},
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.rolloutRestart.workloads[0]: kind CronJob cannot be restarted",
+		},
+		{
+			name: "invalid split targets",
+			obj: &ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: ExternalSecretSpec{
+					Targets: []ExternalSecretSplitTarget{
+						{Name: "app"},
+						{Name: "db", Selector: &ExternalSecretKeySelector{RegExp: "db_("}},
+						{Name: "db", CreationPolicy: CreatePolicyNone},
+						{Name: "cache", CreationPolicy: CreatePolicyOrphan, DeletionPolicy: DeletionPolicyDelete},
+					},
+					DataFrom: []ExternalSecretDataFromRemoteRef{
+						{Extract: &ExternalSecretDataRemoteRef{Key: "config"}},
+					},
+				},
+			},
+			expectedErr: "targets[0]: name must differ from the name of the target\n" +
+				"targets[1].selector.regexp: error parsing regexp: missing closing ): `db_(`\n" +
+				"targets[2]: name db is used by another target\n" +
+				"targets[2]: creationPolicy=None is not supported\n" +
+				"targets[3]: deletionPolicy=Delete requires creationPolicy=Owner",
+		},
+		{
+			name: "valid split targets",
+			obj: &ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{CreationPolicy: CreatePolicyNone},
+					Targets: []ExternalSecretSplitTarget{
+						{Name: "db", Selector: &ExternalSecretKeySelector{RegExp: "^db_"}, DeletionPolicy: DeletionPolicyDelete},
+						{Name: "cache", Selector: &ExternalSecretKeySelector{Keys: []string{"redis"}}, CreationPolicy: CreatePolicyMerge},
+					},
+					DataFrom: []ExternalSecretDataFromRemoteRef{
+						{Extract: &ExternalSecretDataRemoteRef{Key: "config"}},
+					},
+				},
+			},
+		},
+		{
+			name: "rollback without history",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						RollbackTo: new(int64(2)),
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.rollbackTo requires target.history",
+		},
+		{
+			name: "rollout restart without workloads",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						RolloutRestart: &ExternalSecretRolloutRestart{},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.rolloutRestart requires workloads or a selector",
+		},
+		{
+			name: "invalid validation rules",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Validation: []ExternalSecretKeyValidation{
+							{Key: "password", MinLength: new(16), MaxLength: new(8)},
+							{Key: "token", Regex: "("},
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "target.validation[0]: minLength cannot be greater than maxLength\ntarget.validation[1]: invalid regex: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "expiry refresh without the OnExpiry refresh policy",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					RefreshPolicy: RefreshPolicyPeriodic,
+					ExpiryRefresh: &ExternalSecretExpiryRefresh{},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "expiryRefresh requires refreshPolicy=OnExpiry",
+		},
+		{
+			name: "expiry refresh with minInterval greater than maxInterval",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					RefreshPolicy: RefreshPolicyOnExpiry,
+					ExpiryRefresh: &ExternalSecretExpiryRefresh{
+						MinInterval: &metav1.Duration{Duration: time.Hour},
+						MaxInterval: &metav1.Duration{Duration: time.Minute},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "expiryRefresh.minInterval cannot be greater than expiryRefresh.maxInterval",
+		},
+		{
+			name: "store fallbacks referencing the store twice",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					SecretStoreRef: SecretStoreRef{
+						Name: "vault",
+						Fallbacks: []SecretStoreFallbackRef{
+							{Name: "vault-dr", Kind: ClusterSecretStoreKind},
+							{Name: "vault", Kind: SecretStoreKind},
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+					DataFrom: []ExternalSecretDataFromRemoteRef{
+						{
+							Extract: &ExternalSecretDataRemoteRef{Key: "key"},
+							SourceRef: &StoreGeneratorSourceRef{
+								SecretStoreRef: &SecretStoreRef{
+									Name:      "aws",
+									Fallbacks: []SecretStoreFallbackRef{{Name: "aws-dr"}, {Name: "aws-dr"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErr: "secretStoreRef: fallbacks[1] references SecretStore \"vault\" more than once\ndataFrom[0].sourceRef.storeRef: fallbacks[1] references SecretStore \"aws-dr\" more than once",
+		},
 		{
 			name: "deletion policy merge",
 			obj: &ExternalSecret{
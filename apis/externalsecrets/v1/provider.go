@@ -18,6 +18,7 @@ package v1
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -69,6 +70,20 @@ type Provider interface {
 // +k8s:deepcopy-gen:interfaces=nil
 // +k8s:deepcopy-gen=nil
 
+// ClientCachingProvider is an optional interface a Provider may implement
+// when it caches its clients across reconciles, keyed on the store.
+// The clients of a store are evicted when the Secrets or ServiceAccounts
+// it references are rotated, so the next client uses the new credentials.
+type ClientCachingProvider interface {
+	// EvictClients evicts the cached clients of the store, for all namespaces.
+	EvictClients(store GenericStore)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
 // SecretsClient provides access to secrets.
 type SecretsClient interface {
 	// GetSecret returns a single secret from the provider
@@ -99,6 +114,77 @@ type SecretsClient interface {
 	Close(ctx context.Context) error
 }
 
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// BatchSecretsClient is an optional interface a SecretsClient may implement
+// to fetch several secrets with fewer round trips than one GetSecret per ref.
+type BatchSecretsClient interface {
+	// GetSecrets returns one result per ref, in the same order as refs.
+	// Each result follows the GetSecret contract: a missing secret is reported
+	// through a NoSecretError in its Err field.
+	// A non-nil error fails the whole batch.
+	GetSecrets(ctx context.Context, refs []ExternalSecretDataRemoteRef) ([]SecretResult, error)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen=nil
+
+// SecretResult holds the outcome of fetching a single ref in a batch.
+type SecretResult struct {
+	Value []byte
+	Err   error
+	// Version is the version of the value, if the provider reports it.
+	Version SecretVersion
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// SecretVersionClient is an optional interface a SecretsClient may implement
+// to report the version of the secrets it returns. The version is recorded
+// in the provenance of the ExternalSecret status.
+type SecretVersionClient interface {
+	// GetSecretVersion follows the GetSecret contract, and returns
+	// the version of the value along with it.
+	GetSecretVersion(ctx context.Context, ref ExternalSecretDataRemoteRef) ([]byte, SecretVersion, error)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen=nil
+
+// SecretVersion describes the version of a secret returned by a provider.
+type SecretVersion struct {
+	// ID is the identifier of the version reported by the provider.
+	ID string
+	// Expiry is the time the value expires, like the end of the lease of a dynamic secret.
+	// It is zero when the provider does not report one.
+	Expiry time.Time
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// ConditionalPusher is an optional interface a SecretsClient may implement
+// to push a secret only if its remote value is still at a given version,
+// the condition being enforced by the provider.
+type ConditionalPusher interface {
+	// PushSecretIfVersion follows the PushSecret contract, and pushes the secret
+	// only if the remote value is at version. It returns VersionConflictErr when
+	// the remote value is at another version, ConditionalPushUnsupportedErr when
+	// the store does not support conditional pushes, and the version of the
+	// remote value after the push otherwise.
+	PushSecretIfVersion(ctx context.Context, secret *corev1.Secret, data PushSecretData, version SecretVersion) (SecretVersion, error)
+}
+
 // NoSecretErr is a sentinel error for when a secret is not found.
 var NoSecretErr = NoSecretError{}
 
@@ -110,6 +196,29 @@ func (NoSecretError) Error() string {
 	return "Secret does not exist"
 }
 
+// VersionConflictErr is a sentinel error for when a conditional push finds
+// the remote value at another version than the expected one.
+var VersionConflictErr = VersionConflictError{}
+
+// VersionConflictError shall be returned by PushSecretIfVersion when the
+// remote value was changed since the expected version.
+type VersionConflictError struct{}
+
+func (VersionConflictError) Error() string {
+	return "remote value was changed since the expected version"
+}
+
+// ConditionalPushUnsupportedErr is a sentinel error for when a store does not support conditional pushes.
+var ConditionalPushUnsupportedErr = ConditionalPushUnsupportedError{}
+
+// ConditionalPushUnsupportedError shall be returned by PushSecretIfVersion
+// when the store cannot enforce the version, so that the caller falls back to PushSecret.
+type ConditionalPushUnsupportedError struct{}
+
+func (ConditionalPushUnsupportedError) Error() string {
+	return "conditional push is not supported by the store"
+}
+
 // NotModifiedErr is a sentinel error to signal that the webhook received no changes,
 // and it should just return without doing anything.
 var NotModifiedErr = NotModifiedError{}
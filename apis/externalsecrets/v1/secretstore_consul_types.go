@@ -0,0 +1,63 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// ConsulProvider configures a store to sync secrets with the HashiCorp Consul KV store.
+type ConsulProvider struct {
+	// Server is the address of the Consul agent, e.g. "https://consul.example.com:8501".
+	// The scheme defaults to http.
+	Server string `json:"server"`
+
+	// Datacenter to read and write keys in. Defaults to the datacenter of the agent.
+	// +optional
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// Namespace of the keys. Consul Enterprise only.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Partition of the keys. Consul Enterprise only.
+	// +optional
+	Partition string `json:"partition,omitempty"`
+
+	// Prefix is prepended to every key, e.g. "config/production/".
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Auth configures how the operator authenticates with Consul.
+	// Requests are anonymous when it is not set.
+	// +optional
+	Auth *ConsulAuth `json:"auth,omitempty"`
+
+	// Base64 encoded certificate of the CA of the Consul agent.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// CAProvider references the certificate of the CA of the Consul agent.
+	// +optional
+	CAProvider *CAProvider `json:"caProvider,omitempty"`
+}
+
+// ConsulAuth configures the ACL token used to authenticate with Consul.
+type ConsulAuth struct {
+	// TokenSecretRef references the ACL token.
+	TokenSecretRef esmeta.SecretKeySelector `json:"tokenSecretRef"`
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// EtcdProvider configures a store to sync secrets with an etcd v3 cluster.
+type EtcdProvider struct {
+	// Endpoints are the client URLs of the etcd members, e.g. "https://etcd-0.etcd:2379".
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+
+	// Prefix is prepended to every key, e.g. "/config/production/".
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Auth configures the user the operator authenticates as.
+	// Requests are anonymous, or authenticated with the client certificate only, when it is not set.
+	// +optional
+	Auth *EtcdAuth `json:"auth,omitempty"`
+
+	// ClientTLS configures the client certificate used for mutual TLS authentication.
+	// +optional
+	ClientTLS EtcdClientTLS `json:"tls,omitempty"`
+
+	// Base64 encoded certificate of the CA of the etcd members.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// CAProvider references the certificate of the CA of the etcd members.
+	// +optional
+	CAProvider *CAProvider `json:"caProvider,omitempty"`
+}
+
+// EtcdAuth configures the user and password used to authenticate with etcd.
+type EtcdAuth struct {
+	// UsernameSecretRef references the name of the user.
+	UsernameSecretRef esmeta.SecretKeySelector `json:"usernameSecretRef"`
+
+	// PasswordSecretRef references the password of the user.
+	PasswordSecretRef esmeta.SecretKeySelector `json:"passwordSecretRef"`
+}
+
+// EtcdClientTLS is the configuration used for client side related TLS communication,
+// when the etcd members require client certificates.
+type EtcdClientTLS struct {
+	// CertSecretRef is a certificate added to the transport layer
+	// when communicating with the etcd members.
+	// If no key for the Secret is specified, external-secret will default to 'tls.crt'.
+	// +optional
+	CertSecretRef *esmeta.SecretKeySelector `json:"certSecretRef,omitempty"`
+
+	// KeySecretRef to a key in a Secret resource containing client private key
+	// added to the transport layer when communicating with the etcd members.
+	// If no key for the Secret is specified, external-secret will default to 'tls.key'.
+	// +optional
+	KeySecretRef *esmeta.SecretKeySelector `json:"keySecretRef,omitempty"`
+}
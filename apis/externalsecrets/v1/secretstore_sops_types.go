@@ -0,0 +1,87 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+// SopsFormat is the format of a SOPS document.
+// +kubebuilder:validation:Enum=Auto;YAML;JSON;Dotenv;INI
+type SopsFormat string
+
+// Supported SOPS document formats.
+const (
+	// SopsFormatAuto detects the format from the file extension of a document, defaulting to YAML.
+	SopsFormatAuto   SopsFormat = "Auto"
+	SopsFormatYAML   SopsFormat = "YAML"
+	SopsFormatJSON   SopsFormat = "JSON"
+	SopsFormatDotenv SopsFormat = "Dotenv"
+	SopsFormatINI    SopsFormat = "INI"
+)
+
+// SopsProvider configures a store to sync secrets from SOPS-encrypted documents.
+// Every key of the ConfigMap or Secret, or every file of the directory, is a document.
+// Exactly one of configMap, secret or path must be specified.
+// +kubebuilder:validation:XValidation:rule="[has(self.configMap), has(self.secret), has(self.path)].filter(x, x).size() == 1",message="exactly one of configMap, secret or path must be specified"
+type SopsProvider struct {
+	// ConfigMap holding the encrypted documents.
+	// +optional
+	ConfigMap *SopsObjectReference `json:"configMap,omitempty"`
+
+	// Secret holding the encrypted documents.
+	// +optional
+	Secret *SopsObjectReference `json:"secret,omitempty"`
+
+	// Path of a directory of the controller holding the encrypted documents,
+	// e.g. a mounted ConfigMap. Only allowed in a ClusterSecretStore.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Format of the documents.
+	// +kubebuilder:default=Auto
+	// +optional
+	Format SopsFormat `json:"format,omitempty"`
+
+	// Auth configures the keys used to decrypt the documents.
+	Auth SopsAuth `json:"auth"`
+}
+
+// SopsObjectReference references a ConfigMap or Secret holding SOPS documents.
+type SopsObjectReference struct {
+	// Name of the object.
+	Name string `json:"name"`
+
+	// Namespace of the object. Required in a ClusterSecretStore.
+	// Ignored in a SecretStore, which always reads from its own namespace.
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// SopsAuth configures the private keys used to decrypt SOPS documents.
+// At least one key must be specified.
+type SopsAuth struct {
+	// Age references secrets holding age identities, one or more per secret,
+	// as written by age-keygen.
+	// +optional
+	Age []esmeta.SecretKeySelector `json:"age,omitempty"`
+
+	// PGP references secrets holding ASCII-armored PGP private keys.
+	// Keys protected by a passphrase are not supported.
+	// +optional
+	PGP []esmeta.SecretKeySelector `json:"pgp,omitempty"`
+}
@@ -49,6 +49,25 @@ type SecretStoreSpec struct {
 	// Used to constrain a ClusterSecretStore to specific namespaces. Relevant only to ClusterSecretStore.
 	// +optional
 	Conditions []ClusterSecretStoreCondition `json:"conditions,omitempty"`
+
+	// Used to cache the values fetched from the provider (GetSecret, GetSecretMap).
+	// The cache is shared by all ExternalSecrets referencing this store and is dropped when the store changes.
+	// The values of a ClusterSecretStore are cached separately for each namespace using it.
+	// If omitted, caching is disabled (default).
+	// +optional
+	Cache *CacheConfig `json:"cache,omitempty"`
+
+	// Used to limit the rate of the calls to the provider.
+	// The limit is shared by all ExternalSecrets and PushSecrets referencing this store.
+	// If omitted, calls are not limited (default).
+	// +optional
+	RateLimit *SecretStoreRateLimit `json:"rateLimit,omitempty"`
+
+	// Used to stop calling the provider for a while after consecutive errors.
+	// The circuit breaker is shared by all ExternalSecrets and PushSecrets referencing this store.
+	// If omitted, the provider is always called (default).
+	// +optional
+	CircuitBreaker *SecretStoreCircuitBreaker `json:"circuitBreaker,omitempty"`
 }
 
 // GetRefreshInterval resolves the refresh interval to a time.Duration. The field
@@ -285,6 +304,18 @@ type SecretStoreProvider struct {
 	// OpenBao configures this store to sync secrets using the OpenBao provider.
 	// +optional
 	OpenBao *OpenBaoProvider `json:"openBao,omitempty"`
+
+	// Sops configures this store to sync secrets from SOPS-encrypted documents.
+	// +optional
+	Sops *SopsProvider `json:"sops,omitempty"`
+
+	// Consul configures this store to sync secrets using the HashiCorp Consul KV store.
+	// +optional
+	Consul *ConsulProvider `json:"consul,omitempty"`
+
+	// Etcd configures this store to sync secrets using an etcd v3 cluster.
+	// +optional
+	Etcd *EtcdProvider `json:"etcd,omitempty"`
 }
 
 // CAProviderType defines the type of provider for certificate authority.
@@ -333,6 +364,35 @@ type SecretStoreRetrySettings struct {
 	RetryInterval *string `json:"retryInterval,omitempty"`
 }
 
+// SecretStoreRateLimit defines the rate limit of the calls to the provider of a store.
+type SecretStoreRateLimit struct {
+	// RequestsPerSecond is the sustained rate of calls to the provider.
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond int32 `json:"requestsPerSecond"`
+
+	// Burst is the number of calls allowed at once above the sustained rate.
+	// Defaults to requestsPerSecond.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// SecretStoreCircuitBreaker defines when the calls to the provider of a store are suspended.
+type SecretStoreCircuitBreaker struct {
+	// ErrorThreshold is the number of consecutive failed calls which open the circuit.
+	// A secret which does not exist is not a failure.
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ErrorThreshold int32 `json:"errorThreshold,omitempty"`
+
+	// OpenDuration is the time calls fail without reaching the provider once the circuit is open.
+	// A single call is then let through: the circuit closes if it succeeds, and opens again otherwise.
+	// +kubebuilder:default="30s"
+	// +optional
+	OpenDuration metav1.Duration `json:"openDuration,omitempty"`
+}
+
 // SecretStoreConditionType represents the condition of the SecretStore.
 type SecretStoreConditionType string
 
@@ -340,6 +400,8 @@ type SecretStoreConditionType string
 const (
 	// SecretStoreReady indicates that the store is ready and able to serve requests.
 	SecretStoreReady SecretStoreConditionType = "Ready"
+	// SecretStoreCircuitOpen indicates that the calls to the provider are suspended by the circuit breaker.
+	SecretStoreCircuitOpen SecretStoreConditionType = "CircuitOpen"
 
 	ReasonInvalidStore          = "InvalidStoreConfiguration"
 	ReasonInvalidProviderConfig = "InvalidProviderConfig"
@@ -351,6 +413,11 @@ const (
 	ReasonStoreValid        = "Valid"
 	StoreUnmaintained       = "StoreUnmaintained"
 	StoreDeprecated         = "StoreDeprecated"
+
+	// ReasonCircuitOpen indicates that the circuit breaker of the store is open.
+	ReasonCircuitOpen = "CircuitOpen"
+	// ReasonCircuitClosed indicates that the circuit breaker of the store is closed.
+	ReasonCircuitClosed = "CircuitClosed"
 )
 
 // SecretStoreStatusCondition contains condition information for a SecretStore.
@@ -1422,6 +1422,52 @@ func (in *ConjurProvider) DeepCopy() *ConjurProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsulAuth) DeepCopyInto(out *ConsulAuth) {
+	*out = *in
+	in.TokenSecretRef.DeepCopyInto(&out.TokenSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsulAuth.
+func (in *ConsulAuth) DeepCopy() *ConsulAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsulAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsulProvider) DeepCopyInto(out *ConsulProvider) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ConsulAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.CAProvider != nil {
+		in, out := &in.CAProvider, &out.CAProvider
+		*out = new(CAProvider)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsulProvider.
+func (in *ConsulProvider) DeepCopy() *ConsulProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsulProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DVLSAuth) DeepCopyInto(out *DVLSAuth) {
 	*out = *in
@@ -1598,6 +1644,84 @@ func (in *DopplerProvider) DeepCopy() *DopplerProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdAuth) DeepCopyInto(out *EtcdAuth) {
+	*out = *in
+	in.UsernameSecretRef.DeepCopyInto(&out.UsernameSecretRef)
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdAuth.
+func (in *EtcdAuth) DeepCopy() *EtcdAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdClientTLS) DeepCopyInto(out *EtcdClientTLS) {
+	*out = *in
+	if in.CertSecretRef != nil {
+		in, out := &in.CertSecretRef, &out.CertSecretRef
+		*out = new(apismetav1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KeySecretRef != nil {
+		in, out := &in.KeySecretRef, &out.KeySecretRef
+		*out = new(apismetav1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdClientTLS.
+func (in *EtcdClientTLS) DeepCopy() *EtcdClientTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdClientTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdProvider) DeepCopyInto(out *EtcdProvider) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(EtcdAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ClientTLS.DeepCopyInto(&out.ClientTLS)
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.CAProvider != nil {
+		in, out := &in.CAProvider, &out.CAProvider
+		*out = new(CAProvider)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdProvider.
+func (in *EtcdProvider) DeepCopy() *EtcdProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecret) DeepCopyInto(out *ExternalSecret) {
 	*out = *in
@@ -1625,6 +1749,26 @@ func (in *ExternalSecret) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretCertificateValidation) DeepCopyInto(out *ExternalSecretCertificateValidation) {
+	*out = *in
+	if in.MinValidity != nil {
+		in, out := &in.MinValidity, &out.MinValidity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretCertificateValidation.
+func (in *ExternalSecretCertificateValidation) DeepCopy() *ExternalSecretCertificateValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretCertificateValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretData) DeepCopyInto(out *ExternalSecretData) {
 	*out = *in
@@ -1698,6 +1842,86 @@ func (in *ExternalSecretDataRemoteRef) DeepCopy() *ExternalSecretDataRemoteRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretDryRunStatus) DeepCopyInto(out *ExternalSecretDryRunStatus) {
+	*out = *in
+	if in.AddedKeys != nil {
+		in, out := &in.AddedKeys, &out.AddedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedKeys != nil {
+		in, out := &in.RemovedKeys, &out.RemovedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChangedKeys != nil {
+		in, out := &in.ChangedKeys, &out.ChangedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretDryRunStatus.
+func (in *ExternalSecretDryRunStatus) DeepCopy() *ExternalSecretDryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretDryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretExpiryRefresh) DeepCopyInto(out *ExternalSecretExpiryRefresh) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]ExternalSecretExpirySource, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemainingLifetimePercent != nil {
+		in, out := &in.RemainingLifetimePercent, &out.RemainingLifetimePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxInterval != nil {
+		in, out := &in.MaxInterval, &out.MaxInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretExpiryRefresh.
+func (in *ExternalSecretExpiryRefresh) DeepCopy() *ExternalSecretExpiryRefresh {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretExpiryRefresh)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretExpirySource) DeepCopyInto(out *ExternalSecretExpirySource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretExpirySource.
+func (in *ExternalSecretExpirySource) DeepCopy() *ExternalSecretExpirySource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretExpirySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretFind) DeepCopyInto(out *ExternalSecretFind) {
 	*out = *in
@@ -1730,6 +1954,126 @@ func (in *ExternalSecretFind) DeepCopy() *ExternalSecretFind {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretHistory) DeepCopyInto(out *ExternalSecretHistory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretHistory.
+func (in *ExternalSecretHistory) DeepCopy() *ExternalSecretHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretHistoryEntry) DeepCopyInto(out *ExternalSecretHistoryEntry) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretHistoryEntry.
+func (in *ExternalSecretHistoryEntry) DeepCopy() *ExternalSecretHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretJSONValidation) DeepCopyInto(out *ExternalSecretJSONValidation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretJSONValidation.
+func (in *ExternalSecretJSONValidation) DeepCopy() *ExternalSecretJSONValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretJSONValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretKeyProvenance) DeepCopyInto(out *ExternalSecretKeyProvenance) {
+	*out = *in
+	if in.LastChangeTime != nil {
+		in, out := &in.LastChangeTime, &out.LastChangeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretKeyProvenance.
+func (in *ExternalSecretKeyProvenance) DeepCopy() *ExternalSecretKeyProvenance {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretKeyProvenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretKeySelector) DeepCopyInto(out *ExternalSecretKeySelector) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretKeySelector.
+func (in *ExternalSecretKeySelector) DeepCopy() *ExternalSecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretKeyValidation) DeepCopyInto(out *ExternalSecretKeyValidation) {
+	*out = *in
+	if in.MinLength != nil {
+		in, out := &in.MinLength, &out.MinLength
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxLength != nil {
+		in, out := &in.MaxLength, &out.MaxLength
+		*out = new(int)
+		**out = **in
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(ExternalSecretCertificateValidation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = new(ExternalSecretJSONValidation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretKeyValidation.
+func (in *ExternalSecretKeyValidation) DeepCopy() *ExternalSecretKeyValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretKeyValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretList) DeepCopyInto(out *ExternalSecretList) {
 	*out = *in
@@ -1781,92 +2125,210 @@ func (in *ExternalSecretMetadata) DeepCopyInto(out *ExternalSecretMetadata) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretMetadata.
-func (in *ExternalSecretMetadata) DeepCopy() *ExternalSecretMetadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretMetadata.
+func (in *ExternalSecretMetadata) DeepCopy() *ExternalSecretMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRewrite) DeepCopyInto(out *ExternalSecretRewrite) {
+	*out = *in
+	if in.Merge != nil {
+		in, out := &in.Merge, &out.Merge
+		*out = new(ExternalSecretRewriteMerge)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Regexp != nil {
+		in, out := &in.Regexp, &out.Regexp
+		*out = new(ExternalSecretRewriteRegexp)
+		**out = **in
+	}
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		*out = new(ExternalSecretRewriteTransform)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRewrite.
+func (in *ExternalSecretRewrite) DeepCopy() *ExternalSecretRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRewriteMerge) DeepCopyInto(out *ExternalSecretRewriteMerge) {
+	*out = *in
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRewriteMerge.
+func (in *ExternalSecretRewriteMerge) DeepCopy() *ExternalSecretRewriteMerge {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRewriteMerge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRewriteRegexp) DeepCopyInto(out *ExternalSecretRewriteRegexp) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRewriteRegexp.
+func (in *ExternalSecretRewriteRegexp) DeepCopy() *ExternalSecretRewriteRegexp {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRewriteRegexp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRewriteTransform) DeepCopyInto(out *ExternalSecretRewriteTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRewriteTransform.
+func (in *ExternalSecretRewriteTransform) DeepCopy() *ExternalSecretRewriteTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRewriteTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRolloutRestart) DeepCopyInto(out *ExternalSecretRolloutRestart) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]ExternalSecretWorkloadRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRolloutRestart.
+func (in *ExternalSecretRolloutRestart) DeepCopy() *ExternalSecretRolloutRestart {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRolloutRestart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRolloutRestartStatus) DeepCopyInto(out *ExternalSecretRolloutRestartStatus) {
+	*out = *in
+	if in.RestartedAt != nil {
+		in, out := &in.RestartedAt, &out.RestartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRolloutRestartStatus.
+func (in *ExternalSecretRolloutRestartStatus) DeepCopy() *ExternalSecretRolloutRestartStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalSecretMetadata)
+	out := new(ExternalSecretRolloutRestartStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalSecretRewrite) DeepCopyInto(out *ExternalSecretRewrite) {
+func (in *ExternalSecretRotatedKey) DeepCopyInto(out *ExternalSecretRotatedKey) {
 	*out = *in
-	if in.Merge != nil {
-		in, out := &in.Merge, &out.Merge
-		*out = new(ExternalSecretRewriteMerge)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Regexp != nil {
-		in, out := &in.Regexp, &out.Regexp
-		*out = new(ExternalSecretRewriteRegexp)
-		**out = **in
-	}
-	if in.Transform != nil {
-		in, out := &in.Transform, &out.Transform
-		*out = new(ExternalSecretRewriteTransform)
-		**out = **in
-	}
+	in.RotatedAt.DeepCopyInto(&out.RotatedAt)
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRewrite.
-func (in *ExternalSecretRewrite) DeepCopy() *ExternalSecretRewrite {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRotatedKey.
+func (in *ExternalSecretRotatedKey) DeepCopy() *ExternalSecretRotatedKey {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalSecretRewrite)
+	out := new(ExternalSecretRotatedKey)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalSecretRewriteMerge) DeepCopyInto(out *ExternalSecretRewriteMerge) {
+func (in *ExternalSecretRotation) DeepCopyInto(out *ExternalSecretRotation) {
 	*out = *in
-	if in.Priority != nil {
-		in, out := &in.Priority, &out.Priority
+	out.OverlapPeriod = in.OverlapPeriod
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRewriteMerge.
-func (in *ExternalSecretRewriteMerge) DeepCopy() *ExternalSecretRewriteMerge {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRotation.
+func (in *ExternalSecretRotation) DeepCopy() *ExternalSecretRotation {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalSecretRewriteMerge)
+	out := new(ExternalSecretRotation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalSecretRewriteRegexp) DeepCopyInto(out *ExternalSecretRewriteRegexp) {
+func (in *ExternalSecretSecretGeneration) DeepCopyInto(out *ExternalSecretSecretGeneration) {
 	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRewriteRegexp.
-func (in *ExternalSecretRewriteRegexp) DeepCopy() *ExternalSecretRewriteRegexp {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretSecretGeneration.
+func (in *ExternalSecretSecretGeneration) DeepCopy() *ExternalSecretSecretGeneration {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalSecretRewriteRegexp)
+	out := new(ExternalSecretSecretGeneration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExternalSecretRewriteTransform) DeepCopyInto(out *ExternalSecretRewriteTransform) {
+func (in *ExternalSecretServedBy) DeepCopyInto(out *ExternalSecretServedBy) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRewriteTransform.
-func (in *ExternalSecretRewriteTransform) DeepCopy() *ExternalSecretRewriteTransform {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretServedBy.
+func (in *ExternalSecretServedBy) DeepCopy() *ExternalSecretServedBy {
 	if in == nil {
 		return nil
 	}
-	out := new(ExternalSecretRewriteTransform)
+	out := new(ExternalSecretServedBy)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1874,8 +2336,20 @@ func (in *ExternalSecretRewriteTransform) DeepCopy() *ExternalSecretRewriteTrans
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretSpec) DeepCopyInto(out *ExternalSecretSpec) {
 	*out = *in
-	out.SecretStoreRef = in.SecretStoreRef
+	in.SecretStoreRef.DeepCopyInto(&out.SecretStoreRef)
 	in.Target.DeepCopyInto(&out.Target)
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ExternalSecretSplitTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpiryRefresh != nil {
+		in, out := &in.ExpiryRefresh, &out.ExpiryRefresh
+		*out = new(ExternalSecretExpiryRefresh)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RefreshInterval != nil {
 		in, out := &in.RefreshInterval, &out.RefreshInterval
 		*out = new(metav1.Duration)
@@ -1912,6 +2386,31 @@ func (in *ExternalSecretSpec) DeepCopy() *ExternalSecretSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretSplitTarget) DeepCopyInto(out *ExternalSecretSplitTarget) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(ExternalSecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(ExternalSecretTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretSplitTarget.
+func (in *ExternalSecretSplitTarget) DeepCopy() *ExternalSecretSplitTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretSplitTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretStatus) DeepCopyInto(out *ExternalSecretStatus) {
 	*out = *in
@@ -1924,6 +2423,67 @@ func (in *ExternalSecretStatus) DeepCopyInto(out *ExternalSecretStatus) {
 		}
 	}
 	out.Binding = in.Binding
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(ExternalSecretDryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRunTargets != nil {
+		in, out := &in.DryRunTargets, &out.DryRunTargets
+		*out = make([]ExternalSecretDryRunStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ExternalSecretHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RolledBackTo != nil {
+		in, out := &in.RolledBackTo, &out.RolledBackTo
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RolloutRestart != nil {
+		in, out := &in.RolloutRestart, &out.RolloutRestart
+		*out = new(ExternalSecretRolloutRestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpiryTime != nil {
+		in, out := &in.ExpiryTime, &out.ExpiryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRefreshTime != nil {
+		in, out := &in.NextRefreshTime, &out.NextRefreshTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ServedBy != nil {
+		in, out := &in.ServedBy, &out.ServedBy
+		*out = make([]ExternalSecretServedBy, len(*in))
+		copy(*out, *in)
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = make([]ExternalSecretKeyProvenance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = make([]ExternalSecretRotatedKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Versioning != nil {
+		in, out := &in.Versioning, &out.Versioning
+		*out = new(ExternalSecretVersioningStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretStatus.
@@ -2001,6 +2561,38 @@ func (in *ExternalSecretTarget) DeepCopyInto(out *ExternalSecretTarget) {
 		*out = new(ManifestReference)
 		**out = **in
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = new(ExternalSecretHistory)
+		**out = **in
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RolloutRestart != nil {
+		in, out := &in.RolloutRestart, &out.RolloutRestart
+		*out = new(ExternalSecretRolloutRestart)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = make([]ExternalSecretKeyValidation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(ExternalSecretRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Versioning != nil {
+		in, out := &in.Versioning, &out.Versioning
+		*out = new(ExternalSecretVersioning)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTarget.
@@ -2092,6 +2684,73 @@ func (in *ExternalSecretValidator) DeepCopy() *ExternalSecretValidator {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretVersioning) DeepCopyInto(out *ExternalSecretVersioning) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]ExternalSecretWorkloadRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretVersioning.
+func (in *ExternalSecretVersioning) DeepCopy() *ExternalSecretVersioning {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretVersioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretVersioningStatus) DeepCopyInto(out *ExternalSecretVersioningStatus) {
+	*out = *in
+	if in.Generations != nil {
+		in, out := &in.Generations, &out.Generations
+		*out = make([]ExternalSecretSecretGeneration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretVersioningStatus.
+func (in *ExternalSecretVersioningStatus) DeepCopy() *ExternalSecretVersioningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretVersioningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretWorkloadRef) DeepCopyInto(out *ExternalSecretWorkloadRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretWorkloadRef.
+func (in *ExternalSecretWorkloadRef) DeepCopy() *ExternalSecretWorkloadRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretWorkloadRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FakeProvider) DeepCopyInto(out *FakeProvider) {
 	*out = *in
@@ -3921,6 +4580,37 @@ func (in *SecretStoreList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreCircuitBreaker) DeepCopyInto(out *SecretStoreCircuitBreaker) {
+	*out = *in
+	out.OpenDuration = in.OpenDuration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreCircuitBreaker.
+func (in *SecretStoreCircuitBreaker) DeepCopy() *SecretStoreCircuitBreaker {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreCircuitBreaker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreFallbackRef) DeepCopyInto(out *SecretStoreFallbackRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreFallbackRef.
+func (in *SecretStoreFallbackRef) DeepCopy() *SecretStoreFallbackRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreFallbackRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretStoreProvider) DeepCopyInto(out *SecretStoreProvider) {
 	*out = *in
@@ -4139,6 +4829,21 @@ func (in *SecretStoreProvider) DeepCopyInto(out *SecretStoreProvider) {
 		*out = new(OpenBaoProvider)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Sops != nil {
+		in, out := &in.Sops, &out.Sops
+		*out = new(SopsProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Consul != nil {
+		in, out := &in.Consul, &out.Consul
+		*out = new(ConsulProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(EtcdProvider)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreProvider.
@@ -4151,9 +4856,29 @@ func (in *SecretStoreProvider) DeepCopy() *SecretStoreProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreRateLimit) DeepCopyInto(out *SecretStoreRateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRateLimit.
+func (in *SecretStoreRateLimit) DeepCopy() *SecretStoreRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretStoreRef) DeepCopyInto(out *SecretStoreRef) {
 	*out = *in
+	if in.Fallbacks != nil {
+		in, out := &in.Fallbacks, &out.Fallbacks
+		*out = make([]SecretStoreFallbackRef, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRef.
@@ -4216,6 +4941,21 @@ func (in *SecretStoreSpec) DeepCopyInto(out *SecretStoreSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(CacheConfig)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(SecretStoreRateLimit)
+		**out = **in
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(SecretStoreCircuitBreaker)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreSpec.
@@ -4313,13 +5053,88 @@ func (in *SenhaseguraProvider) DeepCopy() *SenhaseguraProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsAuth) DeepCopyInto(out *SopsAuth) {
+	*out = *in
+	if in.Age != nil {
+		in, out := &in.Age, &out.Age
+		*out = make([]apismetav1.SecretKeySelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PGP != nil {
+		in, out := &in.PGP, &out.PGP
+		*out = make([]apismetav1.SecretKeySelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsAuth.
+func (in *SopsAuth) DeepCopy() *SopsAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsObjectReference) DeepCopyInto(out *SopsObjectReference) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsObjectReference.
+func (in *SopsObjectReference) DeepCopy() *SopsObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsProvider) DeepCopyInto(out *SopsProvider) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(SopsObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SopsObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Auth.DeepCopyInto(&out.Auth)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsProvider.
+func (in *SopsProvider) DeepCopy() *SopsProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreGeneratorSourceRef) DeepCopyInto(out *StoreGeneratorSourceRef) {
 	*out = *in
 	if in.SecretStoreRef != nil {
 		in, out := &in.SecretStoreRef, &out.SecretStoreRef
 		*out = new(SecretStoreRef)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.GeneratorRef != nil {
 		in, out := &in.GeneratorRef, &out.GeneratorRef
@@ -4341,7 +5156,7 @@ func (in *StoreGeneratorSourceRef) DeepCopy() *StoreGeneratorSourceRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreSourceRef) DeepCopyInto(out *StoreSourceRef) {
 	*out = *in
-	out.SecretStoreRef = in.SecretStoreRef
+	in.SecretStoreRef.DeepCopyInto(&out.SecretStoreRef)
 	if in.GeneratorRef != nil {
 		in, out := &in.GeneratorRef, &out.GeneratorRef
 		*out = new(GeneratorRef)
@@ -32,6 +32,24 @@ const (
 	ReasonErrored = "Errored"
 	// ReasonSourceDeleted indicates that the source Secret was deleted and provider secrets were cleaned up.
 	ReasonSourceDeleted = "SourceDeleted"
+	// ReasonRemoteDrifted indicates that a pushed value was changed in the provider outside of the controller.
+	ReasonRemoteDrifted = "RemoteDrifted"
+	// ReasonRemoteDriftReverted indicates that a pushed value changed in the provider was pushed again.
+	ReasonRemoteDriftReverted = "RemoteDriftReverted"
+	// ReasonNoRemoteDrift indicates that the remote values match the values pushed by the controller.
+	ReasonNoRemoteDrift = "NoRemoteDrift"
+	// ReasonPushRolledBack indicates that an atomic push failed and the previous values were restored in the secret stores.
+	ReasonPushRolledBack = "PushRolledBack"
+	// ReasonRollbackFailed indicates that an atomic push failed and the previous values could not be restored in a secret store.
+	ReasonRollbackFailed = "RollbackFailed"
+	// ReasonRemoteConflict indicates that remote values changed outside of the PushSecret were not updated.
+	ReasonRemoteConflict = "RemoteConflict"
+	// ReasonNoRemoteConflict indicates that the remote values matched the values last pushed and were updated.
+	ReasonNoRemoteConflict = "NoRemoteConflict"
+	// ReasonDeletionScheduled indicates that remote secrets are deleted at the end of the deletion grace period.
+	ReasonDeletionScheduled = "DeletionScheduled"
+	// ReasonDeletionCancelled indicates that pending deletions of remote secrets were cancelled.
+	ReasonDeletionCancelled = "DeletionCancelled"
 )
 
 // PushSecretStoreRef contains a reference on how to sync to a SecretStore.
@@ -55,7 +73,7 @@ type PushSecretStoreRef struct {
 }
 
 // PushSecretUpdatePolicy defines how push secrets are updated in the provider.
-// +kubebuilder:validation:Enum=Replace;IfNotExists
+// +kubebuilder:validation:Enum=Replace;IfNotExists;IfUnchanged
 type PushSecretUpdatePolicy string
 
 const (
@@ -63,6 +81,11 @@ const (
 	PushSecretUpdatePolicyReplace PushSecretUpdatePolicy = "Replace"
 	// PushSecretUpdatePolicyIfNotExists only creates secrets that don't exist in the provider.
 	PushSecretUpdatePolicyIfNotExists PushSecretUpdatePolicy = "IfNotExists"
+	// PushSecretUpdatePolicyIfUnchanged only updates secrets whose remote value or version still matches
+	// the one last pushed by the PushSecret, and only creates secrets that don't exist in the provider.
+	// It is best-effort on providers which do not implement conditional pushes: a value written between
+	// the comparison and the push is overwritten.
+	PushSecretUpdatePolicyIfUnchanged PushSecretUpdatePolicy = "IfUnchanged"
 )
 
 // PushSecretDeletionPolicy defines how push secrets are deleted in the provider.
@@ -76,6 +99,19 @@ const (
 	PushSecretDeletionPolicyNone PushSecretDeletionPolicy = "None"
 )
 
+// PushSecretDriftPolicy defines how changes of the pushed values made in the provider are handled.
+// +kubebuilder:validation:Enum=Ignore;Report;Revert
+type PushSecretDriftPolicy string
+
+const (
+	// PushSecretDriftPolicyIgnore does not read the remote values back, they are replaced at the next push.
+	PushSecretDriftPolicyIgnore PushSecretDriftPolicy = "Ignore"
+	// PushSecretDriftPolicyReport reports the drift without pushing the values again.
+	PushSecretDriftPolicyReport PushSecretDriftPolicy = "Report"
+	// PushSecretDriftPolicyRevert pushes the values again as soon as a drift is detected.
+	PushSecretDriftPolicyRevert PushSecretDriftPolicy = "Revert"
+)
+
 // PushSecretConversionStrategy defines how secret values are converted when pushed to providers.
 // +kubebuilder:validation:Enum=None;ReverseUnicode
 type PushSecretConversionStrategy string
@@ -96,6 +132,8 @@ type PushSecretSpec struct {
 	SecretStoreRefs []PushSecretStoreRef `json:"secretStoreRefs"`
 
 	// UpdatePolicy to handle Secrets in the provider.
+	// IfUnchanged is best-effort on providers which do not implement conditional pushes:
+	// a value written between the comparison with the value last pushed and the push is overwritten.
 	// +kubebuilder:default="Replace"
 	// +optional
 	UpdatePolicy PushSecretUpdatePolicy `json:"updatePolicy,omitempty"`
@@ -105,6 +143,29 @@ type PushSecretSpec struct {
 	// +optional
 	DeletionPolicy PushSecretDeletionPolicy `json:"deletionPolicy,omitempty"`
 
+	// DeletionGracePeriod delays the deletion of the remote secrets with the Delete deletion policy, when the
+	// PushSecret, its source or one of its entries is removed. The pending deletions are recorded in
+	// status.pendingDeletions, and cancelled if the entries are pushed again before the end of the period.
+	// The finalizer of a deleted PushSecret is kept until its remote secrets are deleted.
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
+
+	// DriftPolicy defines what happens when a pushed value is changed in the provider outside of the controller.
+	// The remote values are read back at every refresh and compared with the values last pushed:
+	// - Revert: the values are pushed again
+	// - Report: the remote values are left untouched, and the drift is reported by the RemoteDrift condition
+	// - Ignore: the remote values are not read back
+	// Only the entries pushing a single key of the Secret with the Replace update policy are checked.
+	// Defaults to "Ignore".
+	// +optional
+	DriftPolicy PushSecretDriftPolicy `json:"driftPolicy,omitempty"`
+
+	// Atomic pushes to the secret stores as a transaction: the previous remote values are read before pushing,
+	// and when the push to a store fails, the previous values are restored in the stores already pushed to.
+	// The outcome of the push to each store is reported in status.stores.
+	// +optional
+	Atomic bool `json:"atomic,omitempty"`
+
 	// The Secret Selector (k8s source) for the Push Secret
 	Selector PushSecretSelector `json:"selector"`
 
@@ -147,6 +208,71 @@ type PushSecretSelector struct {
 	// Point to a generator to create a Secret.
 	// +optional
 	GeneratorRef *esv1.GeneratorRef `json:"generatorRef,omitempty"`
+
+	// Select a ConfigMap to Push. Its data and binaryData are pushed like the data of a Secret.
+	// +optional
+	ConfigMap *PushSecretConfigMap `json:"configMap,omitempty"`
+
+	// Select fields of a Kubernetes object to Push.
+	// +optional
+	ObjectRef *PushSecretObjectRef `json:"objectRef,omitempty"`
+}
+
+// PushSecretConfigMap defines a ConfigMap that will be used as a source for pushing to providers.
+type PushSecretConfigMap struct {
+	// Name of the ConfigMap.
+	// The ConfigMap must exist in the same namespace as the PushSecret manifest.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Selector chooses ConfigMaps using a labelSelector.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// PushSecretObjectRef defines a Kubernetes object whose fields are pushed to providers.
+// The controller must be allowed to get the object.
+type PushSecretObjectRef struct {
+	// APIVersion of the object, e.g. cert-manager.io/v1.
+	// +kubebuilder:validation:MinLength:=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object, e.g. Certificate.
+	// +kubebuilder:validation:MinLength:=1
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	// The object must exist in the same namespace as the PushSecret manifest.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	Name string `json:"name"`
+
+	// Fields extracted from the object, each into a key of the pushed data.
+	// +kubebuilder:validation:MinItems:=1
+	Fields []PushSecretObjectField `json:"fields"`
+}
+
+// PushSecretObjectField extracts a field of a Kubernetes object into a key of the pushed data.
+type PushSecretObjectField struct {
+	// Key of the field in the pushed data.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[-._a-zA-Z0-9]+$
+	Key string `json:"key"`
+
+	// JSONPath of the field in the object, in the kubectl syntax, e.g. {.status.notAfter}.
+	// Strings are extracted as-is, other values are encoded as JSON.
+	// +kubebuilder:validation:MinLength:=1
+	JSONPath string `json:"jsonPath"`
+
+	// Used to define a decoding Strategy for the extracted value, e.g. Base64 for the data of a Secret.
+	// Defaults to None when omitted.
+	// +optional
+	DecodingStrategy esv1.ExternalSecretDecodingStrategy `json:"decodingStrategy,omitempty"`
 }
 
 // PushSecretRemoteRef defines the location of the secret in the provider.
@@ -274,6 +400,10 @@ type PushSecretConditionType string
 const (
 	// PushSecretReady indicates the PushSecret resource is ready.
 	PushSecretReady PushSecretConditionType = "Ready"
+	// PushSecretRemoteDrift indicates that a pushed value was changed in the provider outside of the controller.
+	PushSecretRemoteDrift PushSecretConditionType = "RemoteDrift"
+	// PushSecretConflict indicates that a remote value was not updated because it was changed outside of the PushSecret.
+	PushSecretConflict PushSecretConditionType = "Conflict"
 )
 
 // PushSecretStatusCondition indicates the status of the PushSecret.
@@ -291,9 +421,63 @@ type PushSecretStatusCondition struct {
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
+// PushSecretPendingDeletion is a remote secret deleted at the end of the deletion grace period.
+type PushSecretPendingDeletion struct {
+	// Store is the secret store of the remote secret, as in status.syncedPushSecrets.
+	Store string `json:"store"`
+
+	// RemoteRef is the remote secret, as in status.syncedPushSecrets.
+	RemoteRef string `json:"remoteRef"`
+
+	// DeleteAfter is the time after which the remote secret is deleted.
+	DeleteAfter metav1.Time `json:"deleteAfter"`
+}
+
+// PushSecretStoreOutcome is the outcome of an atomic push to a secret store.
+// +kubebuilder:validation:Enum=Pushed;Failed;RolledBack;RollbackFailed;NotPushed
+type PushSecretStoreOutcome string
+
+const (
+	// PushSecretStorePushed indicates that the values were pushed to the store.
+	PushSecretStorePushed PushSecretStoreOutcome = "Pushed"
+	// PushSecretStoreFailed indicates that the push to the store failed, and the values pushed before the failure were restored.
+	PushSecretStoreFailed PushSecretStoreOutcome = "Failed"
+	// PushSecretStoreRolledBack indicates that the values were pushed, then restored because the push to another store failed.
+	PushSecretStoreRolledBack PushSecretStoreOutcome = "RolledBack"
+	// PushSecretStoreRollbackFailed indicates that the previous values could not be restored, so the store may hold the new values.
+	PushSecretStoreRollbackFailed PushSecretStoreOutcome = "RollbackFailed"
+	// PushSecretStoreNotPushed indicates that the values were not pushed because the push to another store failed first.
+	PushSecretStoreNotPushed PushSecretStoreOutcome = "NotPushed"
+)
+
+// PushSecretStoreStatus is the outcome of the last atomic push to a secret store.
+type PushSecretStoreStatus struct {
+	// Name of the secret store, as in status.syncedPushSecrets.
+	Name string `json:"name"`
+
+	// Outcome of the last push to the secret store.
+	Outcome PushSecretStoreOutcome `json:"outcome"`
+
+	// Message gives the error of a failed push or rollback.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PushSecretStatusData is a data entry stored to a secret store, with the state of its last push.
+type PushSecretStatusData struct {
+	PushSecretData `json:",inline"`
+	// PushedHash is the hash of the value last pushed to the provider, to detect remote drift.
+	// +optional
+	PushedHash string `json:"pushedHash,omitempty"`
+	// PushedVersion is the version of the value last pushed to the provider, if the provider reports it,
+	// to detect remote conflicts with the IfUnchanged update policy.
+	// +optional
+	PushedVersion string `json:"pushedVersion,omitempty"`
+}
+
 // SyncedPushSecretsMap is a map that tracks which PushSecretData was stored to which secret store.
 // The outer map's key is the secret store name, and the inner map's key is the remote key name.
-type SyncedPushSecretsMap map[string]map[string]PushSecretData
+type SyncedPushSecretsMap map[string]map[string]PushSecretStatusData
 
 // PushSecretStatus indicates the history of the status of PushSecret.
 type PushSecretStatus struct {
@@ -308,6 +492,12 @@ type PushSecretStatus struct {
 	// Matches secret stores to PushSecretData that was stored to that secret store.
 	// +optional
 	SyncedPushSecrets SyncedPushSecretsMap `json:"syncedPushSecrets,omitempty"`
+	// Stores reports the outcome of the last push to each secret store when spec.atomic is set.
+	// +optional
+	Stores []PushSecretStoreStatus `json:"stores,omitempty"`
+	// PendingDeletions are the remote secrets deleted at the end of the deletion grace period.
+	// +optional
+	PendingDeletions []PushSecretPendingDeletion `json:"pendingDeletions,omitempty"`
 	// +optional
 	Conditions []PushSecretStatusCondition `json:"conditions,omitempty"`
 }
@@ -218,6 +218,26 @@ func (in *PushSecret) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretConfigMap) DeepCopyInto(out *PushSecretConfigMap) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretConfigMap.
+func (in *PushSecretConfigMap) DeepCopy() *PushSecretConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecretData) DeepCopyInto(out *PushSecretData) {
 	*out = *in
@@ -368,6 +388,57 @@ func (in *PushSecretMetadata) DeepCopy() *PushSecretMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretObjectField) DeepCopyInto(out *PushSecretObjectField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretObjectField.
+func (in *PushSecretObjectField) DeepCopy() *PushSecretObjectField {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretObjectField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretObjectRef) DeepCopyInto(out *PushSecretObjectRef) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]PushSecretObjectField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretObjectRef.
+func (in *PushSecretObjectRef) DeepCopy() *PushSecretObjectRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretObjectRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretPendingDeletion) DeepCopyInto(out *PushSecretPendingDeletion) {
+	*out = *in
+	in.DeleteAfter.DeepCopyInto(&out.DeleteAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretPendingDeletion.
+func (in *PushSecretPendingDeletion) DeepCopy() *PushSecretPendingDeletion {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretPendingDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecretRemoteRef) DeepCopyInto(out *PushSecretRemoteRef) {
 	*out = *in
@@ -441,6 +512,16 @@ func (in *PushSecretSelector) DeepCopyInto(out *PushSecretSelector) {
 		*out = new(externalsecretsv1.GeneratorRef)
 		**out = **in
 	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(PushSecretConfigMap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectRef != nil {
+		in, out := &in.ObjectRef, &out.ObjectRef
+		*out = new(PushSecretObjectRef)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretSelector.
@@ -468,6 +549,11 @@ func (in *PushSecretSpec) DeepCopyInto(out *PushSecretSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeletionGracePeriod != nil {
+		in, out := &in.DeletionGracePeriod, &out.DeletionGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	in.Selector.DeepCopyInto(&out.Selector)
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
@@ -508,13 +594,13 @@ func (in *PushSecretStatus) DeepCopyInto(out *PushSecretStatus) {
 		in, out := &in.SyncedPushSecrets, &out.SyncedPushSecrets
 		*out = make(SyncedPushSecretsMap, len(*in))
 		for key, val := range *in {
-			var outVal map[string]PushSecretData
+			var outVal map[string]PushSecretStatusData
 			if val == nil {
 				(*out)[key] = nil
 			} else {
 				inVal := (*in)[key]
 				in, out := &inVal, &outVal
-				*out = make(map[string]PushSecretData, len(*in))
+				*out = make(map[string]PushSecretStatusData, len(*in))
 				for key, val := range *in {
 					(*out)[key] = *val.DeepCopy()
 				}
@@ -522,6 +608,18 @@ func (in *PushSecretStatus) DeepCopyInto(out *PushSecretStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Stores != nil {
+		in, out := &in.Stores, &out.Stores
+		*out = make([]PushSecretStoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingDeletions != nil {
+		in, out := &in.PendingDeletions, &out.PendingDeletions
+		*out = make([]PushSecretPendingDeletion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]PushSecretStatusCondition, len(*in))
@@ -541,6 +639,22 @@ func (in *PushSecretStatus) DeepCopy() *PushSecretStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretStatusData) DeepCopyInto(out *PushSecretStatusData) {
+	*out = *in
+	in.PushSecretData.DeepCopyInto(&out.PushSecretData)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretStatusData.
+func (in *PushSecretStatusData) DeepCopy() *PushSecretStatusData {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretStatusData)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecretStatusCondition) DeepCopyInto(out *PushSecretStatusCondition) {
 	*out = *in
@@ -577,19 +691,34 @@ func (in *PushSecretStoreRef) DeepCopy() *PushSecretStoreRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretStoreStatus) DeepCopyInto(out *PushSecretStoreStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretStoreStatus.
+func (in *PushSecretStoreStatus) DeepCopy() *PushSecretStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in SyncedPushSecretsMap) DeepCopyInto(out *SyncedPushSecretsMap) {
 	{
 		in := &in
 		*out = make(SyncedPushSecretsMap, len(*in))
 		for key, val := range *in {
-			var outVal map[string]PushSecretData
+			var outVal map[string]PushSecretStatusData
 			if val == nil {
 				(*out)[key] = nil
 			} else {
 				inVal := (*in)[key]
 				in, out := &inVal, &outVal
-				*out = make(map[string]PushSecretData, len(*in))
+				*out = make(map[string]PushSecretStatusData, len(*in))
 				for key, val := range *in {
 					(*out)[key] = *val.DeepCopy()
 				}
@@ -18,8 +18,11 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -55,3 +58,18 @@ type Generator interface {
 
 // GeneratorProviderState represents the state of a generator provider that can be stored and retrieved.
 type GeneratorProviderState *apiextensions.JSON
+
+// StateExpiry returns the expiry recorded as expiresAt, an RFC 3339 time, in the state of a generator.
+// Generators whose values expire record it, so the OnExpiry refresh policy of ExternalSecrets refreshes them in time.
+func StateExpiry(state GeneratorProviderState) (time.Time, bool) {
+	if state == nil || len(state.Raw) == 0 {
+		return time.Time{}, false
+	}
+	var expiry struct {
+		ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	}
+	if err := json.Unmarshal(state.Raw, &expiry); err != nil || expiry.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+	return expiry.ExpiresAt.Time, true
+}
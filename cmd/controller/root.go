@@ -17,6 +17,7 @@ limitations under the License.
 package controller
 
 import (
+	"bytes"
 	"crypto/tls"
 	"os"
 	"time"
@@ -47,6 +48,7 @@ import (
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/generatorstate"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/notification"
 	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret"
 	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret/psmetrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
@@ -108,6 +110,12 @@ var (
 	tlsMinVersion                         string
 	enableHTTP2                           bool
 	allowGenericTargets                   bool
+	notificationAddr                      string
+	notificationGenericSecretFile         string
+	notificationSNSTopicARNs              []string
+	notificationPubSubAudience            string
+	notificationPubSubServiceAccount      string
+	notificationEventGridTokenFile        string
 )
 
 const (
@@ -213,6 +221,29 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		// the notification receiver enqueues the ExternalSecrets and PushSecrets
+		// referencing the remote keys named by the change notifications of providers.
+		var notifications *notification.Receiver
+		if notificationAddr != "" {
+			notifications, err = newNotificationReceiver(mgr.GetClient(), mgr.Elected())
+			if err != nil {
+				setupLog.Error(err, "unable to create notification receiver")
+				os.Exit(1)
+			}
+			if err := mgr.Add(notifications); err != nil {
+				setupLog.Error(err, "unable to add notification receiver")
+				os.Exit(1)
+			}
+		}
+
+		// the credentials cache records the versions of the store credentials to rebuild provider clients after a rotation,
+		// so it is needed by every controller using provider clients, even when the store reconcilers are disabled.
+		credentials, err := secretstore.NewCredentialsCache(cmd.Context(), mgr, namespace)
+		if err != nil {
+			setupLog.Error(err, "unable to create store credentials cache")
+			os.Exit(1)
+		}
+
 		if enableSecretStoreReconciler {
 			ssmetrics.SetUpMetrics()
 			if err = (&secretstore.StoreReconciler{
@@ -222,7 +253,8 @@ var rootCmd = &cobra.Command{
 				ControllerClass:   controllerClass,
 				RequeueInterval:   storeRequeueInterval,
 				PushSecretEnabled: enablePushSecretReconciler,
-			}).SetupWithManager(mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
+				Credentials:       credentials,
+			}).SetupWithManager(cmd.Context(), mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
 				setupLog.Error(err, errCreateController, "controller", "SecretStore")
 				os.Exit(1)
 			}
@@ -237,7 +269,8 @@ var rootCmd = &cobra.Command{
 				ControllerClass:   controllerClass,
 				RequeueInterval:   storeRequeueInterval,
 				PushSecretEnabled: enablePushSecretReconciler,
-			}).SetupWithManager(mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
+				Credentials:       credentials,
+			}).SetupWithManager(cmd.Context(), mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
 				setupLog.Error(err, errCreateController, "controller", "ClusterSecretStore")
 				os.Exit(1)
 			}
@@ -264,6 +297,7 @@ var rootCmd = &cobra.Command{
 			EnableFloodGate:                    enableFloodGate,
 			EnableGeneratorState:               enableGeneratorState,
 			AllowGenericTargets:                allowGenericTargets,
+			Notifications:                      notifications.Watch(notification.KindExternalSecret),
 		}).SetupWithManager(cmd.Context(), mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
 			setupLog.Error(err, errCreateController, "controller", "ExternalSecret")
 			os.Exit(1)
@@ -277,6 +311,7 @@ var rootCmd = &cobra.Command{
 				ControllerClass: controllerClass,
 				RestConfig:      mgr.GetConfig(),
 				RequeueInterval: time.Hour,
+				Notifications:   notifications.Watch(notification.KindPushSecret),
 			}).SetupWithManager(cmd.Context(), mgr, ctrlcommon.BuildControllerOptions(concurrent)); err != nil {
 				setupLog.Error(err, errCreateController, "controller", "PushSecret")
 				os.Exit(1)
@@ -389,12 +424,44 @@ func init() {
 		"If set, HTTP/2 will be enabled for the metrics server")
 	rootCmd.Flags().
 		BoolVar(&allowGenericTargets, "unsafe-allow-generic-targets", false, "Enable support for creating generic resources (ConfigMaps, Custom Resources). WARNING: Using generic resources, please sure all policies are correctly configured.")
+	rootCmd.Flags().StringVar(&notificationAddr, "notification-addr", "", "The address the change notification receiver binds to. The receiver is disabled if empty.")
+	rootCmd.Flags().StringVar(&notificationGenericSecretFile, "notification-generic-secret-file", "", "File holding the shared secret of the HMAC signature of generic change notifications.")
+	rootCmd.Flags().StringSliceVar(&notificationSNSTopicARNs, "notification-sns-topic-arns", nil, "ARNs of the AWS SNS topics allowed to send change notifications.")
+	rootCmd.Flags().StringVar(&notificationPubSubAudience, "notification-pubsub-audience", "", "Audience of the OIDC tokens of the GCP Pub/Sub push subscriptions sending change notifications.")
+	rootCmd.Flags().StringVar(&notificationPubSubServiceAccount, "notification-pubsub-service-account", "", "Email of the service account of the GCP Pub/Sub push subscriptions sending change notifications.")
+	rootCmd.Flags().StringVar(&notificationEventGridTokenFile, "notification-eventgrid-token-file", "", "File holding the token passed by the Azure Event Grid subscriptions sending change notifications.")
 	fs := feature.Features()
 	for _, f := range fs {
 		rootCmd.Flags().AddFlagSet(f.Flags)
 	}
 }
 
+// newNotificationReceiver creates the change notification receiver from the notification flags.
+func newNotificationReceiver(cl client.Reader, elected <-chan struct{}) (*notification.Receiver, error) {
+	opts := notification.Options{
+		Addr:                 notificationAddr,
+		Elected:              elected,
+		SNSTopicARNs:         notificationSNSTopicARNs,
+		PubSubAudience:       notificationPubSubAudience,
+		PubSubServiceAccount: notificationPubSubServiceAccount,
+	}
+	if notificationGenericSecretFile != "" {
+		secret, err := os.ReadFile(notificationGenericSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.GenericSecret = bytes.TrimSpace(secret)
+	}
+	if notificationEventGridTokenFile != "" {
+		token, err := os.ReadFile(notificationEventGridTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.EventGridToken = string(bytes.TrimSpace(token))
+	}
+	return notification.NewReceiver(cl, ctrl.Log.WithName("notifications"), opts)
+}
+
 // disableHTTP2 is a TLS configuration function that disables HTTP/2.
 func disableHTTP2(cfg *tls.Config) {
 	cfg.NextProtos = []string{"http/1.1"}
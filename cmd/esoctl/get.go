@@ -0,0 +1,357 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+
+	// Loading registered providers and generators.
+	_ "github.com/external-secrets/external-secrets/pkg/register"
+)
+
+const defaultNamespace = "default"
+
+var (
+	getStoreFiles         []string
+	getObjectFiles        []string
+	getExternalSecretFile string
+	getKey                string
+	getProperty           string
+	getVersion            string
+	getExtract            bool
+	getFindName           string
+	getNamespace          string
+	getOutputFile         string
+)
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+	getCmd.Flags().StringArrayVar(&getStoreFiles, "store", nil, "Link to a file containing a SecretStore or ClusterSecretStore (can be repeated)")
+	getCmd.Flags().StringArrayVar(&getObjectFiles, "object", nil, "Link to a file containing a Kubernetes object referenced by the store or the template, e.g. a credentials Secret (can be repeated)")
+	getCmd.Flags().StringVar(&getExternalSecretFile, "external-secret", "", "Link to a file containing an ExternalSecret to render the resulting Secret for")
+	getCmd.Flags().StringVar(&getKey, "key", "", "Key of the secret in the provider")
+	getCmd.Flags().StringVar(&getProperty, "property", "", "Property of the secret to fetch")
+	getCmd.Flags().StringVar(&getVersion, "secret-version", "", "Version of the secret to fetch")
+	getCmd.Flags().BoolVar(&getExtract, "extract", false, "If set, fetch all key/value pairs of --key like dataFrom.extract")
+	getCmd.Flags().StringVar(&getFindName, "find-name", "", "If set, fetch all secrets whose name matches this regular expression like dataFrom.find")
+	getCmd.Flags().StringVar(&getNamespace, "namespace", "", "Namespace the secret is fetched from (default: namespace of the store or the ExternalSecret)")
+	getCmd.Flags().StringVar(&getOutputFile, "output", "", "If set, the output will be written to this file")
+	_ = getCmd.MarkFlagRequired("store")
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get",
+	Short: "fetch a secret through a SecretStore manifest",
+	Long: `Fetch a secret through a SecretStore manifest, without a cluster.
+The provider client is created from the store the same way the controller does.
+With --external-secret, the Secret the controller would create is rendered,
+including rewrites and templates.`,
+	RunE:         getRun,
+	SilenceUsage: true,
+}
+
+func getRun(_ *cobra.Command, _ []string) error {
+	if err := validateGetFlags(); err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(esv1.AddToScheme(scheme))
+	utilruntime.Must(genv1alpha1.AddToScheme(scheme))
+
+	stores := make([]esv1.GenericStore, 0, len(getStoreFiles))
+	for _, file := range getStoreFiles {
+		store, err := readStore(file)
+		if err != nil {
+			return err
+		}
+		stores = append(stores, store)
+	}
+
+	var es *esv1.ExternalSecret
+	if getExternalSecretFile != "" {
+		var err error
+		es, err = readExternalSecret(getExternalSecretFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	namespace := getNamespace
+	switch {
+	case namespace != "":
+	case es != nil && es.Namespace != "":
+		namespace = es.Namespace
+	case stores[0].GetNamespace() != "":
+		namespace = stores[0].GetNamespace()
+	default:
+		namespace = defaultNamespace
+	}
+
+	// ClusterSecretStore conditions are evaluated against the namespace labels
+	objects := []client.Object{&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   namespace,
+			Labels: map[string]string{"kubernetes.io/metadata.name": namespace},
+		},
+	}}
+	for _, store := range stores {
+		if store.GetKind() == esv1.SecretStoreKind && store.GetNamespace() == "" {
+			store.SetNamespace(namespace)
+		}
+		objects = append(objects, store)
+	}
+	for _, file := range getObjectFiles {
+		obj, err := readObject(file, namespace)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, obj)
+	}
+
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		Build()
+
+	var content []byte
+	var err error
+	if es != nil {
+		es.Namespace = namespace
+		content, err = renderExternalSecret(ctx, kube, scheme, es)
+	} else {
+		content, err = fetchFromStore(ctx, kube, stores, namespace)
+	}
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if getOutputFile != "" {
+		f, err := os.Create(filepath.Clean(getOutputFile))
+		if err != nil {
+			return fmt.Errorf("could not create output file: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		out = f
+	}
+
+	_, err = fmt.Fprintln(out, string(content))
+
+	return err
+}
+
+func renderExternalSecret(ctx context.Context, kube client.Client, scheme *runtime.Scheme, es *esv1.ExternalSecret) ([]byte, error) {
+	// the CRD defaults are not applied to local manifests
+	if es.Spec.Target.Template != nil && es.Spec.Target.Template.EngineVersion == "" {
+		es.Spec.Target.Template.EngineVersion = esv1.TemplateEngineV2
+	}
+
+	secret, err := externalsecret.RenderSecret(ctx, kube, scheme, logr.Discard(), es)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := yaml.Marshal(secret)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal secret: %w", err)
+	}
+
+	return content, nil
+}
+
+// validateGetFlags checks the flags selecting what is fetched: an ExternalSecret,
+// a key, all key/value pairs of a key, or the secrets matching a name.
+func validateGetFlags() error {
+	if len(getStoreFiles) == 0 {
+		return errors.New("at least one --store is required")
+	}
+	if getExternalSecretFile != "" {
+		if getKey != "" || getExtract || getFindName != "" || getProperty != "" || getVersion != "" {
+			return errors.New("--external-secret cannot be used with --key, --extract, --find-name, --property or --secret-version")
+		}
+		return nil
+	}
+	if len(getStoreFiles) != 1 {
+		return errors.New("exactly one --store is required without --external-secret")
+	}
+	switch {
+	case getKey != "" && getFindName != "":
+		return errors.New("--key and --find-name cannot be used together")
+	case getExtract && getFindName != "":
+		return errors.New("--extract and --find-name cannot be used together")
+	case getFindName != "" && (getProperty != "" || getVersion != ""):
+		return errors.New("--property and --secret-version cannot be used with --find-name")
+	case getExtract && getKey == "":
+		return errors.New("--extract requires --key")
+	case getKey == "" && getFindName == "":
+		return errors.New("one of --key or --find-name is required")
+	}
+	return nil
+}
+
+func fetchFromStore(ctx context.Context, kube client.Client, stores []esv1.GenericStore, namespace string) ([]byte, error) {
+	store := stores[0]
+	mgr := secretstore.NewManager(kube, store.GetSpec().Controller, false)
+	defer func() {
+		_ = mgr.Close(ctx)
+	}()
+
+	secretsClient, err := mgr.GetFromStore(ctx, store, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("could not create provider client: %w", err)
+	}
+
+	var secretMap map[string][]byte
+	switch {
+	case getFindName != "":
+		secretMap, err = secretsClient.GetAllSecrets(ctx, esv1.ExternalSecretFind{
+			Name: &esv1.FindName{RegExp: getFindName},
+		})
+	case getExtract:
+		secretMap, err = secretsClient.GetSecretMap(ctx, esv1.ExternalSecretDataRemoteRef{
+			Key:      getKey,
+			Property: getProperty,
+			Version:  getVersion,
+		})
+	default:
+		secret, err := secretsClient.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{
+			Key:      getKey,
+			Property: getProperty,
+			Version:  getVersion,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not get secret: %w", err)
+		}
+
+		return secret, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get secrets: %w", err)
+	}
+
+	// display the values as strings, like stringData
+	stringMap := make(map[string]string, len(secretMap))
+	for k, v := range secretMap {
+		stringMap[k] = string(v)
+	}
+	content, err := yaml.Marshal(stringMap)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal secrets: %w", err)
+	}
+
+	return content, nil
+}
+
+func readStore(file string) (esv1.GenericStore, error) {
+	obj, err := readUnstructured(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var store esv1.GenericStore
+	switch obj.GetKind() {
+	case esv1.SecretStoreKind:
+		store = &esv1.SecretStore{}
+	case esv1.ClusterSecretStoreKind:
+		store = &esv1.ClusterSecretStore{}
+	default:
+		return nil, fmt.Errorf("unsupported store kind %s", obj.GetKind())
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, store); err != nil {
+		return nil, fmt.Errorf("could not convert store: %w", err)
+	}
+
+	// local stores have no status, they are assumed to be ready
+	store.SetStatus(esv1.SecretStoreStatus{
+		Conditions: []esv1.SecretStoreStatusCondition{{
+			Type:   esv1.SecretStoreReady,
+			Status: corev1.ConditionTrue,
+		}},
+	})
+
+	return store, nil
+}
+
+func readExternalSecret(file string) (*esv1.ExternalSecret, error) {
+	obj, err := readUnstructured(file)
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetKind() != esv1.ExtSecretKind {
+		return nil, fmt.Errorf("unsupported kind %s, expected %s", obj.GetKind(), esv1.ExtSecretKind)
+	}
+
+	es := &esv1.ExternalSecret{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, es); err != nil {
+		return nil, fmt.Errorf("could not convert external secret: %w", err)
+	}
+
+	return es, nil
+}
+
+func readObject(file, namespace string) (client.Object, error) {
+	obj, err := readUnstructured(file)
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+
+	return obj, nil
+}
+
+func readUnstructured(file string) (*unstructured.Unstructured, error) {
+	content, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("could not read file %s: %w", file, err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(content, obj); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s: %w", file, err)
+	}
+
+	return obj, nil
+}
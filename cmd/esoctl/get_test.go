@@ -0,0 +1,284 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/providers/v1/fake"
+)
+
+const (
+	testStore = `apiVersion: external-secrets.io/v1
+kind: SecretStore
+metadata:
+  name: fake
+spec:
+  provider:
+    fake:
+      data:
+      - key: db-password
+        value: s3cr3t
+      - key: db-config
+        value: '{"user":"admin","host":"db"}'
+      - key: api-token
+        value: t0k3n
+`
+	testClusterStore = `apiVersion: external-secrets.io/v1
+kind: ClusterSecretStore
+metadata:
+  name: fake
+spec:
+  provider:
+    fake:
+      data:
+      - key: db-password
+        value: s3cr3t
+`
+	testExternalSecret = `apiVersion: external-secrets.io/v1
+kind: ExternalSecret
+metadata:
+  name: app
+  namespace: apps
+spec:
+  secretStoreRef:
+    name: fake
+    kind: SecretStore
+  target:
+    name: app
+    template:
+      templateFrom:
+      - target: Data
+        configMap:
+          name: app-template
+          items:
+          - key: url
+            templateAs: Values
+  data:
+  - secretKey: password
+    remoteRef:
+      key: db-password
+`
+	testTemplateConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-template
+data:
+  url: 'postgres://admin:{{ .password }}@db'
+`
+)
+
+func init() {
+	// the fake provider is only registered with the fake build tag
+	esv1.ForceRegister(fake.NewProvider(), fake.ProviderSpec(), fake.MaintenanceStatus())
+}
+
+// resetGetFlags resets the flags of the get command, which are kept in package variables.
+func resetGetFlags(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		getStoreFiles = nil
+		getObjectFiles = nil
+		getExternalSecretFile = ""
+		getKey = ""
+		getProperty = ""
+		getVersion = ""
+		getExtract = false
+		getFindName = ""
+		getNamespace = ""
+		getOutputFile = ""
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func writeManifest(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
+
+func TestValidateGetFlags(t *testing.T) {
+	tests := map[string]struct {
+		set     func()
+		wantErr string
+	}{
+		"key": {
+			set: func() { getKey = "db-password" },
+		},
+		"extract": {
+			set: func() { getKey = "db-config"; getExtract = true },
+		},
+		"find name": {
+			set: func() { getFindName = "db-.*" },
+		},
+		"external secret": {
+			set: func() { getExternalSecretFile = "es.yaml"; getStoreFiles = append(getStoreFiles, "other.yaml") },
+		},
+		"no store": {
+			set:     func() { getStoreFiles = nil; getKey = "db-password" },
+			wantErr: "at least one --store is required",
+		},
+		"several stores without external secret": {
+			set:     func() { getStoreFiles = append(getStoreFiles, "other.yaml"); getKey = "db-password" },
+			wantErr: "exactly one --store is required without --external-secret",
+		},
+		"nothing selected": {
+			set:     func() {},
+			wantErr: "one of --key or --find-name is required",
+		},
+		"key and find name": {
+			set:     func() { getKey = "db-password"; getFindName = "db-.*" },
+			wantErr: "--key and --find-name cannot be used together",
+		},
+		"extract and find name": {
+			set:     func() { getExtract = true; getFindName = "db-.*" },
+			wantErr: "--extract and --find-name cannot be used together",
+		},
+		"extract without key": {
+			set:     func() { getExtract = true },
+			wantErr: "--extract requires --key",
+		},
+		"find name with property": {
+			set:     func() { getFindName = "db-.*"; getProperty = "user" },
+			wantErr: "--property and --secret-version cannot be used with --find-name",
+		},
+		"external secret with key": {
+			set:     func() { getExternalSecretFile = "es.yaml"; getKey = "db-password" },
+			wantErr: "--external-secret cannot be used with",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resetGetFlags(t)
+			getStoreFiles = []string{"store.yaml"}
+			tc.set()
+
+			err := validateGetFlags()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestGetRun(t *testing.T) {
+	tests := map[string]struct {
+		store   string
+		set     func(t *testing.T)
+		want    string
+		wantErr string
+	}{
+		"key": {
+			store: testStore,
+			set:   func(*testing.T) { getKey = "db-password" },
+			want:  "s3cr3t\n",
+		},
+		"property": {
+			store: testStore,
+			set:   func(*testing.T) { getKey = "db-config"; getProperty = "user" },
+			want:  "admin\n",
+		},
+		"extract": {
+			store: testStore,
+			set:   func(*testing.T) { getKey = "db-config"; getExtract = true },
+			want:  "host: db\nuser: admin\n\n",
+		},
+		"find name": {
+			store: testStore,
+			set:   func(*testing.T) { getFindName = "^db-password$" },
+			want:  "db-password: s3cr3t\n\n",
+		},
+		"cluster store": {
+			store: testClusterStore,
+			set:   func(*testing.T) { getKey = "db-password" },
+			want:  "s3cr3t\n",
+		},
+		"missing key": {
+			store:   testStore,
+			set:     func(*testing.T) { getKey = "missing" },
+			wantErr: "could not get secret",
+		},
+		"external secret with template object": {
+			store: testStore,
+			set: func(t *testing.T) {
+				getExternalSecretFile = writeManifest(t, "es.yaml", testExternalSecret)
+				getObjectFiles = []string{writeManifest(t, "cm.yaml", testTemplateConfigMap)}
+			},
+			want: "url: cG9zdGdyZXM6Ly9hZG1pbjpzM2NyM3RAZGI=",
+		},
+		"missing object": {
+			store: testStore,
+			set: func(t *testing.T) {
+				getKey = "db-password"
+				getObjectFiles = []string{filepath.Join(t.TempDir(), "missing.yaml")}
+			},
+			wantErr: "could not read file",
+		},
+		"unsupported store kind": {
+			store:   testTemplateConfigMap,
+			set:     func(*testing.T) { getKey = "db-password" },
+			wantErr: "unsupported store kind ConfigMap",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resetGetFlags(t)
+			getStoreFiles = []string{writeManifest(t, "store.yaml", tc.store)}
+			getOutputFile = filepath.Join(t.TempDir(), "out")
+			tc.set(t)
+
+			err := getRun(nil, nil)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			out, err := os.ReadFile(getOutputFile)
+			require.NoError(t, err)
+			assert.Contains(t, string(out), tc.want)
+		})
+	}
+}
+
+func TestGetRunStdout(t *testing.T) {
+	resetGetFlags(t)
+	getStoreFiles = []string{writeManifest(t, "store.yaml", testStore)}
+	getKey = "api-token"
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	t.Cleanup(func() { os.Stdout = stdout })
+
+	require.NoError(t, getRun(nil, nil))
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "t0k3n\n", string(out))
+}
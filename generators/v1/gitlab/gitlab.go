@@ -31,6 +31,7 @@ import (
 	"time"
 
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
@@ -60,11 +61,13 @@ type Generator struct {
 // deployTokenState is persisted as the generator state so that Cleanup can revoke
 // the deploy token that Generate created. GitLab deploy tokens are persistent, so
 // without revoking them every refresh would leave a dangling token behind.
+// ExpiresAt is read by the OnExpiry refresh policy of ExternalSecrets.
 type deployTokenState struct {
-	URL       string `json:"url"`
-	ProjectID string `json:"projectID,omitempty"`
-	GroupID   string `json:"groupID,omitempty"`
-	TokenID   int    `json:"tokenID"`
+	URL       string       `json:"url"`
+	ProjectID string       `json:"projectID,omitempty"`
+	GroupID   string       `json:"groupID,omitempty"`
+	TokenID   int          `json:"tokenID"`
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // createTokenResponse mirrors the fields returned by the GitLab deploy token API.
@@ -155,6 +158,7 @@ func (g *Generator) generate(ctx context.Context, jsonSpec *apiextensions.JSON,
 		ProjectID: spec.Spec.ProjectID,
 		GroupID:   spec.Spec.GroupID,
 		TokenID:   parsed.ID,
+		ExpiresAt: spec.Spec.ExpiresAt,
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("error marshaling state: %w", err)
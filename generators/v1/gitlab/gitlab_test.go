@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -168,6 +169,24 @@ func TestGenerate(t *testing.T) {
 		assert.Equal(t, "42", st.GroupID)
 	})
 
+	t.Run("expiring deploy token", func(t *testing.T) {
+		sink := &captured{}
+		srv := newServer(t, http.StatusCreated, createResp, sink)
+		defer srv.Close()
+
+		spec := specJSON(t, srv.URL, "group/project", "")
+		spec.Raw = append(spec.Raw, "  expiresAt: \"2030-01-01T00:00:00Z\"\n"...)
+		g := &Generator{httpClient: srv.Client()}
+		_, state, err := g.generate(context.Background(), spec, newKube(), testNamespace)
+		require.NoError(t, err)
+		assert.Equal(t, "2030-01-01T00:00:00Z", sink.body["expires_at"])
+
+		// the expiry is recorded in the state for the OnExpiry refresh policy
+		expiry, ok := genv1alpha1.StateExpiry(state)
+		require.True(t, ok)
+		assert.True(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Equal(expiry))
+	})
+
 	t.Run("error when both project and group set", func(t *testing.T) {
 		g := &Generator{}
 		_, _, err := g.generate(context.Background(), specJSON(t, "https://gitlab.com", "1", "2"), newKube(), testNamespace)
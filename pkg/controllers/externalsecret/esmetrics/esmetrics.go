@@ -39,6 +39,8 @@ const (
 	ExternalSecretStatusConditionKey = "status_condition"
 	// ExternalSecretReconcileDurationKey is the metric key for the external secret reconcile duration.
 	ExternalSecretReconcileDurationKey = "reconcile_duration"
+	// DriftDetectedKey is the metric key for the modifications of target Secrets made outside of the controller.
+	DriftDetectedKey = "drift_detected_total"
 )
 
 var counterVecMetrics = map[string]*prometheus.CounterVec{}
@@ -73,11 +75,18 @@ func SetUpMetrics() {
 		Help:      "The duration time to reconcile the External Secret",
 	}, ctrlmetrics.NonConditionMetricLabelNames)
 
-	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition, externalSecretReconcileDuration)
+	driftDetected := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      DriftDetectedKey,
+		Help:      "Total number of the modifications of target Secrets made outside of the controller",
+	}, ctrlmetrics.NonConditionMetricLabelNames)
+
+	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition, externalSecretReconcileDuration, driftDetected)
 
 	counterVecMetrics = map[string]*prometheus.CounterVec{
 		SyncCallsKey:      syncCallsTotal,
 		SyncCallsErrorKey: syncCallsError,
+		DriftDetectedKey:  driftDetected,
 	}
 
 	gaugeVecMetrics = map[string]*prometheus.GaugeVec{
@@ -110,9 +119,13 @@ func UpdateExternalSecretCondition(es *esv1.ExternalSecret, condition *esv1.Exte
 
 		baseLabels["status"] = string(v1.ConditionTrue)
 		externalSecretCondition.DeletePartialMatch(baseLabels)
-		delete(baseLabels, "condition")
 		delete(baseLabels, "status")
 
+		// Remove condition=Drifted metrics as well.
+		baseLabels["condition"] = string(esv1.ExternalSecretDrifted)
+		externalSecretCondition.DeletePartialMatch(baseLabels)
+		delete(baseLabels, "condition")
+
 	case esv1.ExternalSecretReady:
 		// Remove condition=Deleted metrics when the object gets ready.
 		baseLabels["condition"] = string(esv1.ExternalSecretDeleted)
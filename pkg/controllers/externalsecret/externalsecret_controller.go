@@ -52,11 +52,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	// Metrics.
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/notification"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
 	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
 	"github.com/external-secrets/external-secrets/runtime/esutils"
 	"github.com/external-secrets/external-secrets/runtime/esutils/resolvers"
@@ -82,6 +85,16 @@ const (
 	// condition messages for "SecretMissing" reason.
 	msgMissing = "secret will not be created due to CreationPolicy=Merge"
 
+	// condition messages for "SecretDryRun" reason.
+	msgDryRun = "secret not written due to target.dryRun, see status.dryRun"
+
+	// condition messages for "SecretRolledBack" reason.
+	msgRolledBack = "secret pinned to revision %d due to target.rollbackTo"
+
+	// condition messages for the "Drifted" condition.
+	msgDrifted = "data of the secret was modified outside of the controller"
+	msgNoDrift = "secret matches the data written by the controller"
+
 	// condition messages for "SecretSyncedError" reason.
 	msgErrorGetSecretData   = "could not get secret data from provider"
 	msgErrorDeleteSecret    = "could not delete secret"
@@ -90,6 +103,14 @@ const (
 	msgErrorUpdateImmutable = "could not update secret, target is immutable"
 	msgErrorBecomeOwner     = "failed to take ownership of target secret"
 	msgErrorIsOwned         = "target is owned by another ExternalSecret"
+	msgErrorDryRun          = "could not compute the dry run of the secret"
+	msgErrorRollback        = "could not roll back secret"
+	msgErrorHistory         = "could not record secret history"
+	msgErrorRotation        = "could not keep the previous values of rotated keys"
+	msgErrorVersioning      = "could not update the generations of the secret"
+	msgErrorRolloutRestart  = "could not restart workloads"
+	msgErrorSplitTargets    = "could not update the secrets of spec.targets"
+	msgErrorValidation      = "secret did not pass target.validation"
 
 	// log messages.
 	logErrorGetES                = "unable to get ExternalSecret"
@@ -107,27 +128,51 @@ const (
 	logSecretDataChanged     = "secret data keys changed"
 
 	// error formats.
-	errConvert               = "error applying conversion strategy %s to keys: %w"
-	errRewrite               = "error applying rewrite to keys: %w"
-	errDecode                = "error applying decoding strategy %s to data: %w"
-	errGenerate              = "error using generator: %w"
-	errInvalidKeys           = "invalid secret keys (TIP: use rewrite or conversionStrategy to change keys): %w"
-	errFetchTplFrom          = "error fetching templateFrom data: %w"
-	errApplyTemplate         = "could not apply template: %w"
-	errExecTpl               = "could not execute template: %w"
-	errMutate                = "unable to mutate secret %s: %w"
-	errUpdate                = "unable to update secret %s: %w"
-	errUpdateNotFound        = "unable to update secret %s: not found"
-	errDeleteCreatePolicy    = "unable to delete secret %s: creationPolicy=%s is not Owner"
-	errSecretCachesNotSynced = "controller caches for secret %s are not in sync"
+	errConvert                = "error applying conversion strategy %s to keys: %w"
+	errRewrite                = "error applying rewrite to keys: %w"
+	errDecode                 = "error applying decoding strategy %s to data: %w"
+	errGenerate               = "error using generator: %w"
+	errInvalidKeys            = "invalid secret keys (TIP: use rewrite or conversionStrategy to change keys): %w"
+	errFetchTplFrom           = "error fetching templateFrom data: %w"
+	errApplyTemplate          = "could not apply template: %w"
+	errExecTpl                = "could not execute template: %w"
+	errMutate                 = "unable to mutate secret %s: %w"
+	errUpdate                 = "unable to update secret %s: %w"
+	errUpdateNotFound         = "unable to update secret %s: not found"
+	errDeleteCreatePolicy     = "unable to delete secret %s: creationPolicy=%s is not Owner"
+	errSecretCachesNotSynced  = "controller caches for secret %s are not in sync"
+	errGetSnapshot            = "unable to get snapshot %s: %w"
+	errCreateSnapshot         = "unable to create snapshot %s: %w"
+	errDeleteSnapshot         = "unable to delete snapshot %s: %w"
+	errSnapshotNotOwned       = "unable to replace snapshot %s: it is not a snapshot of this ExternalSecret"
+	errGetRotationSecret      = "unable to get companion secret %s: %w"
+	errUpdateRotationSecret   = "unable to update companion secret %s: %w"
+	errDeleteRotationSecret   = "unable to delete companion secret %s: %w"
+	errRotationSecretNotOwned = "unable to update companion secret %s: it is not a companion secret of this ExternalSecret"
+	errListWorkloads          = "unable to list %s workloads: %w"
+	errRestartWorkload        = "unable to restart %s: %w"
+	errGetWorkload            = "unable to get %s: %w"
+	errUpdateWorkload         = "unable to update the secret references of %s: %w"
+	errGetGeneration          = "unable to get secret generation %s: %w"
+	errDeleteGeneration       = "unable to delete secret generation %s: %w"
+	errGenerationNotOwned     = "unable to write secret generation %s: it is not a generation of this ExternalSecret"
+	errSplitTarget            = "unable to write the secret of spec.targets[%d]: %w"
+	errSplitTargetRegexp      = "invalid selector regexp: %w"
 
 	// event messages.
 	eventCreated                  = "secret created"
 	eventUpdated                  = "secret updated"
 	eventDeleted                  = "secret deleted due to DeletionPolicy=Delete"
 	eventDeletedOrphaned          = "secret deleted because it was orphaned"
+	eventRolledBack               = "secret rolled back to revision %d"
+	eventRolloutRestarted         = "restarted %s"
+	eventWorkloadNotFound         = "workload %s not found, it was not restarted"
+	eventWorkloadsUpdated         = "pointed %s to secret %s"
+	eventWorkloadNotUpdated       = "workload %s not found, its secret references were not updated"
 	eventMissingProviderSecret    = "secret does not exist at provider using spec.dataFrom[%d]"
 	eventMissingProviderSecretKey = "secret does not exist at provider using spec.dataFrom[%d] (key=%s)"
+	eventDriftReverted            = "%s, reverting it"
+	eventDriftReported            = "%s, not reverting it due to DriftPolicy=Report"
 
 	// cacheSyncRetryDelay is used when partial and full secret caches are temporarily out of sync.
 	cacheSyncRetryDelay = 200 * time.Millisecond
@@ -135,10 +180,11 @@ const (
 
 // these errors are explicitly defined so we can detect them with `errors.Is()`.
 var (
-	ErrSecretImmutable     = fmt.Errorf("secret is immutable")
-	ErrSecretIsOwned       = fmt.Errorf("secret is owned by another ExternalSecret")
-	ErrSecretSetCtrlRef    = fmt.Errorf("could not set controller reference on secret")
-	ErrSecretRemoveCtrlRef = fmt.Errorf("could not remove controller reference on secret")
+	ErrSecretImmutable        = fmt.Errorf("secret is immutable")
+	ErrSecretIsOwned          = fmt.Errorf("secret is owned by another ExternalSecret")
+	ErrSecretSetCtrlRef       = fmt.Errorf("could not set controller reference on secret")
+	ErrSecretRemoveCtrlRef    = fmt.Errorf("could not remove controller reference on secret")
+	ErrSecretValidationFailed = fmt.Errorf("secret failed validation")
 )
 
 const (
@@ -163,6 +209,9 @@ type Reconciler struct {
 	AllowGenericTargets                bool
 	recorder                           record.EventRecorder
 
+	// Notifications enqueues the ExternalSecrets referencing remote keys changed in the provider
+	Notifications <-chan event.GenericEvent
+
 	// informerManager manages dynamic informers for generic targets
 	informerManager InformerManager
 }
@@ -183,6 +232,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		esmetrics.GetGaugeVec(esmetrics.ExternalSecretReconcileDurationKey).With(resourceLabels).Set(float64(time.Since(start)))
 		esmetrics.GetCounterVec(esmetrics.SyncCallsKey).With(resourceLabels).Inc()
 	}()
+	defer func() { result, err = secretstore.RequeueRateLimited(result, err) }()
 
 	externalSecret := &esv1.ExternalSecret{}
 	err = r.Get(ctx, req.NamespacedName, externalSecret)
@@ -298,67 +348,44 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		secretName = externalSecret.Name
 	}
 
-	// fetch the existing secret (from the partial cache)
-	//  - please note that the ~partial cache~ is different from the ~full cache~
-	//    so there can be race conditions between the two caches
-	//  - the WatchesMetadata(v1.Secret{}) in SetupWithManager() is using the partial cache
-	//    so we might receive a reconcile request before the full cache is updated
-	//  - furthermore, when `--enable-managed-secrets-caching` is true, the full cache
-	//    will ONLY include secrets with the "managed" label, so we cant use the full cache
-	//    to reliably determine if a secret exists or not
-	secretPartial := &metav1.PartialObjectMetadata{}
-	secretPartial.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("Secret"))
-	err = r.Get(ctx, client.ObjectKey{Name: secretName, Namespace: externalSecret.Namespace}, secretPartial)
-	if err != nil && !apierrors.IsNotFound(err) {
-		log.Error(err, logErrorGetSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
-		syncCallsError.With(resourceLabels).Inc()
-		return ctrl.Result{}, err
-	}
+	// with target.versioning, the target secret is its current generation
+	targetName := secretName
+	secretName = currentSecretGeneration(externalSecret, targetName)
 
-	// if the secret exists but does not have the "managed" label, add the label
-	// using a PATCH so it is visible in the cache, then requeue immediately
-	if secretPartial.UID != "" && secretPartial.Labels[esv1.LabelManaged] != esv1.LabelManagedValue {
-		fqdn := fqdnFor(externalSecret.Name)
-		patch := client.MergeFrom(secretPartial.DeepCopy())
-		if secretPartial.Labels == nil {
-			secretPartial.Labels = make(map[string]string)
-		}
-		secretPartial.Labels[esv1.LabelManaged] = esv1.LabelManagedValue
-		err = r.Patch(ctx, secretPartial, patch, client.FieldOwner(fqdn))
-		if err != nil {
-			log.Error(err, logErrorPatchSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
-			syncCallsError.With(resourceLabels).Inc()
-			return ctrl.Result{}, err
-		}
-		return ctrl.Result{Requeue: true}, nil
+	// in dry-run mode the target secret is only read, so this uses a separate reconciliation path
+	if externalSecret.Spec.Target.DryRun {
+		currentStatus := *externalSecret.Status.DeepCopy()
+		defer func() {
+			if equality.Semantic.DeepEqual(currentStatus, externalSecret.Status) {
+				return
+			}
+
+			updateErr := r.Status().Update(ctx, externalSecret)
+			if updateErr != nil && !apierrors.IsConflict(updateErr) {
+				log.Error(updateErr, logErrorUpdateESStatus)
+			}
+		}()
+
+		return r.reconcileDryRun(ctx, externalSecret, log, targetName, secretName, start, resourceLabels, syncCallsError)
 	}
 
-	// fetch existing secret (from the full cache)
-	// NOTE: we are using the `r.SecretClient` which we only use for managed secrets.
-	//       when `enableManagedSecretsCache` is true, this is a cached client that only sees our managed secrets,
-	//       otherwise it will be the normal controller-runtime client which may be cached or make direct API calls,
-	//       depending on if `enabledSecretCache` is true or false.
-	existingSecret := &v1.Secret{}
-	err = r.SecretClient.Get(ctx, client.ObjectKey{Name: secretName, Namespace: externalSecret.Namespace}, existingSecret)
-	if err != nil && !apierrors.IsNotFound(err) {
-		log.Error(err, logErrorGetSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+	existingSecret, requeue, err := r.getExistingSecret(ctx, log, externalSecret, secretName)
+	if err != nil {
 		syncCallsError.With(resourceLabels).Inc()
 		return ctrl.Result{}, err
 	}
+	if requeue != nil {
+		return *requeue, nil
+	}
 
-	// ensure the full cache is up-to-date
-	// NOTE: this prevents race conditions between the partial and full cache.
-	//       if enabled, we verify against the API server before retrying to avoid unnecessary error backoff
-	//       when the cache is temporarily stale.
-	existingSecret, cacheNotSynced, getErr := r.resolveSecretCacheMismatch(ctx, client.ObjectKey{Name: secretName, Namespace: externalSecret.Namespace}, secretPartial, existingSecret)
-	if getErr != nil && !apierrors.IsNotFound(getErr) {
-		log.Error(getErr, logErrorGetSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+	// fetch the existing secrets of spec.targets, the same way
+	splitSecrets, requeue, err := r.getSplitTargetSecrets(ctx, log, externalSecret)
+	if err != nil {
 		syncCallsError.With(resourceLabels).Inc()
-		return ctrl.Result{}, getErr
+		return ctrl.Result{}, err
 	}
-	if cacheNotSynced {
-		log.V(1).Info(logErrorSecretCacheNotSynced, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
-		return ctrl.Result{RequeueAfter: cacheSyncRetryDelay}, nil
+	if requeue != nil {
+		return *requeue, nil
 	}
 
 	// refresh will be skipped if ALL the following conditions are met:
@@ -368,8 +395,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	// 4. the target secret is valid:
 	//     - it exists
 	//     - it has the correct "managed" label
-	//     - it has the correct "data-hash" annotation
-	if !shouldRefresh(externalSecret) && isSecretValid(existingSecret, externalSecret) {
+	//     - it has the correct "data-hash" annotation, unless the drift policy does not revert it right away
+	// 5. no change notification is pending for the ExternalSecret
+	// 6. no previous value kept by target.rotation has expired
+	// 7. the secrets of spec.targets are valid, like the target secret
+	notified := notification.Pending(notification.KindExternalSecret, req.NamespacedName)
+	drift := detectDrift(existingSecret, externalSecret)
+	if !shouldRefreshOnNotification(externalSecret, notified) && isSecretInSync(existingSecret, externalSecret, drift) &&
+		!rotationExpired(externalSecret, time.Now()) && splitTargetsInSync(externalSecret, splitSecrets) {
 		log.V(1).Info("skipping refresh")
 		return r.getRequeueResult(externalSecret), nil
 	}
@@ -413,15 +446,37 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		}
 	}()
 
-	// retrieve the provider secret data.
-	dataMap, err := r.GetProviderSecretData(ctx, externalSecret)
+	// a drifted secret is left untouched while the drift is only reported.
+	// it is checked again at the next refresh, or when the secret or the ExternalSecret changes.
+	if r.handleDrift(externalSecret, drift, resourceLabels) {
+		log.V(1).Info("skipping refresh of drifted secret due to DriftPolicy=Report")
+		return r.getDriftReportRequeueResult(externalSecret), nil
+	}
+
+	// the summary of a previous dry run is obsolete once the secret is written
+	externalSecret.Status.DryRun = nil
+	externalSecret.Status.DryRunTargets = nil
+
+	// the restored revision is forgotten once the rollback is removed
+	if externalSecret.Spec.Target.RollbackTo == nil {
+		externalSecret.Status.RolledBackTo = nil
+	}
+
+	// while a rollback is pinned, the data comes from a snapshot instead of the provider.
+	data, err := r.getTargetData(ctx, externalSecret)
 	if err != nil {
-		r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
-		return ctrl.Result{}, err
+		return r.handleTargetDataError(externalSecret, err, syncCallsError.With(resourceLabels))
+	}
+	dataMap, snapshot := data.dataMap, data.snapshot
+
+	// the secrets of spec.targets are rendered before anything is written, so that an invalid one leaves every secret untouched.
+	// they are written from the same data once the secret of spec.target was written.
+	if _, err := r.desiredSplitSecrets(ctx, externalSecret, splitSecrets, data); err != nil {
+		return r.handleSplitTargetsError(log, externalSecret, err, syncCallsError.With(resourceLabels))
 	}
 
 	// if no data was found we can delete the secret if needed.
-	if len(dataMap) == 0 {
+	if snapshot == nil && len(dataMap) == 0 {
 		switch externalSecret.Spec.Target.DeletionPolicy {
 		// delete secret and return early.
 		case esv1.DeletionPolicyDelete:
@@ -445,11 +500,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 				log.V(1).Info(logSecretDeleted, "secret", secretName, "namespace", externalSecret.Namespace, "reason", "DeletionPolicy=Delete and provider returned no data")
 				r.recorder.Event(externalSecret, v1.EventTypeNormal, esv1.ReasonDeleted, eventDeleted)
 			}
+			if result, err := r.writeSplitTargets(ctx, log, externalSecret, secretName, data, splitSecrets, syncCallsError.With(resourceLabels)); result != nil {
+				return *result, err
+			}
 
 			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretDeleted, msgDeleted)
 			return r.getRequeueResult(externalSecret), nil
 		// In case provider secrets don't exist the kubernetes secret will be kept as-is.
 		case esv1.DeletionPolicyRetain:
+			if result, err := r.writeSplitTargets(ctx, log, externalSecret, secretName, data, splitSecrets, syncCallsError.With(resourceLabels)); result != nil {
+				return *result, err
+			}
 			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSyncedRetain)
 			return r.getRequeueResult(externalSecret), nil
 		// noop, handled below
@@ -457,55 +518,39 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		}
 	}
 
-	// mutationFunc is a function which can be applied to a secret to make it match the desired state.
-	mutationFunc := func(secret *v1.Secret) error {
-		// initialize maps within the secret so it's safe to set values
-		if secret.Annotations == nil {
-			secret.Annotations = make(map[string]string)
-		}
-		if secret.Labels == nil {
-			secret.Labels = make(map[string]string)
-		}
-		if secret.Data == nil {
-			secret.Data = make(map[string][]byte)
-		}
+	// keep the previous value of the keys which changed, during the overlap period of target.rotation
+	keptValues, err := r.keptRotationValues(ctx, externalSecret, existingSecret)
+	if err != nil {
+		r.markAsFailed(msgErrorRotation, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, err
+	}
+	var rotatedKeys []esv1.ExternalSecretRotatedKey
+	var previousValues map[string][]byte
+	mutationFunc := r.targetMutationFunc(ctx, externalSecret, existingSecret, data, keptValues, start,
+		func(rotated []esv1.ExternalSecretRotatedKey, values map[string][]byte) {
+			rotatedKeys, previousValues = rotated, values
+		})
 
-		// set the immutable flag on the secret if requested by the ExternalSecret
-		if externalSecret.Spec.Target.Immutable {
-			secret.Immutable = new(true)
+	// the secret is rendered before anything is written, so that the previous values of target.rotation
+	// are written to the companion Secret before the secret drops them
+	if externalSecret.Spec.Target.CreationPolicy != esv1.CreatePolicyNone {
+		if _, err := desiredSecret(externalSecret, existingSecret, targetName, data.isEmpty(), mutationFunc); err != nil {
+			return r.handleSecretWriteError(log, externalSecret, err, syncCallsError.With(resourceLabels))
 		}
-
-		// only apply the template if the secret is mutable or if the secret is new (has no UID)
-		// otherwise we would mutate an object that is immutable and already exists
-		objectDoesNotExistOrCanBeMutated := secret.GetUID() == "" || !externalSecret.Spec.Target.Immutable
-
-		if objectDoesNotExistOrCanBeMutated {
-			// get the list of keys that are managed by this ExternalSecret
-			keys, err := getManagedDataKeys(secret, externalSecret.Name)
-			if err != nil {
-				return err
-			}
-			// remove any data keys that are managed by this ExternalSecret, so we can re-add them
-			// this ensures keys added by templates are not left behind when they are removed from the template
-			for _, key := range keys {
-				delete(secret.Data, key)
-			}
-
-			// WARNING: this will remove any labels or annotations managed by this ExternalSecret
-			//          so any updates to labels and annotations should be done AFTER this point
-			err = r.ApplyTemplate(ctx, externalSecret, secret, dataMap)
-			if err != nil {
-				return fmt.Errorf(errApplyTemplate, err)
-			}
+		if err := r.syncRotationSecret(ctx, log, externalSecret, previousValues); err != nil {
+			r.markAsFailed(msgErrorRotation, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, err
 		}
+	}
 
-		if err := r.applyOwnership(externalSecret, secret); err != nil {
+	// keep the secret as it was written, to take a snapshot of it and restart workloads afterwards
+	var writtenSecret *v1.Secret
+	renderFunc := mutationFunc
+	mutationFunc = func(secret *v1.Secret) error {
+		if err := renderFunc(secret); err != nil {
 			return err
 		}
-
-		secret.Labels[esv1.LabelManaged] = esv1.LabelManagedValue
-		secret.Annotations[esv1.AnnotationDataHash] = esutils.ObjectHash(secret.Data)
-
+		writtenSecret = secret
 		return nil
 	}
 
@@ -520,6 +565,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		} else {
 			// if the secret does not exist, we wait until the next refresh interval
 			// rather than returning an error which would requeue immediately
+			if result, err := r.writeSplitTargets(ctx, log, externalSecret, secretName, data, splitSecrets, syncCallsError.With(resourceLabels)); result != nil {
+				return *result, err
+			}
 			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretMissing, msgMissing)
 			return r.getRequeueResult(externalSecret), nil
 		}
@@ -543,8 +591,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 			return ctrl.Result{}, err
 		}
 
-		// create the secret, if it does not exist
-		if existingSecret.UID == "" {
+		// write a new generation of the secret, if its data changed
+		if externalSecret.Spec.Target.Versioning != nil {
+			err = r.writeSecretGeneration(ctx, log, externalSecret, existingSecret, targetName, mutationFunc)
+		} else if existingSecret.UID == "" {
+			// create the secret, if it does not exist
 			err = r.createSecret(ctx, mutationFunc, externalSecret, secretName)
 		} else {
 			// if the secret exists, we should update it
@@ -552,43 +603,239 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		}
 	}
 	if err != nil {
-		// if we got an update conflict, we should requeue immediately
-		if apierrors.IsConflict(err) {
-			log.V(1).Info("conflict while updating secret, will requeue")
-			return ctrl.Result{Requeue: true}, nil
-		}
+		return r.handleSecretWriteError(log, externalSecret, err, syncCallsError.With(resourceLabels))
+	}
 
-		// detect errors indicating that we failed to set ourselves as the owner of the secret
-		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
-		if errors.Is(err, ErrSecretSetCtrlRef) {
-			r.markAsFailed(msgErrorBecomeOwner, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
-			return ctrl.Result{}, nil
+	// the rotated keys are recorded once the secret and their previous values are written
+	if writtenSecret != nil {
+		externalSecret.Status.Rotation = rotatedKeys
+	}
+
+	if result, err := r.writeSplitTargets(ctx, log, externalSecret, secretName, data, splitSecrets, syncCallsError.With(resourceLabels)); result != nil {
+		return *result, err
+	}
+
+	// point the workloads to the generation of the secret which was written
+	if writtenSecret != nil {
+		if err := r.syncSecretGenerations(ctx, log, externalSecret, targetName, writtenSecret); err != nil {
+			r.markAsFailed(msgErrorVersioning, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, err
 		}
+	}
 
-		// detect errors indicating that the secret has another ExternalSecret as owner
-		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
-		if errors.Is(err, ErrSecretIsOwned) {
-			r.markAsFailed(msgErrorIsOwned, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretOwnedByOther)
-			return ctrl.Result{}, nil
+	// restart the workloads using the secret once its data changed
+	if writtenSecret != nil {
+		if err := r.rolloutRestart(ctx, log, externalSecret, existingSecret, writtenSecret); err != nil {
+			r.markAsFailed(msgErrorRolloutRestart, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, err
 		}
+	}
 
-		// detect errors indicating that the secret is immutable
-		// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
-		if errors.Is(err, ErrSecretImmutable) {
-			r.markAsFailed(msgErrorUpdateImmutable, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretImmutable)
-			return ctrl.Result{}, nil
+	if snapshot != nil {
+		revision := *externalSecret.Spec.Target.RollbackTo
+		if !isRolledBack(externalSecret, revision) {
+			r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonUpdated, eventRolledBack, revision)
 		}
+		externalSecret.Status.RolledBackTo = &revision
+		r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretRolledBack, fmt.Sprintf(msgRolledBack, revision))
+		return r.getRequeueResult(externalSecret), nil
+	}
 
-		// not marked safe here: this path also carries template errors, which can
-		// echo rendered values. createSecret / updateSecret mark their own API errors.
-		r.markAsFailed(msgErrorUpdateSecret, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+	if err := r.recordHistory(ctx, log, externalSecret, writtenSecret); err != nil {
+		r.markAsFailed(msgErrorHistory, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
 		return ctrl.Result{}, err
 	}
 
+	// schedule the next refresh from the expiry of the data as it was written
+	expiryData := dataMap
+	var oldData, newData map[string][]byte
+	if writtenSecret != nil {
+		expiryData = writtenSecret.Data
+		oldData, newData = existingSecret.Data, writtenSecret.Data
+	}
+	setProvenance(externalSecret, data.keyProvenance, oldData, newData, start)
+	r.setNextRefresh(externalSecret, expiryData, data.keyProvenance.expiries(), start)
+
 	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSynced)
+	notification.Refreshed(notification.KindExternalSecret, req.NamespacedName, notified)
 	return r.getRequeueResult(externalSecret), nil
 }
 
+// handleSecretWriteError marks the ExternalSecret as failed when a secret could not be rendered or written.
+func (r *Reconciler) handleSecretWriteError(log logr.Logger, externalSecret *esv1.ExternalSecret, err error, counter prometheus.Counter) (ctrl.Result, error) {
+	// if we got an update conflict, we should requeue immediately
+	if apierrors.IsConflict(err) {
+		log.V(1).Info("conflict while updating secret, will requeue")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// detect errors indicating that we failed to set ourselves as the owner of the secret
+	// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+	if errors.Is(err, ErrSecretSetCtrlRef) {
+		r.markAsFailed(msgErrorBecomeOwner, ctrlutil.Safe(err), externalSecret, counter, esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, nil
+	}
+
+	// detect errors indicating that the secret has another ExternalSecret as owner
+	// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+	if errors.Is(err, ErrSecretIsOwned) {
+		r.markAsFailed(msgErrorIsOwned, ctrlutil.Safe(err), externalSecret, counter, esv1.ConditionReasonSecretOwnedByOther)
+		return ctrl.Result{}, nil
+	}
+
+	// detect errors indicating that the secret is immutable
+	// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+	if errors.Is(err, ErrSecretImmutable) {
+		r.markAsFailed(msgErrorUpdateImmutable, ctrlutil.Safe(err), externalSecret, counter, esv1.ConditionReasonSecretImmutable)
+		return ctrl.Result{}, nil
+	}
+
+	// detect errors indicating that the rendered secret failed target.validation, the secret was left untouched
+	// NOTE: the provider data may change, so we check again after the refresh interval instead of requeueing immediately
+	if errors.Is(err, ErrSecretValidationFailed) {
+		r.markAsFailed(msgErrorValidation, err, externalSecret, counter, esv1.ConditionReasonValidationFailed)
+		return r.getValidationRequeueResult(externalSecret), nil
+	}
+
+	// not marked safe here: this path also carries template errors, which can
+	// echo rendered values. createSecret / updateSecret mark their own API errors.
+	r.markAsFailed(msgErrorUpdateSecret, err, externalSecret, counter, esv1.ConditionReasonSecretSyncedError)
+	return ctrl.Result{}, err
+}
+
+// getExistingSecret returns the secret, or an empty secret if it does not exist.
+// It returns a result to requeue with instead when the caches are not up-to-date with the secret yet.
+func (r *Reconciler) getExistingSecret(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, secretName string) (*v1.Secret, *ctrl.Result, error) {
+	// fetch the existing secret (from the partial cache)
+	//  - please note that the ~partial cache~ is different from the ~full cache~
+	//    so there can be race conditions between the two caches
+	//  - the WatchesMetadata(v1.Secret{}) in SetupWithManager() is using the partial cache
+	//    so we might receive a reconcile request before the full cache is updated
+	//  - furthermore, when `--enable-managed-secrets-caching` is true, the full cache
+	//    will ONLY include secrets with the "managed" label, so we cant use the full cache
+	//    to reliably determine if a secret exists or not
+	secretPartial := &metav1.PartialObjectMetadata{}
+	secretPartial.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("Secret"))
+	err := r.Get(ctx, client.ObjectKey{Name: secretName, Namespace: externalSecret.Namespace}, secretPartial)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, logErrorGetSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+		return nil, nil, err
+	}
+
+	// if the secret exists but does not have the "managed" label, add the label
+	// using a PATCH so it is visible in the cache, then requeue immediately
+	if secretPartial.UID != "" && secretPartial.Labels[esv1.LabelManaged] != esv1.LabelManagedValue {
+		fqdn := fqdnFor(externalSecret.Name)
+		patch := client.MergeFrom(secretPartial.DeepCopy())
+		if secretPartial.Labels == nil {
+			secretPartial.Labels = make(map[string]string)
+		}
+		secretPartial.Labels[esv1.LabelManaged] = esv1.LabelManagedValue
+		err = r.Patch(ctx, secretPartial, patch, client.FieldOwner(fqdn))
+		if err != nil {
+			log.Error(err, logErrorPatchSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+			return nil, nil, err
+		}
+		return nil, &ctrl.Result{Requeue: true}, nil
+	}
+
+	// fetch existing secret (from the full cache)
+	// NOTE: we are using the `r.SecretClient` which we only use for managed secrets.
+	//       when `enableManagedSecretsCache` is true, this is a cached client that only sees our managed secrets,
+	//       otherwise it will be the normal controller-runtime client which may be cached or make direct API calls,
+	//       depending on if `enabledSecretCache` is true or false.
+	existingSecret := &v1.Secret{}
+	err = r.SecretClient.Get(ctx, client.ObjectKey{Name: secretName, Namespace: externalSecret.Namespace}, existingSecret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, logErrorGetSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+		return nil, nil, err
+	}
+
+	// ensure the full cache is up-to-date
+	// NOTE: this prevents race conditions between the partial and full cache.
+	//       if enabled, we verify against the API server before retrying to avoid unnecessary error backoff
+	//       when the cache is temporarily stale.
+	existingSecret, cacheNotSynced, getErr := r.resolveSecretCacheMismatch(ctx, client.ObjectKey{Name: secretName, Namespace: externalSecret.Namespace}, secretPartial, existingSecret)
+	if getErr != nil && !apierrors.IsNotFound(getErr) {
+		log.Error(getErr, logErrorGetSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+		return nil, nil, getErr
+	}
+	if cacheNotSynced {
+		log.V(1).Info(logErrorSecretCacheNotSynced, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+		return nil, &ctrl.Result{RequeueAfter: cacheSyncRetryDelay}, nil
+	}
+
+	return existingSecret, nil, nil
+}
+
+// secretMutationFunc returns a function which can be applied to a secret to make it match the desired state.
+func (r *Reconciler) secretMutationFunc(ctx context.Context, externalSecret *esv1.ExternalSecret, dataMap map[string][]byte) func(secret *v1.Secret) error {
+	renderFunc := r.renderSecretFunc(ctx, externalSecret, dataMap)
+	return func(secret *v1.Secret) error {
+		if err := renderFunc(secret); err != nil {
+			return err
+		}
+		return r.applyOwnership(externalSecret, secret)
+	}
+}
+
+// renderSecretFunc returns a function which renders the data, labels and annotations of a secret
+// from the provider data, and validates the rendered data with target.validation.
+// It does not apply the ownership of the secret.
+func (r *Reconciler) renderSecretFunc(ctx context.Context, externalSecret *esv1.ExternalSecret, dataMap map[string][]byte) func(secret *v1.Secret) error {
+	return func(secret *v1.Secret) error {
+		// initialize maps within the secret so it's safe to set values
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		if secret.Labels == nil {
+			secret.Labels = make(map[string]string)
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+
+		// set the immutable flag on the secret if requested by the ExternalSecret
+		if externalSecret.Spec.Target.Immutable {
+			secret.Immutable = new(true)
+		}
+
+		// only apply the template if the secret is mutable or if the secret is new (has no UID)
+		// otherwise we would mutate an object that is immutable and already exists
+		objectDoesNotExistOrCanBeMutated := secret.GetUID() == "" || !externalSecret.Spec.Target.Immutable
+
+		if objectDoesNotExistOrCanBeMutated {
+			// get the list of keys that are managed by this ExternalSecret
+			keys, err := getManagedDataKeys(secret, externalSecret.Name)
+			if err != nil {
+				return err
+			}
+			// remove any data keys that are managed by this ExternalSecret, so we can re-add them
+			// this ensures keys added by templates are not left behind when they are removed from the template
+			for _, key := range keys {
+				delete(secret.Data, key)
+			}
+
+			// WARNING: this will remove any labels or annotations managed by this ExternalSecret
+			//          so any updates to labels and annotations should be done AFTER this point
+			err = r.ApplyTemplate(ctx, externalSecret, secret, dataMap)
+			if err != nil {
+				return fmt.Errorf(errApplyTemplate, err)
+			}
+
+			// validate the rendered data before anything is written
+			if err := validateSecretData(externalSecret.Spec.Target.Validation, secret.Data, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		secret.Labels[esv1.LabelManaged] = esv1.LabelManagedValue
+		secret.Annotations[esv1.AnnotationDataHash] = esutils.ObjectHash(secret.Data)
+
+		return nil
+	}
+}
+
 // reconcileGenericTarget handles reconciliation for generic targets (ConfigMaps, Custom Resources).
 func (r *Reconciler) reconcileGenericTarget(
 	ctx context.Context,
@@ -617,12 +864,13 @@ func (r *Reconciler) reconcileGenericTarget(
 		return ctrl.Result{}, err
 	}
 
-	if !shouldRefresh(externalSecret) && valid {
+	notified := notification.Pending(notification.KindExternalSecret, client.ObjectKeyFromObject(externalSecret))
+	if !shouldRefreshOnNotification(externalSecret, notified) && valid {
 		log.V(1).Info("skipping refresh of generic target")
 		return r.getRequeueResult(externalSecret), nil
 	}
 
-	dataMap, err := r.GetProviderSecretData(ctx, externalSecret)
+	dataMap, keyProvenance, err := r.getProviderSecretData(ctx, externalSecret)
 	if err != nil {
 		r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonResourceSyncedError)
 		return ctrl.Result{}, err
@@ -736,12 +984,26 @@ func (r *Reconciler) reconcileGenericTarget(
 		}
 	}
 
+	setProvenance(externalSecret, keyProvenance, nil, nil, start)
+	r.setNextRefresh(externalSecret, dataMap, keyProvenance.expiries(), start)
 	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonResourceSynced, msgSynced)
+	notification.Refreshed(notification.KindExternalSecret, client.ObjectKeyFromObject(externalSecret), notified)
 	return r.getRequeueResult(externalSecret), nil
 }
 
-// getRequeueResult create a result with requeueAfter based on the ExternalSecret refresh interval.
+// getRequeueResult create a result with requeueAfter based on the ExternalSecret refresh interval,
+// or on the expiry of a previous value kept by target.rotation if it comes first.
 func (r *Reconciler) getRequeueResult(externalSecret *esv1.ExternalSecret) ctrl.Result {
+	return getRotationRequeueResult(externalSecret, r.getRefreshRequeueResult(externalSecret))
+}
+
+// getRefreshRequeueResult create a result with requeueAfter based on the ExternalSecret refresh interval.
+func (r *Reconciler) getRefreshRequeueResult(externalSecret *esv1.ExternalSecret) ctrl.Result {
+	// the OnExpiry refresh policy schedules its own refreshes
+	if externalSecret.Spec.RefreshPolicy == esv1.RefreshPolicyOnExpiry {
+		return getExpiryRequeueResult(externalSecret)
+	}
+
 	// default to the global requeue interval
 	// note, this will never be used because the CRD has a default value of 1 hour
 	refreshInterval := r.RequeueInterval
@@ -866,6 +1128,16 @@ func (r *Reconciler) applyOwnership(es *esv1.ExternalSecret, target client.Objec
 }
 
 func (r *Reconciler) cleanupManagedSecrets(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret) error {
+	// the secrets of spec.targets follow their own DeletionPolicy
+	for _, target := range externalSecret.Spec.Targets {
+		if target.DeletionPolicy != esv1.DeletionPolicyDelete {
+			continue
+		}
+		if err := r.deleteManagedSecret(ctx, log, externalSecret, target.Name); err != nil {
+			return err
+		}
+	}
+
 	// Only delete resources if DeletionPolicy is Delete
 	if externalSecret.Spec.Target.DeletionPolicy != esv1.DeletionPolicyDelete {
 		log.V(1).Info("skipping resource deletion due to DeletionPolicy", "policy", externalSecret.Spec.Target.DeletionPolicy)
@@ -882,7 +1154,11 @@ func (r *Reconciler) cleanupManagedSecrets(ctx context.Context, log logr.Logger,
 	if secretName == "" {
 		secretName = externalSecret.Name
 	}
+	return r.deleteManagedSecret(ctx, log, externalSecret, secretName)
+}
 
+// deleteManagedSecret deletes the secret, if it exists and is owned by the ExternalSecret.
+func (r *Reconciler) deleteManagedSecret(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, secretName string) error {
 	var secret v1.Secret
 	err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: externalSecret.Namespace}, &secret)
 	if err != nil {
@@ -920,9 +1196,10 @@ func (r *Reconciler) deleteOrphanedSecrets(ctx context.Context, log logr.Logger,
 		return err
 	}
 
-	// delete all secrets that are not the target secret
+	// delete all secrets that are not the target secret, a retained generation of it, or the secret of spec.targets
 	for _, secretPartial := range secretListPartial.Items {
-		if secretPartial.GetName() != secretName {
+		name := secretPartial.GetName()
+		if name != secretName && !isSecretGeneration(externalSecret, name) && !isSplitTarget(externalSecret, name) {
 			err := r.Delete(ctx, &secretPartial)
 			if err != nil && !apierrors.IsNotFound(err) {
 				return err
@@ -1102,7 +1379,12 @@ func getManagedFieldKeys(
 }
 
 func shouldSkipClusterSecretStore(r *Reconciler, es *esv1.ExternalSecret) bool {
-	return !r.ClusterSecretStoreEnabled && es.Spec.SecretStoreRef.Kind == esv1.ClusterSecretStoreKind
+	if r.ClusterSecretStoreEnabled {
+		return false
+	}
+	return slices.ContainsFunc(storeCandidates(es.Spec.SecretStoreRef), func(ref esv1.SecretStoreRef) bool {
+		return ref.Kind == esv1.ClusterSecretStoreKind
+	})
 }
 
 // shouldSkipUnmanagedStore iterates over all secretStore references in the externalSecret spec,
@@ -1112,18 +1394,18 @@ func shouldSkipUnmanagedStore(ctx context.Context, namespace string, r *Reconcil
 	var storeList []esv1.SecretStoreRef
 
 	if es.Spec.SecretStoreRef.Name != "" {
-		storeList = append(storeList, es.Spec.SecretStoreRef)
+		storeList = append(storeList, storeCandidates(es.Spec.SecretStoreRef)...)
 	}
 
 	for _, ref := range es.Spec.Data {
 		if ref.SourceRef != nil {
-			storeList = append(storeList, ref.SourceRef.SecretStoreRef)
+			storeList = append(storeList, storeCandidates(ref.SourceRef.SecretStoreRef)...)
 		}
 	}
 
 	for _, ref := range es.Spec.DataFrom {
 		if ref.SourceRef != nil && ref.SourceRef.SecretStoreRef != nil {
-			storeList = append(storeList, *ref.SourceRef.SecretStoreRef)
+			storeList = append(storeList, storeCandidates(*ref.SourceRef.SecretStoreRef)...)
 		}
 
 		// verify that generator's controllerClass matches
@@ -1260,6 +1542,9 @@ func shouldRefresh(es *esv1.ExternalSecret) bool {
 
 		return es.Status.SyncedResourceVersion != ctrlutil.GetResourceVersion(es.ObjectMeta)
 
+	case esv1.RefreshPolicyOnExpiry:
+		return shouldRefreshOnExpiry(es)
+
 	case esv1.RefreshPolicyPeriodic:
 		return shouldRefreshPeriodic(es)
 
@@ -1268,6 +1553,15 @@ func shouldRefresh(es *esv1.ExternalSecret) bool {
 	}
 }
 
+// shouldRefreshOnNotification also refreshes when the provider notified a change of a referenced key,
+// unless the ExternalSecret is only synced once.
+func shouldRefreshOnNotification(es *esv1.ExternalSecret, notified uint64) bool {
+	if notified != 0 && es.Spec.RefreshPolicy != esv1.RefreshPolicyCreatedOnce {
+		return true
+	}
+	return shouldRefresh(es)
+}
+
 func shouldRefreshPeriodic(es *esv1.ExternalSecret) bool {
 	// if the refresh interval is 0, and we have synced previously, we should not refresh
 	if es.Spec.RefreshInterval.Duration <= 0 && es.Status.SyncedResourceVersion != "" {
@@ -1364,6 +1658,15 @@ func genericTargetContentHash(obj *unstructured.Unstructured) (string, error) {
 	}
 }
 
+// apiReader returns the reader of the API server, for the objects the controller does not cache,
+// like the Secrets it does not manage and the workloads. Tests without a manager fall back to the client.
+func (r *Reconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
 // SetupWithManager returns a new controller builder that will be started by the provided Manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts controller.Options) error {
 	r.recorder = mgr.GetEventRecorderFor("external-secrets")
@@ -1384,11 +1687,16 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 			return nil
 		}
 		// if the target name is set, use that as the index
+		// otherwise, use the ExternalSecret name
+		names := []string{es.Name}
 		if es.Spec.Target.Name != "" {
-			return []string{es.Spec.Target.Name}
+			names[0] = es.Spec.Target.Name
 		}
-		// otherwise, use the ExternalSecret name
-		return []string{es.Name}
+		// the secrets of spec.targets are indexed too
+		for _, target := range es.Spec.Targets {
+			names = append(names, target.Name)
+		}
+		return names
 	}); err != nil {
 		return err
 	}
@@ -1409,6 +1717,14 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return err
 	}
 
+	// index ExternalSecrets based on the remote keys they reference,
+	// this lets the notification receiver find the ExternalSecrets of a changed key
+	if r.Notifications != nil {
+		if err := mgr.GetFieldIndexer().IndexField(ctx, &esv1.ExternalSecret{}, notification.RemoteKeysField, notification.IndexExternalSecretRemoteKeys); err != nil {
+			return err
+		}
+	}
+
 	// predicate function to ignore secret events unless they have the "managed" label
 	secretHasESLabel := predicate.NewPredicateFuncs(func(object client.Object) bool {
 		value, hasLabel := object.GetLabels()[esv1.LabelManaged]
@@ -1440,6 +1756,10 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		builder = builder.WatchesRawSource(r.informerManager.Source())
 	}
 
+	if r.Notifications != nil {
+		builder = builder.WatchesRawSource(source.Channel(r.Notifications, &handler.EnqueueRequestForObject{}))
+	}
+
 	return builder.Complete(r)
 }
 
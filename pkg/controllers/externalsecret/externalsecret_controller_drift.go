@@ -0,0 +1,165 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+// secretDrift describes a modification of the target Secret made outside of the controller.
+type secretDrift struct {
+	// keys of the data written since the last sync, and the field managers who wrote them.
+	// Both are empty when the managed fields of the Secret do not tell.
+	keys     []string
+	managers []string
+}
+
+// message describes the drift, naming the keys but never their values.
+func (d *secretDrift) message() string {
+	if len(d.keys) == 0 {
+		return msgDrifted
+	}
+	return fmt.Sprintf("%s (keys %s written by %s)", msgDrifted, strings.Join(d.keys, ", "), strings.Join(d.managers, ", "))
+}
+
+// driftPolicy returns the drift policy of the ExternalSecret, defaulting to Revert.
+func driftPolicy(es *esv1.ExternalSecret) esv1.ExternalSecretDriftPolicy {
+	if es.Spec.Target.DriftPolicy == "" {
+		return esv1.DriftPolicyRevert
+	}
+	return es.Spec.Target.DriftPolicy
+}
+
+// detectDrift returns the modification of the target Secret made since the last sync, or nil if there is none.
+// The data of the Secret is compared with the data-hash annotation written by the controller,
+// and the managed fields tell which keys were written by whom.
+func detectDrift(secret *v1.Secret, es *esv1.ExternalSecret) *secretDrift {
+	switch es.Spec.Target.CreationPolicy {
+	case esv1.CreatePolicyOrphan, esv1.CreatePolicyNone:
+		return nil
+	}
+	if secret.UID == "" || es.Status.RefreshTime.IsZero() || secret.Labels[esv1.LabelManaged] != esv1.LabelManagedValue {
+		return nil
+	}
+	hash, ok := secret.Annotations[esv1.AnnotationDataHash]
+	if !ok || hash == esutils.ObjectHash(secret.Data) {
+		return nil
+	}
+
+	drift := &secretDrift{}
+	fqdn := fqdnFor(es.Name)
+	for _, entry := range secret.ManagedFields {
+		if entry.Manager == fqdn || entry.FieldsV1 == nil {
+			continue
+		}
+		if entry.Time != nil && entry.Time.Before(&es.Status.RefreshTime) {
+			continue
+		}
+		var fields struct {
+			Data map[string]any `json:"f:data"`
+		}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		var written bool
+		for field := range fields.Data {
+			if key, ok := strings.CutPrefix(field, "f:"); ok {
+				drift.keys = append(drift.keys, key)
+				written = true
+			}
+		}
+		if written {
+			drift.managers = append(drift.managers, entry.Manager)
+		}
+	}
+	slices.Sort(drift.keys)
+	drift.keys = slices.Compact(drift.keys)
+	slices.Sort(drift.managers)
+	drift.managers = slices.Compact(drift.managers)
+	return drift
+}
+
+// isSecretInSync is like isSecretValid, but takes the drift of the secret into account:
+// a drift is reverted right away unless the policy says otherwise, and a drift which was reported
+// and was since resolved is cleared by syncing again.
+func isSecretInSync(existingSecret *v1.Secret, es *esv1.ExternalSecret, drift *secretDrift) bool {
+	if drift != nil {
+		return driftPolicy(es) == esv1.DriftPolicyIgnore
+	}
+	if cond := esv1.GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted); cond != nil && cond.Status == v1.ConditionTrue {
+		return false
+	}
+	return isSecretValid(existingSecret, es)
+}
+
+// handleDrift records the drift of the target Secret in the Drifted condition, with an event and a metric.
+// It returns true if the Secret must be left untouched because the drift is only reported.
+func (r *Reconciler) handleDrift(es *esv1.ExternalSecret, drift *secretDrift, resourceLabels prometheus.Labels) bool {
+	policy := driftPolicy(es)
+	cond := esv1.GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted)
+	if drift == nil || policy == esv1.DriftPolicyIgnore {
+		// syncing the secret resolves a drift which was reported before
+		if cond != nil && cond.Status == v1.ConditionTrue {
+			SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1.ExternalSecretDrifted, v1.ConditionFalse, esv1.ConditionReasonNoDrift, msgNoDrift))
+		}
+		return false
+	}
+
+	if policy == esv1.DriftPolicyReport {
+		// a drift is reported once, not on every reconcile until it is resolved
+		if cond == nil || cond.Status != v1.ConditionTrue {
+			r.recorder.Eventf(es, v1.EventTypeWarning, esv1.ReasonDrifted, eventDriftReported, drift.message())
+			esmetrics.GetCounterVec(esmetrics.DriftDetectedKey).With(resourceLabels).Inc()
+		}
+		SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1.ExternalSecretDrifted, v1.ConditionTrue, esv1.ConditionReasonSecretDrifted, drift.message()))
+		return true
+	}
+
+	r.recorder.Eventf(es, v1.EventTypeWarning, esv1.ReasonDrifted, eventDriftReverted, drift.message())
+	esmetrics.GetCounterVec(esmetrics.DriftDetectedKey).With(resourceLabels).Inc()
+	SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1.ExternalSecretDrifted, v1.ConditionFalse, esv1.ConditionReasonDriftReverted, drift.message()))
+	return false
+}
+
+// getDriftReportRequeueResult returns the result of a refresh skipped because the drift is only reported.
+// The refresh time is not updated then, so an overdue refresh is checked again a full refresh interval later
+// rather than right away.
+func (r *Reconciler) getDriftReportRequeueResult(es *esv1.ExternalSecret) ctrl.Result {
+	result := r.getRequeueResult(es)
+	if !result.Requeue {
+		return result
+	}
+	refreshInterval := r.RequeueInterval
+	if es.Spec.RefreshInterval != nil {
+		refreshInterval = es.Spec.RefreshInterval.Duration
+	}
+	if refreshInterval <= 0 {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: refreshInterval}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
+	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+var driftTestRefreshTime = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newDriftTestExternalSecret(policy esv1.ExternalSecretDriftPolicy) *esv1.ExternalSecret {
+	es := newTestExternalSecret()
+	es.Spec.Target.DriftPolicy = policy
+	es.Status.RefreshTime = metav1.Time{Time: driftTestRefreshTime}
+	return es
+}
+
+// newDriftTestSecret returns a managed secret written with data, and then modified to hold current.
+func newDriftTestSecret(data, current map[string][]byte, managedFields ...metav1.ManagedFieldsEntry) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "es",
+			Namespace:     "default",
+			UID:           "uid",
+			Labels:        map[string]string{esv1.LabelManaged: esv1.LabelManagedValue},
+			Annotations:   map[string]string{esv1.AnnotationDataHash: esutils.ObjectHash(data)},
+			ManagedFields: managedFields,
+		},
+		Data: current,
+	}
+}
+
+func dataManagedFields(manager string, at time.Time, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:   manager,
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		Time:      &metav1.Time{Time: at},
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	written := map[string][]byte{"user": []byte("admin"), "password": []byte("secret")}
+	edited := map[string][]byte{"user": []byte("admin"), "password": []byte("changed")}
+	after := driftTestRefreshTime.Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		es     func(es *esv1.ExternalSecret)
+		secret *v1.Secret
+		want   *secretDrift
+	}{
+		{
+			name:   "secret in sync",
+			secret: newDriftTestSecret(written, written),
+		},
+		{
+			name:   "secret modified",
+			secret: newDriftTestSecret(written, edited),
+			want:   &secretDrift{},
+		},
+		{
+			name: "secret modified, keys known from the managed fields",
+			secret: newDriftTestSecret(written, edited,
+				dataManagedFields(fqdnFor("es"), driftTestRefreshTime, `{"f:data":{".":{},"f:user":{}}}`),
+				dataManagedFields("helm", driftTestRefreshTime.Add(-time.Hour), `{"f:data":{"f:other":{}}}`),
+				dataManagedFields("kubectl-edit", after, `{"f:data":{"f:password":{}},"f:metadata":{"f:labels":{}}}`),
+				dataManagedFields("kubectl-label", after, `{"f:metadata":{"f:labels":{"f:team":{}}}}`),
+			),
+			want: &secretDrift{keys: []string{"password"}, managers: []string{"kubectl-edit"}},
+		},
+		{
+			name:   "secret not synced yet",
+			es:     func(es *esv1.ExternalSecret) { es.Status.RefreshTime = metav1.Time{} },
+			secret: newDriftTestSecret(written, edited),
+		},
+		{
+			name:   "orphaned secret",
+			es:     func(es *esv1.ExternalSecret) { es.Spec.Target.CreationPolicy = esv1.CreatePolicyOrphan },
+			secret: newDriftTestSecret(written, edited),
+		},
+		{
+			name: "secret without data hash",
+			secret: func() *v1.Secret {
+				s := newDriftTestSecret(written, edited)
+				s.Annotations = nil
+				return s
+			}(),
+		},
+		{
+			name:   "missing secret",
+			secret: &v1.Secret{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newDriftTestExternalSecret("")
+			if tt.es != nil {
+				tt.es(es)
+			}
+			assert.Equal(t, tt.want, detectDrift(tt.secret, es))
+		})
+	}
+}
+
+func TestSecretDriftMessage(t *testing.T) {
+	assert.Equal(t, msgDrifted, (&secretDrift{}).message())
+	assert.Equal(t, msgDrifted+" (keys password, user written by kubectl-edit)",
+		(&secretDrift{keys: []string{"password", "user"}, managers: []string{"kubectl-edit"}}).message())
+}
+
+func TestIsSecretInSync(t *testing.T) {
+	data := map[string][]byte{"password": []byte("secret")}
+	secret := newDriftTestSecret(data, data)
+	drift := &secretDrift{}
+
+	assert.True(t, isSecretInSync(secret, newDriftTestExternalSecret(""), nil))
+	assert.False(t, isSecretInSync(secret, newDriftTestExternalSecret(""), drift))
+	assert.False(t, isSecretInSync(secret, newDriftTestExternalSecret(esv1.DriftPolicyRevert), drift))
+	assert.False(t, isSecretInSync(secret, newDriftTestExternalSecret(esv1.DriftPolicyReport), drift))
+	assert.True(t, isSecretInSync(secret, newDriftTestExternalSecret(esv1.DriftPolicyIgnore), drift))
+
+	// a resolved drift is cleared by syncing again
+	es := newDriftTestExternalSecret(esv1.DriftPolicyReport)
+	SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1.ExternalSecretDrifted, v1.ConditionTrue, esv1.ConditionReasonSecretDrifted, msgDrifted))
+	assert.False(t, isSecretInSync(secret, es, nil))
+}
+
+func TestHandleDrift(t *testing.T) {
+	drift := &secretDrift{keys: []string{"password"}, managers: []string{"kubectl-edit"}}
+
+	t.Run("revert", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{recorder: recorder}
+		es := newDriftTestExternalSecret("")
+		es.Name = "drift-revert"
+		labels := ctrlmetrics.RefineNonConditionMetricLabels(map[string]string{"name": es.Name, "namespace": es.Namespace})
+		counter := esmetrics.GetCounterVec(esmetrics.DriftDetectedKey).With(labels)
+
+		for range 2 {
+			assert.False(t, r.handleDrift(es, drift, labels))
+		}
+		cond := esv1.GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted)
+		require.NotNil(t, cond)
+		assert.Equal(t, v1.ConditionFalse, cond.Status)
+		assert.Equal(t, esv1.ConditionReasonDriftReverted, cond.Reason)
+		assert.Equal(t, drift.message(), cond.Message)
+		assert.Len(t, recorder.Events, 2)
+		assert.InDelta(t, 2, testutil.ToFloat64(counter), 0)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{recorder: recorder}
+		es := newDriftTestExternalSecret(esv1.DriftPolicyReport)
+		es.Name = "drift-report"
+		labels := ctrlmetrics.RefineNonConditionMetricLabels(map[string]string{"name": es.Name, "namespace": es.Namespace})
+		counter := esmetrics.GetCounterVec(esmetrics.DriftDetectedKey).With(labels)
+
+		// the drift is reported once
+		for range 2 {
+			assert.True(t, r.handleDrift(es, drift, labels))
+		}
+		cond := esv1.GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted)
+		require.NotNil(t, cond)
+		assert.Equal(t, v1.ConditionTrue, cond.Status)
+		assert.Equal(t, esv1.ConditionReasonSecretDrifted, cond.Reason)
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "DriftPolicy=Report")
+		assert.InDelta(t, 1, testutil.ToFloat64(counter), 0)
+
+		// the condition is cleared once the drift is resolved
+		assert.False(t, r.handleDrift(es, nil, labels))
+		cond = esv1.GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted)
+		require.NotNil(t, cond)
+		assert.Equal(t, v1.ConditionFalse, cond.Status)
+		assert.Equal(t, esv1.ConditionReasonNoDrift, cond.Reason)
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{recorder: recorder}
+		es := newDriftTestExternalSecret(esv1.DriftPolicyIgnore)
+		labels := ctrlmetrics.RefineNonConditionMetricLabels(map[string]string{"name": "drift-ignore", "namespace": es.Namespace})
+
+		assert.False(t, r.handleDrift(es, drift, labels))
+		assert.Nil(t, esv1.GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted))
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func TestReconcileDriftReportRequeues(t *testing.T) {
+	written := map[string][]byte{"password": []byte("secret")}
+	secret := newDriftTestSecret(written, map[string][]byte{"password": []byte("changed")})
+	es := newDriftTestExternalSecret(esv1.DriftPolicyReport)
+	es.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+	h := newTestReconciler(t, secret, es)
+
+	result, _, err := h.reconcile(es)
+	require.NoError(t, err)
+
+	// the drifted secret is left untouched, and checked again at the next refresh
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Hour}, result)
+	assert.Equal(t, secret.Data, h.secret("es").Data)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+// reconcileDryRun fetches and renders the Secrets of the ExternalSecret like a regular reconcile,
+// but only records a redacted summary of the changes in the status.
+// secretName is the Secret of spec.target, which is the current generation of targetName with target.versioning.
+func (r *Reconciler) reconcileDryRun(
+	ctx context.Context,
+	externalSecret *esv1.ExternalSecret,
+	log logr.Logger,
+	targetName, secretName string,
+	start time.Time,
+	resourceLabels map[string]string,
+	syncCallsError *prometheus.CounterVec,
+) (ctrl.Result, error) {
+	if !shouldRefresh(externalSecret) {
+		log.V(1).Info("skipping refresh of dry run")
+		return r.getRequeueResult(externalSecret), nil
+	}
+
+	existingSecret, err := r.getDryRunSecret(ctx, externalSecret, secretName)
+	if err != nil {
+		log.Error(err, logErrorGetSecret, "secretName", secretName, "secretNamespace", externalSecret.Namespace)
+		syncCallsError.With(resourceLabels).Inc()
+		return ctrl.Result{}, err
+	}
+	splitSecrets := make([]*v1.Secret, len(externalSecret.Spec.Targets))
+	for i, target := range externalSecret.Spec.Targets {
+		if splitSecrets[i], err = r.getDryRunSecret(ctx, externalSecret, target.Name); err != nil {
+			log.Error(err, logErrorGetSecret, "secretName", target.Name, "secretNamespace", externalSecret.Namespace)
+			syncCallsError.With(resourceLabels).Inc()
+			return ctrl.Result{}, err
+		}
+	}
+
+	// the data and the secrets are rendered by the same code as a regular reconcile
+	data, err := r.getTargetData(ctx, externalSecret)
+	if err != nil {
+		return r.handleTargetDataError(externalSecret, err, syncCallsError.With(resourceLabels))
+	}
+	keptValues, err := r.keptRotationValues(ctx, externalSecret, existingSecret)
+	if err != nil {
+		r.markAsFailed(msgErrorRotation, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, err
+	}
+	mutationFunc := r.targetMutationFunc(ctx, externalSecret, existingSecret, data, keptValues, start,
+		func([]esv1.ExternalSecretRotatedKey, map[string][]byte) {})
+
+	desired, err := desiredSecret(externalSecret, existingSecret, targetName, data.isEmpty(), mutationFunc)
+	var desiredSplits []*v1.Secret
+	if err == nil {
+		desiredSplits, err = r.desiredSplitSecrets(ctx, externalSecret, splitSecrets, data)
+	}
+	if err != nil {
+		// NOTE: these errors cant be fixed by retrying so we don't return an error (which would requeue immediately)
+		switch {
+		case errors.Is(err, ErrSecretIsOwned):
+			r.markAsFailed(msgErrorIsOwned, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretOwnedByOther)
+			return ctrl.Result{}, nil
+		case errors.Is(err, ErrSecretSetCtrlRef):
+			r.markAsFailed(msgErrorBecomeOwner, ctrlutil.Safe(err), externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+			return ctrl.Result{}, nil
+		case errors.Is(err, ErrSecretValidationFailed):
+			r.markAsFailed(msgErrorValidation, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonValidationFailed)
+			return r.getValidationRequeueResult(externalSecret), nil
+		}
+
+		// template errors stay generic: rendering can echo secret values.
+		r.markAsFailed(msgErrorDryRun, err, externalSecret, syncCallsError.With(resourceLabels), esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, err
+	}
+
+	// a new generation is compared with the generation holding the same data, if it was retained
+	currentSecret := existingSecret
+	if desired != nil && desired.Name != existingSecret.Name {
+		if currentSecret, err = r.getDryRunSecret(ctx, externalSecret, desired.Name); err != nil {
+			log.Error(err, logErrorGetSecret, "secretName", desired.Name, "secretNamespace", externalSecret.Namespace)
+			syncCallsError.With(resourceLabels).Inc()
+			return ctrl.Result{}, err
+		}
+	}
+
+	externalSecret.Status.DryRun = dryRunSummary(currentSecret, desired)
+	externalSecret.Status.DryRunTargets = nil
+	for i := range splitSecrets {
+		externalSecret.Status.DryRunTargets = append(externalSecret.Status.DryRunTargets, *dryRunSummary(splitSecrets[i], desiredSplits[i]))
+	}
+	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretDryRun, msgDryRun)
+	return r.getRequeueResult(externalSecret), nil
+}
+
+// getDryRunSecret returns the secret, or an empty secret with its name if it does not exist.
+// The secret is read from the API server, as the secret caches may only contain secrets
+// that already have the "managed" label.
+func (r *Reconciler) getDryRunSecret(ctx context.Context, externalSecret *esv1.ExternalSecret, secretName string) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	err := r.apiReader().Get(ctx, client.ObjectKey{Name: secretName, Namespace: externalSecret.Namespace}, secret)
+	if apierrors.IsNotFound(err) {
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: externalSecret.Namespace}}, nil
+	}
+	return secret, err
+}
+
+// dryRunSummary compares the existing secret with the desired one, named like the desired secret if any.
+// Only key names and hashes are recorded, never values.
+func dryRunSummary(existingSecret, desiredSecret *v1.Secret) *esv1.ExternalSecretDryRunStatus {
+	exists := existingSecret.UID != ""
+	summary := &esv1.ExternalSecretDryRunStatus{Name: existingSecret.Name, Action: esv1.DryRunActionNone}
+	var currentData, desiredData map[string][]byte
+	if exists {
+		currentData = existingSecret.Data
+		summary.CurrentDataHash = esutils.ObjectHash(currentData)
+	}
+	if desiredSecret != nil {
+		summary.Name = desiredSecret.Name
+		desiredData = desiredSecret.Data
+		summary.DesiredDataHash = esutils.ObjectHash(desiredData)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(desiredData)) {
+		current, ok := currentData[key]
+		switch {
+		case !ok:
+			summary.AddedKeys = append(summary.AddedKeys, key)
+		case !bytes.Equal(current, desiredData[key]):
+			summary.ChangedKeys = append(summary.ChangedKeys, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(currentData)) {
+		if _, ok := desiredData[key]; !ok {
+			summary.RemovedKeys = append(summary.RemovedKeys, key)
+		}
+	}
+
+	switch {
+	case !exists && desiredSecret != nil:
+		summary.Action = esv1.DryRunActionCreate
+	case exists && desiredSecret == nil:
+		summary.Action = esv1.DryRunActionDelete
+	case exists:
+		summary.MetadataChanged = metadataChanged(existingSecret, desiredSecret)
+		if summary.MetadataChanged || len(summary.AddedKeys)+len(summary.RemovedKeys)+len(summary.ChangedKeys) > 0 {
+			summary.Action = esv1.DryRunActionUpdate
+		}
+	}
+
+	return summary
+}
+
+// metadataChanged reports changes of the labels, annotations or type of a secret.
+// The data-hash annotation is ignored, as data changes are reported per key.
+func metadataChanged(existingSecret, desiredSecret *v1.Secret) bool {
+	withoutHash := func(annotations map[string]string) map[string]string {
+		out := maps.Clone(annotations)
+		delete(out, esv1.AnnotationDataHash)
+		return out
+	}
+
+	return existingSecret.Type != desiredSecret.Type ||
+		!maps.Equal(existingSecret.Labels, desiredSecret.Labels) ||
+		!maps.Equal(withoutHash(existingSecret.Annotations), withoutHash(desiredSecret.Annotations))
+}
@@ -0,0 +1,265 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+func TestDryRunSummary(t *testing.T) {
+	existing := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:         "uid",
+			Labels:      map[string]string{"app": "foo"},
+			Annotations: map[string]string{esv1.AnnotationDataHash: "old"},
+		},
+		Data: map[string][]byte{
+			"kept":    []byte("same"),
+			"changed": []byte("old"),
+			"removed": []byte("gone"),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		existing *v1.Secret
+		desired  func() *v1.Secret
+		want     *esv1.ExternalSecretDryRunStatus
+	}{
+		{
+			name:     "create",
+			existing: &v1.Secret{},
+			desired: func() *v1.Secret {
+				return &v1.Secret{Data: map[string][]byte{"b": []byte("1"), "a": []byte("2")}}
+			},
+			want: &esv1.ExternalSecretDryRunStatus{
+				Action:          esv1.DryRunActionCreate,
+				AddedKeys:       []string{"a", "b"},
+				DesiredDataHash: esutils.ObjectHash(map[string][]byte{"b": []byte("1"), "a": []byte("2")}),
+			},
+		},
+		{
+			name:     "update",
+			existing: existing,
+			desired: func() *v1.Secret {
+				desired := existing.DeepCopy()
+				desired.Annotations[esv1.AnnotationDataHash] = "new"
+				desired.Data = map[string][]byte{
+					"kept":    []byte("same"),
+					"changed": []byte("new"),
+					"added":   []byte("new"),
+				}
+				return desired
+			},
+			want: &esv1.ExternalSecretDryRunStatus{
+				Action:          esv1.DryRunActionUpdate,
+				AddedKeys:       []string{"added"},
+				RemovedKeys:     []string{"removed"},
+				ChangedKeys:     []string{"changed"},
+				CurrentDataHash: esutils.ObjectHash(existing.Data),
+				DesiredDataHash: esutils.ObjectHash(map[string][]byte{
+					"kept":    []byte("same"),
+					"changed": []byte("new"),
+					"added":   []byte("new"),
+				}),
+			},
+		},
+		{
+			name:     "metadata only",
+			existing: existing,
+			desired: func() *v1.Secret {
+				desired := existing.DeepCopy()
+				desired.Labels["app"] = "bar"
+				return desired
+			},
+			want: &esv1.ExternalSecretDryRunStatus{
+				Action:          esv1.DryRunActionUpdate,
+				MetadataChanged: true,
+				CurrentDataHash: esutils.ObjectHash(existing.Data),
+				DesiredDataHash: esutils.ObjectHash(existing.Data),
+			},
+		},
+		{
+			name:     "unchanged",
+			existing: existing,
+			desired:  existing.DeepCopy,
+			want: &esv1.ExternalSecretDryRunStatus{
+				Action:          esv1.DryRunActionNone,
+				CurrentDataHash: esutils.ObjectHash(existing.Data),
+				DesiredDataHash: esutils.ObjectHash(existing.Data),
+			},
+		},
+		{
+			name:     "delete",
+			existing: existing,
+			desired:  func() *v1.Secret { return nil },
+			want: &esv1.ExternalSecretDryRunStatus{
+				Action:          esv1.DryRunActionDelete,
+				RemovedKeys:     []string{"changed", "kept", "removed"},
+				CurrentDataHash: esutils.ObjectHash(existing.Data),
+			},
+		},
+		{
+			name:     "not created",
+			existing: &v1.Secret{},
+			desired:  func() *v1.Secret { return nil },
+			want:     &esv1.ExternalSecretDryRunStatus{Action: esv1.DryRunActionNone},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dryRunSummary(tt.existing, tt.desired()))
+		})
+	}
+}
+
+func TestReconcileDryRun(t *testing.T) {
+	existing := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: types.UID("secret")},
+		Data:       map[string][]byte{"foo": []byte("old"), "bar": []byte("old")},
+	}
+	es := newTestExternalSecret()
+	es.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+	es.Spec.Target = esv1.ExternalSecretTarget{
+		CreationPolicy: esv1.CreatePolicyOrphan,
+		DryRun:         true,
+	}
+	h := newTestReconciler(t, existing, es)
+	fakeProvider.WithGetSecret([]byte("new"), nil)
+
+	_, got, err := h.reconcile(es)
+	require.NoError(t, err)
+
+	// the target secret is untouched
+	secret := h.secret("es")
+	assert.Equal(t, existing.Data, secret.Data)
+	assert.Empty(t, secret.Labels)
+
+	require.NotNil(t, got.Status.DryRun)
+	assert.Equal(t, esv1.DryRunActionUpdate, got.Status.DryRun.Action)
+	assert.Equal(t, []string{"foo"}, got.Status.DryRun.ChangedKeys)
+	assert.Equal(t, []string{"bar"}, got.Status.DryRun.RemovedKeys)
+	assert.True(t, got.Status.DryRun.MetadataChanged)
+	assert.Equal(t, esutils.ObjectHash(map[string][]byte{"foo": []byte("new")}), got.Status.DryRun.DesiredDataHash)
+
+	cond := esv1.GetExternalSecretCondition(got.Status, esv1.ExternalSecretReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, esv1.ConditionReasonSecretDryRun, cond.Reason)
+}
+
+func TestReconcileDryRunLikeTheController(t *testing.T) {
+	dryRun := func(t *testing.T, es *esv1.ExternalSecret, objects ...client.Object) *esv1.ExternalSecret {
+		t.Helper()
+		h := newTestReconciler(t, append(objects, es)...)
+		fakeProvider.WithGetSecret([]byte("new"), nil)
+		_, got, err := h.reconcile(es)
+		require.NoError(t, err)
+
+		// no secret is written
+		secrets := &v1.SecretList{}
+		require.NoError(t, h.kube.List(context.Background(), secrets))
+		assert.Len(t, secrets.Items, len(objects))
+
+		require.NotNil(t, got.Status.DryRun)
+		return got
+	}
+
+	t.Run("versioning and targets", func(t *testing.T) {
+		es := &esv1.ExternalSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+			Spec: esv1.ExternalSecretSpec{
+				SecretStoreRef:  esv1.SecretStoreRef{Name: testStoreName, Kind: esv1.SecretStoreKind},
+				RefreshInterval: &metav1.Duration{Duration: time.Hour},
+				Target: esv1.ExternalSecretTarget{
+					CreationPolicy: esv1.CreatePolicyOwner,
+					DryRun:         true,
+					Versioning:     &esv1.ExternalSecretVersioning{},
+				},
+				Targets: []esv1.ExternalSecretSplitTarget{
+					{Name: "foo-only", Selector: &esv1.ExternalSecretKeySelector{Keys: []string{"foo"}}},
+					{Name: "none", Selector: &esv1.ExternalSecretKeySelector{Keys: []string{"other"}}},
+				},
+				Data: []esv1.ExternalSecretData{
+					{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "foo"}},
+					{SecretKey: "bar", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "bar"}},
+				},
+			},
+		}
+		got := dryRun(t, es)
+
+		data := map[string][]byte{"foo": []byte("new"), "bar": []byte("new")}
+		assert.Equal(t, secretGenerationName("es", esutils.ObjectHash(data)), got.Status.DryRun.Name)
+		assert.Equal(t, esv1.DryRunActionCreate, got.Status.DryRun.Action)
+		assert.Equal(t, []esv1.ExternalSecretDryRunStatus{
+			{
+				Name:            "foo-only",
+				Action:          esv1.DryRunActionCreate,
+				AddedKeys:       []string{"foo"},
+				DesiredDataHash: esutils.ObjectHash(map[string][]byte{"foo": []byte("new")}),
+			},
+			{Name: "none", Action: esv1.DryRunActionNone},
+		}, got.Status.DryRunTargets)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		existing := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default", UID: types.UID("secret")},
+			Data:       map[string][]byte{"foo": []byte("new")},
+		}
+		snapshot := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "es-1", Namespace: "default"},
+			Data:       map[string][]byte{"foo": []byte("old")},
+		}
+		es := &esv1.ExternalSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+			Spec: esv1.ExternalSecretSpec{
+				SecretStoreRef:  esv1.SecretStoreRef{Name: testStoreName, Kind: esv1.SecretStoreKind},
+				RefreshInterval: &metav1.Duration{Duration: time.Hour},
+				Target: esv1.ExternalSecretTarget{
+					CreationPolicy: esv1.CreatePolicyOrphan,
+					DryRun:         true,
+					History:        &esv1.ExternalSecretHistory{Limit: 3},
+					RollbackTo:     new(int64(1)),
+				},
+				Data: []esv1.ExternalSecretData{
+					{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "foo"}},
+				},
+			},
+			Status: esv1.ExternalSecretStatus{
+				History: []esv1.ExternalSecretHistoryEntry{{Revision: 1, SnapshotName: "es-1"}},
+			},
+		}
+		got := dryRun(t, es, existing, snapshot)
+
+		assert.Equal(t, "es", got.Status.DryRun.Name)
+		assert.Equal(t, esv1.DryRunActionUpdate, got.Status.DryRun.Action)
+		assert.Equal(t, []string{"foo"}, got.Status.DryRun.ChangedKeys)
+		assert.Equal(t, esutils.ObjectHash(snapshot.Data), got.Status.DryRun.DesiredDataHash)
+	})
+}
@@ -0,0 +1,244 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+const (
+	defaultRemainingLifetimePercent = 75
+	defaultExpiryMinInterval        = time.Minute
+)
+
+// setNextRefresh schedules the next refresh of an ExternalSecret with the OnExpiry refresh policy,
+// after a percentage of the remaining lifetime of the earliest expiry found in the data,
+// or reported for its keys by the providers and the generators.
+// Without an expiry, the next refresh falls back to the refresh interval.
+func (r *Reconciler) setNextRefresh(es *esv1.ExternalSecret, data map[string][]byte, reported map[string]time.Time, now time.Time) {
+	if es.Spec.RefreshPolicy != esv1.RefreshPolicyOnExpiry {
+		es.Status.ExpiryTime = nil
+		es.Status.NextRefreshTime = nil
+		return
+	}
+	cfg := es.Spec.ExpiryRefresh
+	if cfg == nil {
+		cfg = &esv1.ExternalSecretExpiryRefresh{}
+	}
+
+	var next time.Time
+	if expiry, ok := findExpiry(cfg.Sources, data, reported, now); ok {
+		es.Status.ExpiryTime = &metav1.Time{Time: expiry}
+		percent := int64(defaultRemainingLifetimePercent)
+		if cfg.RemainingLifetimePercent != nil {
+			percent = int64(*cfg.RemainingLifetimePercent)
+		}
+		next = now.Add(expiry.Sub(now) / 100 * time.Duration(percent))
+	} else {
+		es.Status.ExpiryTime = nil
+		refreshInterval := r.RequeueInterval
+		if es.Spec.RefreshInterval != nil {
+			refreshInterval = es.Spec.RefreshInterval.Duration
+		}
+		if refreshInterval <= 0 {
+			es.Status.NextRefreshTime = nil
+			return
+		}
+		next = now.Add(refreshInterval)
+	}
+
+	minInterval := defaultExpiryMinInterval
+	if cfg.MinInterval != nil {
+		minInterval = cfg.MinInterval.Duration
+	}
+	if next.Before(now.Add(minInterval)) {
+		next = now.Add(minInterval)
+	}
+	if cfg.MaxInterval != nil && next.After(now.Add(cfg.MaxInterval.Duration)) {
+		next = now.Add(cfg.MaxInterval.Duration)
+	}
+	es.Status.NextRefreshTime = &metav1.Time{Time: next}
+}
+
+// shouldRefreshOnExpiry refreshes once the next refresh time scheduled by setNextRefresh has passed.
+func shouldRefreshOnExpiry(es *esv1.ExternalSecret) bool {
+	if es.Status.SyncedResourceVersion != ctrlutil.GetResourceVersion(es.ObjectMeta) || es.Status.RefreshTime.IsZero() {
+		return true
+	}
+	if es.Status.NextRefreshTime == nil {
+		return false
+	}
+	return !time.Now().Before(es.Status.NextRefreshTime.Time)
+}
+
+// getExpiryRequeueResult requeues at the next refresh time scheduled by setNextRefresh.
+func getExpiryRequeueResult(es *esv1.ExternalSecret) ctrl.Result {
+	if es.Status.NextRefreshTime == nil {
+		return ctrl.Result{}
+	}
+	untilNextRefresh := time.Until(es.Status.NextRefreshTime.Time)
+	if untilNextRefresh <= 0 {
+		return ctrl.Result{Requeue: true}
+	}
+	return ctrl.Result{RequeueAfter: untilNextRefresh}
+}
+
+// findExpiry returns the earliest expiry of the sources. Without sources, every key is read with the Auto format,
+// and every reported expiry is used.
+// The reported expiries are keyed by the keys of the provider data, which templates may rename in the data.
+func findExpiry(sources []esv1.ExternalSecretExpirySource, data map[string][]byte, reported map[string]time.Time, now time.Time) (time.Time, bool) {
+	if len(sources) == 0 {
+		for _, key := range slices.Sorted(maps.Keys(data)) {
+			sources = append(sources, esv1.ExternalSecretExpirySource{Key: key, Format: esv1.ExpiryFormatAuto})
+		}
+		for _, key := range slices.Sorted(maps.Keys(reported)) {
+			sources = append(sources, esv1.ExternalSecretExpirySource{Key: key, Format: esv1.ExpiryFormatReported})
+		}
+	}
+
+	var earliest time.Time
+	found := false
+	observe := func(expiry time.Time) {
+		if !found || expiry.Before(earliest) {
+			earliest = expiry
+			found = true
+		}
+	}
+	for _, src := range sources {
+		if src.Format == esv1.ExpiryFormatAuto || src.Format == esv1.ExpiryFormatReported {
+			if expiry, ok := reported[src.Key]; ok {
+				observe(expiry)
+			}
+			if src.Format == esv1.ExpiryFormatReported {
+				continue
+			}
+		}
+		value, ok := data[src.Key]
+		if !ok {
+			continue
+		}
+		if expiry, ok := parseExpiry(src.Format, value, now); ok {
+			observe(expiry)
+		}
+	}
+	return earliest, found
+}
+
+func parseExpiry(format esv1.ExternalSecretExpiryFormat, value []byte, now time.Time) (time.Time, bool) {
+	switch format {
+	case esv1.ExpiryFormatCertificate:
+		return certificateExpiry(value)
+	case esv1.ExpiryFormatJWT:
+		return jwtExpiry(value)
+	case esv1.ExpiryFormatTimestamp:
+		return timestampExpiry(value)
+	case esv1.ExpiryFormatDuration:
+		return durationExpiry(value, now)
+	default:
+		if expiry, ok := certificateExpiry(value); ok {
+			return expiry, true
+		}
+		return jwtExpiry(value)
+	}
+}
+
+// certificateExpiry returns the earliest notAfter of the PEM encoded certificates.
+func certificateExpiry(value []byte) (time.Time, bool) {
+	var earliest time.Time
+	found := false
+	rest := value
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if !found || cert.NotAfter.Before(earliest) {
+			earliest = cert.NotAfter
+			found = true
+		}
+	}
+	return earliest, found
+}
+
+// jwtExpiry returns the exp claim of a JWT. The signature is not verified, the token is only read.
+func jwtExpiry(value []byte) (time.Time, bool) {
+	parts := bytes.Split(bytes.TrimSpace(value), []byte("."))
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(string(bytes.TrimRight(parts[1], "=")))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(exp), 0), true
+}
+
+// timestampExpiry reads an RFC 3339 time or a Unix time in seconds.
+func timestampExpiry(value []byte) (time.Time, bool) {
+	s := string(bytes.TrimSpace(value))
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	return time.Time{}, false
+}
+
+// durationExpiry reads a lifetime from now, as a Go duration or a number of seconds.
+func durationExpiry(value []byte, now time.Time) (time.Time, bool) {
+	s := string(bytes.TrimSpace(value))
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d), true
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,268 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+func newExpiryTestJWT(exp time.Time) []byte {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"eso","exp":%d}`, exp.Unix())))
+	return []byte(header + "." + payload + ".signature")
+}
+
+func TestFindExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	certExpiry := now.Add(30 * 24 * time.Hour)
+	jwtExpiry := now.Add(time.Hour)
+	data := map[string][]byte{
+		"tls.crt":    newValidationTestCert(t, now.Add(-time.Hour), certExpiry),
+		"token":      newExpiryTestJWT(jwtExpiry),
+		"expires_at": []byte(strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10)),
+		"expiry":     []byte(now.Add(20 * time.Minute).Format(time.RFC3339)),
+		"lease":      []byte("90s"),
+		"password":   []byte("1234567890"),
+	}
+	// the lease of the password, and of a key renamed by a template
+	leaseExpiry := now.Add(5 * time.Minute)
+	reported := map[string]time.Time{
+		"password":    leaseExpiry,
+		"DB_PASSWORD": now.Add(2 * time.Minute),
+	}
+
+	tests := []struct {
+		name     string
+		sources  []esv1.ExternalSecretExpirySource
+		reported map[string]time.Time
+		want     time.Time
+		wantOk   bool
+	}{
+		{
+			name:   "detects certificates and JWTs of every key",
+			want:   jwtExpiry,
+			wantOk: true,
+		},
+		{
+			name:     "uses every reported expiry without sources",
+			reported: reported,
+			want:     now.Add(2 * time.Minute),
+			wantOk:   true,
+		},
+		{
+			name:     "reported expiry",
+			sources:  []esv1.ExternalSecretExpirySource{{Key: "password", Format: esv1.ExpiryFormatReported}},
+			reported: reported,
+			want:     leaseExpiry,
+			wantOk:   true,
+		},
+		{
+			name:     "auto uses the reported expiry of the key",
+			sources:  []esv1.ExternalSecretExpirySource{{Key: "password", Format: esv1.ExpiryFormatAuto}},
+			reported: reported,
+			want:     leaseExpiry,
+			wantOk:   true,
+		},
+		{
+			name:     "auto uses the earliest of the value and the reported expiry",
+			sources:  []esv1.ExternalSecretExpirySource{{Key: "token", Format: esv1.ExpiryFormatAuto}},
+			reported: map[string]time.Time{"token": now.Add(2 * time.Hour)},
+			want:     jwtExpiry,
+			wantOk:   true,
+		},
+		{
+			name:    "reported format does not read the value",
+			sources: []esv1.ExternalSecretExpirySource{{Key: "token", Format: esv1.ExpiryFormatReported}},
+		},
+		{
+			name:    "certificate",
+			sources: []esv1.ExternalSecretExpirySource{{Key: "tls.crt", Format: esv1.ExpiryFormatCertificate}},
+			want:    certExpiry,
+			wantOk:  true,
+		},
+		{
+			name:    "unix timestamp",
+			sources: []esv1.ExternalSecretExpirySource{{Key: "expires_at", Format: esv1.ExpiryFormatTimestamp}},
+			want:    now.Add(10 * time.Minute),
+			wantOk:  true,
+		},
+		{
+			name:    "RFC 3339 timestamp",
+			sources: []esv1.ExternalSecretExpirySource{{Key: "expiry", Format: esv1.ExpiryFormatTimestamp}},
+			want:    now.Add(20 * time.Minute),
+			wantOk:  true,
+		},
+		{
+			name: "earliest of the sources",
+			sources: []esv1.ExternalSecretExpirySource{
+				{Key: "tls.crt", Format: esv1.ExpiryFormatCertificate},
+				{Key: "lease", Format: esv1.ExpiryFormatDuration},
+			},
+			want:   now.Add(90 * time.Second),
+			wantOk: true,
+		},
+		{
+			name:    "numbers are not detected as expiries",
+			sources: []esv1.ExternalSecretExpirySource{{Key: "password", Format: esv1.ExpiryFormatAuto}},
+		},
+		{
+			name:    "missing key",
+			sources: []esv1.ExternalSecretExpirySource{{Key: "missing", Format: esv1.ExpiryFormatTimestamp}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := findExpiry(tt.sources, data, tt.reported, now)
+			require.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetNextRefresh(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := map[string][]byte{"token": newExpiryTestJWT(now.Add(4 * time.Hour))}
+
+	tests := []struct {
+		name       string
+		spec       esv1.ExternalSecretSpec
+		data       map[string][]byte
+		reported   map[string]time.Time
+		wantNext   *time.Time
+		wantExpiry bool
+	}{
+		{
+			name:       "refreshes at the default percentage of the remaining lifetime",
+			spec:       esv1.ExternalSecretSpec{RefreshPolicy: esv1.RefreshPolicyOnExpiry},
+			data:       token,
+			wantNext:   new(now.Add(3 * time.Hour)),
+			wantExpiry: true,
+		},
+		{
+			name: "refreshes at the configured percentage",
+			spec: esv1.ExternalSecretSpec{
+				RefreshPolicy: esv1.RefreshPolicyOnExpiry,
+				ExpiryRefresh: &esv1.ExternalSecretExpiryRefresh{RemainingLifetimePercent: new(int32(50))},
+			},
+			data:       token,
+			wantNext:   new(now.Add(2 * time.Hour)),
+			wantExpiry: true,
+		},
+		{
+			name:       "refreshes before the reported expiry",
+			spec:       esv1.ExternalSecretSpec{RefreshPolicy: esv1.RefreshPolicyOnExpiry},
+			data:       map[string][]byte{"password": []byte("secret")},
+			reported:   map[string]time.Time{"password": now.Add(4 * time.Hour)},
+			wantNext:   new(now.Add(3 * time.Hour)),
+			wantExpiry: true,
+		},
+		{
+			name: "expired credentials are refreshed after the min interval",
+			spec: esv1.ExternalSecretSpec{RefreshPolicy: esv1.RefreshPolicyOnExpiry},
+			data: map[string][]byte{"token": newExpiryTestJWT(now.Add(-time.Hour))},
+			// the default min interval
+			wantNext:   new(now.Add(time.Minute)),
+			wantExpiry: true,
+		},
+		{
+			name: "long-lived credentials are refreshed after the max interval",
+			spec: esv1.ExternalSecretSpec{
+				RefreshPolicy: esv1.RefreshPolicyOnExpiry,
+				ExpiryRefresh: &esv1.ExternalSecretExpiryRefresh{MaxInterval: &metav1.Duration{Duration: time.Hour}},
+			},
+			data:       token,
+			wantNext:   new(now.Add(time.Hour)),
+			wantExpiry: true,
+		},
+		{
+			name: "falls back to the refresh interval without an expiry",
+			spec: esv1.ExternalSecretSpec{
+				RefreshPolicy:   esv1.RefreshPolicyOnExpiry,
+				RefreshInterval: &metav1.Duration{Duration: 6 * time.Hour},
+			},
+			data:     map[string][]byte{"password": []byte("secret")},
+			wantNext: new(now.Add(6 * time.Hour)),
+		},
+		{
+			name: "no refresh without an expiry and a refresh interval",
+			spec: esv1.ExternalSecretSpec{
+				RefreshPolicy:   esv1.RefreshPolicyOnExpiry,
+				RefreshInterval: &metav1.Duration{},
+			},
+			data: map[string][]byte{"password": []byte("secret")},
+		},
+		{
+			name: "other refresh policies are not scheduled",
+			spec: esv1.ExternalSecretSpec{RefreshPolicy: esv1.RefreshPolicyPeriodic},
+			data: token,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reconciler{RequeueInterval: time.Hour}
+			es := &esv1.ExternalSecret{Spec: tt.spec}
+			r.setNextRefresh(es, tt.data, tt.reported, now)
+			assert.Equal(t, tt.wantExpiry, es.Status.ExpiryTime != nil)
+			if tt.wantNext == nil {
+				assert.Nil(t, es.Status.NextRefreshTime)
+				return
+			}
+			require.NotNil(t, es.Status.NextRefreshTime)
+			assert.True(t, tt.wantNext.Equal(es.Status.NextRefreshTime.Time), "want %s, got %s", tt.wantNext, es.Status.NextRefreshTime)
+		})
+	}
+}
+
+func TestExpiryRefreshSchedule(t *testing.T) {
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Generation: 1},
+		Spec:       esv1.ExternalSecretSpec{RefreshPolicy: esv1.RefreshPolicyOnExpiry},
+		Status:     esv1.ExternalSecretStatus{RefreshTime: metav1.Now()},
+	}
+	es.Status.SyncedResourceVersion = ctrlutil.GetResourceVersion(es.ObjectMeta)
+	r := &Reconciler{RequeueInterval: time.Hour}
+
+	// nothing is scheduled without an expiry and a refresh interval
+	assert.False(t, shouldRefresh(es))
+	assert.Equal(t, time.Duration(0), r.getRequeueResult(es).RequeueAfter)
+
+	es.Status.NextRefreshTime = &metav1.Time{Time: time.Now().Add(time.Hour)}
+	assert.False(t, shouldRefresh(es))
+	requeueAfter := r.getRequeueResult(es).RequeueAfter
+	assert.InDelta(t, time.Hour, requeueAfter, float64(time.Minute))
+
+	es.Status.NextRefreshTime = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	assert.True(t, shouldRefresh(es))
+
+	// a spec change is refreshed regardless of the schedule
+	es.Status.NextRefreshTime = &metav1.Time{Time: time.Now().Add(time.Hour)}
+	es.Generation = 2
+	assert.True(t, shouldRefresh(es))
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+const (
+	errStoreFailover   = "%w (fallback stores failed, last error: %v)"
+	eventStoreFailover = "running in degraded mode, served by fallback stores: %s"
+)
+
+// storeCandidates returns the store followed by its fallbacks, in the order they are tried.
+func storeCandidates(ref esv1.SecretStoreRef) []esv1.SecretStoreRef {
+	candidates := make([]esv1.SecretStoreRef, 0, 1+len(ref.Fallbacks))
+	candidates = append(candidates, esv1.SecretStoreRef{Name: ref.Name, Kind: ref.Kind})
+	for _, fallback := range ref.Fallbacks {
+		candidates = append(candidates, esv1.SecretStoreRef{Name: fallback.Name, Kind: fallback.Kind})
+	}
+	return candidates
+}
+
+// shouldFailover returns true if the next store should be tried after the error.
+// A secret missing from a store is not a failure of the store, so it never fails over.
+func shouldFailover(err error) bool {
+	return err != nil && !errors.Is(err, esv1.NoSecretErr) && !errors.Is(err, context.Canceled)
+}
+
+// failoverError returns the error of the primary store, annotated with the error of the last fallback.
+// Only the primary error is wrapped: a secret missing from a fallback must not be
+// mistaken for a secret deleted from the primary store.
+func failoverError(primaryErr, fallbackErr error) error {
+	return fmt.Errorf(errStoreFailover, primaryErr, fallbackErr)
+}
+
+// withFailover fetches from the store, then from its fallbacks in order while the fetch fails over.
+// It returns the store which served the value.
+func withFailover[T any](ctx context.Context, cmgr *secretstore.Manager, namespace string, storeRef esv1.SecretStoreRef, fetch func(esv1.SecretsClient) (T, error)) (T, esv1.SecretStoreRef, error) {
+	var zero T
+	candidates := storeCandidates(storeRef)
+	var primaryErr error
+	for i, candidate := range candidates {
+		client, err := cmgr.Get(ctx, candidate, namespace, nil)
+		if err == nil {
+			var value T
+			if value, err = fetch(client); err == nil {
+				return value, candidate, nil
+			}
+		}
+		if i == 0 {
+			if !shouldFailover(err) {
+				return zero, candidate, err
+			}
+			primaryErr = err
+			continue
+		}
+		if !shouldFailover(err) || i == len(candidates)-1 {
+			return zero, candidate, failoverError(primaryErr, err)
+		}
+	}
+	return zero, candidates[0], primaryErr
+}
+
+// dataStoreRef returns the store of a spec.data entry.
+func dataStoreRef(es *esv1.ExternalSecret, secretRef esv1.ExternalSecretData) esv1.SecretStoreRef {
+	if secretRef.SourceRef != nil {
+		return secretRef.SourceRef.SecretStoreRef
+	}
+	return es.Spec.SecretStoreRef
+}
+
+// dataFromStoreRef returns the store of a spec.dataFrom entry.
+func dataFromStoreRef(es *esv1.ExternalSecret, remoteRef esv1.ExternalSecretDataFromRemoteRef) esv1.SecretStoreRef {
+	if remoteRef.SourceRef != nil && remoteRef.SourceRef.SecretStoreRef != nil {
+		return *remoteRef.SourceRef.SecretStoreRef
+	}
+	return es.Spec.SecretStoreRef
+}
+
+// newServedBy records the store which served an entry, if the store of the entry has fallbacks.
+func newServedBy(servedBy []esv1.ExternalSecretServedBy, key string, storeRef, served esv1.SecretStoreRef) []esv1.ExternalSecretServedBy {
+	if len(storeRef.Fallbacks) == 0 || served.Name == "" {
+		return servedBy
+	}
+	return append(servedBy, esv1.ExternalSecretServedBy{
+		Key:       key,
+		StoreName: served.Name,
+		StoreKind: served.Kind,
+		Fallback:  served.Name != storeRef.Name || served.Kind != storeRef.Kind,
+	})
+}
+
+// setServedBy records the stores which served the entries in the status,
+// and emits an event when any entry was served by a fallback store.
+func (r *Reconciler) setServedBy(es *esv1.ExternalSecret, servedBy []esv1.ExternalSecretServedBy) {
+	es.Status.ServedBy = servedBy
+
+	var degraded []string
+	for _, served := range servedBy {
+		if !served.Fallback {
+			continue
+		}
+		kind := served.StoreKind
+		if kind == "" {
+			kind = esv1.SecretStoreKind
+		}
+		degraded = append(degraded, fmt.Sprintf("%s from %s %q", served.Key, kind, served.StoreName))
+	}
+	if len(degraded) == 0 {
+		return
+	}
+	slices.Sort(degraded)
+	r.recorder.Eventf(es, v1.EventTypeWarning, esv1.ReasonStoreFailover, eventStoreFailover, strings.Join(degraded, ", "))
+}
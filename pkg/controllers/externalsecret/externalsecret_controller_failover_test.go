@@ -0,0 +1,155 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+var errFailoverTestUnavailable = errors.New("connection refused")
+
+// newFailoverTestReconciler returns a reconciler with the dr and backup stores, the fallbacks of the test store.
+// The provider clients of the stores are built by clients, by store name,
+// and building the client of any other store fails like an unreachable backend.
+func newFailoverTestReconciler(t *testing.T, clients map[string]esv1.SecretsClient) *testReconciler {
+	t.Helper()
+	h := newTestReconciler(t, newTestStore("dr"), newTestStore("backup"))
+	fakeProvider.WithNew(func(_ context.Context, store esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		if c, ok := clients[store.GetName()]; ok {
+			return c, nil
+		}
+		return nil, errFailoverTestUnavailable
+	})
+	return h
+}
+
+// newFailoverTestExternalSecret returns the test ExternalSecret, with the dr and backup stores as fallbacks.
+func newFailoverTestExternalSecret() *esv1.ExternalSecret {
+	es := newTestExternalSecret()
+	es.Spec.SecretStoreRef.Fallbacks = []esv1.SecretStoreFallbackRef{
+		{Name: "dr", Kind: esv1.SecretStoreKind},
+		{Name: "backup", Kind: esv1.SecretStoreKind},
+	}
+	return es
+}
+
+func TestFetchSecretDataFailover(t *testing.T) {
+	tests := []struct {
+		name       string
+		clients    map[string]esv1.SecretsClient
+		wantValue  []byte
+		wantServed string
+		wantErr    error
+	}{
+		{
+			name:       "served by the primary store",
+			clients:    map[string]esv1.SecretsClient{testStoreName: fake.New().WithGetSecret([]byte("primary"), nil), "dr": fake.New().WithGetSecret([]byte("dr"), nil)},
+			wantValue:  []byte("primary"),
+			wantServed: testStoreName,
+		},
+		{
+			name:       "fails over to the first available fallback",
+			clients:    map[string]esv1.SecretsClient{"backup": fake.New().WithGetSecret([]byte("backup"), nil)},
+			wantValue:  []byte("backup"),
+			wantServed: "backup",
+		},
+		{
+			name:       "fails over on errors of the provider",
+			clients:    map[string]esv1.SecretsClient{testStoreName: fake.New().WithGetSecret(nil, errFailoverTestUnavailable), "dr": fake.New().WithGetSecret([]byte("dr"), nil)},
+			wantValue:  []byte("dr"),
+			wantServed: "dr",
+		},
+		{
+			name:    "does not fail over when the secret does not exist",
+			clients: map[string]esv1.SecretsClient{testStoreName: fake.New().WithGetSecret(nil, esv1.NoSecretErr), "dr": fake.New().WithGetSecret([]byte("dr"), nil)},
+			wantErr: esv1.NoSecretErr,
+		},
+		{
+			name:    "returns the error of the primary store when the fallbacks fail",
+			clients: map[string]esv1.SecretsClient{"dr": fake.New().WithGetSecret(nil, esv1.NoSecretErr)},
+			wantErr: errFailoverTestUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newFailoverTestReconciler(t, tt.clients)
+			es := newFailoverTestExternalSecret()
+
+			mgr := secretstore.NewManager(h.kube, "", false)
+			defer func() {
+				_ = mgr.Close(context.Background())
+			}()
+
+			results, servedBy := h.r.fetchSecretData(context.Background(), es, mgr)
+			require.Len(t, results, 1)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, results[0].Err, tt.wantErr)
+				if !errors.Is(tt.wantErr, esv1.NoSecretErr) {
+					// a secret missing from a fallback is not a secret deleted from the primary store
+					assert.NotErrorIs(t, results[0].Err, esv1.NoSecretErr)
+				}
+				return
+			}
+			require.NoError(t, results[0].Err)
+			assert.Equal(t, tt.wantValue, results[0].Value)
+			assert.Equal(t, tt.wantServed, servedBy[0].Name)
+		})
+	}
+}
+
+func TestGetProviderSecretDataRecordsServingStores(t *testing.T) {
+	h := newFailoverTestReconciler(t, map[string]esv1.SecretsClient{
+		"dr": fake.New().
+			WithGetSecret([]byte("dr"), nil).
+			WithGetSecretMap(map[string][]byte{"extracted": []byte("dr")}, nil),
+	})
+	es := newFailoverTestExternalSecret()
+	es.Spec.Data = append(es.Spec.Data, esv1.ExternalSecretData{
+		SecretKey: "other",
+		RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "other"},
+		SourceRef: &esv1.StoreSourceRef{SecretStoreRef: esv1.SecretStoreRef{Name: "dr", Kind: esv1.SecretStoreKind}},
+	})
+	es.Spec.DataFrom = []esv1.ExternalSecretDataFromRemoteRef{
+		{Extract: &esv1.ExternalSecretDataRemoteRef{Key: "map"}},
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	h.r.recorder = recorder
+	data, err := h.r.GetProviderSecretData(context.Background(), es)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"foo": []byte("dr"), "other": []byte("dr"), "extracted": []byte("dr")}, data)
+
+	// entries of stores without fallbacks are not recorded
+	assert.Equal(t, []esv1.ExternalSecretServedBy{
+		{Key: "spec.dataFrom[0]", StoreName: "dr", StoreKind: esv1.SecretStoreKind, Fallback: true},
+		{Key: "foo", StoreName: "dr", StoreKind: esv1.SecretStoreKind, Fallback: true},
+	}, es.Status.ServedBy)
+
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, `Warning StoreFailover running in degraded mode, served by fallback stores: foo from SecretStore "dr", spec.dataFrom[0] from SecretStore "dr"`, <-recorder.Events)
+}
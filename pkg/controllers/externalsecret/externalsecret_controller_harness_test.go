@@ -0,0 +1,151 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// testStoreName is the name of the SecretStore added to the client of every testReconciler.
+const testStoreName = "store"
+
+// newTestStore returns a ready SecretStore served by fakeProvider.
+func newTestStore(name string) *esv1.SecretStore {
+	return &esv1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: esv1.SecretStoreSpec{
+			Provider: &esv1.SecretStoreProvider{
+				AWS: &esv1.AWSProvider{Service: esv1.AWSServiceSecretsManager},
+			},
+		},
+		Status: esv1.SecretStoreStatus{
+			Conditions: []esv1.SecretStoreStatusCondition{
+				{Type: esv1.SecretStoreReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+}
+
+// newTestExternalSecret returns an ExternalSecret named es, which reads the key foo
+// from the test store into a Secret it owns, at every reconcile.
+func newTestExternalSecret() *esv1.ExternalSecret {
+	return &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			SecretStoreRef:  esv1.SecretStoreRef{Name: testStoreName, Kind: esv1.SecretStoreKind},
+			RefreshInterval: &metav1.Duration{Duration: time.Nanosecond},
+			Target:          esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyOwner},
+			Data: []esv1.ExternalSecretData{
+				{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "foo"}},
+			},
+		},
+	}
+}
+
+// testReconciler runs a Reconciler against a fake client.
+type testReconciler struct {
+	t    *testing.T
+	r    *Reconciler
+	kube client.Client
+}
+
+// newTestReconciler returns a reconciler on a fake client holding the test store and objs.
+// The fake client sets the UID of the objects it creates, which the controller uses to tell if a target exists.
+// fakeProvider is reset, so it has to be configured afterwards.
+func newTestReconciler(t *testing.T, objs ...client.Object) *testReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(esv1.AddToScheme(scheme))
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append(objs, newTestStore(testStoreName))...).
+		WithStatusSubresource(&esv1.ExternalSecret{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				obj.SetUID(types.UID(obj.GetName()))
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	fakeProvider.Reset()
+	t.Cleanup(fakeProvider.Reset)
+
+	return &testReconciler{
+		t: t,
+		r: &Reconciler{
+			Client:       kube,
+			SecretClient: kube,
+			Log:          logr.Discard(),
+			Scheme:       scheme,
+			recorder:     record.NewFakeRecorder(100),
+		},
+		kube: kube,
+	}
+}
+
+// reconcile reconciles the ExternalSecret, and returns it as stored afterwards.
+func (h *testReconciler) reconcile(es *esv1.ExternalSecret) (ctrl.Result, *esv1.ExternalSecret, error) {
+	h.t.Helper()
+	result, err := h.r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(es)})
+	got := &esv1.ExternalSecret{}
+	require.NoError(h.t, h.kube.Get(context.Background(), client.ObjectKeyFromObject(es), got))
+	return result, got, err
+}
+
+// reconcileValue reconciles the ExternalSecret with fakeProvider returning value for every key,
+// and returns it as stored afterwards. The reconcile must succeed.
+func (h *testReconciler) reconcileValue(es *esv1.ExternalSecret, value string) *esv1.ExternalSecret {
+	h.t.Helper()
+	fakeProvider.WithGetSecret([]byte(value), nil)
+	_, got, err := h.reconcile(es)
+	require.NoError(h.t, err)
+	return got
+}
+
+// secret returns the Secret of the default namespace, or nil if it does not exist.
+func (h *testReconciler) secret(name string) *corev1.Secret {
+	h.t.Helper()
+	secret := &corev1.Secret{}
+	err := h.kube.Get(context.Background(), client.ObjectKey{Name: name, Namespace: "default"}, secret)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	require.NoError(h.t, err)
+	return secret
+}
@@ -0,0 +1,241 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+// defaultHistoryLimit is used when target.history is set without the CRD defaults applied.
+const defaultHistoryLimit = 5
+
+var errUnknownRevision = errors.New("revision is not in status.history")
+
+// recordHistory takes a snapshot of the written secret if its data changed since the newest revision,
+// then removes the snapshots beyond the history limit.
+// A nil secret only prunes the history. Every snapshot is removed when target.history is not set.
+func (r *Reconciler) recordHistory(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, secret *v1.Secret) error {
+	limit := 0
+	if externalSecret.Spec.Target.History != nil {
+		limit = externalSecret.Spec.Target.History.Limit
+		if limit <= 0 {
+			limit = defaultHistoryLimit
+		}
+	}
+
+	history := externalSecret.Status.History
+	if secret != nil && limit > 0 {
+		dataHash := esutils.ObjectHash(secret.Data)
+		if len(history) == 0 || history[0].DataHash != dataHash {
+			revision := int64(1)
+			if len(history) > 0 {
+				revision = history[0].Revision + 1
+			}
+			snapshotName := historySnapshotName(secret.Name, revision)
+			if err := r.createSnapshot(ctx, externalSecret, secret, snapshotName, dataHash); err != nil {
+				return err
+			}
+			log.V(1).Info("created secret snapshot", "secret", snapshotName, "revision", revision)
+
+			history = slices.Insert(slices.Clone(history), 0, esv1.ExternalSecretHistoryEntry{
+				Revision:     revision,
+				SnapshotName: snapshotName,
+				DataHash:     dataHash,
+				CreatedAt:    metav1.Now(),
+			})
+		}
+	}
+
+	if len(history) > limit {
+		for _, entry := range history[limit:] {
+			snapshot := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: entry.SnapshotName, Namespace: externalSecret.Namespace}}
+			if err := r.Delete(ctx, snapshot); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf(errDeleteSnapshot, entry.SnapshotName, err)
+			}
+			log.V(1).Info("deleted secret snapshot", "secret", entry.SnapshotName, "revision", entry.Revision)
+		}
+		history = history[:limit]
+	}
+
+	if len(history) == 0 {
+		history = nil
+	}
+	externalSecret.Status.History = history
+	return nil
+}
+
+// createSnapshot stores an immutable copy of the data of the secret.
+// The snapshot is owned by the ExternalSecret, so it is garbage collected with it,
+// but it does not carry the owner label, so it is not mistaken for an orphaned target.
+func (r *Reconciler) createSnapshot(ctx context.Context, externalSecret *esv1.ExternalSecret, secret *v1.Secret, snapshotName, dataHash string) error {
+	snapshotOf := esutils.ObjectHash(fmt.Sprintf("%v/%v", externalSecret.Namespace, externalSecret.Name))
+	snapshot := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName,
+			Namespace: externalSecret.Namespace,
+			Labels: map[string]string{
+				esv1.LabelSnapshotOf: snapshotOf,
+			},
+			Annotations: map[string]string{
+				esv1.AnnotationDataHash: dataHash,
+			},
+		},
+		Immutable: new(true),
+		Type:      secret.Type,
+		Data:      maps.Clone(secret.Data),
+	}
+	if err := controllerutil.SetOwnerReference(externalSecret, snapshot, r.Scheme); err != nil {
+		return fmt.Errorf(errCreateSnapshot, snapshotName, err)
+	}
+
+	err := r.Create(ctx, snapshot)
+	if apierrors.IsAlreadyExists(err) {
+		// a previous snapshot of this revision was not recorded in the status,
+		// it is immutable so it has to be replaced
+		stale := &v1.Secret{}
+		if err := r.apiReader().Get(ctx, client.ObjectKey{Name: snapshotName, Namespace: externalSecret.Namespace}, stale); err != nil {
+			return fmt.Errorf(errGetSnapshot, snapshotName, err)
+		}
+		// never replace a secret which is not a snapshot of this ExternalSecret
+		if stale.Labels[esv1.LabelSnapshotOf] != snapshotOf {
+			return fmt.Errorf(errSnapshotNotOwned, snapshotName)
+		}
+		if err := r.Delete(ctx, stale, client.Preconditions{UID: &stale.UID}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf(errDeleteSnapshot, snapshotName, err)
+		}
+		snapshot.ResourceVersion = ""
+		err = r.Create(ctx, snapshot)
+	}
+	if err != nil {
+		return fmt.Errorf(errCreateSnapshot, snapshotName, err)
+	}
+	return nil
+}
+
+// getRollbackSnapshot returns the snapshot of the revision set in target.rollbackTo.
+func (r *Reconciler) getRollbackSnapshot(ctx context.Context, externalSecret *esv1.ExternalSecret) (*v1.Secret, error) {
+	revision := *externalSecret.Spec.Target.RollbackTo
+	idx := slices.IndexFunc(externalSecret.Status.History, func(entry esv1.ExternalSecretHistoryEntry) bool {
+		return entry.Revision == revision
+	})
+	if idx < 0 {
+		return nil, fmt.Errorf("%w: %d", errUnknownRevision, revision)
+	}
+	snapshotName := externalSecret.Status.History[idx].SnapshotName
+
+	snapshot := &v1.Secret{}
+	if err := r.apiReader().Get(ctx, client.ObjectKey{Name: snapshotName, Namespace: externalSecret.Namespace}, snapshot); err != nil {
+		return nil, fmt.Errorf(errGetSnapshot, snapshotName, err)
+	}
+	return snapshot, nil
+}
+
+// rollbackMutationFunc returns a function which sets the data of the snapshot on a secret,
+// instead of the rendered provider data.
+// With creationPolicy=Merge or CreateOrMerge, only the keys managed by the ExternalSecret are restored,
+// the keys of other owners keep their current value.
+func (r *Reconciler) rollbackMutationFunc(externalSecret *esv1.ExternalSecret, snapshot *v1.Secret) func(secret *v1.Secret) error {
+	return func(secret *v1.Secret) error {
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+		if secret.Labels == nil {
+			secret.Labels = make(map[string]string)
+		}
+
+		if externalSecret.Spec.Target.Immutable {
+			secret.Immutable = new(true)
+		}
+
+		// the type of an existing secret cannot be changed
+		if secret.GetUID() == "" {
+			secret.Type = snapshot.Type
+		}
+		if secret.GetUID() == "" || !externalSecret.Spec.Target.Immutable {
+			if err := restoreSnapshotData(externalSecret, secret, snapshot); err != nil {
+				return err
+			}
+		}
+
+		if err := r.applyOwnership(externalSecret, secret); err != nil {
+			return err
+		}
+
+		secret.Labels[esv1.LabelManaged] = esv1.LabelManagedValue
+		secret.Annotations[esv1.AnnotationDataHash] = esutils.ObjectHash(secret.Data)
+
+		return nil
+	}
+}
+
+// restoreSnapshotData sets the data of the snapshot on the secret.
+func restoreSnapshotData(externalSecret *esv1.ExternalSecret, secret, snapshot *v1.Secret) error {
+	switch externalSecret.Spec.Target.CreationPolicy {
+	case esv1.CreatePolicyMerge, esv1.CreatePolicyCreateOrMerge:
+		keys, err := getManagedDataKeys(secret, externalSecret.Name)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			delete(secret.Data, key)
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		// the snapshot holds the keys of other owners at the time it was taken, they are not restored
+		for key, value := range snapshot.Data {
+			if _, ok := secret.Data[key]; !ok {
+				secret.Data[key] = value
+			}
+		}
+	default:
+		secret.Data = maps.Clone(snapshot.Data)
+	}
+	return nil
+}
+
+// isRolledBack reports whether the target Secret was already restored to the revision.
+func isRolledBack(externalSecret *esv1.ExternalSecret, revision int64) bool {
+	return externalSecret.Status.RolledBackTo != nil && *externalSecret.Status.RolledBackTo == revision
+}
+
+// historySnapshotName returns the name of the snapshot of a revision of the secret,
+// truncating the secret name if needed.
+func historySnapshotName(secretName string, revision int64) string {
+	suffix := "-" + strconv.FormatInt(revision, 10)
+	if len(secretName)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		secretName = strings.TrimRight(secretName[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.")
+	}
+	return secretName + suffix
+}
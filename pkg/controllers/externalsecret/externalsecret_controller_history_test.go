@@ -0,0 +1,164 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+func TestHistorySnapshotName(t *testing.T) {
+	assert.Equal(t, "foo-3", historySnapshotName("foo", 3))
+
+	long := historySnapshotName(strings.Repeat("a", 250)+".b", 12)
+	assert.Len(t, long, 253)
+	assert.True(t, strings.HasSuffix(long, "a-12"))
+}
+
+func TestReconcileHistoryAndRollback(t *testing.T) {
+	es := newTestExternalSecret()
+	es.Spec.Target.History = &esv1.ExternalSecretHistory{Limit: 2}
+	h := newTestReconciler(t, es)
+	revisions := func(es *esv1.ExternalSecret) []int64 {
+		out := make([]int64, 0, len(es.Status.History))
+		for _, entry := range es.Status.History {
+			out = append(out, entry.Revision)
+		}
+		return out
+	}
+	targetData := func() string {
+		return string(h.secret("es").Data["foo"])
+	}
+
+	got := h.reconcileValue(es, "v1")
+	assert.Equal(t, []int64{1}, revisions(got))
+	snapshot := h.secret("es-1")
+	require.NotNil(t, snapshot)
+	assert.Equal(t, map[string][]byte{"foo": []byte("v1")}, snapshot.Data)
+	assert.True(t, *snapshot.Immutable)
+	assert.NotContains(t, snapshot.Labels, esv1.LabelOwner)
+
+	// unchanged data does not add a revision
+	assert.Equal(t, []int64{1}, revisions(h.reconcileValue(es, "v1")))
+	assert.Equal(t, []int64{2, 1}, revisions(h.reconcileValue(es, "v2")))
+
+	// the oldest snapshot is pruned beyond the limit
+	got = h.reconcileValue(es, "v3")
+	assert.Equal(t, []int64{3, 2}, revisions(got))
+	assert.Nil(t, h.secret("es-1"))
+	assert.Equal(t, "v3", targetData())
+
+	// the target is pinned to the snapshot while rollbackTo is set
+	got.Spec.Target.RollbackTo = new(int64(2))
+	require.NoError(t, h.kube.Update(context.Background(), got))
+	got = h.reconcileValue(es, "v4")
+	assert.Equal(t, "v2", targetData())
+	assert.Equal(t, []int64{3, 2}, revisions(got))
+	cond := esv1.GetExternalSecretCondition(got.Status, esv1.ExternalSecretReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, esv1.ConditionReasonSecretRolledBack, cond.Reason)
+	require.NotNil(t, got.Status.RolledBackTo)
+	assert.Equal(t, int64(2), *got.Status.RolledBackTo)
+
+	// an unknown revision fails without touching the target
+	got.Spec.Target.RollbackTo = new(int64(1))
+	require.NoError(t, h.kube.Update(context.Background(), got))
+	got = h.reconcileValue(es, "v4")
+	assert.Equal(t, "v2", targetData())
+	cond = esv1.GetExternalSecretCondition(got.Status, esv1.ExternalSecretReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionFalse, cond.Status)
+
+	// clearing rollbackTo resumes syncing
+	got.Spec.Target.RollbackTo = nil
+	require.NoError(t, h.kube.Update(context.Background(), got))
+	got = h.reconcileValue(es, "v4")
+	assert.Equal(t, "v4", targetData())
+	assert.Equal(t, []int64{4, 3}, revisions(got))
+	assert.Nil(t, got.Status.RolledBackTo)
+
+	// disabling the history removes the snapshots
+	got.Spec.Target.History = nil
+	require.NoError(t, h.kube.Update(context.Background(), got))
+	got = h.reconcileValue(es, "v4")
+	assert.Empty(t, got.Status.History)
+	assert.Nil(t, h.secret("es-4"))
+}
+
+func TestCreateSnapshotReplacesOnlyOwnSnapshots(t *testing.T) {
+	ctx := context.Background()
+	es := newTestExternalSecret()
+	es.UID = "es"
+	foreign := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es-1", Namespace: "default"},
+		Data:       map[string][]byte{"foo": []byte("foreign")},
+	}
+	stale := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "es-2",
+			Namespace: "default",
+			Labels:    map[string]string{esv1.LabelSnapshotOf: esutils.ObjectHash("default/es")},
+		},
+		Data: map[string][]byte{"foo": []byte("stale")},
+	}
+	h := newTestReconciler(t, foreign, stale)
+	secret := &v1.Secret{Data: map[string][]byte{"foo": []byte("new")}}
+
+	// a secret which is not a snapshot of the ExternalSecret is left untouched
+	err := h.r.createSnapshot(ctx, es, secret, "es-1", "hash")
+	require.ErrorContains(t, err, "it is not a snapshot of this ExternalSecret")
+	assert.Equal(t, foreign.Data, h.secret("es-1").Data)
+
+	// a stale snapshot of the ExternalSecret is replaced
+	require.NoError(t, h.r.createSnapshot(ctx, es, secret, "es-2", "hash"))
+	assert.Equal(t, secret.Data, h.secret("es-2").Data)
+}
+
+func TestRestoreSnapshotData(t *testing.T) {
+	snapshot := &v1.Secret{Data: map[string][]byte{"managed": []byte("old"), "unmanaged": []byte("old")}}
+	secret := func() *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{{
+				Manager:  fqdnFor("es"),
+				FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:managed":{},"f:removed":{}}}`)},
+			}}},
+			Data: map[string][]byte{"managed": []byte("new"), "removed": []byte("new"), "unmanaged": []byte("new")},
+		}
+	}
+
+	// the keys of other owners keep their value when the secret is merged
+	es := &esv1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: "es"}}
+	es.Spec.Target.CreationPolicy = esv1.CreatePolicyMerge
+	merged := secret()
+	require.NoError(t, restoreSnapshotData(es, merged, snapshot))
+	assert.Equal(t, map[string][]byte{"managed": []byte("old"), "unmanaged": []byte("new")}, merged.Data)
+
+	// an owned secret gets the data of the snapshot
+	es.Spec.Target.CreationPolicy = esv1.CreatePolicyOwner
+	owned := secret()
+	require.NoError(t, restoreSnapshotData(es, owned, snapshot))
+	assert.Equal(t, snapshot.Data, owned.Data)
+}
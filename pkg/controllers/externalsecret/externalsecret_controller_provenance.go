@@ -0,0 +1,139 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// maxProvenanceEntries bounds the provenance recorded in the status, to keep its size in check.
+const maxProvenanceEntries = 100
+
+// provenance collects the source of every key of the provider data.
+// Like the provider data, a key set by a later entry replaces the earlier one.
+type provenance map[string]keySource
+
+// keySource is the provenance of a key, along with the expiry reported for its value.
+type keySource struct {
+	esv1.ExternalSecretKeyProvenance
+	// expiry is reported by the provider or recorded in the state of the generator, zero if unknown.
+	expiry time.Time
+}
+
+// addStore records the keys fetched from a store, and the version of their value reported by the provider.
+func (p provenance) addStore(keys []string, store esv1.SecretStoreRef, remoteKey, property string, version esv1.SecretVersion) {
+	kind := store.Kind
+	if kind == "" {
+		kind = esv1.SecretStoreKind
+	}
+	for _, key := range keys {
+		p[key] = keySource{
+			ExternalSecretKeyProvenance: esv1.ExternalSecretKeyProvenance{
+				Key:       key,
+				StoreName: store.Name,
+				StoreKind: kind,
+				RemoteKey: remoteKey,
+				Property:  property,
+				Version:   version.ID,
+			},
+			expiry: version.Expiry,
+		}
+	}
+}
+
+// addGenerator records the keys generated by a generator, and the expiry recorded in its state.
+func (p provenance) addGenerator(keys []string, generator *esv1.GeneratorRef, expiry time.Time) {
+	for _, key := range keys {
+		p[key] = keySource{
+			ExternalSecretKeyProvenance: esv1.ExternalSecretKeyProvenance{
+				Key:           key,
+				GeneratorName: generator.Name,
+				GeneratorKind: generator.Kind,
+			},
+			expiry: expiry,
+		}
+	}
+}
+
+// expiries returns the expiries reported for the keys.
+func (p provenance) expiries() map[string]time.Time {
+	expiries := make(map[string]time.Time)
+	for key, source := range p {
+		if !source.expiry.IsZero() {
+			expiries[key] = source.expiry
+		}
+	}
+	return expiries
+}
+
+// entries returns the provenance sorted by key, bounded to maxProvenanceEntries.
+func (p provenance) entries() []esv1.ExternalSecretKeyProvenance {
+	keys := slices.Sorted(maps.Keys(p))
+	if len(keys) > maxProvenanceEntries {
+		keys = keys[:maxProvenanceEntries]
+	}
+	entries := make([]esv1.ExternalSecretKeyProvenance, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, p[key].ExternalSecretKeyProvenance)
+	}
+	return entries
+}
+
+// setProvenance records the provenance of the synced keys in the status.
+// A key changed when its source or its version changed, or when its value in the target changed.
+// Keys renamed by a template are compared through the whole data of the target.
+// oldData and newData are nil when the values of the target are not known.
+func setProvenance(es *esv1.ExternalSecret, p provenance, oldData, newData map[string][]byte, now time.Time) {
+	previous := make(map[string]esv1.ExternalSecretKeyProvenance, len(es.Status.Provenance))
+	for _, entry := range es.Status.Provenance {
+		previous[entry.Key] = entry
+	}
+	dataChanged := !maps.EqualFunc(oldData, newData, bytes.Equal)
+
+	entries := p.entries()
+	for i := range entries {
+		entry := &entries[i]
+		prev, ok := previous[entry.Key]
+		changed := !ok || prev.LastChangeTime == nil || !sameProvenance(prev, *entry)
+		if !changed {
+			if value, inTarget := newData[entry.Key]; inTarget {
+				changed = !bytes.Equal(oldData[entry.Key], value)
+			} else {
+				changed = dataChanged
+			}
+		}
+		if changed {
+			entry.LastChangeTime = &metav1.Time{Time: now}
+		} else {
+			entry.LastChangeTime = prev.LastChangeTime
+		}
+	}
+	es.Status.Provenance = entries
+}
+
+// sameProvenance returns true if both entries have the same source and version.
+func sameProvenance(a, b esv1.ExternalSecretKeyProvenance) bool {
+	a.LastChangeTime, b.LastChangeTime = nil, nil
+	return a == b
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+// versionedClient reports the key of every secret as its version, leased until versionedExpiry.
+type versionedClient struct {
+	*fake.Client
+}
+
+var versionedExpiry = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (c *versionedClient) GetSecretVersion(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, esv1.SecretVersion, error) {
+	value, err := c.GetSecret(ctx, ref)
+	return value, esv1.SecretVersion{ID: "v-" + ref.Key, Expiry: versionedExpiry}, err
+}
+
+func TestGetProviderSecretDataProvenance(t *testing.T) {
+	h := newTestReconciler(t, newTestStore("versioned"), newTestStore("plain"))
+	fakeProvider.WithNew(func(_ context.Context, store esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		if store.GetName() == "versioned" {
+			return &versionedClient{Client: fake.New().WithGetSecret([]byte("value"), nil)}, nil
+		}
+		return fake.New().WithGetSecretMap(map[string][]byte{"user": []byte("admin"), "password": []byte("value")}, nil), nil
+	})
+
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			SecretStoreRef: esv1.SecretStoreRef{Name: "versioned"},
+			DataFrom: []esv1.ExternalSecretDataFromRemoteRef{
+				{
+					Extract:   &esv1.ExternalSecretDataRemoteRef{Key: "database"},
+					SourceRef: &esv1.StoreGeneratorSourceRef{SecretStoreRef: &esv1.SecretStoreRef{Name: "plain", Kind: esv1.SecretStoreKind}},
+				},
+			},
+			Data: []esv1.ExternalSecretData{
+				// replaces the key extracted from the plain store
+				{SecretKey: "password", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db-password", Property: "value"}},
+			},
+		},
+	}
+
+	data, keyProvenance, err := h.r.getProviderSecretData(context.Background(), es)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"user": []byte("admin"), "password": []byte("value")}, data)
+	assert.Equal(t, []esv1.ExternalSecretKeyProvenance{
+		{Key: "password", StoreName: "versioned", StoreKind: esv1.SecretStoreKind, RemoteKey: "db-password", Property: "value", Version: "v-db-password"},
+		{Key: "user", StoreName: "plain", StoreKind: esv1.SecretStoreKind, RemoteKey: "database"},
+	}, keyProvenance.entries())
+	assert.Equal(t, map[string]time.Time{"password": versionedExpiry}, keyProvenance.expiries())
+}
+
+func TestProvenanceIsBounded(t *testing.T) {
+	p := make(provenance)
+	keys := make([]string, 0, maxProvenanceEntries+10)
+	for i := range maxProvenanceEntries + 10 {
+		keys = append(keys, fmt.Sprintf("key-%03d", i))
+	}
+	p.addGenerator(keys, &esv1.GeneratorRef{Kind: "Password", Name: "pw"}, time.Time{})
+
+	entries := p.entries()
+	require.Len(t, entries, maxProvenanceEntries)
+	assert.Equal(t, "key-000", entries[0].Key)
+	assert.Equal(t, esv1.ExternalSecretKeyProvenance{Key: "key-099", GeneratorName: "pw", GeneratorKind: "Password"}, entries[maxProvenanceEntries-1])
+}
+
+func TestSetProvenance(t *testing.T) {
+	before := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := before.Add(time.Hour)
+	store := esv1.SecretStoreRef{Name: "vault", Kind: esv1.ClusterSecretStoreKind}
+	previous := []esv1.ExternalSecretKeyProvenance{
+		{Key: "password", StoreName: "vault", StoreKind: esv1.ClusterSecretStoreKind, RemoteKey: "db", Version: "1", LastChangeTime: &metav1.Time{Time: before}},
+	}
+
+	tests := []struct {
+		name        string
+		version     string
+		remoteKey   string
+		oldData     map[string][]byte
+		newData     map[string][]byte
+		wantChanged bool
+	}{
+		{
+			name:      "unchanged value",
+			version:   "1",
+			remoteKey: "db",
+			oldData:   map[string][]byte{"password": []byte("a")},
+			newData:   map[string][]byte{"password": []byte("a")},
+		},
+		{
+			name:        "changed value",
+			version:     "1",
+			remoteKey:   "db",
+			oldData:     map[string][]byte{"password": []byte("a")},
+			newData:     map[string][]byte{"password": []byte("b")},
+			wantChanged: true,
+		},
+		{
+			name:        "changed version",
+			version:     "2",
+			remoteKey:   "db",
+			wantChanged: true,
+		},
+		{
+			name:        "changed source",
+			version:     "1",
+			remoteKey:   "other-db",
+			wantChanged: true,
+		},
+		{
+			name:      "key renamed by a template, unchanged target",
+			version:   "1",
+			remoteKey: "db",
+			oldData:   map[string][]byte{"DB_PASSWORD": []byte("a")},
+			newData:   map[string][]byte{"DB_PASSWORD": []byte("a")},
+		},
+		{
+			name:        "key renamed by a template, changed target",
+			version:     "1",
+			remoteKey:   "db",
+			oldData:     map[string][]byte{"DB_PASSWORD": []byte("a")},
+			newData:     map[string][]byte{"DB_PASSWORD": []byte("b")},
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &esv1.ExternalSecret{Status: esv1.ExternalSecretStatus{Provenance: previous}}
+			p := make(provenance)
+			p.addStore([]string{"password"}, store, tt.remoteKey, "", esv1.SecretVersion{ID: tt.version})
+
+			setProvenance(es, p, tt.oldData, tt.newData, now)
+			require.Len(t, es.Status.Provenance, 1)
+			want := before
+			if tt.wantChanged {
+				want = now
+			}
+			assert.True(t, want.Equal(es.Status.Provenance[0].LastChangeTime.Time), "want %s, got %s", want, es.Status.Provenance[0].LastChangeTime)
+		})
+	}
+
+	// new keys are recorded with the time of the sync
+	es := &esv1.ExternalSecret{}
+	p := make(provenance)
+	p.addStore([]string{"password"}, esv1.SecretStoreRef{Name: "vault"}, "db", "", esv1.SecretVersion{})
+	setProvenance(es, p, nil, nil, now)
+	require.Len(t, es.Status.Provenance, 1)
+	assert.Equal(t, esv1.SecretStoreKind, es.Status.Provenance[0].StoreKind)
+	assert.True(t, now.Equal(es.Status.Provenance[0].LastChangeTime.Time))
+}
@@ -0,0 +1,228 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+// RenderSecret returns the Secret the controller would create for the ExternalSecret,
+// without writing anything to the cluster.
+// The Secret is rendered and validated by the same code as the controller, so a template or
+// target.validation error fails the render. With target.versioning, the Secret is named after
+// the generation the controller would write.
+// The stores, and any object they or the template reference, are read through kube.
+// Ownership and generator state are not applied, and events are discarded.
+func RenderSecret(ctx context.Context, kube client.Client, scheme *runtime.Scheme, log logr.Logger, es *esv1.ExternalSecret) (*v1.Secret, error) {
+	r := &Reconciler{
+		Client:                    kube,
+		Log:                       log,
+		Scheme:                    scheme,
+		ClusterSecretStoreEnabled: true,
+		recorder:                  &record.FakeRecorder{},
+	}
+
+	dataMap, _, err := r.getProviderSecretData(ctx, es)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msgErrorGetSecretData, err)
+	}
+
+	secret := &v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getTargetName(es),
+			Namespace: es.Namespace,
+		},
+	}
+	if err := r.renderSecretFunc(ctx, es, dataMap)(secret); err != nil {
+		return nil, err
+	}
+	if es.Spec.Target.Versioning != nil {
+		secret.Name = secretGenerationName(secret.Name, secret.Annotations[esv1.AnnotationDataHash])
+		secret.Immutable = new(true)
+	}
+
+	return secret, nil
+}
+
+// targetData is the data the Secrets of an ExternalSecret are rendered from.
+type targetData struct {
+	dataMap       map[string][]byte
+	keyProvenance provenance
+	// snapshot is the snapshot of target.rollbackTo the Secret of spec.target is pinned to, if any.
+	// The Secrets of spec.targets are not pinned by a rollback.
+	snapshot *v1.Secret
+}
+
+// isEmpty returns true if the provider returned no data, so the deletion policy of the target applies.
+func (d *targetData) isEmpty() bool {
+	return d.snapshot == nil && len(d.dataMap) == 0
+}
+
+// getTargetData returns the data of the snapshot of target.rollbackTo while it is set,
+// and the data of the provider otherwise.
+func (r *Reconciler) getTargetData(ctx context.Context, externalSecret *esv1.ExternalSecret) (*targetData, error) {
+	if externalSecret.Spec.Target.RollbackTo != nil {
+		snapshot, err := r.getRollbackSnapshot(ctx, externalSecret)
+		if err != nil {
+			return nil, err
+		}
+		return &targetData{snapshot: snapshot}, nil
+	}
+
+	dataMap, keyProvenance, err := r.getProviderSecretData(ctx, externalSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &targetData{dataMap: dataMap, keyProvenance: keyProvenance}, nil
+}
+
+// handleTargetDataError marks the ExternalSecret as failed when the data of its Secrets could not be read.
+func (r *Reconciler) handleTargetDataError(externalSecret *esv1.ExternalSecret, err error, counter prometheus.Counter) (ctrl.Result, error) {
+	if externalSecret.Spec.Target.RollbackTo == nil {
+		r.markAsFailed(msgErrorGetSecretData, err, externalSecret, counter, esv1.ConditionReasonSecretSyncedError)
+		return ctrl.Result{}, err
+	}
+
+	r.markAsFailed(msgErrorRollback, ctrlutil.Safe(err), externalSecret, counter, esv1.ConditionReasonSecretSyncedError)
+	// a missing revision cant be fixed by retrying
+	if apierrors.IsNotFound(err) || errors.Is(err, errUnknownRevision) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, err
+}
+
+// targetMutationFunc returns the function writing the Secret of spec.target from the data,
+// keeping the previous values of the keys rotated by target.rotation.
+// The rotated keys are passed to done once the secret was mutated.
+func (r *Reconciler) targetMutationFunc(ctx context.Context, externalSecret *esv1.ExternalSecret, existingSecret *v1.Secret, data *targetData,
+	kept map[string][]byte, now time.Time, done func([]esv1.ExternalSecretRotatedKey, map[string][]byte)) func(secret *v1.Secret) error {
+	mutationFunc := r.secretMutationFunc(ctx, externalSecret, data.dataMap)
+	if data.snapshot != nil {
+		mutationFunc = r.rollbackMutationFunc(externalSecret, data.snapshot)
+	}
+	return rotationMutationFunc(externalSecret, existingSecret, kept, now, mutationFunc, done)
+}
+
+// desiredSecret returns the secret as a reconcile would leave it, or nil if there would be no secret afterwards.
+// empty is true when there is no data, so the deletion policy applies. With target.versioning, the secret
+// is the generation which would be written for the target. The existing secret is never modified.
+func desiredSecret(externalSecret *esv1.ExternalSecret, existingSecret *v1.Secret, targetName string, empty bool,
+	mutationFunc func(secret *v1.Secret) error) (*v1.Secret, error) {
+	exists := existingSecret.UID != ""
+	unchanged := existingSecret
+	if !exists {
+		unchanged = nil
+	}
+
+	creationPolicy := externalSecret.Spec.Target.CreationPolicy
+	if empty {
+		switch externalSecret.Spec.Target.DeletionPolicy {
+		case esv1.DeletionPolicyDelete:
+			if creationPolicy != esv1.CreatePolicyOwner {
+				return nil, ctrlutil.Safe(fmt.Errorf(errDeleteCreatePolicy, targetName, creationPolicy))
+			}
+			return nil, nil
+		case esv1.DeletionPolicyRetain:
+			return unchanged, nil
+		case esv1.DeletionPolicyMerge:
+		}
+	}
+
+	switch creationPolicy {
+	case esv1.CreatePolicyNone:
+		return unchanged, nil
+	case esv1.CreatePolicyMerge:
+		if !exists {
+			return nil, nil
+		}
+	case esv1.CreatePolicyOwner, esv1.CreatePolicyOrphan, esv1.CreatePolicyCreateOrMerge:
+	}
+
+	// a generation is rendered from scratch and named after its data, like writeSecretGeneration does
+	if externalSecret.Spec.Target.Versioning != nil && creationPolicy == esv1.CreatePolicyOwner {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        targetName,
+				Namespace:   externalSecret.Namespace,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+			Data: make(map[string][]byte),
+		}
+		if err := mutationFunc(secret); err != nil {
+			return nil, err
+		}
+		secret.Name = secretGenerationName(targetName, secret.Annotations[esv1.AnnotationDataHash])
+		secret.Immutable = new(true)
+		return secret, nil
+	}
+
+	secret := existingSecret.DeepCopy()
+	if !exists {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      targetName,
+				Namespace: externalSecret.Namespace,
+			},
+		}
+	}
+	if err := mutationFunc(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// desiredSplitSecrets returns the Secrets of spec.targets as a reconcile would leave them, in the same order.
+// They are left as they are while the Secret of spec.target is pinned by a rollback.
+func (r *Reconciler) desiredSplitSecrets(ctx context.Context, externalSecret *esv1.ExternalSecret, existingSecrets []*v1.Secret, data *targetData) ([]*v1.Secret, error) {
+	desired := make([]*v1.Secret, len(externalSecret.Spec.Targets))
+	for i, target := range externalSecret.Spec.Targets {
+		if data.snapshot != nil {
+			if existingSecrets[i].UID != "" {
+				desired[i] = existingSecrets[i]
+			}
+			continue
+		}
+
+		selected, err := selectSplitTargetData(data.dataMap, target.Selector)
+		if err == nil {
+			split := splitTargetExternalSecret(externalSecret, target)
+			desired[i], err = desiredSecret(split, existingSecrets[i], target.Name, len(selected) == 0, r.secretMutationFunc(ctx, split, selected))
+		}
+		if err != nil {
+			return nil, fmt.Errorf(errSplitTarget, i, err)
+		}
+	}
+	return desired, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+func TestRenderSecret(t *testing.T) {
+	h := newTestReconciler(t)
+	fakeProvider.WithGetSecret([]byte("bar"), nil)
+	fakeProvider.WithGetSecretMap(map[string][]byte{"username": []byte("admin")}, nil)
+
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			SecretStoreRef: esv1.SecretStoreRef{Name: testStoreName, Kind: esv1.SecretStoreKind},
+			Target: esv1.ExternalSecretTarget{
+				Name: "target",
+				Template: &esv1.ExternalSecretTemplate{
+					EngineVersion: esv1.TemplateEngineV2,
+					MergePolicy:   esv1.MergePolicyMerge,
+					Data: map[string]string{
+						"greeting": "hello {{ .username }}",
+					},
+				},
+			},
+			Data: []esv1.ExternalSecretData{
+				{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "foo"}},
+			},
+			DataFrom: []esv1.ExternalSecretDataFromRemoteRef{
+				{Extract: &esv1.ExternalSecretDataRemoteRef{Key: "credentials"}},
+			},
+		},
+	}
+
+	secret, err := RenderSecret(context.Background(), h.kube, h.r.Scheme, logr.Discard(), es)
+	require.NoError(t, err)
+
+	assert.Equal(t, "target", secret.Name)
+	assert.Equal(t, "default", secret.Namespace)
+	assert.Equal(t, map[string][]byte{
+		"foo":      []byte("bar"),
+		"username": []byte("admin"),
+		"greeting": []byte("hello admin"),
+	}, secret.Data)
+	assert.Equal(t, esv1.LabelManagedValue, secret.Labels[esv1.LabelManaged])
+	assert.Equal(t, esutils.ObjectHash(secret.Data), secret.Annotations[esv1.AnnotationDataHash])
+}
+
+func TestRenderSecretLikeTheController(t *testing.T) {
+	h := newTestReconciler(t)
+	fakeProvider.WithGetSecret([]byte("bar"), nil)
+
+	newES := func(target esv1.ExternalSecretTarget) *esv1.ExternalSecret {
+		es := newTestExternalSecret()
+		es.Spec.Target = target
+		return es
+	}
+
+	// the rendered data is validated with target.validation
+	_, err := RenderSecret(context.Background(), h.kube, h.r.Scheme, logr.Discard(), newES(esv1.ExternalSecretTarget{
+		Validation: []esv1.ExternalSecretKeyValidation{{Key: "foo", MinLength: new(8)}},
+	}))
+	require.ErrorIs(t, err, ErrSecretValidationFailed)
+
+	// the secret is named after the generation written with target.versioning
+	secret, err := RenderSecret(context.Background(), h.kube, h.r.Scheme, logr.Discard(), newES(esv1.ExternalSecretTarget{
+		Name:       "target",
+		Versioning: &esv1.ExternalSecretVersioning{},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, secretGenerationName("target", esutils.ObjectHash(secret.Data)), secret.Name)
+	assert.True(t, *secret.Immutable)
+	assert.Empty(t, secret.OwnerReferences)
+}
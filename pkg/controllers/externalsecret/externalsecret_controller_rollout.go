@@ -0,0 +1,215 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// rolloutRestart restarts the workloads in target.rolloutRestart if the data hash of the secret changed
+// since they were last restarted. Newly created secrets do not restart any workload.
+func (r *Reconciler) rolloutRestart(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, existingSecret, writtenSecret *v1.Secret) error {
+	if externalSecret.Spec.Target.RolloutRestart == nil {
+		externalSecret.Status.RolloutRestart = nil
+		return nil
+	}
+
+	dataHash := writtenSecret.Annotations[esv1.AnnotationDataHash]
+	previousHash := existingSecret.Annotations[esv1.AnnotationDataHash]
+	if externalSecret.Status.RolloutRestart != nil {
+		previousHash = externalSecret.Status.RolloutRestart.DataHash
+	}
+	if previousHash == dataHash {
+		return nil
+	}
+
+	status := &esv1.ExternalSecretRolloutRestartStatus{DataHash: dataHash}
+	if previousHash == "" {
+		externalSecret.Status.RolloutRestart = status
+		return nil
+	}
+
+	workloads, err := r.rolloutWorkloads(ctx, externalSecret)
+	if err != nil {
+		return err
+	}
+
+	// like `kubectl rollout restart`, a change of the pod template triggers a rollout
+	restartedAt := metav1.Now()
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{
+						esv1.AnnotationRestartedAt: restartedAt.Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, workload := range workloads {
+		ref := workloadRef(workload)
+		err := r.Patch(ctx, workload, client.RawPatch(types.MergePatchType, patch))
+		if apierrors.IsNotFound(err) {
+			r.recorder.Eventf(externalSecret, v1.EventTypeWarning, esv1.ReasonUpdateFailed, eventWorkloadNotFound, ref)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf(errRestartWorkload, ref, err)
+		}
+		log.V(1).Info("restarted workload", "workload", ref)
+		status.Workloads = append(status.Workloads, ref)
+	}
+
+	status.RestartedAt = &restartedAt
+	if len(status.Workloads) > 0 {
+		r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonRolloutRestarted, eventRolloutRestarted, strings.Join(status.Workloads, ", "))
+	}
+	externalSecret.Status.RolloutRestart = status
+	return nil
+}
+
+// rolloutWorkloads returns the workloads referenced by name, followed by the workloads matching the selector.
+func (r *Reconciler) rolloutWorkloads(ctx context.Context, externalSecret *esv1.ExternalSecret) ([]client.Object, error) {
+	spec := externalSecret.Spec.Target.RolloutRestart
+	seen := make(map[string]bool)
+	var workloads []client.Object
+	add := func(workload client.Object) {
+		if ref := workloadRef(workload); !seen[ref] {
+			seen[ref] = true
+			workloads = append(workloads, workload)
+		}
+	}
+
+	for _, ref := range spec.Workloads {
+		workload, err := newWorkload(ref.Kind)
+		if err != nil {
+			return nil, err
+		}
+		workload.SetName(ref.Name)
+		workload.SetNamespace(externalSecret.Namespace)
+		add(workload)
+	}
+
+	selected, err := r.selectWorkloads(ctx, externalSecret.Namespace, spec.Selector,
+		esv1.WorkloadKindDeployment, esv1.WorkloadKindStatefulSet, esv1.WorkloadKindDaemonSet)
+	if err != nil {
+		return nil, err
+	}
+	for _, workload := range selected {
+		add(workload)
+	}
+	return workloads, nil
+}
+
+// selectWorkloads returns the workloads of the given kinds matching the label selector, if any.
+func (r *Reconciler) selectWorkloads(ctx context.Context, namespace string, labelSelector *metav1.LabelSelector, kinds ...esv1.ExternalSecretWorkloadKind) ([]client.Object, error) {
+	if labelSelector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []client.ListOption{client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}}
+
+	var workloads []client.Object
+	for _, kind := range kinds {
+		list, err := newWorkloadList(kind)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.apiReader().List(ctx, list, opts...); err != nil {
+			return nil, fmt.Errorf(errListWorkloads, kind, err)
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, fmt.Errorf(errListWorkloads, kind, err)
+		}
+		for _, item := range items {
+			workloads = append(workloads, item.(client.Object))
+		}
+	}
+	return workloads, nil
+}
+
+func newWorkload(kind esv1.ExternalSecretWorkloadKind) (client.Object, error) {
+	switch kind {
+	case esv1.WorkloadKindDeployment:
+		return &appsv1.Deployment{}, nil
+	case esv1.WorkloadKindStatefulSet:
+		return &appsv1.StatefulSet{}, nil
+	case esv1.WorkloadKindDaemonSet:
+		return &appsv1.DaemonSet{}, nil
+	case esv1.WorkloadKindCronJob:
+		return &batchv1.CronJob{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+}
+
+func newWorkloadList(kind esv1.ExternalSecretWorkloadKind) (client.ObjectList, error) {
+	switch kind {
+	case esv1.WorkloadKindDeployment:
+		return &appsv1.DeploymentList{}, nil
+	case esv1.WorkloadKindStatefulSet:
+		return &appsv1.StatefulSetList{}, nil
+	case esv1.WorkloadKindDaemonSet:
+		return &appsv1.DaemonSetList{}, nil
+	case esv1.WorkloadKindCronJob:
+		return &batchv1.CronJobList{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+}
+
+// workloadRef returns the kind/name reference of a workload.
+func workloadRef(workload client.Object) string {
+	var kind esv1.ExternalSecretWorkloadKind
+	switch workload.(type) {
+	case *appsv1.Deployment:
+		kind = esv1.WorkloadKindDeployment
+	case *appsv1.StatefulSet:
+		kind = esv1.WorkloadKindStatefulSet
+	case *appsv1.DaemonSet:
+		kind = esv1.WorkloadKindDaemonSet
+	case *batchv1.CronJob:
+		kind = esv1.WorkloadKindCronJob
+	}
+	return fmt.Sprintf("%s/%s", kind, workload.GetName())
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestReconcileRolloutRestart(t *testing.T) {
+	es := newTestExternalSecret()
+	es.Spec.Target.RolloutRestart = &esv1.ExternalSecretRolloutRestart{
+		Workloads: []esv1.ExternalSecretWorkloadRef{
+			{Kind: esv1.WorkloadKindDeployment, Name: "app"},
+			{Kind: esv1.WorkloadKindDeployment, Name: "missing"},
+		},
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"uses": "es"}},
+	}
+	app := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Labels: map[string]string{"uses": "es"}}}
+	db := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", Labels: map[string]string{"uses": "es"}}}
+	agent := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"}}
+	h := newTestReconciler(t, es, app, db, agent)
+	restartedAt := func(obj client.Object, template *metav1.ObjectMeta) string {
+		t.Helper()
+		require.NoError(t, h.kube.Get(context.Background(), client.ObjectKeyFromObject(obj), obj))
+		return template.Annotations[esv1.AnnotationRestartedAt]
+	}
+
+	// creating the secret does not restart anything
+	got := h.reconcileValue(es, "v1")
+	require.NotNil(t, got.Status.RolloutRestart)
+	assert.Nil(t, got.Status.RolloutRestart.RestartedAt)
+	assert.Empty(t, restartedAt(app, &app.Spec.Template.ObjectMeta))
+
+	got = h.reconcileValue(es, "v2")
+	require.NotNil(t, got.Status.RolloutRestart.RestartedAt)
+	assert.Equal(t, []string{"Deployment/app", "StatefulSet/db"}, got.Status.RolloutRestart.Workloads)
+	assert.NotEmpty(t, restartedAt(app, &app.Spec.Template.ObjectMeta))
+	assert.NotEmpty(t, restartedAt(db, &db.Spec.Template.ObjectMeta))
+	assert.Empty(t, restartedAt(agent, &agent.Spec.Template.ObjectMeta))
+
+	// unchanged data does not restart again
+	first := *got.Status.RolloutRestart
+	got = h.reconcileValue(es, "v2")
+	assert.Equal(t, first, *got.Status.RolloutRestart)
+}
@@ -0,0 +1,280 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+// defaultRotationKeySuffix names the companion keys when target.rotation.keySuffix is not set.
+const defaultRotationKeySuffix = "_previous"
+
+// rotationKeySuffix returns the suffix of the companion keys holding the previous values.
+func rotationKeySuffix(rotation *esv1.ExternalSecretRotation) string {
+	if rotation.KeySuffix == "" {
+		return defaultRotationKeySuffix
+	}
+	return rotation.KeySuffix
+}
+
+// rotateKeys returns the keys whose previous value is kept once the data of the secret changed from oldData to newData,
+// sorted by key, along with their previous values. A key which changed starts a new overlap period,
+// and the keys rotated before are kept until the end of their overlap period.
+// kept holds the previous values of the keys listed in the status.
+func rotateKeys(es *esv1.ExternalSecret, kept, oldData, newData map[string][]byte, now time.Time) ([]esv1.ExternalSecretRotatedKey, map[string][]byte) {
+	rotation := es.Spec.Target.Rotation
+	if rotation == nil {
+		return nil, nil
+	}
+	suffix := rotationKeySuffix(rotation)
+	var previousKey func(key string) string
+	if rotation.SecretName == "" {
+		previousKey = func(key string) string { return key + suffix }
+	} else {
+		previousKey = func(string) string { return "" }
+	}
+	previous := make(map[string]esv1.ExternalSecretRotatedKey, len(es.Status.Rotation))
+	for _, entry := range es.Status.Rotation {
+		previous[entry.Key] = entry
+	}
+
+	var rotated []esv1.ExternalSecretRotatedKey
+	values := make(map[string][]byte)
+	for _, key := range slices.Sorted(maps.Keys(newData)) {
+		if len(rotation.Keys) > 0 && !slices.Contains(rotation.Keys, key) {
+			continue
+		}
+		// never replace a key of the secret with a companion key
+		if _, clash := newData[key+suffix]; clash && rotation.SecretName == "" {
+			continue
+		}
+
+		if oldValue, ok := oldData[key]; ok && !bytes.Equal(oldValue, newData[key]) {
+			rotated = append(rotated, esv1.ExternalSecretRotatedKey{
+				Key:         key,
+				PreviousKey: previousKey(key),
+				RotatedAt:   metav1.NewTime(now),
+				ExpiresAt:   metav1.NewTime(now.Add(rotation.OverlapPeriod.Duration)),
+			})
+			values[key] = oldValue
+			continue
+		}
+
+		entry, ok := previous[key]
+		value, isKept := kept[key]
+		if ok && isKept && now.Before(entry.ExpiresAt.Time) {
+			entry.PreviousKey = previousKey(key)
+			rotated = append(rotated, entry)
+			values[key] = value
+		}
+	}
+	return rotated, values
+}
+
+// rotationExpired returns true if a previous value kept in the status must be removed.
+func rotationExpired(es *esv1.ExternalSecret, now time.Time) bool {
+	for _, entry := range es.Status.Rotation {
+		if es.Spec.Target.Rotation == nil || !now.Before(entry.ExpiresAt.Time) {
+			return true
+		}
+	}
+	return false
+}
+
+// getRotationRequeueResult requeues the ExternalSecret when the next previous value expires, if it is before the result.
+func getRotationRequeueResult(es *esv1.ExternalSecret, result ctrl.Result) ctrl.Result {
+	if len(es.Status.Rotation) == 0 || result.Requeue {
+		return result
+	}
+	next := es.Status.Rotation[0].ExpiresAt.Time
+	for _, entry := range es.Status.Rotation[1:] {
+		if entry.ExpiresAt.Before(&metav1.Time{Time: next}) {
+			next = entry.ExpiresAt.Time
+		}
+	}
+	untilExpiry := time.Until(next)
+	if untilExpiry <= 0 {
+		return ctrl.Result{Requeue: true}
+	}
+	if result.RequeueAfter <= 0 || untilExpiry < result.RequeueAfter {
+		return ctrl.Result{RequeueAfter: untilExpiry}
+	}
+	return result
+}
+
+// keptRotationValues returns the previous values of the keys listed in the status,
+// from the companion keys of the secret or from the companion Secret.
+func (r *Reconciler) keptRotationValues(ctx context.Context, es *esv1.ExternalSecret, existingSecret *v1.Secret) (map[string][]byte, error) {
+	rotation := es.Spec.Target.Rotation
+	if rotation == nil || len(es.Status.Rotation) == 0 {
+		return nil, nil
+	}
+
+	var companion *v1.Secret
+	kept := make(map[string][]byte, len(es.Status.Rotation))
+	for _, entry := range es.Status.Rotation {
+		if entry.PreviousKey != "" {
+			if value, ok := existingSecret.Data[entry.PreviousKey]; ok {
+				kept[entry.Key] = value
+			}
+			continue
+		}
+		// a companion Secret which was renamed holds no previous value
+		if rotation.SecretName == "" {
+			continue
+		}
+		if companion == nil {
+			var err error
+			if companion, err = r.getRotationSecret(ctx, es, rotation.SecretName); err != nil {
+				return nil, err
+			}
+		}
+		if value, ok := companion.Data[entry.Key]; ok {
+			kept[entry.Key] = value
+		}
+	}
+	return kept, nil
+}
+
+// rotationMutationFunc wraps a mutation function to keep the previous value of the keys which changed.
+// The rotated keys are passed to done once the secret was mutated.
+func rotationMutationFunc(es *esv1.ExternalSecret, existingSecret *v1.Secret, kept map[string][]byte, now time.Time,
+	mutationFunc func(secret *v1.Secret) error, done func([]esv1.ExternalSecretRotatedKey, map[string][]byte)) func(secret *v1.Secret) error {
+	return func(secret *v1.Secret) error {
+		if err := mutationFunc(secret); err != nil {
+			return err
+		}
+		rotation := es.Spec.Target.Rotation
+		if rotation == nil && len(es.Status.Rotation) == 0 {
+			return nil
+		}
+
+		// the companion keys written before are replaced
+		for _, entry := range es.Status.Rotation {
+			if entry.PreviousKey != "" {
+				delete(secret.Data, entry.PreviousKey)
+			}
+		}
+		rotated, values := rotateKeys(es, kept, existingSecret.Data, secret.Data, now)
+		for _, entry := range rotated {
+			if entry.PreviousKey == "" {
+				continue
+			}
+			if secret.Data == nil {
+				secret.Data = make(map[string][]byte)
+			}
+			secret.Data[entry.PreviousKey] = values[entry.Key]
+		}
+		secret.Annotations[esv1.AnnotationDataHash] = esutils.ObjectHash(secret.Data)
+
+		done(rotated, values)
+		return nil
+	}
+}
+
+// getRotationSecret returns the companion Secret of the ExternalSecret, or an empty secret if it does not exist.
+func (r *Reconciler) getRotationSecret(ctx context.Context, es *esv1.ExternalSecret, name string) (*v1.Secret, error) {
+	secret := &v1.Secret{}
+	err := r.apiReader().Get(ctx, client.ObjectKey{Name: name, Namespace: es.Namespace}, secret)
+	if apierrors.IsNotFound(err) {
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: es.Namespace}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(errGetRotationSecret, name, err)
+	}
+	return secret, nil
+}
+
+// syncRotationSecret writes the previous values to the companion Secret of target.rotation, if any,
+// and deletes the companion Secrets which are no longer used.
+// The companion Secret is kept while it is empty, so that workloads mounting it can start.
+func (r *Reconciler) syncRotationSecret(ctx context.Context, log logr.Logger, es *esv1.ExternalSecret, values map[string][]byte) error {
+	var name string
+	if es.Spec.Target.Rotation != nil {
+		name = es.Spec.Target.Rotation.SecretName
+	}
+	ownerLabel := esutils.ObjectHash(fmt.Sprintf("%v/%v", es.Namespace, es.Name))
+
+	secretListPartial := &metav1.PartialObjectMetadataList{}
+	secretListPartial.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("SecretList"))
+	listOpts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{esv1.LabelRotationOf: ownerLabel}),
+		Namespace:     es.Namespace,
+	}
+	if err := r.List(ctx, secretListPartial, listOpts); err != nil {
+		return err
+	}
+	for _, secretPartial := range secretListPartial.Items {
+		if secretPartial.GetName() == name {
+			continue
+		}
+		if err := r.Delete(ctx, &secretPartial); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf(errDeleteRotationSecret, secretPartial.GetName(), err)
+		}
+		log.V(1).Info("deleted companion secret", "secret", secretPartial.GetName())
+	}
+	if name == "" {
+		return nil
+	}
+
+	companion, err := r.getRotationSecret(ctx, es, name)
+	if err != nil {
+		return err
+	}
+	// never take over a secret which is not a companion secret of this ExternalSecret
+	if companion.ResourceVersion != "" && companion.Labels[esv1.LabelRotationOf] != ownerLabel {
+		return fmt.Errorf(errRotationSecretNotOwned, name)
+	}
+
+	updated := companion.DeepCopy()
+	if updated.Labels == nil {
+		updated.Labels = make(map[string]string)
+	}
+	updated.Labels[esv1.LabelRotationOf] = ownerLabel
+	updated.Data = maps.Clone(values)
+	if err := controllerutil.SetControllerReference(es, updated, r.Scheme); err != nil {
+		return fmt.Errorf(errUpdateRotationSecret, name, err)
+	}
+
+	if companion.ResourceVersion == "" {
+		err = r.Create(ctx, updated)
+	} else if !equality.Semantic.DeepEqual(companion, updated) {
+		err = r.Update(ctx, updated)
+	}
+	if err != nil {
+		return fmt.Errorf(errUpdateRotationSecret, name, err)
+	}
+	return nil
+}
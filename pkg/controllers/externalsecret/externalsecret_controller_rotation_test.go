@@ -0,0 +1,334 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+var rotationTestTime = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newRotationTestExternalSecret(rotation *esv1.ExternalSecretRotation, status ...esv1.ExternalSecretRotatedKey) *esv1.ExternalSecret {
+	es := newTestExternalSecret()
+	es.UID = "es-uid"
+	es.Spec.Target.Rotation = rotation
+	es.Status.Rotation = status
+	return es
+}
+
+func rotatedKey(key, previousKey string, rotatedAt time.Time, overlap time.Duration) esv1.ExternalSecretRotatedKey {
+	return esv1.ExternalSecretRotatedKey{
+		Key:         key,
+		PreviousKey: previousKey,
+		RotatedAt:   metav1.NewTime(rotatedAt),
+		ExpiresAt:   metav1.NewTime(rotatedAt.Add(overlap)),
+	}
+}
+
+func TestRotateKeys(t *testing.T) {
+	overlap := time.Hour
+	rotation := &esv1.ExternalSecretRotation{OverlapPeriod: metav1.Duration{Duration: overlap}}
+	earlier := rotationTestTime.Add(-30 * time.Minute)
+
+	tests := []struct {
+		name       string
+		rotation   *esv1.ExternalSecretRotation
+		status     []esv1.ExternalSecretRotatedKey
+		kept       map[string][]byte
+		oldData    map[string][]byte
+		newData    map[string][]byte
+		wantKeys   []esv1.ExternalSecretRotatedKey
+		wantValues map[string][]byte
+	}{
+		{
+			name:       "changed key",
+			rotation:   rotation,
+			oldData:    map[string][]byte{"password": []byte("old"), "user": []byte("admin")},
+			newData:    map[string][]byte{"password": []byte("new"), "user": []byte("admin")},
+			wantKeys:   []esv1.ExternalSecretRotatedKey{rotatedKey("password", "password_previous", rotationTestTime, overlap)},
+			wantValues: map[string][]byte{"password": []byte("old")},
+		},
+		{
+			name:       "previous value kept until its expiry",
+			rotation:   rotation,
+			status:     []esv1.ExternalSecretRotatedKey{rotatedKey("password", "password_previous", earlier, overlap)},
+			kept:       map[string][]byte{"password": []byte("old")},
+			oldData:    map[string][]byte{"password": []byte("new")},
+			newData:    map[string][]byte{"password": []byte("new")},
+			wantKeys:   []esv1.ExternalSecretRotatedKey{rotatedKey("password", "password_previous", earlier, overlap)},
+			wantValues: map[string][]byte{"password": []byte("old")},
+		},
+		{
+			name:       "expired previous value",
+			rotation:   rotation,
+			status:     []esv1.ExternalSecretRotatedKey{rotatedKey("password", "password_previous", rotationTestTime.Add(-2*overlap), overlap)},
+			kept:       map[string][]byte{"password": []byte("old")},
+			oldData:    map[string][]byte{"password": []byte("new")},
+			newData:    map[string][]byte{"password": []byte("new")},
+			wantValues: map[string][]byte{},
+		},
+		{
+			name:       "rotated again during the overlap period",
+			rotation:   rotation,
+			status:     []esv1.ExternalSecretRotatedKey{rotatedKey("password", "password_previous", earlier, overlap)},
+			kept:       map[string][]byte{"password": []byte("oldest")},
+			oldData:    map[string][]byte{"password": []byte("old")},
+			newData:    map[string][]byte{"password": []byte("new")},
+			wantKeys:   []esv1.ExternalSecretRotatedKey{rotatedKey("password", "password_previous", rotationTestTime, overlap)},
+			wantValues: map[string][]byte{"password": []byte("old")},
+		},
+		{
+			name:       "removed key",
+			rotation:   rotation,
+			status:     []esv1.ExternalSecretRotatedKey{rotatedKey("password", "password_previous", earlier, overlap)},
+			kept:       map[string][]byte{"password": []byte("old")},
+			oldData:    map[string][]byte{"password": []byte("new")},
+			newData:    map[string][]byte{"user": []byte("admin")},
+			wantValues: map[string][]byte{},
+		},
+		{
+			name:       "only the listed keys",
+			rotation:   &esv1.ExternalSecretRotation{OverlapPeriod: rotation.OverlapPeriod, Keys: []string{"user"}},
+			oldData:    map[string][]byte{"password": []byte("old"), "user": []byte("admin")},
+			newData:    map[string][]byte{"password": []byte("new"), "user": []byte("root")},
+			wantKeys:   []esv1.ExternalSecretRotatedKey{rotatedKey("user", "user_previous", rotationTestTime, overlap)},
+			wantValues: map[string][]byte{"user": []byte("admin")},
+		},
+		{
+			name:       "companion key clashing with a key of the secret",
+			rotation:   rotation,
+			oldData:    map[string][]byte{"password": []byte("old")},
+			newData:    map[string][]byte{"password": []byte("new"), "password_previous": []byte("x")},
+			wantValues: map[string][]byte{},
+		},
+		{
+			name:     "companion secret does not clash",
+			rotation: &esv1.ExternalSecretRotation{OverlapPeriod: rotation.OverlapPeriod, SecretName: "companion"},
+			oldData:  map[string][]byte{"password": []byte("old")},
+			newData:  map[string][]byte{"password": []byte("new"), "password_previous": []byte("x")},
+			wantKeys: []esv1.ExternalSecretRotatedKey{rotatedKey("password", "", rotationTestTime, overlap)},
+			wantValues: map[string][]byte{
+				"password": []byte("old"),
+			},
+		},
+		{
+			name:       "new secret",
+			rotation:   rotation,
+			newData:    map[string][]byte{"password": []byte("new")},
+			wantValues: map[string][]byte{},
+		},
+		{
+			name:    "rotation disabled",
+			status:  []esv1.ExternalSecretRotatedKey{rotatedKey("password", "password_previous", earlier, overlap)},
+			oldData: map[string][]byte{"password": []byte("old")},
+			newData: map[string][]byte{"password": []byte("new")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newRotationTestExternalSecret(tt.rotation, tt.status...)
+			keys, values := rotateKeys(es, tt.kept, tt.oldData, tt.newData, rotationTestTime)
+			assert.Equal(t, tt.wantKeys, keys)
+			assert.Equal(t, tt.wantValues, values)
+		})
+	}
+}
+
+func TestRotationExpired(t *testing.T) {
+	rotation := &esv1.ExternalSecretRotation{OverlapPeriod: metav1.Duration{Duration: time.Hour}}
+
+	assert.False(t, rotationExpired(newRotationTestExternalSecret(rotation), rotationTestTime))
+	assert.False(t, rotationExpired(newRotationTestExternalSecret(rotation, rotatedKey("password", "password_previous", rotationTestTime, time.Hour)), rotationTestTime))
+	assert.True(t, rotationExpired(newRotationTestExternalSecret(rotation, rotatedKey("password", "password_previous", rotationTestTime.Add(-time.Hour), time.Hour)), rotationTestTime))
+	// the previous values are removed once target.rotation is unset
+	assert.True(t, rotationExpired(newRotationTestExternalSecret(nil, rotatedKey("password", "password_previous", rotationTestTime, time.Hour)), rotationTestTime))
+}
+
+func TestGetRotationRequeueResult(t *testing.T) {
+	now := time.Now()
+	es := newRotationTestExternalSecret(nil,
+		rotatedKey("password", "password_previous", now, 2*time.Hour),
+		rotatedKey("user", "user_previous", now, 30*time.Minute),
+	)
+
+	result := getRotationRequeueResult(es, ctrl.Result{RequeueAfter: time.Hour})
+	assert.InDelta(t, 30*time.Minute, result.RequeueAfter, float64(time.Minute))
+	result = getRotationRequeueResult(es, ctrl.Result{})
+	assert.InDelta(t, 30*time.Minute, result.RequeueAfter, float64(time.Minute))
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, getRotationRequeueResult(es, ctrl.Result{RequeueAfter: time.Minute}))
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, getRotationRequeueResult(newRotationTestExternalSecret(nil), ctrl.Result{RequeueAfter: time.Minute}))
+
+	expired := newRotationTestExternalSecret(nil, rotatedKey("password", "password_previous", now.Add(-2*time.Hour), time.Hour))
+	assert.Equal(t, ctrl.Result{Requeue: true}, getRotationRequeueResult(expired, ctrl.Result{RequeueAfter: time.Hour}))
+}
+
+func TestRotationMutationFunc(t *testing.T) {
+	overlap := time.Hour
+	rotation := &esv1.ExternalSecretRotation{OverlapPeriod: metav1.Duration{Duration: overlap}, KeySuffix: "-old"}
+	es := newRotationTestExternalSecret(rotation, rotatedKey("user", "user-old", rotationTestTime.Add(-30*time.Minute), overlap))
+	existingSecret := &v1.Secret{Data: map[string][]byte{
+		"password": []byte("old"),
+		"user":     []byte("root"),
+		"user-old": []byte("admin"),
+	}}
+	kept := map[string][]byte{"user": []byte("admin")}
+	render := func(secret *v1.Secret) error {
+		secret.Annotations = map[string]string{}
+		secret.Data["password"] = []byte("new")
+		return nil
+	}
+
+	var rotated []esv1.ExternalSecretRotatedKey
+	var values map[string][]byte
+	mutate := rotationMutationFunc(es, existingSecret, kept, rotationTestTime, render, func(r []esv1.ExternalSecretRotatedKey, v map[string][]byte) {
+		rotated, values = r, v
+	})
+	secret := existingSecret.DeepCopy()
+	require.NoError(t, mutate(secret))
+
+	want := map[string][]byte{
+		"password":     []byte("new"),
+		"password-old": []byte("old"),
+		"user":         []byte("root"),
+		"user-old":     []byte("admin"),
+	}
+	assert.Equal(t, want, secret.Data)
+	assert.Equal(t, esutils.ObjectHash(want), secret.Annotations[esv1.AnnotationDataHash])
+	assert.Equal(t, []esv1.ExternalSecretRotatedKey{
+		rotatedKey("password", "password-old", rotationTestTime, overlap),
+		es.Status.Rotation[0],
+	}, rotated)
+	assert.Equal(t, map[string][]byte{"password": []byte("old"), "user": []byte("admin")}, values)
+
+	// the companion keys are removed once target.rotation is unset
+	es.Spec.Target.Rotation = nil
+	secret = existingSecret.DeepCopy()
+	require.NoError(t, rotationMutationFunc(es, existingSecret, nil, rotationTestTime, render, func(r []esv1.ExternalSecretRotatedKey, v map[string][]byte) {
+		rotated, values = r, v
+	})(secret))
+	assert.Equal(t, map[string][]byte{"password": []byte("new"), "user": []byte("root")}, secret.Data)
+	assert.Nil(t, rotated)
+	assert.Nil(t, values)
+}
+
+func TestSyncRotationSecret(t *testing.T) {
+	rotation := &esv1.ExternalSecretRotation{OverlapPeriod: metav1.Duration{Duration: time.Hour}, SecretName: "companion"}
+	ownerLabel := esutils.ObjectHash("default/es")
+	ctx := context.Background()
+
+	unused := &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      "unused",
+		Namespace: "default",
+		Labels:    map[string]string{esv1.LabelRotationOf: ownerLabel},
+	}}
+	h := newTestReconciler(t, unused)
+	es := newRotationTestExternalSecret(rotation)
+
+	// the companion secret is created, even without previous values
+	require.NoError(t, h.r.syncRotationSecret(ctx, logr.Discard(), es, nil))
+	companion := h.secret("companion")
+	require.NotNil(t, companion)
+	assert.Equal(t, ownerLabel, companion.Labels[esv1.LabelRotationOf])
+	assert.Empty(t, companion.Data)
+	require.Len(t, companion.OwnerReferences, 1)
+	assert.Equal(t, es.Name, companion.OwnerReferences[0].Name)
+	assert.Nil(t, h.secret("unused"))
+
+	// the previous values are written
+	require.NoError(t, h.r.syncRotationSecret(ctx, logr.Discard(), es, map[string][]byte{"password": []byte("old")}))
+	assert.Equal(t, map[string][]byte{"password": []byte("old")}, h.secret("companion").Data)
+
+	// the companion secret is deleted once target.rotation is unset
+	es.Spec.Target.Rotation = nil
+	require.NoError(t, h.r.syncRotationSecret(ctx, logr.Discard(), es, nil))
+	assert.Nil(t, h.secret("companion"))
+}
+
+func TestSyncRotationSecretNotOwned(t *testing.T) {
+	foreign := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "companion", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("value")},
+	}
+	h := newTestReconciler(t, foreign)
+	es := newRotationTestExternalSecret(&esv1.ExternalSecretRotation{OverlapPeriod: metav1.Duration{Duration: time.Hour}, SecretName: "companion"})
+
+	err := h.r.syncRotationSecret(context.Background(), logr.Discard(), es, nil)
+	assert.EqualError(t, err, fmt.Sprintf(errRotationSecretNotOwned, "companion"))
+	assert.Equal(t, foreign.Data, h.secret("companion").Data)
+}
+
+func TestKeptRotationValues(t *testing.T) {
+	companion := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "companion", Namespace: "default"},
+		Data:       map[string][]byte{"user": []byte("admin")},
+	}
+	h := newTestReconciler(t, companion)
+	existingSecret := &v1.Secret{Data: map[string][]byte{"password": []byte("new"), "password_previous": []byte("old")}}
+
+	// the previous values are read from where they were written, even if target.rotation changed since
+	es := newRotationTestExternalSecret(&esv1.ExternalSecretRotation{OverlapPeriod: metav1.Duration{Duration: time.Hour}, SecretName: "companion"},
+		rotatedKey("password", "password_previous", rotationTestTime, time.Hour),
+		rotatedKey("user", "", rotationTestTime, time.Hour),
+	)
+	kept, err := h.r.keptRotationValues(context.Background(), es, existingSecret)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"password": []byte("old"), "user": []byte("admin")}, kept)
+
+	es.Spec.Target.Rotation.SecretName = ""
+	kept, err = h.r.keptRotationValues(context.Background(), es, existingSecret)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"password": []byte("old")}, kept)
+}
+
+func TestReconcileRotationWritesCompanionFirst(t *testing.T) {
+	existing := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "es",
+			Namespace: "default",
+			UID:       "secret",
+			Labels:    map[string]string{esv1.LabelManaged: esv1.LabelManagedValue},
+		},
+		Data: map[string][]byte{"password": []byte("old")},
+	}
+	foreign := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "companion", Namespace: "default"}}
+	es := newRotationTestExternalSecret(&esv1.ExternalSecretRotation{OverlapPeriod: metav1.Duration{Duration: time.Hour}, SecretName: "companion"})
+	es.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+	es.Spec.Target.CreationPolicy = esv1.CreatePolicyOrphan
+	es.Spec.Data = []esv1.ExternalSecretData{{SecretKey: "password", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "password"}}}
+	h := newTestReconciler(t, existing, foreign, es)
+	fakeProvider.WithGetSecret([]byte("new"), nil)
+
+	_, got, err := h.reconcile(es)
+	require.Error(t, err)
+
+	// the previous value could not be kept, so the secret is left untouched
+	assert.Equal(t, existing.Data, h.secret("es").Data)
+	assert.Empty(t, got.Status.Rotation)
+}
@@ -22,8 +22,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -41,7 +43,13 @@ import (
 )
 
 // GetProviderSecretData returns the provider's secret data with the provided ExternalSecret.
-func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret) (providerData map[string][]byte, err error) {
+func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret) (map[string][]byte, error) {
+	providerData, _, err := r.getProviderSecretData(ctx, externalSecret)
+	return providerData, err
+}
+
+// getProviderSecretData returns the provider's secret data, along with the provenance of its keys.
+func (r *Reconciler) getProviderSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret) (providerData map[string][]byte, keyProvenance provenance, err error) {
 	// We MUST NOT create multiple instances of a provider client (mostly due to limitations with GCP)
 	// Clientmanager keeps track of the client instances
 	// that are created during the fetching process and closes clients
@@ -77,21 +85,25 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 		}()
 	}
 	providerData = make(map[string][]byte)
+	keyProvenance = make(provenance)
+	var servedBy []esv1.ExternalSecretServedBy
 	for i, remoteRef := range externalSecret.Spec.DataFrom {
 		var secretMap map[string][]byte
+		var served esv1.SecretStoreRef
+		var generatedExpiry time.Time
 
 		if remoteRef.Find != nil {
-			secretMap, err = r.handleFindAllSecrets(ctx, externalSecret, remoteRef, mgr, genState, i)
+			secretMap, served, err = r.handleFindAllSecrets(ctx, externalSecret, remoteRef, mgr, genState, i)
 			if err != nil {
 				err = fmt.Errorf("error processing spec.dataFrom[%d].find, err: %w", i, err)
 			}
 		} else if remoteRef.Extract != nil {
-			secretMap, err = r.handleExtractSecrets(ctx, externalSecret, remoteRef, mgr, genState, i)
+			secretMap, served, err = r.handleExtractSecrets(ctx, externalSecret, remoteRef, mgr, genState, i)
 			if err != nil {
 				err = fmt.Errorf("error processing spec.dataFrom[%d].extract, err: %w", i, err)
 			}
 		} else if remoteRef.SourceRef != nil && remoteRef.SourceRef.GeneratorRef != nil {
-			secretMap, err = r.handleGenerateSecrets(ctx, externalSecret.Namespace, remoteRef, i, genState)
+			secretMap, generatedExpiry, err = r.handleGenerateSecrets(ctx, externalSecret.Namespace, remoteRef, i, genState)
 			if err != nil {
 				err = fmt.Errorf("error processing spec.dataFrom[%d].sourceRef.generatorRef, err: %w", i, err)
 			}
@@ -102,40 +114,141 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 			continue
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		providerData = esutils.MergeByteMap(providerData, secretMap)
+		servedBy = newServedBy(servedBy, fmt.Sprintf("spec.dataFrom[%d]", i), dataFromStoreRef(externalSecret, remoteRef), served)
+		keys := slices.Collect(maps.Keys(secretMap))
+		switch {
+		case remoteRef.Extract != nil:
+			keyProvenance.addStore(keys, served, remoteRef.Extract.Key, remoteRef.Extract.Property, esv1.SecretVersion{})
+		case remoteRef.Find != nil:
+			keyProvenance.addStore(keys, served, "", "", esv1.SecretVersion{})
+		case remoteRef.SourceRef != nil && remoteRef.SourceRef.GeneratorRef != nil:
+			keyProvenance.addGenerator(keys, remoteRef.SourceRef.GeneratorRef, generatedExpiry)
+		}
 	}
 
+	fetched, fetchedFrom := r.fetchSecretData(ctx, externalSecret, mgr)
 	for i, secretRef := range externalSecret.Spec.Data {
-		err := r.handleSecretData(ctx, externalSecret, secretRef, providerData, mgr)
+		err := r.handleSecretData(secretRef, fetched[i], providerData)
 		if errors.Is(err, esv1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1.DeletionPolicyRetain {
 			r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonMissingProviderSecret, eventMissingProviderSecretKey, i, secretRef.RemoteRef.Key)
 			continue
 		}
 		if err != nil {
-			return nil, fmt.Errorf("error processing spec.data[%d] (key: %s), err: %w", i, secretRef.RemoteRef.Key, err)
+			return nil, nil, fmt.Errorf("error processing spec.data[%d] (key: %s), err: %w", i, secretRef.RemoteRef.Key, err)
 		}
+		servedBy = newServedBy(servedBy, secretRef.SecretKey, dataStoreRef(externalSecret, secretRef), fetchedFrom[i])
+		keyProvenance.addStore([]string{secretRef.SecretKey}, fetchedFrom[i], secretRef.RemoteRef.Key, secretRef.RemoteRef.Property, fetched[i].Version)
 	}
 
-	return providerData, nil
+	r.setServedBy(externalSecret, servedBy)
+	return providerData, keyProvenance, nil
 }
 
-func (r *Reconciler) handleSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret, secretRef esv1.ExternalSecretData, providerData map[string][]byte, cmgr *secretstore.Manager) error {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, toStoreGenSourceRef(secretRef.SourceRef))
-	if err != nil {
-		return err
+// fetchSecretData fetches the remote values of spec.data and returns one result per entry,
+// with the store which served it. Entries are grouped by the store they reference so that
+// providers implementing esv1.BatchSecretsClient can fetch a whole group at once.
+// The entries a store fails to fetch are fetched again from its fallbacks, in order.
+func (r *Reconciler) fetchSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret, cmgr *secretstore.Manager) ([]esv1.SecretResult, []esv1.SecretStoreRef) {
+	results := make([]esv1.SecretResult, len(externalSecret.Spec.Data))
+	servedBy := make([]esv1.SecretStoreRef, len(externalSecret.Spec.Data))
+	var groups []storeGroup
+	for i, secretRef := range externalSecret.Spec.Data {
+		storeRef := dataStoreRef(externalSecret, secretRef)
+		g := slices.IndexFunc(groups, func(group storeGroup) bool {
+			return group.storeRef.Name == storeRef.Name && group.storeRef.Kind == storeRef.Kind &&
+				slices.Equal(group.storeRef.Fallbacks, storeRef.Fallbacks)
+		})
+		if g < 0 {
+			groups = append(groups, storeGroup{storeRef: storeRef})
+			g = len(groups) - 1
+		}
+		groups[g].indices = append(groups[g].indices, i)
+	}
+
+	for _, group := range groups {
+		candidates := storeCandidates(group.storeRef)
+		primaryErrs := make(map[int]error)
+		pending := group.indices
+		for c, storeRef := range candidates {
+			r.fetchStoreSecretData(ctx, externalSecret, cmgr, storeRef, pending, results)
+			var failed []int
+			for _, i := range pending {
+				err := results[i].Err
+				if err == nil {
+					servedBy[i] = storeRef
+					continue
+				}
+				if c == 0 {
+					primaryErrs[i] = err
+				} else {
+					results[i].Err = failoverError(primaryErrs[i], err)
+				}
+				if shouldFailover(err) && c < len(candidates)-1 {
+					failed = append(failed, i)
+				}
+			}
+			if len(failed) == 0 {
+				break
+			}
+			pending = failed
+		}
 	}
 
-	// get a single secret from the store
-	secretData, err := client.GetSecret(ctx, secretRef.RemoteRef)
+	return results, servedBy
+}
+
+// storeGroup is a store and the indices of the spec.data entries referencing it.
+type storeGroup struct {
+	storeRef esv1.SecretStoreRef
+	indices  []int
+}
+
+// fetchStoreSecretData fetches the remote values of the spec.data entries at indices from a single store.
+func (r *Reconciler) fetchStoreSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret, cmgr *secretstore.Manager, storeRef esv1.SecretStoreRef, indices []int, results []esv1.SecretResult) {
+	client, err := cmgr.Get(ctx, storeRef, externalSecret.Namespace, nil)
 	if err != nil {
-		return err
+		for _, i := range indices {
+			results[i] = esv1.SecretResult{Err: err}
+		}
+		return
+	}
+
+	batchClient, ok := client.(esv1.BatchSecretsClient)
+	if !ok || len(indices) == 1 {
+		for _, i := range indices {
+			results[i].Value, results[i].Version, results[i].Err = secretstore.GetSecretVersion(ctx, client, externalSecret.Spec.Data[i].RemoteRef)
+		}
+		return
+	}
+
+	refs := make([]esv1.ExternalSecretDataRemoteRef, 0, len(indices))
+	for _, i := range indices {
+		refs = append(refs, externalSecret.Spec.Data[i].RemoteRef)
+	}
+	batch, err := batchClient.GetSecrets(ctx, refs)
+	if err == nil {
+		err = secretstore.CheckBatchResults(batch, len(refs))
+	}
+	for j, i := range indices {
+		if err != nil {
+			results[i] = esv1.SecretResult{Err: err}
+			continue
+		}
+		results[i] = batch[j]
+	}
+}
+
+func (r *Reconciler) handleSecretData(secretRef esv1.ExternalSecretData, fetched esv1.SecretResult, providerData map[string][]byte) error {
+	if fetched.Err != nil {
+		return fetched.Err
 	}
 
 	// decode the secret if needed
-	secretData, err = decoding.Decode(secretRef.RemoteRef.DecodingStrategy, secretData)
+	secretData, err := decoding.Decode(secretRef.RemoteRef.DecodingStrategy, fetched.Value)
 	if err != nil {
 		return fmt.Errorf(errDecode, secretRef.RemoteRef.DecodingStrategy, err)
 	}
@@ -149,36 +262,27 @@ func (r *Reconciler) handleSecretData(ctx context.Context, externalSecret *esv1.
 	return nil
 }
 
-func toStoreGenSourceRef(ref *esv1.StoreSourceRef) *esv1.StoreGeneratorSourceRef {
-	if ref == nil {
-		return nil
-	}
-	return &esv1.StoreGeneratorSourceRef{
-		SecretStoreRef: &ref.SecretStoreRef,
-	}
-}
-
 func (r *Reconciler) handleGenerateSecrets(
 	ctx context.Context,
 	namespace string,
 	remoteRef esv1.ExternalSecretDataFromRemoteRef,
 	i int,
 	generatorState *statemanager.Manager,
-) (map[string][]byte, error) {
+) (map[string][]byte, time.Time, error) {
 	impl, generatorResource, err := resolvers.GeneratorRef(ctx, r.Client, r.Scheme, namespace, remoteRef.SourceRef.GeneratorRef)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 	var latestState *genv1alpha1.GeneratorState
 	if generatorState != nil {
 		latestState, err = generatorState.GetLatestState(generatorStateKey(i))
 		if err != nil {
-			return nil, fmt.Errorf("unable to get latest state: %w", err)
+			return nil, time.Time{}, fmt.Errorf("unable to get latest state: %w", err)
 		}
 	}
 	secretMap, newState, err := impl.Generate(ctx, generatorResource, r.Client, namespace)
 	if err != nil {
-		return nil, fmt.Errorf(errGenerate, err)
+		return nil, time.Time{}, fmt.Errorf(errGenerate, err)
 	}
 	if latestState != nil {
 		if generatorState != nil {
@@ -188,19 +292,20 @@ func (r *Reconciler) handleGenerateSecrets(
 	if generatorState != nil {
 		generatorState.EnqueueSetLatest(ctx, generatorStateKey(i), namespace, generatorResource, impl, newState)
 	}
+	expiry, _ := genv1alpha1.StateExpiry(newState)
 	// rewrite the keys if needed
 	secretMap, err = esutils.RewriteMap(remoteRef.Rewrite, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errRewrite, err)
+		return nil, time.Time{}, fmt.Errorf(errRewrite, err)
 	}
 
 	// validate the keys
 	err = esutils.ValidateKeys(r.Log, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errInvalidKeys, err)
+		return nil, time.Time{}, fmt.Errorf(errInvalidKeys, err)
 	}
 
-	return secretMap, err
+	return secretMap, expiry, err
 }
 
 // We're using the index of the generator as the key for the generator state
@@ -217,48 +322,45 @@ func (r *Reconciler) handleExtractSecrets(
 	cmgr *secretstore.Manager,
 	genState *statemanager.Manager,
 	i int,
-) (map[string][]byte, error) {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, remoteRef.SourceRef)
-	if err != nil {
-		return nil, err
-	}
-
+) (map[string][]byte, esv1.SecretStoreRef, error) {
 	// get multiple secrets from the store
-	secretMap, err := client.GetSecretMap(ctx, *remoteRef.Extract)
+	secretMap, servedBy, err := withFailover(ctx, cmgr, externalSecret.Namespace, dataFromStoreRef(externalSecret, remoteRef), func(client esv1.SecretsClient) (map[string][]byte, error) {
+		return client.GetSecretMap(ctx, *remoteRef.Extract)
+	})
 	if err != nil {
-		return nil, err
+		return nil, servedBy, err
 	}
 
 	// rewrite the keys if needed
 	secretMap, err = esutils.RewriteMap(remoteRef.Rewrite, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errRewrite, err)
+		return nil, servedBy, fmt.Errorf(errRewrite, err)
 	}
 	if len(remoteRef.Rewrite) == 0 {
 		secretMap, err = esutils.ConvertKeys(remoteRef.Extract.ConversionStrategy, secretMap)
 		if err != nil {
-			return nil, fmt.Errorf(errConvert, remoteRef.Extract.ConversionStrategy, err)
+			return nil, servedBy, fmt.Errorf(errConvert, remoteRef.Extract.ConversionStrategy, err)
 		}
 	}
 
 	// validate the keys
 	err = esutils.ValidateKeys(r.Log, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errInvalidKeys, err)
+		return nil, servedBy, fmt.Errorf(errInvalidKeys, err)
 	}
 
 	// decode the secrets if needed
 	secretMap, err = decoding.DecodeMap(remoteRef.Extract.DecodingStrategy, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errDecode, remoteRef.Extract.DecodingStrategy, err)
+		return nil, servedBy, fmt.Errorf(errDecode, remoteRef.Extract.DecodingStrategy, err)
 	}
 	if err := validateFetchedSecretMap(remoteRef.Extract.NullBytePolicy, secretMap); err != nil {
-		return nil, err
+		return nil, servedBy, err
 	}
 	if genState != nil {
 		genState.EnqueueFlagLatestStateForGC(generatorStateKey(i))
 	}
-	return secretMap, nil
+	return secretMap, servedBy, nil
 }
 
 func (r *Reconciler) handleFindAllSecrets(
@@ -268,48 +370,49 @@ func (r *Reconciler) handleFindAllSecrets(
 	cmgr *secretstore.Manager,
 	genState *statemanager.Manager,
 	i int,
-) (map[string][]byte, error) {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, remoteRef.SourceRef)
-	if err != nil {
-		return nil, err
-	}
-
+) (map[string][]byte, esv1.SecretStoreRef, error) {
 	// get all secrets from the store that match the selector
-	secretMap, err := client.GetAllSecrets(ctx, *remoteRef.Find)
+	secretMap, servedBy, err := withFailover(ctx, cmgr, externalSecret.Namespace, dataFromStoreRef(externalSecret, remoteRef), func(client esv1.SecretsClient) (map[string][]byte, error) {
+		secrets, err := client.GetAllSecrets(ctx, *remoteRef.Find)
+		if err != nil {
+			return nil, fmt.Errorf("error getting all secrets: %w", err)
+		}
+		return secrets, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error getting all secrets: %w", err)
+		return nil, servedBy, err
 	}
 
 	// rewrite the keys if needed
 	secretMap, err = esutils.RewriteMap(remoteRef.Rewrite, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errRewrite, err)
+		return nil, servedBy, fmt.Errorf(errRewrite, err)
 	}
 	if len(remoteRef.Rewrite) == 0 {
 		secretMap, err = esutils.ConvertKeys(remoteRef.Find.ConversionStrategy, secretMap)
 		if err != nil {
-			return nil, fmt.Errorf(errConvert, remoteRef.Find.ConversionStrategy, err)
+			return nil, servedBy, fmt.Errorf(errConvert, remoteRef.Find.ConversionStrategy, err)
 		}
 	}
 
 	// validate the keys
 	err = esutils.ValidateKeys(r.Log, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errInvalidKeys, err)
+		return nil, servedBy, fmt.Errorf(errInvalidKeys, err)
 	}
 
 	// decode the secrets if needed
 	secretMap, err = decoding.DecodeMap(remoteRef.Find.DecodingStrategy, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errDecode, remoteRef.Find.DecodingStrategy, err)
+		return nil, servedBy, fmt.Errorf(errDecode, remoteRef.Find.DecodingStrategy, err)
 	}
 	if err := validateFetchedSecretMap(remoteRef.Find.NullBytePolicy, secretMap); err != nil {
-		return nil, err
+		return nil, servedBy, err
 	}
 	if genState != nil {
 		genState.EnqueueFlagLatestStateForGC(generatorStateKey(i))
 	}
-	return secretMap, nil
+	return secretMap, servedBy, nil
 }
 
 func validateFetchedSecretValue(policy esv1.ExternalSecretNullBytePolicy, key string, value []byte) error {
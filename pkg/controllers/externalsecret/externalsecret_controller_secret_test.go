@@ -0,0 +1,95 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+// batchFakeClient records the refs passed to GetSecrets.
+type batchFakeClient struct {
+	*fake.Client
+	batches [][]esv1.ExternalSecretDataRemoteRef
+}
+
+func (c *batchFakeClient) GetSecrets(_ context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([]esv1.SecretResult, error) {
+	c.batches = append(c.batches, refs)
+	results := make([]esv1.SecretResult, len(refs))
+	for i, ref := range refs {
+		if ref.Key == "missing" {
+			results[i].Err = esv1.NoSecretErr
+			continue
+		}
+		results[i].Value = []byte("batch-" + ref.Key)
+	}
+	return results, nil
+}
+
+func TestFetchSecretDataUsesBatchClient(t *testing.T) {
+	h := newTestReconciler(t, newTestStore("batch"), newTestStore("single"))
+	batchClient := &batchFakeClient{Client: fake.New()}
+	batchClient.WithGetSecret([]byte("single"), nil)
+	fakeProvider.WithNew(func(context.Context, esv1.GenericStore, client.Client, string) (esv1.SecretsClient, error) {
+		return batchClient, nil
+	})
+
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			SecretStoreRef: esv1.SecretStoreRef{Name: "batch", Kind: esv1.SecretStoreKind},
+			Data: []esv1.ExternalSecretData{
+				{SecretKey: "a", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "a"}},
+				{
+					SecretKey: "other",
+					RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "other"},
+					SourceRef: &esv1.StoreSourceRef{
+						SecretStoreRef: esv1.SecretStoreRef{Name: "single", Kind: esv1.SecretStoreKind},
+					},
+				},
+				{SecretKey: "missing", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "missing"}},
+				{SecretKey: "b", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "b"}},
+			},
+		},
+	}
+
+	mgr := secretstore.NewManager(h.kube, "", false)
+	defer func() {
+		_ = mgr.Close(context.Background())
+	}()
+
+	results, _ := h.r.fetchSecretData(context.Background(), es, mgr)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, []byte("batch-a"), results[0].Value)
+	assert.Equal(t, []byte("single"), results[1].Value)
+	assert.True(t, errors.Is(results[2].Err, esv1.NoSecretErr))
+	assert.Equal(t, []byte("batch-b"), results[3].Value)
+
+	require.Len(t, batchClient.batches, 1)
+	assert.Equal(t, []esv1.ExternalSecretDataRemoteRef{{Key: "a"}, {Key: "missing"}, {Key: "b"}}, batchClient.batches[0])
+}
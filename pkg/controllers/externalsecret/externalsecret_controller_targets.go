@@ -0,0 +1,216 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+// splitTargetExternalSecret returns a copy of the ExternalSecret targeting the Secret of an entry of spec.targets,
+// so that Secret is rendered, owned and written the same way as the Secret of spec.target.
+func splitTargetExternalSecret(externalSecret *esv1.ExternalSecret, target esv1.ExternalSecretSplitTarget) *esv1.ExternalSecret {
+	split := externalSecret.DeepCopy()
+	split.Spec.Targets = nil
+	split.Spec.Target = esv1.ExternalSecretTarget{
+		Name:           target.Name,
+		CreationPolicy: target.CreationPolicy,
+		DeletionPolicy: target.DeletionPolicy,
+		Template:       target.Template.DeepCopy(),
+	}
+	if split.Spec.Target.CreationPolicy == "" {
+		split.Spec.Target.CreationPolicy = esv1.CreatePolicyOwner
+	}
+	if split.Spec.Target.DeletionPolicy == "" {
+		split.Spec.Target.DeletionPolicy = esv1.DeletionPolicyRetain
+	}
+	return split
+}
+
+// isSplitTarget returns true if the secret is the Secret of an entry of spec.targets, so it is not orphaned.
+func isSplitTarget(externalSecret *esv1.ExternalSecret, name string) bool {
+	return slices.ContainsFunc(externalSecret.Spec.Targets, func(target esv1.ExternalSecretSplitTarget) bool {
+		return target.Name == name
+	})
+}
+
+// selectSplitTargetData returns the keys of the data selected by the selector of a target.
+// Every key is selected when the selector is empty.
+func selectSplitTargetData(dataMap map[string][]byte, selector *esv1.ExternalSecretKeySelector) (map[string][]byte, error) {
+	if selector == nil || (len(selector.Keys) == 0 && selector.RegExp == "") {
+		return maps.Clone(dataMap), nil
+	}
+	var re *regexp.Regexp
+	if selector.RegExp != "" {
+		var err error
+		if re, err = regexp.Compile(selector.RegExp); err != nil {
+			return nil, ctrlutil.Safe(fmt.Errorf(errSplitTargetRegexp, err))
+		}
+	}
+	selected := make(map[string][]byte)
+	for key, value := range dataMap {
+		if slices.Contains(selector.Keys, key) || (re != nil && re.MatchString(key)) {
+			selected[key] = value
+		}
+	}
+	return selected, nil
+}
+
+// getSplitTargetSecrets returns the existing Secrets of spec.targets, in the same order.
+// It returns a result to requeue with instead when the caches are not up-to-date with one of them yet.
+func (r *Reconciler) getSplitTargetSecrets(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret) ([]*v1.Secret, *ctrl.Result, error) {
+	secrets := make([]*v1.Secret, len(externalSecret.Spec.Targets))
+	for i, target := range externalSecret.Spec.Targets {
+		secret, requeue, err := r.getExistingSecret(ctx, log, externalSecret, target.Name)
+		if err != nil || requeue != nil {
+			return nil, requeue, err
+		}
+		secrets[i] = secret
+	}
+	return secrets, nil, nil
+}
+
+// splitTargetsInSync returns true if the Secrets of spec.targets are valid, like the Secret of spec.target.
+func splitTargetsInSync(externalSecret *esv1.ExternalSecret, secrets []*v1.Secret) bool {
+	for i, target := range externalSecret.Spec.Targets {
+		if !isSecretValid(secrets[i], splitTargetExternalSecret(externalSecret, target)) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeSplitTargets writes the Secrets of spec.targets once the Secret of spec.target was written.
+// They are not written while the Secret of spec.target is pinned by a rollback.
+// It returns a result to return with when the reconcile has to stop.
+func (r *Reconciler) writeSplitTargets(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, secretName string,
+	data *targetData, existingSecrets []*v1.Secret, counter prometheus.Counter) (*ctrl.Result, error) {
+	if data.snapshot != nil {
+		return nil, nil
+	}
+	if err := r.syncSplitTargets(ctx, log, externalSecret, secretName, data.dataMap, existingSecrets); err != nil {
+		result, err := r.handleSplitTargetsError(log, externalSecret, err, counter)
+		return &result, err
+	}
+	return nil, nil
+}
+
+// handleSplitTargetsError marks the ExternalSecret as failed when a Secret of spec.targets could not be rendered or written.
+func (r *Reconciler) handleSplitTargetsError(log logr.Logger, externalSecret *esv1.ExternalSecret, err error, counter prometheus.Counter) (ctrl.Result, error) {
+	if apierrors.IsConflict(err) {
+		log.V(1).Info("conflict while updating secret of spec.targets, will requeue")
+		return ctrl.Result{Requeue: true}, nil
+	}
+	// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+	if errors.Is(err, ErrSecretIsOwned) {
+		r.markAsFailed(msgErrorIsOwned, ctrlutil.Safe(err), externalSecret, counter, esv1.ConditionReasonSecretOwnedByOther)
+		return ctrl.Result{}, nil
+	}
+	r.markAsFailed(msgErrorSplitTargets, err, externalSecret, counter, esv1.ConditionReasonSecretSyncedError)
+	return ctrl.Result{}, err
+}
+
+// syncSplitTargets writes the Secrets of spec.targets from the data fetched for the ExternalSecret.
+// secretName is the Secret of spec.target, which is never orphaned here.
+func (r *Reconciler) syncSplitTargets(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, secretName string,
+	dataMap map[string][]byte, existingSecrets []*v1.Secret) error {
+	// the secrets of removed targets are deleted with the orphaned secrets of spec.target when it is owned,
+	// otherwise they are deleted here as long as a target is owned
+	if externalSecret.Spec.Target.CreationPolicy != esv1.CreatePolicyOwner {
+		for _, target := range externalSecret.Spec.Targets {
+			if target.CreationPolicy == "" || target.CreationPolicy == esv1.CreatePolicyOwner {
+				if err := r.deleteOrphanedSecrets(ctx, log, externalSecret, secretName); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	for i, target := range externalSecret.Spec.Targets {
+		data, err := selectSplitTargetData(dataMap, target.Selector)
+		if err == nil {
+			err = r.writeSplitTarget(ctx, log, splitTargetExternalSecret(externalSecret, target), existingSecrets[i], data)
+		}
+		if err != nil {
+			return fmt.Errorf(errSplitTarget, i, err)
+		}
+	}
+	return nil
+}
+
+// writeSplitTarget writes the Secret of an entry of spec.targets, following its creation and deletion policies.
+func (r *Reconciler) writeSplitTarget(ctx context.Context, log logr.Logger, split *esv1.ExternalSecret, existingSecret *v1.Secret, data map[string][]byte) error {
+	secretName := split.Spec.Target.Name
+
+	// if no key was selected we can delete the secret if needed
+	if len(data) == 0 {
+		switch split.Spec.Target.DeletionPolicy {
+		case esv1.DeletionPolicyDelete:
+			// safeguard that we only can delete secrets we own.
+			if creationPolicy := split.Spec.Target.CreationPolicy; creationPolicy != esv1.CreatePolicyOwner {
+				return ctrlutil.Safe(fmt.Errorf(errDeleteCreatePolicy, secretName, creationPolicy))
+			}
+			if existingSecret.UID == "" {
+				return nil
+			}
+			if err := r.Delete(ctx, existingSecret); err != nil && !apierrors.IsNotFound(err) {
+				return ctrlutil.Safe(err)
+			}
+			log.V(1).Info(logSecretDeleted, "secret", secretName, "namespace", split.Namespace, "reason", "DeletionPolicy=Delete and no key was selected")
+			r.recorder.Event(split, v1.EventTypeNormal, esv1.ReasonDeleted, eventDeleted)
+			return nil
+		// the secret is kept as-is.
+		case esv1.DeletionPolicyRetain:
+			return nil
+		// noop, handled below
+		case esv1.DeletionPolicyMerge:
+		}
+	}
+
+	mutationFunc := r.secretMutationFunc(ctx, split, data)
+	switch split.Spec.Target.CreationPolicy {
+	case esv1.CreatePolicyNone:
+		log.V(1).Info("secret creation skipped due to CreationPolicy=None", "secret", secretName)
+	case esv1.CreatePolicyMerge:
+		// the secret is written once it exists
+		if existingSecret.UID == "" {
+			log.V(1).Info("secret creation skipped due to CreationPolicy=Merge", "secret", secretName)
+			return nil
+		}
+		return r.updateSecret(ctx, log, existingSecret, mutationFunc, split, secretName)
+	case esv1.CreatePolicyOwner, esv1.CreatePolicyOrphan, esv1.CreatePolicyCreateOrMerge:
+		if existingSecret.UID == "" {
+			return r.createSecret(ctx, mutationFunc, split, secretName)
+		}
+		return r.updateSecret(ctx, log, existingSecret, mutationFunc, split, secretName)
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestSelectSplitTargetData(t *testing.T) {
+	data := map[string][]byte{
+		"db_user":     []byte("admin"),
+		"db_password": []byte("secret"),
+		"redis_url":   []byte("redis://cache"),
+	}
+
+	tests := []struct {
+		name     string
+		selector *esv1.ExternalSecretKeySelector
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "no selector",
+			want: []string{"db_password", "db_user", "redis_url"},
+		},
+		{
+			name:     "empty selector",
+			selector: &esv1.ExternalSecretKeySelector{},
+			want:     []string{"db_password", "db_user", "redis_url"},
+		},
+		{
+			name:     "keys",
+			selector: &esv1.ExternalSecretKeySelector{Keys: []string{"redis_url", "missing"}},
+			want:     []string{"redis_url"},
+		},
+		{
+			name:     "regexp",
+			selector: &esv1.ExternalSecretKeySelector{RegExp: "^db_"},
+			want:     []string{"db_password", "db_user"},
+		},
+		{
+			name:     "keys or regexp",
+			selector: &esv1.ExternalSecretKeySelector{Keys: []string{"redis_url"}, RegExp: "password$"},
+			want:     []string{"db_password", "redis_url"},
+		},
+		{
+			name:     "no key matches",
+			selector: &esv1.ExternalSecretKeySelector{RegExp: "^mq_"},
+			want:     []string{},
+		},
+		{
+			name:     "invalid regexp",
+			selector: &esv1.ExternalSecretKeySelector{RegExp: "db_("},
+			wantErr:  "invalid selector regexp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectSplitTargetData(data, tt.selector)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			keys := make([]string, 0, len(got))
+			for key, value := range got {
+				assert.Equal(t, data[key], value)
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, tt.want, keys)
+		})
+	}
+}
+
+func TestSplitTargetExternalSecret(t *testing.T) {
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			Target: esv1.ExternalSecretTarget{Name: "app", Immutable: true, History: &esv1.ExternalSecretHistory{Limit: 3}},
+			Targets: []esv1.ExternalSecretSplitTarget{
+				{Name: "db", Template: &esv1.ExternalSecretTemplate{Type: v1.SecretTypeBasicAuth}},
+			},
+		},
+	}
+
+	split := splitTargetExternalSecret(es, es.Spec.Targets[0])
+	assert.Equal(t, esv1.ExternalSecretTarget{
+		Name:           "db",
+		CreationPolicy: esv1.CreatePolicyOwner,
+		DeletionPolicy: esv1.DeletionPolicyRetain,
+		Template:       &esv1.ExternalSecretTemplate{Type: v1.SecretTypeBasicAuth},
+	}, split.Spec.Target)
+	assert.Empty(t, split.Spec.Targets)
+	assert.Equal(t, es.Name, split.Name)
+
+	// the ExternalSecret is left untouched
+	assert.Equal(t, "app", es.Spec.Target.Name)
+	assert.Len(t, es.Spec.Targets, 1)
+
+	assert.True(t, isSplitTarget(es, "db"))
+	assert.False(t, isSplitTarget(es, "app"))
+}
+
+func TestReconcileSplitTargets(t *testing.T) {
+
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: esv1.ExternalSecretSpec{
+			SecretStoreRef:  esv1.SecretStoreRef{Name: testStoreName, Kind: esv1.SecretStoreKind},
+			RefreshInterval: &metav1.Duration{Duration: time.Nanosecond},
+			Target:          esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyNone},
+			Targets: []esv1.ExternalSecretSplitTarget{
+				{
+					Name:           "db",
+					Selector:       &esv1.ExternalSecretKeySelector{RegExp: "^db_"},
+					DeletionPolicy: esv1.DeletionPolicyDelete,
+					Template: &esv1.ExternalSecretTemplate{
+						EngineVersion: esv1.TemplateEngineV2,
+						Type:          v1.SecretTypeBasicAuth,
+						Data: map[string]string{
+							v1.BasicAuthUsernameKey: "{{ .db_user }}",
+							v1.BasicAuthPasswordKey: "{{ .db_password }}",
+						},
+					},
+				},
+				{
+					Name:           "cache",
+					Selector:       &esv1.ExternalSecretKeySelector{Keys: []string{"redis_url"}},
+					CreationPolicy: esv1.CreatePolicyOrphan,
+					Template: &esv1.ExternalSecretTemplate{
+						EngineVersion: esv1.TemplateEngineV2,
+						Metadata:      esv1.ExternalSecretTemplateMetadata{Labels: map[string]string{"component": "cache"}},
+					},
+				},
+			},
+			DataFrom: []esv1.ExternalSecretDataFromRemoteRef{
+				{Extract: &esv1.ExternalSecretDataRemoteRef{Key: "config"}},
+			},
+		},
+	}
+	h := newTestReconciler(t, es)
+	reconcile := func(data map[string][]byte) {
+		t.Helper()
+		fakeProvider.WithGetSecretMap(data, nil)
+		_, got, err := h.reconcile(es)
+		require.NoError(t, err)
+		cond := esv1.GetExternalSecretCondition(got.Status, esv1.ExternalSecretReady)
+		require.NotNil(t, cond)
+		assert.Equal(t, v1.ConditionTrue, cond.Status, cond.Message)
+	}
+	get := h.secret
+
+	reconcile(map[string][]byte{
+		"db_user":     []byte("admin"),
+		"db_password": []byte("secret"),
+		"redis_url":   []byte("redis://cache"),
+	})
+
+	// the secret of spec.target is not created due to CreationPolicy=None
+	assert.Nil(t, get("app"))
+
+	db := get("db")
+	require.NotNil(t, db)
+	assert.Equal(t, v1.SecretTypeBasicAuth, db.Type)
+	assert.Equal(t, map[string][]byte{
+		v1.BasicAuthUsernameKey: []byte("admin"),
+		v1.BasicAuthPasswordKey: []byte("secret"),
+	}, db.Data)
+	assert.True(t, metav1.IsControlledBy(db, es))
+	assert.NotEmpty(t, db.Labels[esv1.LabelOwner])
+
+	cache := get("cache")
+	require.NotNil(t, cache)
+	assert.Equal(t, map[string][]byte{"redis_url": []byte("redis://cache")}, cache.Data)
+	assert.Equal(t, "cache", cache.Labels["component"])
+	assert.Nil(t, metav1.GetControllerOf(cache))
+	assert.Empty(t, cache.Labels[esv1.LabelOwner])
+
+	// the secret is deleted due to DeletionPolicy=Delete once no key is selected
+	reconcile(map[string][]byte{"redis_url": []byte("redis://replica")})
+	assert.Nil(t, get("db"))
+	cache = get("cache")
+	require.NotNil(t, cache)
+	assert.Equal(t, map[string][]byte{"redis_url": []byte("redis://replica")}, cache.Data)
+
+	// the secrets of spec.targets follow their own DeletionPolicy when the ExternalSecret is deleted
+	reconcile(map[string][]byte{"db_user": []byte("admin"), "db_password": []byte("rotated")})
+	require.NotNil(t, get("db"))
+	got := &esv1.ExternalSecret{}
+	require.NoError(t, h.kube.Get(context.Background(), client.ObjectKeyFromObject(es), got))
+	require.NoError(t, h.r.cleanupManagedSecrets(context.Background(), logr.Discard(), got))
+	assert.Nil(t, get("db"))
+	assert.NotNil(t, get("cache"))
+}
+
+func TestReconcileSplitTargetsOrphaned(t *testing.T) {
+
+	es := &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "app"},
+		Spec: esv1.ExternalSecretSpec{
+			SecretStoreRef:  esv1.SecretStoreRef{Name: testStoreName, Kind: esv1.SecretStoreKind},
+			RefreshInterval: &metav1.Duration{Duration: time.Nanosecond},
+			Target:          esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyOwner},
+			Targets: []esv1.ExternalSecretSplitTarget{
+				{Name: "db", Selector: &esv1.ExternalSecretKeySelector{RegExp: "^db_"}},
+			},
+			Data: []esv1.ExternalSecretData{
+				{SecretKey: "db_password", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db"}},
+			},
+		},
+	}
+	h := newTestReconciler(t, es)
+	fakeProvider.WithGetSecret([]byte("secret"), nil)
+	reconcile := func() {
+		t.Helper()
+		_, _, err := h.reconcile(es)
+		require.NoError(t, err)
+	}
+	exists := func(name string) bool {
+		t.Helper()
+		return h.secret(name) != nil
+	}
+
+	// the secret of spec.targets is not an orphaned secret of spec.target
+	reconcile()
+	assert.True(t, exists("app"))
+	assert.True(t, exists("db"))
+	reconcile()
+	assert.True(t, exists("db"))
+
+	// the secret of a removed target is orphaned
+	current := &esv1.ExternalSecret{}
+	require.NoError(t, h.kube.Get(context.Background(), client.ObjectKeyFromObject(es), current))
+	current.Spec.Targets = nil
+	require.NoError(t, h.kube.Update(context.Background(), current))
+	reconcile()
+	assert.True(t, exists("app"))
+	assert.False(t, exists("db"))
+}
+
+func TestReconcileSplitTargetsAfterTarget(t *testing.T) {
+	reconcile := func(t *testing.T, es *esv1.ExternalSecret) client.Client {
+		t.Helper()
+		h := newTestReconciler(t, es)
+		fakeProvider.WithGetSecret([]byte("short"), nil)
+		_, _, _ = h.reconcile(es)
+		return h.kube
+	}
+	newExternalSecret := func() *esv1.ExternalSecret {
+		return &esv1.ExternalSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec: esv1.ExternalSecretSpec{
+				SecretStoreRef:  esv1.SecretStoreRef{Name: testStoreName, Kind: esv1.SecretStoreKind},
+				RefreshInterval: &metav1.Duration{Duration: time.Hour},
+				Target:          esv1.ExternalSecretTarget{CreationPolicy: esv1.CreatePolicyOwner},
+				Targets:         []esv1.ExternalSecretSplitTarget{{Name: "db"}},
+				Data: []esv1.ExternalSecretData{
+					{SecretKey: "password", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "password"}},
+				},
+			},
+		}
+	}
+	secrets := func(t *testing.T, kube client.Client) []string {
+		t.Helper()
+		list := &v1.SecretList{}
+		require.NoError(t, kube.List(context.Background(), list))
+		names := make([]string, 0, len(list.Items))
+		for _, secret := range list.Items {
+			names = append(names, secret.Name)
+		}
+		return names
+	}
+
+	t.Run("invalid target", func(t *testing.T) {
+		es := newExternalSecret()
+		es.Spec.Target.Validation = []esv1.ExternalSecretKeyValidation{{Key: "password", MinLength: new(8)}}
+		assert.Empty(t, secrets(t, reconcile(t, es)))
+	})
+
+	t.Run("invalid split target", func(t *testing.T) {
+		es := newExternalSecret()
+		es.Spec.Targets[0].Template = &esv1.ExternalSecretTemplate{
+			EngineVersion: esv1.TemplateEngineV2,
+			Data:          map[string]string{"password": "{{ .password | nosuchfunction }}"},
+		}
+		assert.Empty(t, secrets(t, reconcile(t, es)))
+	})
+
+	t.Run("valid targets", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"app", "db"}, secrets(t, reconcile(t, newExternalSecret())))
+	})
+}
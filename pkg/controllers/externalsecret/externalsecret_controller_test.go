@@ -3094,6 +3094,26 @@ var _ = Describe("ExternalSecret refresh logic", func() {
 			Expect(shouldRefresh(es)).To(BeTrue())
 		})
 
+		It("should refresh on a change notification unless it is created once", func() {
+			es := &esv1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 1,
+				},
+				Spec: esv1.ExternalSecretSpec{
+					RefreshInterval: &metav1.Duration{Duration: time.Hour},
+				},
+				Status: esv1.ExternalSecretStatus{
+					RefreshTime: metav1.Now(),
+				},
+			}
+			es.Status.SyncedResourceVersion = ctrlutil.GetResourceVersion(es.ObjectMeta)
+			Expect(shouldRefreshOnNotification(es, 0)).To(BeFalse())
+			Expect(shouldRefreshOnNotification(es, 1)).To(BeTrue())
+
+			es.Spec.RefreshPolicy = esv1.RefreshPolicyCreatedOnce
+			Expect(shouldRefreshOnNotification(es, 1)).To(BeFalse())
+		})
+
 	})
 	Context("objectmeta hash", func() {
 		It("should produce different hashes for different k/v pairs", func() {
@@ -0,0 +1,249 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+// celCostLimit bounds the evaluation of a CEL rule, so an expensive expression cannot stall the reconciler.
+const celCostLimit = 1000000
+
+// validateSecretData checks the data of the rendered secret against target.validation.
+// The failures never contain the values of the secret, so the error is marked safe for the status.
+func validateSecretData(rules []esv1.ExternalSecretKeyValidation, data map[string][]byte, now time.Time) error {
+	var failures []string
+	for i := range rules {
+		rule := &rules[i]
+		value, ok := data[rule.Key]
+		var reason string
+		switch {
+		case !ok && rule.Optional:
+			continue
+		case !ok:
+			reason = "key is missing"
+		default:
+			reason = checkKeyValidation(rule, value, data, now)
+		}
+		if reason == "" {
+			continue
+		}
+		if rule.Message != "" {
+			reason = rule.Message
+		}
+		failures = append(failures, fmt.Sprintf("key %q: %s", rule.Key, reason))
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return ctrlutil.Safe(fmt.Errorf("%w: %s", ErrSecretValidationFailed, strings.Join(failures, "; ")))
+}
+
+// getValidationRequeueResult requeues after a whole refresh interval.
+// A failed validation does not update the refresh time, so getRequeueResult would requeue
+// immediately once the last successful refresh is older than the interval.
+func (r *Reconciler) getValidationRequeueResult(externalSecret *esv1.ExternalSecret) ctrl.Result {
+	refreshInterval := r.RequeueInterval
+	if externalSecret.Spec.RefreshInterval != nil {
+		refreshInterval = externalSecret.Spec.RefreshInterval.Duration
+	}
+	if refreshInterval <= 0 {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: refreshInterval}
+}
+
+// checkKeyValidation returns why the value does not satisfy the rule, or "" if it does.
+func checkKeyValidation(rule *esv1.ExternalSecretKeyValidation, value []byte, data map[string][]byte, now time.Time) string {
+	if rule.NonEmpty && len(value) == 0 {
+		return "value is empty"
+	}
+	if rule.MinLength != nil && len(value) < *rule.MinLength {
+		return fmt.Sprintf("value is shorter than %d bytes", *rule.MinLength)
+	}
+	if rule.MaxLength != nil && len(value) > *rule.MaxLength {
+		return fmt.Sprintf("value is longer than %d bytes", *rule.MaxLength)
+	}
+	if rule.Regex != "" {
+		re, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return fmt.Sprintf("invalid regex: %v", err)
+		}
+		if !re.Match(value) {
+			return "value does not match the regex"
+		}
+	}
+	if rule.Certificate != nil {
+		if reason := checkCertificates(rule.Certificate, value, now); reason != "" {
+			return reason
+		}
+	}
+	if rule.JSON != nil {
+		if reason := checkJSON(rule.JSON, value); reason != "" {
+			return reason
+		}
+	}
+	if rule.CEL != "" {
+		return checkCEL(rule.CEL, value, data)
+	}
+	return ""
+}
+
+// checkCertificates requires every certificate of the PEM bundle to be valid now, and for at least minValidity.
+// Blocks other than certificates, like the private key of a bundle, are ignored.
+func checkCertificates(rule *esv1.ExternalSecretCertificateValidation, value []byte, now time.Time) string {
+	var minValidity time.Duration
+	if rule.MinValidity != nil {
+		minValidity = rule.MinValidity.Duration
+	}
+
+	found := false
+	rest := value
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		found = true
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "value contains an invalid certificate"
+		}
+		switch {
+		case now.Before(cert.NotBefore):
+			return "certificate is not valid yet"
+		case now.After(cert.NotAfter):
+			return "certificate has expired"
+		case now.Add(minValidity).After(cert.NotAfter):
+			return fmt.Sprintf("certificate expires within %s", minValidity)
+		}
+	}
+	if !found {
+		return "value does not contain a PEM encoded certificate"
+	}
+	return ""
+}
+
+// checkJSON requires the value to be a JSON document matching the schema of the rule, if any.
+// Only the location of a schema violation is reported, as the messages of the validator quote the document.
+func checkJSON(rule *esv1.ExternalSecretJSONValidation, value []byte) string {
+	if !json.Valid(value) {
+		return "value is not valid JSON"
+	}
+	if rule.Schema == "" {
+		return ""
+	}
+
+	schemaDoc, err := jsonschema.UnmarshalJSON(strings.NewReader(rule.Schema))
+	if err != nil {
+		return "invalid JSON schema"
+	}
+	// the schema must be self-contained, references to files or URLs are not loaded
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(jsonschema.SchemeURLLoader{})
+	if err := compiler.AddResource("schema.json", schemaDoc); err != nil {
+		return "invalid JSON schema"
+	}
+	schema, err := compiler.Compile("schema.json")
+	if err != nil {
+		return "invalid JSON schema"
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(value))
+	if err != nil {
+		return "value is not valid JSON"
+	}
+	err = schema.Validate(doc)
+	if err == nil {
+		return ""
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return "value does not match the JSON schema"
+	}
+	// the innermost cause points at the value which failed the schema
+	for len(validationErr.Causes) > 0 {
+		validationErr = validationErr.Causes[0]
+	}
+	return fmt.Sprintf("value does not match the JSON schema at %q", "/"+strings.Join(validationErr.InstanceLocation, "/"))
+}
+
+// checkCEL requires the expression to evaluate to true.
+// Evaluation errors are not reported, as they may quote the values of the secret.
+func checkCEL(expression string, value []byte, data map[string][]byte) string {
+	env, ast, err := compileCEL(expression)
+	if err != nil {
+		return fmt.Sprintf("invalid CEL expression: %v", err)
+	}
+	program, err := env.Program(ast, cel.CostLimit(celCostLimit))
+	if err != nil {
+		return "invalid CEL expression"
+	}
+
+	vars := make(map[string]string, len(data))
+	for key, v := range data {
+		vars[key] = string(v)
+	}
+	out, _, err := program.Eval(map[string]any{"value": string(value), "data": vars})
+	if err != nil {
+		return "CEL expression could not be evaluated"
+	}
+	ok, isBool := out.Value().(bool)
+	if !isBool {
+		return "CEL expression does not evaluate to a bool"
+	}
+	if !ok {
+		return "CEL expression evaluated to false"
+	}
+	return ""
+}
+
+// compileCEL compiles an expression of target.validation.
+// The value of the key is available as value, and the data of the secret as data.
+func compileCEL(expression string) (*cel.Env, *cel.Ast, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("value", cel.StringType),
+		cel.Variable("data", cel.MapType(cel.StringType, cel.StringType)),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, nil, issues.Err()
+	}
+	return env, ast, nil
+}
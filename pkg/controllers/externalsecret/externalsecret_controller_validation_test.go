@@ -17,10 +17,23 @@ limitations under the License.
 package externalsecret
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
 )
 
 func TestValidateFetchedSecretValue(t *testing.T) {
@@ -124,3 +137,193 @@ func assertFetchedSecretValidationError(t *testing.T, err error, wantErr string)
 		t.Fatalf("error = %q, want substring %q", got, wantErr)
 	}
 }
+
+func TestValidateSecretData(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	valid := newValidationTestCert(t, now.Add(-time.Hour), now.Add(90*24*time.Hour))
+	expired := newValidationTestCert(t, now.Add(-48*time.Hour), now.Add(-time.Hour))
+	schema := `{"type": "object", "properties": {"port": {"type": "integer"}}, "required": ["port"]}`
+
+	tests := []struct {
+		name    string
+		rule    esv1.ExternalSecretKeyValidation
+		data    map[string][]byte
+		wantErr string
+	}{
+		{
+			name:    "missing key",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "password"},
+			data:    map[string][]byte{},
+			wantErr: `key "password": key is missing`,
+		},
+		{
+			name: "missing optional key",
+			rule: esv1.ExternalSecretKeyValidation{Key: "password", Optional: true, NonEmpty: true},
+			data: map[string][]byte{},
+		},
+		{
+			name:    "empty value",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "password", NonEmpty: true},
+			data:    map[string][]byte{"password": {}},
+			wantErr: `key "password": value is empty`,
+		},
+		{
+			name:    "too short",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "password", MinLength: new(16)},
+			data:    map[string][]byte{"password": []byte("hunter2")},
+			wantErr: `key "password": value is shorter than 16 bytes`,
+		},
+		{
+			name:    "too long",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "pin", MaxLength: new(4)},
+			data:    map[string][]byte{"pin": []byte("123456")},
+			wantErr: `key "pin": value is longer than 4 bytes`,
+		},
+		{
+			name: "matching regex",
+			rule: esv1.ExternalSecretKeyValidation{Key: "token", Regex: "^ghp_[A-Za-z0-9]+$"},
+			data: map[string][]byte{"token": []byte("ghp_abc123")},
+		},
+		{
+			name:    "regex mismatch with a custom message",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "token", Regex: "^ghp_", Message: "not a GitHub token"},
+			data:    map[string][]byte{"token": []byte("glpat-abc")},
+			wantErr: `key "token": not a GitHub token`,
+		},
+		{
+			name: "valid certificate",
+			rule: esv1.ExternalSecretKeyValidation{Key: "tls.crt", Certificate: &esv1.ExternalSecretCertificateValidation{MinValidity: &metav1.Duration{Duration: 30 * 24 * time.Hour}}},
+			data: map[string][]byte{"tls.crt": valid},
+		},
+		{
+			name:    "certificate expiring soon",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "tls.crt", Certificate: &esv1.ExternalSecretCertificateValidation{MinValidity: &metav1.Duration{Duration: 100 * 24 * time.Hour}}},
+			data:    map[string][]byte{"tls.crt": valid},
+			wantErr: `key "tls.crt": certificate expires within 2400h0m0s`,
+		},
+		{
+			name:    "expired certificate in a bundle",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "ca.crt", Certificate: &esv1.ExternalSecretCertificateValidation{}},
+			data:    map[string][]byte{"ca.crt": append(append([]byte{}, valid...), expired...)},
+			wantErr: `key "ca.crt": certificate has expired`,
+		},
+		{
+			name:    "not a certificate",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "tls.crt", Certificate: &esv1.ExternalSecretCertificateValidation{}},
+			data:    map[string][]byte{"tls.crt": []byte("s3cr3t")},
+			wantErr: `key "tls.crt": value does not contain a PEM encoded certificate`,
+		},
+		{
+			name:    "invalid JSON",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "config", JSON: &esv1.ExternalSecretJSONValidation{}},
+			data:    map[string][]byte{"config": []byte(`{"port": `)},
+			wantErr: `key "config": value is not valid JSON`,
+		},
+		{
+			name: "JSON matching the schema",
+			rule: esv1.ExternalSecretKeyValidation{Key: "config", JSON: &esv1.ExternalSecretJSONValidation{Schema: schema}},
+			data: map[string][]byte{"config": []byte(`{"port": 5432}`)},
+		},
+		{
+			name:    "JSON not matching the schema",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "config", JSON: &esv1.ExternalSecretJSONValidation{Schema: schema}},
+			data:    map[string][]byte{"config": []byte(`{"port": "s3cr3t"}`)},
+			wantErr: `key "config": value does not match the JSON schema at "/port"`,
+		},
+		{
+			name: "CEL expression",
+			rule: esv1.ExternalSecretKeyValidation{Key: "password", CEL: `value != data["username"] && size(value) >= 8`},
+			data: map[string][]byte{"username": []byte("admin"), "password": []byte("correct horse")},
+		},
+		{
+			name:    "CEL expression evaluating to false",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "password", CEL: `value != data["username"]`},
+			data:    map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+			wantErr: `key "password": CEL expression evaluated to false`,
+		},
+		{
+			name:    "CEL evaluation errors are not echoed",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "password", CEL: `int(value) > 0`},
+			data:    map[string][]byte{"password": []byte("s3cr3t")},
+			wantErr: `key "password": CEL expression could not be evaluated`,
+		},
+		{
+			name:    "CEL expression not returning a bool",
+			rule:    esv1.ExternalSecretKeyValidation{Key: "password", CEL: `size(value)`},
+			data:    map[string][]byte{"password": []byte("s3cr3t")},
+			wantErr: `key "password": CEL expression does not evaluate to a bool`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateSecretData([]esv1.ExternalSecretKeyValidation{tt.rule}, tt.data, now)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrSecretValidationFailed)
+			assert.Equal(t, ErrSecretValidationFailed.Error()+": "+tt.wantErr, ctrlutil.SafeMessage(err))
+			assert.NotContains(t, err.Error(), "s3cr3t")
+		})
+	}
+}
+
+func TestReconcileValidationFailed(t *testing.T) {
+	es := newTestExternalSecret()
+	es.Spec.Target.Validation = []esv1.ExternalSecretKeyValidation{
+		{Key: "foo", MinLength: new(8)},
+	}
+	h := newTestReconciler(t, es)
+	secretValue := func() string {
+		t.Helper()
+		return string(h.secret("es").Data["foo"])
+	}
+
+	// a secret failing the validation is not created
+	fakeProvider.WithGetSecret([]byte("tiny"), nil)
+	result, got, err := h.reconcile(es)
+	require.NoError(t, err)
+	cond := esv1.GetExternalSecretCondition(got.Status, esv1.ExternalSecretReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, esv1.ConditionReasonValidationFailed, cond.Reason)
+	assert.Contains(t, cond.Message, `key "foo": value is shorter than 8 bytes`)
+	assert.NotContains(t, cond.Message, "tiny")
+	assert.Equal(t, time.Nanosecond, result.RequeueAfter)
+	assert.Nil(t, h.secret("es"))
+
+	got = h.reconcileValue(es, "long enough")
+	cond = esv1.GetExternalSecretCondition(got.Status, esv1.ExternalSecretReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, esv1.ConditionReasonSecretSynced, cond.Reason)
+	assert.Equal(t, "long enough", secretValue())
+
+	// an existing secret is left untouched
+	fakeProvider.WithGetSecret([]byte("tiny"), nil)
+	result, got, err = h.reconcile(es)
+	require.NoError(t, err)
+	cond = esv1.GetExternalSecretCondition(got.Status, esv1.ExternalSecretReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, esv1.ConditionReasonValidationFailed, cond.Reason)
+	assert.Equal(t, time.Nanosecond, result.RequeueAfter)
+	assert.Equal(t, "long enough", secretValue())
+}
+
+// newValidationTestCert returns a PEM encoded self-signed certificate valid between notBefore and notAfter.
+func newValidationTestCert(t *testing.T, notBefore, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "validation"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,296 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+// defaultVersioningRetain is used when target.versioning.retain is not set.
+const defaultVersioningRetain = 3
+
+// generationHashLength is the length of the data hash in the name of a generation, like kustomize.
+const generationHashLength = 10
+
+// secretGenerationName returns the name of the generation of the secret holding the data with this hash,
+// truncating the secret name if needed.
+func secretGenerationName(secretName, dataHash string) string {
+	suffix := "-" + dataHash[:min(len(dataHash), generationHashLength)]
+	if len(secretName)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		secretName = strings.TrimRight(secretName[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.")
+	}
+	return secretName + suffix
+}
+
+// currentSecretGeneration returns the name of the current generation of the target secret,
+// or the name of the target if target.versioning is not set or no generation of it was written yet.
+func currentSecretGeneration(externalSecret *esv1.ExternalSecret, secretName string) string {
+	if externalSecret.Spec.Target.Versioning == nil || externalSecret.Status.Versioning == nil {
+		return secretName
+	}
+	generations := externalSecret.Status.Versioning.Generations
+	// the generations of a renamed target are not generations of the target anymore
+	if len(generations) == 0 || generations[0].Name != secretGenerationName(secretName, generations[0].DataHash) {
+		return secretName
+	}
+	return generations[0].Name
+}
+
+// isSecretGeneration returns true if the secret is a retained generation of the target, so it is not orphaned.
+func isSecretGeneration(externalSecret *esv1.ExternalSecret, name string) bool {
+	if externalSecret.Spec.Target.Versioning == nil || externalSecret.Status.Versioning == nil {
+		return false
+	}
+	return slices.ContainsFunc(externalSecret.Status.Versioning.Generations, func(generation esv1.ExternalSecretSecretGeneration) bool {
+		return generation.Name == name
+	})
+}
+
+// writeSecretGeneration writes the rendered secret to the immutable generation named after its data.
+// A generation is created once, only its metadata is updated afterwards.
+func (r *Reconciler) writeSecretGeneration(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, existingSecret *v1.Secret,
+	secretName string, mutationFunc func(secret *v1.Secret) error) error {
+	// the secret is rendered once to name the generation, the same way createSecret does
+	rendered := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secretName,
+			Namespace:   externalSecret.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Data: make(map[string][]byte),
+	}
+	if err := mutationFunc(rendered); err != nil {
+		return err
+	}
+	rendered.Name = secretGenerationName(secretName, rendered.Annotations[esv1.AnnotationDataHash])
+	rendered.Immutable = new(true)
+
+	if existingSecret.UID != "" && existingSecret.Name == rendered.Name {
+		return r.updateSecret(ctx, log, existingSecret, mutationFunc, externalSecret, rendered.Name)
+	}
+
+	// the data may go back to a generation which was retained
+	generation := &v1.Secret{}
+	err := r.apiReader().Get(ctx, client.ObjectKey{Name: rendered.Name, Namespace: externalSecret.Namespace}, generation)
+	if err == nil {
+		// never adopt a secret which is not a generation written for this ExternalSecret
+		if generation.Labels[esv1.LabelOwner] != esutils.ObjectHash(fmt.Sprintf("%v/%v", externalSecret.Namespace, externalSecret.Name)) {
+			return fmt.Errorf(errGenerationNotOwned, rendered.Name)
+		}
+		return r.updateSecret(ctx, log, generation, mutationFunc, externalSecret, rendered.Name)
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf(errGetGeneration, rendered.Name, err)
+	}
+
+	return r.createSecret(ctx, func(secret *v1.Secret) error {
+		rendered.ObjectMeta.DeepCopyInto(&secret.ObjectMeta)
+		secret.Immutable = rendered.Immutable
+		secret.Type = rendered.Type
+		secret.Data = rendered.Data
+		return nil
+	}, externalSecret, rendered.Name)
+}
+
+// syncSecretGenerations records the written generation of the secret, points the workloads of target.versioning
+// to it, then removes the generations beyond the retention count.
+func (r *Reconciler) syncSecretGenerations(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, secretName string, written *v1.Secret) error {
+	versioning := externalSecret.Spec.Target.Versioning
+	if versioning == nil {
+		// the generations are orphaned secrets now, they are deleted with them
+		externalSecret.Status.Versioning = nil
+		return nil
+	}
+
+	status := &esv1.ExternalSecretVersioningStatus{}
+	if externalSecret.Status.Versioning != nil {
+		status = externalSecret.Status.Versioning.DeepCopy()
+	}
+	if len(status.Generations) == 0 || status.Generations[0].Name != written.Name {
+		status.Generations = slices.DeleteFunc(status.Generations, func(generation esv1.ExternalSecretSecretGeneration) bool {
+			return generation.Name == written.Name
+		})
+		status.Generations = slices.Insert(status.Generations, 0, esv1.ExternalSecretSecretGeneration{
+			Name:      written.Name,
+			DataHash:  written.Annotations[esv1.AnnotationDataHash],
+			CreatedAt: metav1.Now(),
+		})
+	}
+	// the generation is recorded before the workloads are updated, so it is not deleted as an orphan on failure
+	externalSecret.Status.Versioning = status
+
+	// the workloads may still reference the target, or any generation of it
+	previous := map[string]bool{secretName: true}
+	for _, generation := range status.Generations[1:] {
+		previous[generation.Name] = true
+	}
+	updated, err := r.updateWorkloadReferences(ctx, log, externalSecret, written.Name, previous)
+	if err != nil {
+		return err
+	}
+	if len(updated) > 0 {
+		status.Workloads = updated
+		r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonUpdated, eventWorkloadsUpdated, strings.Join(updated, ", "), written.Name)
+	}
+
+	retain := versioning.Retain
+	if retain <= 0 {
+		retain = defaultVersioningRetain
+	}
+	if len(status.Generations) > retain {
+		for _, generation := range status.Generations[retain:] {
+			secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: generation.Name, Namespace: externalSecret.Namespace}}
+			if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf(errDeleteGeneration, generation.Name, err)
+			}
+			log.V(1).Info("deleted secret generation", "secret", generation.Name)
+		}
+		status.Generations = status.Generations[:retain]
+	}
+	return nil
+}
+
+// updateWorkloadReferences replaces the references to the previous secrets by the current one
+// in the workloads of target.versioning, and returns the updated workloads.
+func (r *Reconciler) updateWorkloadReferences(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, current string, previous map[string]bool) ([]string, error) {
+	versioning := externalSecret.Spec.Target.Versioning
+
+	seen := make(map[string]bool)
+	var workloads []client.Object
+	for _, ref := range versioning.Workloads {
+		workload, err := newWorkload(ref.Kind)
+		if err != nil {
+			return nil, err
+		}
+		err = r.apiReader().Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: externalSecret.Namespace}, workload)
+		if apierrors.IsNotFound(err) {
+			r.recorder.Eventf(externalSecret, v1.EventTypeWarning, esv1.ReasonUpdateFailed, eventWorkloadNotUpdated, fmt.Sprintf("%s/%s", ref.Kind, ref.Name))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf(errGetWorkload, fmt.Sprintf("%s/%s", ref.Kind, ref.Name), err)
+		}
+		seen[workloadRef(workload)] = true
+		workloads = append(workloads, workload)
+	}
+	selected, err := r.selectWorkloads(ctx, externalSecret.Namespace, versioning.Selector,
+		esv1.WorkloadKindDeployment, esv1.WorkloadKindStatefulSet, esv1.WorkloadKindDaemonSet, esv1.WorkloadKindCronJob)
+	if err != nil {
+		return nil, err
+	}
+	for _, workload := range selected {
+		if !seen[workloadRef(workload)] {
+			workloads = append(workloads, workload)
+		}
+	}
+
+	var updated []string
+	for _, workload := range workloads {
+		ref := workloadRef(workload)
+		original := workload.DeepCopyObject().(client.Object)
+		if !replaceSecretReferences(workloadPodSpec(workload), current, previous) {
+			continue
+		}
+		// the pod template changes, so the workload rolls out
+		patch := client.StrategicMergeFrom(original, client.MergeFromWithOptimisticLock{})
+		if err := r.Patch(ctx, workload, patch); err != nil {
+			return nil, fmt.Errorf(errUpdateWorkload, ref, err)
+		}
+		log.V(1).Info("updated secret references of workload", "workload", ref, "secret", current)
+		updated = append(updated, ref)
+	}
+	return updated, nil
+}
+
+// workloadPodSpec returns the pod spec of a workload.
+func workloadPodSpec(workload client.Object) *v1.PodSpec {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return &w.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		return &w.Spec.Template.Spec
+	case *batchv1.CronJob:
+		return &w.Spec.JobTemplate.Spec.Template.Spec
+	}
+	return &v1.PodSpec{}
+}
+
+// replaceSecretReferences replaces the references to the previous secrets by the current one in the volumes,
+// environment and image pull secrets of the pod spec. It returns true if a reference was replaced.
+func replaceSecretReferences(spec *v1.PodSpec, current string, previous map[string]bool) bool {
+	var replaced bool
+	replace := func(name *string) {
+		if *name != current && previous[*name] {
+			*name = current
+			replaced = true
+		}
+	}
+
+	for i := range spec.Volumes {
+		volume := &spec.Volumes[i]
+		if volume.Secret != nil {
+			replace(&volume.Secret.SecretName)
+		}
+		if volume.Projected != nil {
+			for j := range volume.Projected.Sources {
+				if source := volume.Projected.Sources[j].Secret; source != nil {
+					replace(&source.Name)
+				}
+			}
+		}
+	}
+	replaceEnv := func(env []v1.EnvVar, envFrom []v1.EnvFromSource) {
+		for i := range env {
+			if env[i].ValueFrom != nil && env[i].ValueFrom.SecretKeyRef != nil {
+				replace(&env[i].ValueFrom.SecretKeyRef.Name)
+			}
+		}
+		for i := range envFrom {
+			if envFrom[i].SecretRef != nil {
+				replace(&envFrom[i].SecretRef.Name)
+			}
+		}
+	}
+	for i := range spec.InitContainers {
+		replaceEnv(spec.InitContainers[i].Env, spec.InitContainers[i].EnvFrom)
+	}
+	for i := range spec.Containers {
+		replaceEnv(spec.Containers[i].Env, spec.Containers[i].EnvFrom)
+	}
+	for i := range spec.ImagePullSecrets {
+		replace(&spec.ImagePullSecrets[i].Name)
+	}
+	return replaced
+}
@@ -0,0 +1,220 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+func TestSecretGenerationName(t *testing.T) {
+	assert.Equal(t, "db-0123456789", secretGenerationName("db", "0123456789abcdef"))
+	assert.Equal(t, "db-0123", secretGenerationName("db", "0123"))
+
+	name := secretGenerationName(strings.Repeat("a", 250), "0123456789abcdef")
+	assert.Equal(t, strings.Repeat("a", 242)+"-0123456789", name)
+	name = secretGenerationName(strings.Repeat("a", 241)+"-b", "0123456789abcdef")
+	assert.Equal(t, strings.Repeat("a", 241)+"-0123456789", name)
+}
+
+func TestCurrentSecretGeneration(t *testing.T) {
+	es := &esv1.ExternalSecret{
+		Spec: esv1.ExternalSecretSpec{Target: esv1.ExternalSecretTarget{Versioning: &esv1.ExternalSecretVersioning{}}},
+		Status: esv1.ExternalSecretStatus{Versioning: &esv1.ExternalSecretVersioningStatus{
+			Generations: []esv1.ExternalSecretSecretGeneration{
+				{Name: "db-0123456789", DataHash: "0123456789abcdef"},
+				{Name: "db-abcdef0123", DataHash: "abcdef0123456789"},
+			},
+		}},
+	}
+	assert.Equal(t, "db-0123456789", currentSecretGeneration(es, "db"))
+	assert.True(t, isSecretGeneration(es, "db-abcdef0123"))
+	assert.False(t, isSecretGeneration(es, "db"))
+
+	// the generations of a renamed target are not used
+	assert.Equal(t, "app", currentSecretGeneration(es, "app"))
+
+	es.Spec.Target.Versioning = nil
+	assert.Equal(t, "db", currentSecretGeneration(es, "db"))
+	assert.False(t, isSecretGeneration(es, "db-abcdef0123"))
+}
+
+func TestReplaceSecretReferences(t *testing.T) {
+	previous := map[string]bool{"db": true, "db-old": true}
+	spec := &v1.PodSpec{
+		Volumes: []v1.Volume{
+			{Name: "creds", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "db-old"}}},
+			{Name: "other", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "other"}}},
+			{Name: "projected", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: []v1.VolumeProjection{
+				{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "db"}}},
+			}}}},
+		},
+		InitContainers: []v1.Container{{
+			EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "db"}}}},
+		}},
+		Containers: []v1.Container{{
+			Env: []v1.EnvVar{
+				{Name: "PASSWORD", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "db"}, Key: "password"}}},
+				{Name: "USER", Value: "admin"},
+			},
+		}},
+		ImagePullSecrets: []v1.LocalObjectReference{{Name: "registry"}},
+	}
+
+	require.True(t, replaceSecretReferences(spec, "db-new", previous))
+	assert.Equal(t, "db-new", spec.Volumes[0].Secret.SecretName)
+	assert.Equal(t, "other", spec.Volumes[1].Secret.SecretName)
+	assert.Equal(t, "db-new", spec.Volumes[2].Projected.Sources[0].Secret.Name)
+	assert.Equal(t, "db-new", spec.InitContainers[0].EnvFrom[0].SecretRef.Name)
+	assert.Equal(t, "db-new", spec.Containers[0].Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "registry", spec.ImagePullSecrets[0].Name)
+
+	assert.False(t, replaceSecretReferences(spec, "db-new", previous))
+}
+
+func TestReconcileVersioning(t *testing.T) {
+	ctx := context.Background()
+	es := newTestExternalSecret()
+	es.Spec.Target.Versioning = &esv1.ExternalSecretVersioning{
+		Retain:    2,
+		Workloads: []esv1.ExternalSecretWorkloadRef{{Kind: esv1.WorkloadKindDeployment, Name: "app"}},
+		Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"uses": "es"}},
+	}
+	app := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			Volumes: []v1.Volume{{Name: "creds", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "es"}}}},
+		}}},
+	}
+	backup := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", Labels: map[string]string{"uses": "es"}},
+		Spec: batchv1.CronJobSpec{JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:    "backup",
+				EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "es"}}}},
+			}},
+		}}}}},
+	}
+	h := newTestReconciler(t, es, app, backup)
+	reconcile := func(value string) []esv1.ExternalSecretSecretGeneration {
+		t.Helper()
+		got := h.reconcileValue(es, value)
+		require.NotNil(t, got.Status.Versioning)
+		require.NotEmpty(t, got.Status.Versioning.Generations)
+		assert.Equal(t, got.Status.Versioning.Generations[0].Name, got.Status.Binding.Name)
+		return got.Status.Versioning.Generations
+	}
+	references := func() (string, string) {
+		t.Helper()
+		require.NoError(t, h.kube.Get(ctx, client.ObjectKeyFromObject(app), app))
+		require.NoError(t, h.kube.Get(ctx, client.ObjectKeyFromObject(backup), backup))
+		return app.Spec.Template.Spec.Volumes[0].Secret.SecretName,
+			backup.Spec.JobTemplate.Spec.Template.Spec.Containers[0].EnvFrom[0].SecretRef.Name
+	}
+	exists := func(name string) bool {
+		t.Helper()
+		return h.secret(name) != nil
+	}
+
+	generations := reconcile("v1")
+	first := generations[0].Name
+	assert.True(t, strings.HasPrefix(first, "es-"), first)
+	secret := h.secret(first)
+	require.NotNil(t, secret)
+	assert.True(t, *secret.Immutable)
+	assert.Equal(t, []byte("v1"), secret.Data["foo"])
+	assert.False(t, exists("es"))
+	appRef, backupRef := references()
+	assert.Equal(t, first, appRef)
+	assert.Equal(t, first, backupRef)
+
+	// the same data is the same generation
+	generations = reconcile("v1")
+	assert.Len(t, generations, 1)
+	assert.Equal(t, first, generations[0].Name)
+
+	generations = reconcile("v2")
+	require.Len(t, generations, 2)
+	second := generations[0].Name
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, first, generations[1].Name)
+	assert.True(t, exists(first))
+	appRef, backupRef = references()
+	assert.Equal(t, second, appRef)
+	assert.Equal(t, second, backupRef)
+
+	// the generations beyond the retention count are deleted
+	generations = reconcile("v3")
+	require.Len(t, generations, 2)
+	third := generations[0].Name
+	assert.Equal(t, second, generations[1].Name)
+	assert.False(t, exists(first))
+	assert.True(t, exists(second))
+
+	// a retained generation becomes the current one again
+	generations = reconcile("v2")
+	require.Len(t, generations, 2)
+	assert.Equal(t, second, generations[0].Name)
+	assert.Equal(t, third, generations[1].Name)
+	appRef, _ = references()
+	assert.Equal(t, second, appRef)
+}
+
+func TestWriteSecretGenerationAdoptsOnlyOwnGenerations(t *testing.T) {
+	ctx := context.Background()
+	data := map[string][]byte{"foo": []byte("bar")}
+	name := secretGenerationName("es", esutils.ObjectHash(data))
+	mutationFunc := func(secret *v1.Secret) error {
+		secret.Data = data
+		secret.Annotations = map[string]string{esv1.AnnotationDataHash: esutils.ObjectHash(data)}
+		return nil
+	}
+	es := newTestExternalSecret()
+	es.Spec.Target.Versioning = &esv1.ExternalSecretVersioning{}
+	generation := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: "generation"},
+		Data:       map[string][]byte{"foo": []byte("foreign")},
+	}
+	h := newTestReconciler(t, generation)
+
+	// a secret named like the generation which was not written for the ExternalSecret is left untouched
+	err := h.r.writeSecretGeneration(ctx, logr.Discard(), es, &v1.Secret{}, "es", mutationFunc)
+	require.ErrorContains(t, err, "it is not a generation of this ExternalSecret")
+	got := &v1.Secret{}
+	require.NoError(t, h.kube.Get(ctx, client.ObjectKeyFromObject(generation), got))
+	assert.Equal(t, generation.Data, got.Data)
+
+	// a retained generation of the ExternalSecret is written again
+	got.Labels = map[string]string{esv1.LabelOwner: esutils.ObjectHash("default/es")}
+	require.NoError(t, h.kube.Update(ctx, got))
+	require.NoError(t, h.r.writeSecretGeneration(ctx, logr.Discard(), es, &v1.Secret{}, "es", mutationFunc))
+	require.NoError(t, h.kube.Get(ctx, client.ObjectKeyFromObject(generation), got))
+	assert.Equal(t, data, got.Data)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// Ensures Validator implements the admission.Validator interface correctly.
+var _ admission.Validator[*esv1.ExternalSecret] = &Validator{}
+
+// Validator validates ExternalSecrets like esv1.ExternalSecretValidator,
+// and compiles the CEL expressions of target.validation in the environment the controller evaluates them in.
+type Validator struct {
+	esv1.ExternalSecretValidator
+}
+
+// SetupWebhookWithManager registers the ExternalSecret webhook with the controller manager.
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &esv1.ExternalSecret{}).
+		WithValidator(&Validator{}).
+		Complete()
+}
+
+// ValidateCreate validates the creation of an external secret object.
+func (v *Validator) ValidateCreate(ctx context.Context, obj *esv1.ExternalSecret) (admission.Warnings, error) {
+	warnings, err := v.ExternalSecretValidator.ValidateCreate(ctx, obj)
+	return warnings, errors.Join(err, validateCELRules(obj))
+}
+
+// ValidateUpdate validates the update of an external secret object.
+func (v *Validator) ValidateUpdate(ctx context.Context, oldObj, newObj *esv1.ExternalSecret) (admission.Warnings, error) {
+	warnings, err := v.ExternalSecretValidator.ValidateUpdate(ctx, oldObj, newObj)
+	return warnings, errors.Join(err, validateCELRules(newObj))
+}
+
+// validateCELRules compiles the CEL expressions of target.validation, which must evaluate to a bool.
+func validateCELRules(es *esv1.ExternalSecret) error {
+	var errs error
+	for i, rule := range es.Spec.Target.Validation {
+		if rule.CEL == "" {
+			continue
+		}
+		_, ast, err := compileCEL(rule.CEL)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("target.validation[%d]: invalid cel expression: %w", i, err))
+			continue
+		}
+		if out := ast.OutputType(); !out.IsExactType(cel.BoolType) && !out.IsExactType(cel.DynType) {
+			errs = errors.Join(errs, fmt.Errorf("target.validation[%d]: cel expression must evaluate to a bool, not %s", i, out))
+		}
+	}
+	return errs
+}
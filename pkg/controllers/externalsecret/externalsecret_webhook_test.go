@@ -0,0 +1,55 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestValidatorCompilesCEL(t *testing.T) {
+	withRules := func(rules ...esv1.ExternalSecretKeyValidation) *esv1.ExternalSecret {
+		return &esv1.ExternalSecret{Spec: esv1.ExternalSecretSpec{
+			Target: esv1.ExternalSecretTarget{Validation: rules},
+			Data:   []esv1.ExternalSecretData{{SecretKey: "token"}},
+		}}
+	}
+	v := &Validator{}
+	ctx := context.Background()
+
+	_, err := v.ValidateCreate(ctx, withRules(esv1.ExternalSecretKeyValidation{Key: "token", CEL: `value.startsWith("sk_") && data["user"] != ""`}))
+	require.NoError(t, err)
+
+	_, err = v.ValidateCreate(ctx, withRules(
+		esv1.ExternalSecretKeyValidation{Key: "token", CEL: `size(value)`},
+		esv1.ExternalSecretKeyValidation{Key: "token", CEL: `secret == ""`},
+	))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "target.validation[0]: cel expression must evaluate to a bool, not int")
+	assert.Contains(t, err.Error(), "target.validation[1]: invalid cel expression: ERROR: <input>:1:1: undeclared reference to 'secret'")
+
+	// the rules of the API are still validated
+	_, err = v.ValidateUpdate(ctx, nil, withRules(esv1.ExternalSecretKeyValidation{Key: "token", Regex: "(", CEL: "value ="}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "target.validation[0]: invalid regex")
+	assert.Contains(t, err.Error(), "target.validation[0]: invalid cel expression")
+}
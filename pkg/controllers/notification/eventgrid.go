@@ -0,0 +1,81 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	eventGridValidationEvent = "Microsoft.EventGrid.SubscriptionValidationEvent"
+	eventGridKeyVaultPrefix  = "Microsoft.KeyVault."
+)
+
+// eventGridKeyVaultPrefixes map the object types of Key Vault events to the prefixes of the keys of the Azure provider.
+var eventGridKeyVaultPrefixes = map[string]string{
+	"Secret":      "secret/",
+	"Certificate": "cert/",
+	"Key":         "key/",
+}
+
+// eventGridEvent is an event of the Event Grid schema.
+type eventGridEvent struct {
+	EventType string `json:"eventType"`
+	Data      struct {
+		ValidationCode string `json:"validationCode"`
+		ObjectType     string `json:"ObjectType"`
+		ObjectName     string `json:"ObjectName"`
+	} `json:"data"`
+}
+
+// eventGridSource accepts the Key Vault events of an Event Grid webhook subscription.
+// Event Grid cannot sign its deliveries, so the endpoint URL of the subscription holds a shared token.
+type eventGridSource struct {
+	token string
+}
+
+func (s *eventGridSource) keys(w http.ResponseWriter, req *http.Request, body []byte) ([]string, bool, error) {
+	if subtle.ConstantTimeCompare([]byte(req.URL.Query().Get("token")), []byte(s.token)) != 1 {
+		return nil, false, fmt.Errorf("%w: token mismatch", errUnauthorized)
+	}
+
+	var events []eventGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errBadRequest, err)
+	}
+	var keys []string
+	for _, ev := range events {
+		if ev.EventType == eventGridValidationEvent {
+			// the subscription is validated by echoing the validation code
+			w.Header().Set("Content-Type", "application/json")
+			err := json.NewEncoder(w).Encode(map[string]string{"validationResponse": ev.Data.ValidationCode})
+			return nil, true, err
+		}
+		if !strings.HasPrefix(ev.EventType, eventGridKeyVaultPrefix) || ev.Data.ObjectName == "" {
+			continue
+		}
+		keys = append(keys, ev.Data.ObjectName)
+		if prefix, ok := eventGridKeyVaultPrefixes[ev.Data.ObjectType]; ok {
+			keys = append(keys, prefix+ev.Data.ObjectName)
+		}
+	}
+	return uniqueKeys(keys), false, nil
+}
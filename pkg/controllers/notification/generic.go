@@ -0,0 +1,68 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// GenericSignatureHeader holds the HMAC-SHA256 of the body of a generic notification, as `sha256=<hex>`.
+	GenericSignatureHeader = "X-Signature-256"
+	genericSignaturePrefix = "sha256="
+)
+
+// GenericNotification is the body of a generic notification.
+type GenericNotification struct {
+	// Keys are the remote keys which changed.
+	Keys []string `json:"keys"`
+}
+
+// genericSource accepts notifications signed with a shared secret, for providers without a native notification service.
+type genericSource struct {
+	secret []byte
+}
+
+func (s *genericSource) keys(_ http.ResponseWriter, req *http.Request, body []byte) ([]string, bool, error) {
+	signature, ok := strings.CutPrefix(req.Header.Get(GenericSignatureHeader), genericSignaturePrefix)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: missing %s header", errUnauthorized, GenericSignatureHeader)
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, GenericSignature(s.secret, body)) {
+		return nil, false, fmt.Errorf("%w: signature mismatch", errUnauthorized)
+	}
+
+	var notification GenericNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errBadRequest, err)
+	}
+	return notification.Keys, false, nil
+}
+
+// GenericSignature returns the HMAC-SHA256 of the body of a generic notification.
+func GenericSignature(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
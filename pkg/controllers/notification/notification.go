@@ -0,0 +1,396 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notification implements a receiver for the change notifications of providers.
+// A notification names the remote keys which changed, and only the ExternalSecrets and PushSecrets
+// referencing them are refreshed, instead of waiting for their refresh interval.
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+var (
+	// KindExternalSecret is the kind of the ExternalSecrets refreshed by the receiver.
+	KindExternalSecret = esv1.ExtSecretKind
+	// KindPushSecret is the kind of the PushSecrets refreshed by the receiver.
+	KindPushSecret = esv1alpha1.PushSecretKind
+)
+
+const (
+	// RemoteKeysField indexes ExternalSecrets and PushSecrets by the remote keys they reference.
+	RemoteKeysField = "spec.remoteKeys"
+
+	// maxBodySize bounds the size of a notification.
+	maxBodySize = 1 << 20
+	// eventBufferSize is the number of objects which can wait to be enqueued per kind.
+	eventBufferSize = 1024
+
+	shutdownTimeout   = 10 * time.Second
+	readHeaderTimeout = 10 * time.Second
+)
+
+var (
+	errUnauthorized = errors.New("notification could not be authenticated")
+	errBadRequest   = errors.New("invalid notification")
+)
+
+// Options configures the sources accepted by the receiver. A source is enabled when its credentials are set.
+type Options struct {
+	// Addr is the address the receiver binds to.
+	Addr string
+
+	// GenericSecret is the shared secret of the HMAC signature of generic notifications.
+	GenericSecret []byte
+
+	// SNSTopicARNs are the SNS topics allowed to send notifications.
+	SNSTopicARNs []string
+
+	// PubSubAudience is the audience of the OIDC tokens of Pub/Sub push subscriptions.
+	PubSubAudience string
+	// PubSubServiceAccount is the email of the service account signing the OIDC tokens, any if empty.
+	PubSubServiceAccount string
+
+	// EventGridToken is the shared token passed in the `token` query parameter by Event Grid subscriptions.
+	EventGridToken string
+
+	// Elected is closed once the replica is the leader, as only the controllers of the leader can refresh objects.
+	// Replicas which are not the leader answer notifications with 503, so they are retried by the provider.
+	// The receiver always refreshes objects if it is nil.
+	Elected <-chan struct{}
+}
+
+// Receiver serves the endpoints of the enabled sources, and enqueues the objects referencing the changed keys.
+type Receiver struct {
+	client  client.Reader
+	log     logr.Logger
+	addr    string
+	mux     *http.ServeMux
+	elected <-chan struct{}
+
+	mu     sync.Mutex
+	events map[string]chan event.GenericEvent
+}
+
+// NewReceiver returns a receiver serving the sources enabled in the options.
+// The client must be able to list with RemoteKeysField, so it is usually the cached client of the manager.
+func NewReceiver(cl client.Reader, log logr.Logger, opts Options) (*Receiver, error) {
+	r := &Receiver{
+		client:  cl,
+		log:     log,
+		addr:    opts.Addr,
+		mux:     http.NewServeMux(),
+		elected: opts.Elected,
+		events:  make(map[string]chan event.GenericEvent),
+	}
+
+	var sources int
+	if len(opts.GenericSecret) > 0 {
+		r.handle("/notify/generic", &genericSource{secret: opts.GenericSecret})
+		sources++
+	}
+	if len(opts.SNSTopicARNs) > 0 {
+		r.handle("/notify/aws/sns", newSNSSource(opts.SNSTopicARNs))
+		sources++
+	}
+	if opts.PubSubAudience != "" {
+		src, err := newPubSubSource(opts.PubSubAudience, opts.PubSubServiceAccount)
+		if err != nil {
+			return nil, err
+		}
+		r.handle("/notify/gcp/pubsub", src)
+		sources++
+	}
+	if opts.EventGridToken != "" {
+		r.handle("/notify/azure/eventgrid", &eventGridSource{token: opts.EventGridToken})
+		sources++
+	}
+	if sources == 0 {
+		return nil, errors.New("no notification source is configured")
+	}
+	return r, nil
+}
+
+// Watch returns the events of the objects of the kind to refresh, to be watched by their controller.
+// Objects of kinds which are not watched are never looked up. Watch returns nil on a nil receiver,
+// so controllers can be set up the same way whether the receiver is enabled or not.
+func (r *Receiver) Watch(kind string) <-chan event.GenericEvent {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch, ok := r.events[kind]
+	if !ok {
+		ch = make(chan event.GenericEvent, eventBufferSize)
+		r.events[kind] = ch
+	}
+	return ch
+}
+
+// Start serves the receiver until the context is cancelled.
+func (r *Receiver) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              r.addr,
+		Handler:           r.mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		r.log.Info("starting notification receiver", "addr", r.addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection runs the receiver on all replicas, so a Service can route to any of them.
+// Only the leader refreshes objects, see Options.Elected.
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// isLeader reports whether the controllers of this replica are running.
+func (r *Receiver) isLeader() bool {
+	if r.elected == nil {
+		return true
+	}
+	select {
+	case <-r.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// source parses the notifications of a provider.
+type source interface {
+	// keys authenticates the request and returns the changed remote keys.
+	// A source can answer the request itself, like a subscription handshake, by returning handled.
+	keys(w http.ResponseWriter, req *http.Request, body []byte) (keys []string, handled bool, err error)
+}
+
+func (r *Receiver) handle(path string, src source) {
+	r.mux.HandleFunc(path, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := readBody(w, req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		keys, handled, err := src.keys(w, req, body)
+		switch {
+		case errors.Is(err, errUnauthorized):
+			r.log.V(1).Info("rejected notification", "path", path, "error", err)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		case err != nil:
+			r.log.V(1).Info("invalid notification", "path", path, "error", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case handled:
+			return
+		}
+		if !r.isLeader() {
+			http.Error(w, "not the leader", http.StatusServiceUnavailable)
+			return
+		}
+		if err := r.refresh(req.Context(), keys); err != nil {
+			r.log.Error(err, "could not refresh objects of notification", "path", path)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+func readBody(w http.ResponseWriter, req *http.Request) ([]byte, error) {
+	body := http.MaxBytesReader(w, req.Body, maxBodySize)
+	defer func() {
+		_ = body.Close()
+	}()
+	return io.ReadAll(body)
+}
+
+// refresh marks the objects referencing the keys as notified, and enqueues them in their controller.
+func (r *Receiver) refresh(ctx context.Context, keys []string) error {
+	r.mu.Lock()
+	events := make(map[string]chan event.GenericEvent, len(r.events))
+	for kind, ch := range r.events {
+		events[kind] = ch
+	}
+	r.mu.Unlock()
+
+	for kind, ch := range events {
+		objs, err := r.find(ctx, kind, keys)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			if es, ok := obj.(*esv1.ExternalSecret); ok {
+				evictValues(es, keys)
+			}
+			Notify(kind, client.ObjectKeyFromObject(obj))
+			r.log.V(1).Info("refreshing on notification", "kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+			select {
+			case ch <- event.GenericEvent{Object: obj}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// evictValues drops the keys from the value caches of the stores the ExternalSecret reads from,
+// so that its refresh fetches the changed values from the provider.
+func evictValues(es *esv1.ExternalSecret, keys []string) {
+	refs := []*esv1.SecretStoreRef{&es.Spec.SecretStoreRef}
+	for i := range es.Spec.Data {
+		if src := es.Spec.Data[i].SourceRef; src != nil {
+			refs = append(refs, &src.SecretStoreRef)
+		}
+	}
+	for i := range es.Spec.DataFrom {
+		if src := es.Spec.DataFrom[i].SourceRef; src != nil {
+			refs = append(refs, src.SecretStoreRef)
+		}
+	}
+	for _, ref := range refs {
+		if ref == nil || ref.Name == "" {
+			continue
+		}
+		kind, namespace := esv1.SecretStoreKind, es.Namespace
+		if ref.Kind == esv1.ClusterSecretStoreKind {
+			kind, namespace = esv1.ClusterSecretStoreKind, ""
+		}
+		for _, key := range keys {
+			secretstore.EvictValues(kind, namespace, ref.Name, key)
+		}
+	}
+}
+
+// find returns the objects of the kind referencing any of the keys.
+func (r *Receiver) find(ctx context.Context, kind string, keys []string) ([]client.Object, error) {
+	seen := make(map[types.NamespacedName]bool)
+	var objs []client.Object
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		var found []client.Object
+		switch kind {
+		case KindExternalSecret:
+			var list esv1.ExternalSecretList
+			if err := r.client.List(ctx, &list, client.MatchingFields{RemoteKeysField: key}); err != nil {
+				return nil, fmt.Errorf("could not list %s: %w", kind, err)
+			}
+			for i := range list.Items {
+				found = append(found, &list.Items[i])
+			}
+		case KindPushSecret:
+			var list esv1alpha1.PushSecretList
+			if err := r.client.List(ctx, &list, client.MatchingFields{RemoteKeysField: key}); err != nil {
+				return nil, fmt.Errorf("could not list %s: %w", kind, err)
+			}
+			for i := range list.Items {
+				found = append(found, &list.Items[i])
+			}
+		}
+		for _, obj := range found {
+			name := client.ObjectKeyFromObject(obj)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// IndexExternalSecretRemoteKeys is the index function of RemoteKeysField for ExternalSecrets.
+// Keys found by dataFrom.find cannot be known in advance, so they are not indexed.
+func IndexExternalSecretRemoteKeys(obj client.Object) []string {
+	es, ok := obj.(*esv1.ExternalSecret)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, data := range es.Spec.Data {
+		keys = append(keys, data.RemoteRef.Key)
+	}
+	for _, data := range es.Spec.DataFrom {
+		if data.Extract != nil {
+			keys = append(keys, data.Extract.Key)
+		}
+	}
+	return uniqueKeys(keys)
+}
+
+// IndexPushSecretRemoteKeys is the index function of RemoteKeysField for PushSecrets.
+func IndexPushSecretRemoteKeys(obj client.Object) []string {
+	ps, ok := obj.(*esv1alpha1.PushSecret)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(ps.Spec.Data))
+	for _, data := range ps.Spec.Data {
+		keys = append(keys, data.Match.RemoteRef.RemoteKey)
+	}
+	return uniqueKeys(keys)
+}
+
+func uniqueKeys(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	out := keys[:0]
+	for _, key := range keys {
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, key)
+	}
+	return out
+}
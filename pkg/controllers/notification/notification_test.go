@@ -0,0 +1,314 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/idtoken"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func signedGenericRequest(secret []byte, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/notify/generic", bytes.NewBufferString(body))
+	req.Header.Set(GenericSignatureHeader, genericSignaturePrefix+hex.EncodeToString(GenericSignature(secret, []byte(body))))
+	return req
+}
+
+func TestGenericSource(t *testing.T) {
+	secret := []byte("shared")
+	src := &genericSource{secret: secret}
+	body := `{"keys":["db/password","api-token"]}`
+
+	keys, handled, err := src.keys(httptest.NewRecorder(), signedGenericRequest(secret, body), []byte(body))
+	require.NoError(t, err)
+	assert.False(t, handled)
+	assert.Equal(t, []string{"db/password", "api-token"}, keys)
+
+	_, _, err = src.keys(httptest.NewRecorder(), signedGenericRequest([]byte("other"), body), []byte(body))
+	assert.ErrorIs(t, err, errUnauthorized)
+
+	unsigned := httptest.NewRequest(http.MethodPost, "/notify/generic", bytes.NewBufferString(body))
+	_, _, err = src.keys(httptest.NewRecorder(), unsigned, []byte(body))
+	assert.ErrorIs(t, err, errUnauthorized)
+}
+
+func newSNSTestSource(t *testing.T, topic, certURL string) (*snsSource, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	src := newSNSSource([]string{topic})
+	src.certs[certURL] = cert
+	return src, key
+}
+
+func signSNSMessage(t *testing.T, key *rsa.PrivateKey, msg *snsMessage) []byte {
+	t.Helper()
+	msg.SignatureVersion = "2"
+	digest := sha256.Sum256([]byte(snsStringToSign(msg)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	msg.Signature = base64.StdEncoding.EncodeToString(signature)
+	body, err := json.Marshal(msg)
+	require.NoError(t, err)
+	return body
+}
+
+func TestSNSSource(t *testing.T) {
+	topic := "arn:aws:sns:eu-west-1:123456789012:secret-changes"
+	certURL := "https://sns.eu-west-1.amazonaws.com/SimpleNotificationService-test.pem"
+	src, key := newSNSTestSource(t, topic, certURL)
+	req := httptest.NewRequest(http.MethodPost, "/notify/aws/sns", http.NoBody)
+
+	event := `{"source":"aws.secretsmanager","detail":{"eventName":"PutSecretValue","requestParameters":{"secretId":"arn:aws:secretsmanager:eu-west-1:123456789012:secret:prod/db-AbC123"}}}`
+	notification := func() *snsMessage {
+		return &snsMessage{
+			Type:           snsTypeNotification,
+			MessageID:      "1",
+			TopicArn:       topic,
+			Message:        event,
+			Timestamp:      "2026-01-01T00:00:00.000Z",
+			SigningCertURL: certURL,
+		}
+	}
+
+	keys, handled, err := src.keys(httptest.NewRecorder(), req, signSNSMessage(t, key, notification()))
+	require.NoError(t, err)
+	assert.False(t, handled)
+	assert.Equal(t, []string{"arn:aws:secretsmanager:eu-west-1:123456789012:secret:prod/db-AbC123", "prod/db"}, keys)
+
+	t.Run("tampered message", func(t *testing.T) {
+		msg := notification()
+		body := signSNSMessage(t, key, msg)
+		body = bytes.Replace(body, []byte("prod/db"), []byte("prod/xx"), 1)
+		_, _, err := src.keys(httptest.NewRecorder(), req, body)
+		assert.ErrorIs(t, err, errUnauthorized)
+	})
+
+	t.Run("topic not allowed", func(t *testing.T) {
+		msg := notification()
+		msg.TopicArn = "arn:aws:sns:eu-west-1:210987654321:other"
+		_, _, err := src.keys(httptest.NewRecorder(), req, signSNSMessage(t, key, msg))
+		assert.ErrorIs(t, err, errUnauthorized)
+	})
+
+	t.Run("certificate not served by SNS", func(t *testing.T) {
+		msg := notification()
+		msg.SigningCertURL = "https://example.com/sns.pem"
+		_, _, err := src.keys(httptest.NewRecorder(), req, signSNSMessage(t, key, msg))
+		assert.ErrorIs(t, err, errUnauthorized)
+	})
+}
+
+func TestEventBridgeKeys(t *testing.T) {
+	assert.Equal(t, []string{"/app/db/password"},
+		eventBridgeKeys(`{"source":"aws.ssm","detail-type":"Parameter Store Change","detail":{"name":"/app/db/password","operation":"Update"}}`))
+	assert.Equal(t, []string{"prod/db"},
+		eventBridgeKeys(`{"source":"aws.secretsmanager","detail":{"eventName":"RotationSucceeded","additionalEventData":{"SecretId":"prod/db"}}}`))
+	assert.Empty(t, eventBridgeKeys("not an event"))
+}
+
+func TestPubSubSource(t *testing.T) {
+	payload := &idtoken.Payload{
+		Issuer:   "https://accounts.google.com",
+		Audience: "https://eso.example.com/notify/gcp/pubsub",
+		Claims:   map[string]any{"email": "pubsub@project.iam.gserviceaccount.com", "email_verified": true},
+	}
+	src := &pubSubSource{
+		audience:       payload.Audience,
+		serviceAccount: "pubsub@project.iam.gserviceaccount.com",
+		validate: func(_ context.Context, token, audience string) (*idtoken.Payload, error) {
+			if token != "valid" || audience != payload.Audience {
+				return nil, errors.New("invalid token")
+			}
+			return payload, nil
+		},
+	}
+	body := []byte(`{"message":{"attributes":{"eventType":"SECRET_VERSION_ADD","secretId":"projects/my-project/secrets/db-password"}},"subscription":"projects/my-project/subscriptions/eso"}`)
+	request := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/notify/gcp/pubsub", http.NoBody)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req
+	}
+
+	keys, _, err := src.keys(httptest.NewRecorder(), request("valid"), body)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"projects/my-project/secrets/db-password", "db-password"}, keys)
+
+	_, _, err = src.keys(httptest.NewRecorder(), request("forged"), body)
+	assert.ErrorIs(t, err, errUnauthorized)
+
+	src.serviceAccount = "other@project.iam.gserviceaccount.com"
+	_, _, err = src.keys(httptest.NewRecorder(), request("valid"), body)
+	assert.ErrorIs(t, err, errUnauthorized)
+}
+
+func TestEventGridSource(t *testing.T) {
+	src := &eventGridSource{token: "shared"}
+
+	validation := []byte(`[{"eventType":"Microsoft.EventGrid.SubscriptionValidationEvent","data":{"validationCode":"512d38b6"}}]`)
+	rec := httptest.NewRecorder()
+	_, handled, err := src.keys(rec, httptest.NewRequest(http.MethodPost, "/notify/azure/eventgrid?token=shared", http.NoBody), validation)
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.JSONEq(t, `{"validationResponse":"512d38b6"}`, rec.Body.String())
+
+	changed := []byte(`[{"eventType":"Microsoft.KeyVault.SecretNewVersionCreated","data":{"ObjectType":"Secret","ObjectName":"db-password","VaultName":"vault"}}]`)
+	keys, handled, err := src.keys(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/notify/azure/eventgrid?token=shared", http.NoBody), changed)
+	require.NoError(t, err)
+	assert.False(t, handled)
+	assert.Equal(t, []string{"db-password", "secret/db-password"}, keys)
+
+	_, _, err = src.keys(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/notify/azure/eventgrid?token=other", http.NoBody), changed)
+	assert.ErrorIs(t, err, errUnauthorized)
+}
+
+func TestReceiverEnqueuesReferencingObjects(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(esv1.AddToScheme(scheme))
+	utilruntime.Must(esv1alpha1.AddToScheme(scheme))
+
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&esv1.ExternalSecret{}, RemoteKeysField, IndexExternalSecretRemoteKeys).
+		WithIndex(&esv1alpha1.PushSecret{}, RemoteKeysField, IndexPushSecretRemoteKeys).
+		WithObjects(
+			&esv1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "receiver"},
+				Spec:       esv1.ExternalSecretSpec{Data: []esv1.ExternalSecretData{{SecretKey: "password", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db"}}}},
+			},
+			&esv1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "extract", Namespace: "receiver"},
+				Spec:       esv1.ExternalSecretSpec{DataFrom: []esv1.ExternalSecretDataFromRemoteRef{{Extract: &esv1.ExternalSecretDataRemoteRef{Key: "db"}}}},
+			},
+			&esv1.ExternalSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "receiver"},
+				Spec:       esv1.ExternalSecretSpec{Data: []esv1.ExternalSecretData{{SecretKey: "token", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "api"}}}},
+			},
+			&esv1alpha1.PushSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "push", Namespace: "receiver"},
+				Spec:       esv1alpha1.PushSecretSpec{Data: []esv1alpha1.PushSecretData{{Match: esv1alpha1.PushSecretMatch{RemoteRef: esv1alpha1.PushSecretRemoteRef{RemoteKey: "db"}}}}},
+			},
+		).
+		Build()
+
+	secret := []byte("shared")
+	receiver, err := NewReceiver(kube, logr.Discard(), Options{GenericSecret: secret})
+	require.NoError(t, err)
+	externalSecrets := receiver.Watch(KindExternalSecret)
+	pushSecrets := receiver.Watch(KindPushSecret)
+
+	rec := httptest.NewRecorder()
+	receiver.mux.ServeHTTP(rec, signedGenericRequest(secret, `{"keys":["db"]}`))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var enqueued []string
+	for len(externalSecrets) > 0 {
+		enqueued = append(enqueued, (<-externalSecrets).Object.GetName())
+	}
+	assert.ElementsMatch(t, []string{"data", "extract"}, enqueued)
+	require.Len(t, pushSecrets, 1)
+	assert.Equal(t, "push", (<-pushSecrets).Object.GetName())
+
+	name := types.NamespacedName{Namespace: "receiver", Name: "data"}
+	seq := Pending(KindExternalSecret, name)
+	assert.NotZero(t, seq)
+	assert.Zero(t, Pending(KindExternalSecret, types.NamespacedName{Namespace: "receiver", Name: "unrelated"}))
+	Refreshed(KindExternalSecret, name, seq)
+	assert.Zero(t, Pending(KindExternalSecret, name))
+
+	rec = httptest.NewRecorder()
+	receiver.mux.ServeHTTP(rec, signedGenericRequest([]byte("other"), `{"keys":["db"]}`))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestReceiverWaitsForLeaderElection(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(esv1.AddToScheme(scheme))
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&esv1.ExternalSecret{}, RemoteKeysField, IndexExternalSecretRemoteKeys).
+		Build()
+
+	secret := []byte("shared")
+	elected := make(chan struct{})
+	receiver, err := NewReceiver(kube, logr.Discard(), Options{GenericSecret: secret, Elected: elected})
+	require.NoError(t, err)
+	receiver.Watch(KindExternalSecret)
+
+	// the provider retries the notification until it reaches the leader
+	rec := httptest.NewRecorder()
+	receiver.mux.ServeHTTP(rec, signedGenericRequest(secret, `{"keys":["db"]}`))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	close(elected)
+	rec = httptest.NewRecorder()
+	receiver.mux.ServeHTTP(rec, signedGenericRequest(secret, `{"keys":["db"]}`))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
+
+func TestRefreshedKeepsNewerNotification(t *testing.T) {
+	name := types.NamespacedName{Namespace: "pending", Name: "es"}
+	Notify(KindExternalSecret, name)
+	seq := Pending(KindExternalSecret, name)
+
+	// a notification received during the refresh is kept
+	Notify(KindExternalSecret, name)
+	Refreshed(KindExternalSecret, name, seq)
+	newer := Pending(KindExternalSecret, name)
+	assert.Greater(t, newer, seq)
+
+	Refreshed(KindExternalSecret, name, newer)
+	assert.Zero(t, Pending(KindExternalSecret, name))
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// pending holds the notifications which were not yet followed by a refresh.
+// Enqueueing an object is not enough to refresh it before its refresh interval,
+// so the controllers check for a pending notification when they skip a refresh.
+var pending = &pendingNotifications{
+	seqs: make(map[pendingKey]uint64),
+}
+
+type pendingKey struct {
+	kind string
+	name types.NamespacedName
+}
+
+type pendingNotifications struct {
+	mu   sync.Mutex
+	last uint64
+	seqs map[pendingKey]uint64
+}
+
+// Notify records a notification for the object.
+func Notify(kind string, name types.NamespacedName) {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	pending.last++
+	pending.seqs[pendingKey{kind: kind, name: name}] = pending.last
+}
+
+// Pending returns the sequence number of the pending notification of the object, or 0 if there is none.
+func Pending(kind string, name types.NamespacedName) uint64 {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	return pending.seqs[pendingKey{kind: kind, name: name}]
+}
+
+// Refreshed clears the notification returned by Pending once the object is refreshed.
+// A notification received during the refresh is kept, so the object is refreshed again.
+func Refreshed(kind string, name types.NamespacedName, seq uint64) {
+	if seq == 0 {
+		return
+	}
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	key := pendingKey{kind: kind, name: name}
+	if pending.seqs[key] == seq {
+		delete(pending.seqs, key)
+	}
+}
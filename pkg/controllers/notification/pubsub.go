@@ -0,0 +1,94 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/api/idtoken"
+)
+
+// googleIssuers are the issuers of the OIDC tokens of Pub/Sub push subscriptions.
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+// pubSubPush is the body of a Pub/Sub push delivery.
+type pubSubPush struct {
+	Message struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"message"`
+	Subscription string `json:"subscription"`
+}
+
+// pubSubSource accepts the Secret Manager notifications delivered by an authenticated Pub/Sub push subscription.
+type pubSubSource struct {
+	audience       string
+	serviceAccount string
+	validate       func(ctx context.Context, token, audience string) (*idtoken.Payload, error)
+}
+
+func newPubSubSource(audience, serviceAccount string) (*pubSubSource, error) {
+	validator, err := idtoken.NewValidator(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("could not create Pub/Sub token validator: %w", err)
+	}
+	return &pubSubSource{
+		audience:       audience,
+		serviceAccount: serviceAccount,
+		validate:       validator.Validate,
+	}, nil
+}
+
+func (s *pubSubSource) keys(_ http.ResponseWriter, req *http.Request, body []byte) ([]string, bool, error) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil, false, fmt.Errorf("%w: missing bearer token", errUnauthorized)
+	}
+	payload, err := s.validate(req.Context(), token, s.audience)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errUnauthorized, err)
+	}
+	if !slices.Contains(googleIssuers, payload.Issuer) {
+		return nil, false, fmt.Errorf("%w: unexpected issuer %q", errUnauthorized, payload.Issuer)
+	}
+	if s.serviceAccount != "" {
+		email, _ := payload.Claims["email"].(string)
+		verified, _ := payload.Claims["email_verified"].(bool)
+		if email != s.serviceAccount || !verified {
+			return nil, false, fmt.Errorf("%w: token is not signed for %q", errUnauthorized, s.serviceAccount)
+		}
+	}
+
+	var push pubSubPush
+	if err := json.Unmarshal(body, &push); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errBadRequest, err)
+	}
+	// projects/<project>/secrets/<name>
+	secretID := push.Message.Attributes["secretId"]
+	if secretID == "" {
+		return nil, false, nil
+	}
+	keys := []string{secretID}
+	if _, name, ok := strings.Cut(secretID, "/secrets/"); ok {
+		keys = append(keys, name)
+	}
+	return keys, false, nil
+}
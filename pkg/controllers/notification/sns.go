@@ -0,0 +1,296 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // SNS signature version 1 is signed with SHA1.
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	snsTypeNotification             = "Notification"
+	snsTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	snsTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+
+	snsRequestTimeout = 10 * time.Second
+)
+
+// snsHost matches the hosts of SNS, which serve the signing certificates and the subscription confirmations.
+var snsHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// snsMessage is the body of an HTTP(S) delivery of SNS.
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+// eventBridgeEvent holds the fields of the EventBridge events of Secrets Manager and Parameter Store naming the changed key.
+type eventBridgeEvent struct {
+	Source string `json:"source"`
+	Detail struct {
+		// Name is set by the "Parameter Store Change" events.
+		Name              string `json:"name"`
+		RequestParameters struct {
+			SecretID string `json:"secretId"`
+			Name     string `json:"name"`
+		} `json:"requestParameters"`
+		AdditionalEventData struct {
+			SecretID string `json:"SecretId"`
+		} `json:"additionalEventData"`
+	} `json:"detail"`
+}
+
+// snsSource accepts the notifications of SNS topics, usually the target of an EventBridge rule
+// matching the changes of Secrets Manager or Parameter Store.
+type snsSource struct {
+	topicARNs []string
+	client    *http.Client
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+func newSNSSource(topicARNs []string) *snsSource {
+	return &snsSource{
+		topicARNs: topicARNs,
+		client:    &http.Client{Timeout: snsRequestTimeout},
+		certs:     make(map[string]*x509.Certificate),
+	}
+}
+
+func (s *snsSource) keys(_ http.ResponseWriter, req *http.Request, body []byte) ([]string, bool, error) {
+	var msg snsMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errBadRequest, err)
+	}
+	if !slices.Contains(s.topicARNs, msg.TopicArn) {
+		return nil, false, fmt.Errorf("%w: topic %q is not allowed", errUnauthorized, msg.TopicArn)
+	}
+	if err := s.verify(req.Context(), &msg); err != nil {
+		return nil, false, fmt.Errorf("%w: %w", errUnauthorized, err)
+	}
+
+	switch msg.Type {
+	case snsTypeSubscriptionConfirmation:
+		if err := s.confirm(req.Context(), msg.SubscribeURL); err != nil {
+			return nil, false, err
+		}
+		return nil, true, nil
+	case snsTypeUnsubscribeConfirmation:
+		return nil, true, nil
+	case snsTypeNotification:
+		return eventBridgeKeys(msg.Message), false, nil
+	default:
+		return nil, false, fmt.Errorf("%w: unknown message type %q", errBadRequest, msg.Type)
+	}
+}
+
+// verify checks the signature of the message with the certificate of SNS.
+func (s *snsSource) verify(ctx context.Context, msg *snsMessage) error {
+	var hash crypto.Hash
+	switch msg.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unsupported signature version %q", msg.SignatureVersion)
+	}
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	cert, err := s.certificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("signing certificate does not hold an RSA key")
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(snsStringToSign(msg))) //nolint:gosec // SNS signature version 1 is signed with SHA1.
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(snsStringToSign(msg)))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// snsStringToSign returns the string signed by SNS, made of the fields of the message type.
+func snsStringToSign(msg *snsMessage) string {
+	var b strings.Builder
+	add := func(name, value string) {
+		b.WriteString(name + "\n" + value + "\n")
+	}
+	add("Message", msg.Message)
+	add("MessageId", msg.MessageID)
+	if msg.Type == snsTypeNotification {
+		if msg.Subject != "" {
+			add("Subject", msg.Subject)
+		}
+	} else {
+		add("SubscribeURL", msg.SubscribeURL)
+	}
+	add("Timestamp", msg.Timestamp)
+	if msg.Type != snsTypeNotification {
+		add("Token", msg.Token)
+	}
+	add("TopicArn", msg.TopicArn)
+	add("Type", msg.Type)
+	return b.String()
+}
+
+// certificate returns the signing certificate, which is only fetched from SNS.
+func (s *snsSource) certificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	if err := checkSNSURL(certURL); err != nil {
+		return nil, fmt.Errorf("invalid signing certificate URL: %w", err)
+	}
+	s.mu.Lock()
+	cert, ok := s.certs[certURL]
+	s.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	body, err := s.get(ctx, certURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch signing certificate: %w", err)
+	}
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, errors.New("signing certificate is not PEM encoded")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing certificate: %w", err)
+	}
+	s.mu.Lock()
+	s.certs[certURL] = cert
+	s.mu.Unlock()
+	return cert, nil
+}
+
+// confirm confirms the subscription of the receiver to the topic.
+func (s *snsSource) confirm(ctx context.Context, subscribeURL string) error {
+	if err := checkSNSURL(subscribeURL); err != nil {
+		return fmt.Errorf("%w: invalid subscribe URL: %w", errBadRequest, err)
+	}
+	if _, err := s.get(ctx, subscribeURL); err != nil {
+		return fmt.Errorf("could not confirm subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *snsSource) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+}
+
+func checkSNSURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || !snsHost.MatchString(u.Host) {
+		return fmt.Errorf("%q is not an SNS URL", raw)
+	}
+	return nil
+}
+
+// eventBridgeKeys returns the keys named by an EventBridge event. A secret identified by its ARN
+// is also returned by its name, as ExternalSecrets usually reference secrets by name.
+func eventBridgeKeys(message string) []string {
+	var ev eventBridgeEvent
+	if err := json.Unmarshal([]byte(message), &ev); err != nil {
+		return nil
+	}
+	var keys []string
+	for _, id := range []string{
+		ev.Detail.Name,
+		ev.Detail.RequestParameters.SecretID,
+		ev.Detail.RequestParameters.Name,
+		ev.Detail.AdditionalEventData.SecretID,
+	} {
+		if id == "" {
+			continue
+		}
+		keys = append(keys, id)
+		if name := secretsManagerName(id); name != "" {
+			keys = append(keys, name)
+		}
+	}
+	return uniqueKeys(keys)
+}
+
+// secretARNSuffix is the random suffix appended by Secrets Manager to the name in the ARN of a secret.
+var secretARNSuffix = regexp.MustCompile(`-[A-Za-z0-9]{6}$`)
+
+// secretsManagerName returns the name of the secret of a Secrets Manager ARN, or "" for other ids.
+func secretsManagerName(id string) string {
+	// arn:partition:secretsmanager:region:account:secret:name-suffix
+	parts := strings.SplitN(id, ":", 7)
+	if len(parts) != 7 || parts[0] != "arn" || parts[2] != "secretsmanager" || parts[5] != "secret" {
+		return ""
+	}
+	return secretARNSuffix.ReplaceAllString(parts[6], "")
+}
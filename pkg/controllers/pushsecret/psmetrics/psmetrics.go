@@ -37,8 +37,13 @@ const (
 
 	// PushSecretStatusConditionKey is the key for the status condition metric.
 	PushSecretStatusConditionKey = "status_condition"
+
+	// RemoteDriftDetectedKey is the key for the changes of pushed values made in the provider outside of the controller.
+	RemoteDriftDetectedKey = "remote_drift_detected_total"
 )
 
+var counterVecMetrics = map[string]*prometheus.CounterVec{}
+
 var gaugeVecMetrics = map[string]*prometheus.GaugeVec{}
 
 // SetUpMetrics is called at the root to set-up the metric logic using the
@@ -56,7 +61,17 @@ func SetUpMetrics() {
 		Help:      "The duration time to reconcile the Push Secret",
 	}, ctrlmetrics.NonConditionMetricLabelNames)
 
-	metrics.Registry.MustRegister(pushSecretReconcileDuration, pushSecretCondition)
+	remoteDriftDetected := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: PushSecretSubsystem,
+		Name:      RemoteDriftDetectedKey,
+		Help:      "Total number of the changes of pushed values made in the provider outside of the controller",
+	}, ctrlmetrics.NonConditionMetricLabelNames)
+
+	metrics.Registry.MustRegister(pushSecretReconcileDuration, pushSecretCondition, remoteDriftDetected)
+
+	counterVecMetrics = map[string]*prometheus.CounterVec{
+		RemoteDriftDetectedKey: remoteDriftDetected,
+	}
 
 	gaugeVecMetrics = map[string]*prometheus.GaugeVec{
 		PushSecretStatusConditionKey:   pushSecretCondition,
@@ -128,6 +143,11 @@ func UpdatePushSecretCondition(ps *esapi.PushSecret, condition *esapi.PushSecret
 		})).Set(value)
 }
 
+// GetCounterVec returns a CounterVec for the given metric key.
+func GetCounterVec(key string) *prometheus.CounterVec {
+	return counterVecMetrics[key]
+}
+
 // GetGaugeVec returns a GaugeVec for the given metric key.
 func GetGaugeVec(key string) *prometheus.GaugeVec {
 	return gaugeVecMetrics[key]
@@ -44,12 +44,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/notification"
 	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret/psmetrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
 	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
@@ -81,12 +84,16 @@ const (
 // specified secret stores according to the defined policies and templates.
 type Reconciler struct {
 	client.Client
+	APIReader       client.Reader
 	Log             logr.Logger
 	Scheme          *runtime.Scheme
 	recorder        record.EventRecorder
 	RestConfig      *rest.Config
 	RequeueInterval time.Duration
 	ControllerClass string
+
+	// Notifications enqueues the PushSecrets referencing remote keys changed in the provider
+	Notifications <-chan event.GenericEvent
 }
 
 // storeInfo holds the identifying attributes of a secret store for per-store processing.
@@ -96,11 +103,23 @@ type storeInfo struct {
 	Labels map[string]string
 }
 
+// apiReader returns the reader of the API server, for the objects the controller does not cache,
+// like the source objects of arbitrary kinds. Tests without a manager fall back to the client.
+func (r *Reconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
 // SetupWithManager sets up the controller with the Manager.
 // It configures the controller to watch PushSecret resources and
 // manages indexing for efficient lookups based on secret stores and deletion policies.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts controller.Options) error {
 	r.recorder = mgr.GetEventRecorderFor("pushsecret")
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
 
 	// Index PushSecrets by the stores they have pushed to (for finalizer management on store deletion)
 	// Refer to common.go for more details on the index function
@@ -130,10 +149,20 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	// Index PushSecrets by the remote keys they push to, for the notification receiver
+	if r.Notifications != nil {
+		if err := mgr.GetFieldIndexer().IndexField(ctx, &esapi.PushSecret{}, notification.RemoteKeysField, notification.IndexPushSecretRemoteKeys); err != nil {
+			return err
+		}
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
-		For(&esapi.PushSecret{}, builder.WithPredicates(pushSecretWatchPredicate())).
-		Complete(r)
+		For(&esapi.PushSecret{}, builder.WithPredicates(pushSecretWatchPredicate()))
+	if r.Notifications != nil {
+		b = b.WatchesRawSource(source.Channel(r.Notifications, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
 
 func pushSecretWatchPredicate() predicate.Predicate {
@@ -174,7 +203,7 @@ func shouldReconcilePushSecretUpdate(oldObj, newObj client.Object) bool {
 // move the current state of the cluster closer to the desired state.
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/reconcile
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("pushsecret", req.NamespacedName)
 
 	resourceLabels := ctrlmetrics.RefineNonConditionMetricLabels(map[string]string{"name": req.Name, "namespace": req.Namespace})
@@ -182,6 +211,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	pushSecretReconcileDuration := psmetrics.GetGaugeVec(psmetrics.PushSecretReconcileDurationKey)
 	defer func() { pushSecretReconcileDuration.With(resourceLabels).Set(float64(time.Since(start))) }()
+	defer func() { result, err = secretstore.RequeueRateLimited(result, err) }()
 
 	var ps esapi.PushSecret
 	mgr := secretstore.NewManager(r.Client, r.ControllerClass, false)
@@ -231,6 +261,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				r.markAsFailed(msg, &ps, badState)
 				return ctrl.Result{}, err
 			}
+			// the finalizer is kept until the end of the deletion grace period
+			if len(ps.Status.PendingDeletions) > 0 {
+				r.setSecrets(&ps, badState)
+				return requeueForPendingDeletions(&ps, ctrl.Result{}, time.Now()), nil
+			}
 			controllerutil.RemoveFinalizer(&ps, pushSecretFinalizer)
 			if err := r.Client.Update(ctx, &ps, &client.UpdateOptions{}); err != nil {
 				return ctrl.Result{}, fmt.Errorf("could not update finalizers: %w", err)
@@ -252,10 +287,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if !ps.Status.RefreshTime.IsZero() {
 		timeSinceLastRefresh = time.Since(ps.Status.RefreshTime.Time)
 	}
-	if !shouldRefresh(ps) {
+	notified := notification.Pending(notification.KindPushSecret, req.NamespacedName)
+	if !shouldRefresh(ps) && notified == 0 && !deletionsDue(&ps, time.Now()) {
 		refreshInt = (ps.Spec.RefreshInterval.Duration - timeSinceLastRefresh) + 5*time.Second
 		log.V(1).Info("skipping refresh", "rv", ctrlutil.GetResourceVersion(ps.ObjectMeta), "nr", refreshInt.Seconds())
-		return ctrl.Result{RequeueAfter: refreshInt}, nil
+		return requeueForPendingDeletions(&ps, ctrl.Result{RequeueAfter: refreshInt}, time.Now()), nil
 	}
 
 	if err := validateDataToStoreRefs(ps.Spec.DataTo, ps.Spec.SecretStoreRefs); err != nil {
@@ -265,11 +301,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	secrets, err := r.resolveSecrets(ctx, &ps)
 	if err != nil {
-		isSecretSelector := ps.Spec.Selector.Secret != nil && ps.Spec.Selector.Secret.Name != ""
-		if apierrors.IsNotFound(err) && isSecretSelector &&
+		if apierrors.IsNotFound(err) && isNamedSelector(ps.Spec.Selector) &&
 			ps.Spec.DeletionPolicy == esapi.PushSecretDeletionPolicyDelete &&
 			len(ps.Status.SyncedPushSecrets) > 0 {
-			return ctrl.Result{}, r.handleSourceSecretDeleted(ctx, &ps, mgr)
+			if err := r.handleSourceSecretDeleted(ctx, &ps, mgr); err != nil {
+				return ctrl.Result{}, err
+			}
+			return requeueForPendingDeletions(&ps, ctrl.Result{}, time.Now()), nil
 		}
 		r.markAsFailed(errFailedGetSecret, &ps, nil)
 		return ctrl.Result{}, err
@@ -308,12 +346,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	allSyncedSecrets := make(esapi.SyncedPushSecretsMap)
-	for _, secret := range secrets {
-		if err := r.applyTemplate(ctx, &ps, &secret); err != nil {
+	var changes remoteChanges
+	if !ps.Spec.Atomic {
+		ps.Status.Stores = nil
+	}
+	for i := range secrets {
+		if err := r.applyTemplate(ctx, &ps, &secrets[i]); err != nil {
 			return ctrl.Result{}, err
 		}
-
-		syncedSecrets, err := r.PushSecretToProviders(ctx, secretStores, ps, &secret, mgr)
+	}
+	for _, batch := range pushBatches(ps.Spec.Atomic, secrets) {
+		var syncedSecrets esapi.SyncedPushSecretsMap
+		if ps.Spec.Atomic {
+			var stores []esapi.PushSecretStoreStatus
+			syncedSecrets, stores, err = r.pushSecretsToProvidersAtomically(ctx, secretStores, ps, batch, mgr, &changes)
+			r.handleAtomicPush(&ps, stores)
+		} else {
+			syncedSecrets, err = r.PushSecretToProviders(ctx, secretStores, ps, &batch[0], mgr, &changes)
+		}
 		if err != nil {
 			if errors.Is(err, locks.ErrConflict) {
 				log.Info("retry to acquire lock to update the secret later", "error", err)
@@ -328,12 +378,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 		switch ps.Spec.DeletionPolicy {
 		case esapi.PushSecretDeletionPolicyDelete:
-			badSyncState, err := r.DeleteSecretFromProviders(ctx, &ps, syncedSecrets, mgr)
+			remaining, err := r.DeleteSecretFromProviders(ctx, &ps, syncedSecrets, mgr)
 			if err != nil {
 				msg := fmt.Sprintf("Failed to Delete Secrets from Provider: %v", err)
-				r.markAsFailed(msg, &ps, badSyncState)
+				r.markAsFailed(msg, &ps, remaining)
 				return ctrl.Result{}, err
 			}
+			// the entries pending deletion are kept synced until the end of the deletion grace period
+			syncedSecrets = remaining
 		case esapi.PushSecretDeletionPolicyNone:
 		default:
 		}
@@ -341,9 +393,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		allSyncedSecrets = mergeSecretState(allSyncedSecrets, syncedSecrets)
 	}
 
+	r.handleRemoteDrift(&ps, changes.drifted, resourceLabels)
+	r.handleRemoteConflicts(&ps, changes.conflicts)
 	r.markAsDone(&ps, allSyncedSecrets, start)
+	notification.Refreshed(notification.KindPushSecret, req.NamespacedName, notified)
 
-	return ctrl.Result{RequeueAfter: refreshInt}, nil
+	return requeueForPendingDeletions(&ps, ctrl.Result{RequeueAfter: refreshInt}, time.Now()), nil
+}
+
+// pushBatches groups the source secrets pushed together.
+// An atomic push pushes all the secrets at once, so that the stores are snapshot and rolled back once,
+// otherwise every secret is pushed on its own.
+func pushBatches(atomic bool, secrets []v1.Secret) [][]v1.Secret {
+	if atomic {
+		if len(secrets) == 0 {
+			return nil
+		}
+		return [][]v1.Secret{secrets}
+	}
+	batches := make([][]v1.Secret, len(secrets))
+	for i := range secrets {
+		batches[i] = secrets[i : i+1]
+	}
+	return batches
 }
 
 // handleSourceSecretDeleted cleans up provider secrets when source Secret is unavailable.
@@ -358,11 +430,19 @@ func (r *Reconciler) handleSourceSecretDeleted(ctx context.Context, ps *esapi.Pu
 		return err
 	}
 
-	r.setSecrets(ps, esapi.SyncedPushSecretsMap{})
+	// the entries pending deletion are kept synced until the end of the deletion grace period
+	r.setSecrets(ps, badState)
 	r.markAsSourceDeleted(ps)
 	return nil
 }
 
+// isNamedSelector returns true if the PushSecret selects a single source by name, whose deletion cleans up the provider secrets.
+func isNamedSelector(selector esapi.PushSecretSelector) bool {
+	return (selector.Secret != nil && selector.Secret.Name != "") ||
+		(selector.ConfigMap != nil && selector.ConfigMap.Name != "") ||
+		selector.ObjectRef != nil
+}
+
 func shouldRefresh(ps esapi.PushSecret) bool {
 	if ps.Status.SyncedResourceVersion != ctrlutil.GetResourceVersion(ps.ObjectMeta) {
 		return true
@@ -387,6 +467,9 @@ func (r *Reconciler) markAsFailed(msg string, ps *esapi.PushSecret, syncState es
 
 func (r *Reconciler) markAsSourceDeleted(ps *esapi.PushSecret) {
 	msg := "source secret deleted; provider secrets cleaned up"
+	if len(ps.Status.PendingDeletions) > 0 {
+		msg = "source secret deleted; provider secrets are deleted at the end of the deletion grace period"
+	}
 	cond := NewPushSecretCondition(esapi.PushSecretReady, v1.ConditionFalse, esapi.ReasonSourceDeleted, msg)
 	SetPushSecretCondition(ps, *cond)
 	r.recorder.Event(ps, v1.EventTypeNormal, esapi.ReasonSourceDeleted, msg)
@@ -418,7 +501,7 @@ func mergeSecretState(newMap, old esapi.SyncedPushSecretsMap) esapi.SyncedPushSe
 	for k, v := range old {
 		_, ok := out[k]
 		if !ok {
-			out[k] = make(map[string]esapi.PushSecretData)
+			out[k] = make(map[string]esapi.PushSecretStatusData)
 		}
 		maps.Insert(out[k], maps.All(v))
 	}
@@ -428,8 +511,11 @@ func mergeSecretState(newMap, old esapi.SyncedPushSecretsMap) esapi.SyncedPushSe
 // DeleteSecretFromProviders removes secrets from providers that are no longer needed.
 // It compares the existing synced secrets in the PushSecret status with the new desired state,
 // and deletes any secrets that are no longer present in the new state.
+// With a deletion grace period, the secrets are kept in the returned state, and in status.pendingDeletions,
+// until the end of the period.
 func (r *Reconciler) DeleteSecretFromProviders(ctx context.Context, ps *esapi.PushSecret, newMap esapi.SyncedPushSecretsMap, mgr *secretstore.Manager) (esapi.SyncedPushSecretsMap, error) {
 	out := mergeSecretState(newMap, ps.Status.SyncedPushSecrets)
+	schedule := newDeletionSchedule(ps, time.Now())
 	for storeName, oldData := range ps.Status.SyncedPushSecrets {
 		storeRef := esv1.SecretStoreRef{
 			Name: strings.Split(storeName, "/")[1],
@@ -440,39 +526,28 @@ func (r *Reconciler) DeleteSecretFromProviders(ctx context.Context, ps *esapi.Pu
 			return out, fmt.Errorf("could not get secrets client for store %v: %w", storeName, err)
 		}
 		newData, ok := newMap[storeName]
-		if !ok {
-			err = r.DeleteAllSecretsFromStore(ctx, client, oldData)
+		for oldEntry, oldRef := range oldData {
+			if _, keep := newData[oldEntry]; keep {
+				schedule.keep(storeName, oldEntry)
+				continue
+			}
+			if !schedule.due(storeName, oldEntry) {
+				continue
+			}
+			err = r.DeleteSecretFromStore(ctx, client, oldRef.PushSecretData)
 			if err != nil {
 				return out, err
 			}
-			delete(out, storeName)
-			continue
+			delete(out[storeName], oldEntry)
 		}
-		for oldEntry, oldRef := range oldData {
-			_, ok := newData[oldEntry]
-			if !ok {
-				err = r.DeleteSecretFromStore(ctx, client, oldRef)
-				if err != nil {
-					return out, err
-				}
-				delete(out[storeName], oldEntry)
-			}
+		if !ok && len(out[storeName]) == 0 {
+			delete(out, storeName)
 		}
 	}
+	r.handleDeletionSchedule(ps, schedule)
 	return out, nil
 }
 
-// DeleteAllSecretsFromStore removes all secrets from a given secret store.
-func (r *Reconciler) DeleteAllSecretsFromStore(ctx context.Context, client esv1.SecretsClient, data map[string]esapi.PushSecretData) error {
-	for _, v := range data {
-		err := r.DeleteSecretFromStore(ctx, client, v)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // DeleteSecretFromStore removes a specific secret from a given secret store.
 func (r *Reconciler) DeleteSecretFromStore(ctx context.Context, client esv1.SecretsClient, data esapi.PushSecretData) error {
 	return client.DeleteSecret(ctx, data.Match.RemoteRef)
@@ -480,19 +555,22 @@ func (r *Reconciler) DeleteSecretFromStore(ctx context.Context, client esv1.Secr
 
 // PushSecretToProviders pushes the secret data to the specified secret stores.
 // It iterates over each store and handles the push operation according to the
-// defined update policies and conversion strategies.
+// defined update policies, conversion strategies and drift policy.
+// The remote refs whose remote value drifted from the value last pushed, or which were not
+// updated by the IfUnchanged update policy, are recorded in changes.
 func (r *Reconciler) PushSecretToProviders(
 	ctx context.Context,
 	stores map[esapi.PushSecretStoreRef]esv1.GenericStore,
 	ps esapi.PushSecret,
 	secret *v1.Secret,
 	mgr *secretstore.Manager,
+	changes *remoteChanges,
 ) (esapi.SyncedPushSecretsMap, error) {
 	out := make(esapi.SyncedPushSecretsMap)
 	var err error
 	for ref, store := range stores {
 		si := storeInfo{Name: store.GetName(), Kind: ref.Kind, Labels: store.GetLabels()}
-		out, err = r.handlePushSecretDataForStore(ctx, ps, secret, out, mgr, si)
+		out, err = r.handlePushSecretDataForStore(ctx, ps, secret, out, changes, mgr, si)
 		if err != nil {
 			return out, err
 		}
@@ -505,51 +583,155 @@ func (r *Reconciler) handlePushSecretDataForStore(
 	ps esapi.PushSecret,
 	secret *v1.Secret,
 	out esapi.SyncedPushSecretsMap,
+	changes *remoteChanges,
 	mgr *secretstore.Manager,
 	si storeInfo,
 ) (esapi.SyncedPushSecretsMap, error) {
-	storeKey := fmt.Sprintf("%v/%v", si.Kind, si.Name)
-	out[storeKey] = make(map[string]esapi.PushSecretData)
+	out[storeKeyOf(si)] = make(map[string]esapi.PushSecretStatusData)
+	sp, err := r.prepareStorePush(ctx, ps, secret, mgr, si)
+	if err != nil {
+		return out, err
+	}
+	return r.pushStoreEntries(ctx, ps, sp, out, changes, nil)
+}
+
+// remoteChanges collects the remote refs, as "Kind/Name:remoteRef", whose remote value was changed outside of the PushSecret.
+type remoteChanges struct {
+	// drifted are the remote refs which drifted from the value last pushed.
+	drifted []string
+	// conflicts are the remote refs which were not updated by the IfUnchanged update policy.
+	conflicts []string
+}
+
+// storePush holds the data entries to push to a secret store.
+type storePush struct {
+	key     string
+	info    storeInfo
+	client  esv1.SecretsClient
+	secret  *v1.Secret
+	entries []pushEntryParams
+}
+
+// storeKeyOf returns the key of a secret store in status.syncedPushSecrets.
+func storeKeyOf(si storeInfo) string {
+	return fmt.Sprintf("%v/%v", si.Kind, si.Name)
+}
+
+// prepareStorePush resolves the client of a secret store and the data entries to push to it.
+func (r *Reconciler) prepareStorePush(
+	ctx context.Context,
+	ps esapi.PushSecret,
+	secret *v1.Secret,
+	mgr *secretstore.Manager,
+	si storeInfo,
+) (*storePush, error) {
 	storeRef := esv1.SecretStoreRef{
 		Name: si.Name,
 		Kind: si.Kind,
 	}
 	secretClient, err := mgr.Get(ctx, storeRef, ps.GetNamespace(), nil)
 	if err != nil {
-		return out, fmt.Errorf("could not get secrets client for store %v: %w", si.Name, err)
+		return nil, fmt.Errorf("could not get secrets client for store %v: %w", si.Name, err)
 	}
 
 	storeSecret := secret.DeepCopy()
 
 	filteredDataTo, err := filterDataToForStore(ps.Spec.DataTo, si.Name, si.Kind, si.Labels)
 	if err != nil {
-		return out, fmt.Errorf("failed to filter dataTo: %w", err)
+		return nil, fmt.Errorf("failed to filter dataTo: %w", err)
 	}
 
 	dataToEntries, bundleOverrides, err := r.expandDataTo(storeSecret, filteredDataTo)
 	if err != nil {
-		return out, fmt.Errorf("failed to expand dataTo: %w", err)
+		return nil, fmt.Errorf("failed to expand dataTo: %w", err)
 	}
 
 	allData, err := mergeDataEntries(dataToEntries, ps.Spec.Data, storeSecret)
 	if err != nil {
-		return out, fmt.Errorf("failed to merge data entries: %w", err)
+		return nil, fmt.Errorf("failed to merge data entries: %w", err)
 	}
 
-	originalStoreSecretData := storeSecret.Data
-
+	sp := &storePush{
+		key:     storeKeyOf(si),
+		info:    si,
+		client:  secretClient,
+		secret:  storeSecret,
+		entries: make([]pushEntryParams, 0, len(allData)),
+	}
 	for _, data := range allData {
-		params := pushEntryParams{
+		sp.entries = append(sp.entries, pushEntryParams{
 			data:         data,
 			updatePolicy: ps.Spec.UpdatePolicy,
-			originalData: originalStoreSecretData,
+			originalData: storeSecret.Data,
 			dataOverride: bundleOverrides[statusRef(data)],
 			storeName:    si.Name,
+		})
+	}
+	return sp, nil
+}
+
+// pushStoreEntries pushes the data entries of a secret store following the drift and update policies,
+// and records them in out. beforePush, when non-nil, is called with every entry right before it is pushed,
+// and returns a func undoing the call for an entry which turns out not to be pushed.
+func (r *Reconciler) pushStoreEntries(
+	ctx context.Context,
+	ps esapi.PushSecret,
+	sp *storePush,
+	out esapi.SyncedPushSecretsMap,
+	changes *remoteChanges,
+	beforePush func(params pushEntryParams) func(),
+) (esapi.SyncedPushSecretsMap, error) {
+	if out[sp.key] == nil {
+		out[sp.key] = make(map[string]esapi.PushSecretStatusData)
+	}
+	for _, params := range sp.entries {
+		data := params.data
+		synced, recorded := ps.Status.SyncedPushSecrets[sp.key][statusRef(data)]
+		detectDrift := detectsRemoteDrift(&ps, data, params.dataOverride)
+		if detectDrift {
+			isDrifted, err := remoteDrifted(ctx, sp.client, synced, sp.info.Name)
+			if err != nil {
+				return out, err
+			}
+			if isDrifted {
+				changes.drifted = append(changes.drifted, sp.key+":"+statusRef(data))
+				// the remote value is left untouched, and keeps drifting from the value last pushed
+				if ps.Spec.DriftPolicy == esapi.PushSecretDriftPolicyReport {
+					out[sp.key][statusRef(data)] = synced
+					continue
+				}
+			}
 		}
-		if err := r.pushSecretEntry(ctx, secretClient, storeSecret, params); err != nil {
+		if params.updatePolicy == esapi.PushSecretUpdatePolicyIfUnchanged {
+			var syncedRef *esapi.PushSecretStatusData
+			if recorded {
+				syncedRef = &synced
+			}
+			entry, pushed, err := r.pushEntryIfUnchanged(ctx, sp, params, syncedRef, beforePush)
+			if err != nil {
+				return out, err
+			}
+			if !pushed {
+				changes.conflicts = append(changes.conflicts, sp.key+":"+statusRef(data))
+				// a remote value which was never pushed by the PushSecret is not recorded as synced
+				if recorded {
+					out[sp.key][statusRef(data)] = synced
+				}
+				continue
+			}
+			out[sp.key][statusRef(data)] = entry
+			continue
+		}
+		notifyBeforePush(beforePush, params)
+		pushedHash, err := r.pushSecretEntry(ctx, sp.client, sp.secret, params)
+		if err != nil {
 			return out, err
 		}
-		out[storeKey][statusRef(data)] = data
+		entry := esapi.PushSecretStatusData{PushSecretData: data}
+		if detectDrift {
+			entry.PushedHash = pushedHash
+		}
+		out[sp.key][statusRef(data)] = entry
 	}
 	return out, nil
 }
@@ -566,6 +748,7 @@ type pushEntryParams struct {
 
 // pushSecretEntry converts, validates, and pushes a single data entry to the provider.
 // If the update policy is IfNotExists and the secret already exists, the push is skipped.
+// It returns the hash of the pushed value of the secret key, which is empty when nothing was pushed.
 // params.dataOverride, when non-nil, replaces params.originalData for the conversion step —
 // used by bundle entries (dataTo with remoteKey) to restrict the pushed payload to matched keys only.
 func (r *Reconciler) pushSecretEntry(
@@ -573,7 +756,58 @@ func (r *Reconciler) pushSecretEntry(
 	secretClient esv1.SecretsClient,
 	storeSecret *v1.Secret,
 	params pushEntryParams,
-) error {
+) (string, error) {
+	localSecret, err := entrySecret(storeSecret, params)
+	if err != nil {
+		return "", err
+	}
+	key := params.data.GetSecretKey()
+
+	if params.updatePolicy == esapi.PushSecretUpdatePolicyIfNotExists {
+		exists, err := secretClient.SecretExists(ctx, params.data.Match.RemoteRef)
+		if err != nil {
+			return "", fmt.Errorf("could not verify if secret exists in store: %w", err)
+		}
+		if exists {
+			return "", nil
+		}
+	}
+
+	if err := secretClient.PushSecret(ctx, localSecret, params.data); err != nil {
+		return "", fmt.Errorf(errSetSecretFailed, key, params.storeName, err)
+	}
+	return esutils.ObjectHash(localSecret.Data[key]), nil
+}
+
+// pushSecretEntryIfVersion converts, validates, and pushes a single data entry to the provider, only if its
+// remote value is still at version. It returns the hash of the pushed value of the secret key, and the version
+// of the remote value after the push. The esv1.VersionConflictErr and esv1.ConditionalPushUnsupportedErr errors
+// are returned as is.
+func (r *Reconciler) pushSecretEntryIfVersion(
+	ctx context.Context,
+	secretClient esv1.SecretsClient,
+	storeSecret *v1.Secret,
+	params pushEntryParams,
+	version string,
+) (string, esv1.SecretVersion, error) {
+	localSecret, err := entrySecret(storeSecret, params)
+	if err != nil {
+		return "", esv1.SecretVersion{}, err
+	}
+	key := params.data.GetSecretKey()
+	pushed, err := secretstore.PushSecretIfVersion(ctx, secretClient, localSecret, params.data, esv1.SecretVersion{ID: version})
+	if errors.Is(err, esv1.VersionConflictErr) || errors.Is(err, esv1.ConditionalPushUnsupportedErr) {
+		return "", esv1.SecretVersion{}, err
+	}
+	if err != nil {
+		return "", esv1.SecretVersion{}, fmt.Errorf(errSetSecretFailed, key, params.storeName, err)
+	}
+	return esutils.ObjectHash(localSecret.Data[key]), pushed, nil
+}
+
+// entrySecret returns the secret holding the converted data of a single data entry, to push to the provider.
+// params.dataOverride, when non-nil, replaces params.originalData for the conversion step.
+func entrySecret(storeSecret *v1.Secret, params pushEntryParams) (*v1.Secret, error) {
 	sourceData := params.originalData
 	if params.dataOverride != nil {
 		sourceData = params.dataOverride
@@ -581,30 +815,17 @@ func (r *Reconciler) pushSecretEntry(
 
 	secretData, err := esutils.ReverseKeys(params.data.ConversionStrategy, sourceData)
 	if err != nil {
-		return fmt.Errorf(errConvert, err)
+		return nil, fmt.Errorf(errConvert, err)
 	}
 
 	key := params.data.GetSecretKey()
 	if !secretKeyExists(key, secretData) {
-		return fmt.Errorf("secret key %v does not exist", key)
-	}
-
-	if params.updatePolicy == esapi.PushSecretUpdatePolicyIfNotExists {
-		exists, err := secretClient.SecretExists(ctx, params.data.Match.RemoteRef)
-		if err != nil {
-			return fmt.Errorf("could not verify if secret exists in store: %w", err)
-		}
-		if exists {
-			return nil
-		}
+		return nil, fmt.Errorf("secret key %v does not exist", key)
 	}
 
 	localSecret := storeSecret.DeepCopy()
 	localSecret.Data = secretData
-	if err := secretClient.PushSecret(ctx, localSecret, params.data); err != nil {
-		return fmt.Errorf(errSetSecretFailed, key, params.storeName, err)
-	}
-	return nil
+	return localSecret, nil
 }
 
 func secretKeyExists(key string, data map[string][]byte) bool {
@@ -660,6 +881,22 @@ func (r *Reconciler) resolveSecrets(ctx context.Context, ps *esapi.PushSecret) (
 		}
 
 		return secretList.Items, err
+	case ps.Spec.Selector.ConfigMap != nil:
+		secrets, err := r.resolveSecretsFromConfigMaps(ctx, ps.Namespace, ps.Spec.Selector.ConfigMap)
+		if err != nil {
+			return nil, err
+		}
+		generatorState.EnqueueFlagLatestStateForGC(defaultGeneratorStateKey)
+
+		return secrets, nil
+	case ps.Spec.Selector.ObjectRef != nil:
+		secret, err := r.resolveSecretFromObject(ctx, ps.Namespace, ps.Spec.Selector.ObjectRef)
+		if err != nil {
+			return nil, err
+		}
+		generatorState.EnqueueFlagLatestStateForGC(defaultGeneratorStateKey)
+
+		return []v1.Secret{*secret}, nil
 	}
 
 	return nil, errors.New("no secret selector provided")
@@ -0,0 +1,269 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+const (
+	errSnapshotRemoteValue = "could not read previous value of remote ref %v from secretstore %v: %w"
+	errRestoreRemoteValue  = "could not restore previous value of remote ref %v in secretstore %v: %w"
+
+	msgPushRolledBack = "push to secret stores failed, previous values were restored in the stores already pushed to: %s"
+	msgRollbackFailed = "push to secret stores failed, previous values could not be restored in: %s"
+)
+
+// remoteSnapshot is the value of a remote ref read before an atomic push.
+type remoteSnapshot struct {
+	exists bool
+	// value is the previous value of an entry pushing a single key of the secret.
+	value []byte
+	// values is the previous value of an entry pushing the whole secret or a bundle.
+	values map[string][]byte
+}
+
+// storeTransaction tracks the entries pushed to a secret store during an atomic push, to restore them on failure.
+type storeTransaction struct {
+	info storeInfo
+	// pushes holds the entries to push to the store, one per source secret.
+	pushes    []*storePush
+	snapshots map[string]remoteSnapshot
+	pushed    []pushedEntry
+}
+
+// pushedEntry is an entry pushed to a secret store, with the push of the source secret it belongs to.
+type pushedEntry struct {
+	push   *storePush
+	params pushEntryParams
+}
+
+// pushSecretsToProvidersAtomically pushes the data of the source secrets to the secret stores as a transaction.
+// The previous remote values of every store are read before anything is pushed, then the stores are
+// pushed one after the other. When a push fails, the entries already pushed are restored to their
+// previous values, or deleted if they did not exist, in the reverse order of the pushes.
+// A failed push returns a nil map, so that the status keeps the entries synced before the push.
+// The outcome of the push to each store is returned along the error.
+func (r *Reconciler) pushSecretsToProvidersAtomically(
+	ctx context.Context,
+	stores map[esapi.PushSecretStoreRef]esv1.GenericStore,
+	ps esapi.PushSecret,
+	secrets []v1.Secret,
+	mgr *secretstore.Manager,
+	changes *remoteChanges,
+) (esapi.SyncedPushSecretsMap, []esapi.PushSecretStoreStatus, error) {
+	infos := make([]storeInfo, 0, len(stores))
+	for ref, store := range stores {
+		infos = append(infos, storeInfo{Name: store.GetName(), Kind: ref.Kind, Labels: store.GetLabels()})
+	}
+	// the stores are pushed in a stable order, so the outcome does not change between reconciles
+	slices.SortFunc(infos, func(a, b storeInfo) int {
+		return strings.Compare(storeKeyOf(a), storeKeyOf(b))
+	})
+
+	statuses := make([]esapi.PushSecretStoreStatus, len(infos))
+	for i, si := range infos {
+		statuses[i] = esapi.PushSecretStoreStatus{Name: storeKeyOf(si), Outcome: esapi.PushSecretStoreNotPushed}
+	}
+
+	txs := make([]*storeTransaction, 0, len(infos))
+	for i, si := range infos {
+		tx, err := r.beginStoreTransaction(ctx, ps, secrets, mgr, si)
+		if err != nil {
+			statuses[i].Outcome, statuses[i].Message = esapi.PushSecretStoreFailed, err.Error()
+			return nil, statuses, err
+		}
+		txs = append(txs, tx)
+	}
+
+	out := make(esapi.SyncedPushSecretsMap)
+	for i, tx := range txs {
+		err := r.pushStoreTransaction(ctx, ps, tx, out, changes)
+		if err == nil {
+			statuses[i].Outcome = esapi.PushSecretStorePushed
+			continue
+		}
+
+		statuses[i].Outcome, statuses[i].Message = esapi.PushSecretStoreFailed, err.Error()
+		errs := []error{err}
+		for j := i; j >= 0; j-- {
+			rollbackErr := rollbackStore(ctx, txs[j])
+			switch {
+			case rollbackErr != nil && j == i:
+				statuses[j].Outcome = esapi.PushSecretStoreRollbackFailed
+				statuses[j].Message = fmt.Sprintf("%v; %v", err, rollbackErr)
+				errs = append(errs, rollbackErr)
+			case rollbackErr != nil:
+				statuses[j].Outcome, statuses[j].Message = esapi.PushSecretStoreRollbackFailed, rollbackErr.Error()
+				errs = append(errs, rollbackErr)
+			case j < i:
+				statuses[j].Outcome = esapi.PushSecretStoreRolledBack
+			}
+		}
+		return nil, statuses, errors.Join(errs...)
+	}
+	return out, statuses, nil
+}
+
+// beginStoreTransaction resolves the entries of every source secret to push to a secret store,
+// and reads their remote values before any of them is pushed.
+// A remote ref pushed from several secrets keeps the value it had before the first push.
+func (r *Reconciler) beginStoreTransaction(
+	ctx context.Context,
+	ps esapi.PushSecret,
+	secrets []v1.Secret,
+	mgr *secretstore.Manager,
+	si storeInfo,
+) (*storeTransaction, error) {
+	tx := &storeTransaction{info: si, snapshots: make(map[string]remoteSnapshot)}
+	for i := range secrets {
+		sp, err := r.prepareStorePush(ctx, ps, &secrets[i], mgr, si)
+		if err != nil {
+			return nil, err
+		}
+		if err := snapshotRemoteValues(ctx, sp, tx.snapshots); err != nil {
+			return nil, err
+		}
+		tx.pushes = append(tx.pushes, sp)
+	}
+	return tx, nil
+}
+
+// pushStoreTransaction pushes the entries of every source secret to a secret store, and records them in out.
+func (r *Reconciler) pushStoreTransaction(
+	ctx context.Context,
+	ps esapi.PushSecret,
+	tx *storeTransaction,
+	out esapi.SyncedPushSecretsMap,
+	changes *remoteChanges,
+) error {
+	for _, sp := range tx.pushes {
+		synced, err := r.pushStoreEntries(ctx, ps, sp, make(esapi.SyncedPushSecretsMap), changes, func(params pushEntryParams) func() {
+			tx.pushed = append(tx.pushed, pushedEntry{push: sp, params: params})
+			n := len(tx.pushed)
+			return func() { tx.pushed = tx.pushed[:n-1] }
+		})
+		if err != nil {
+			return err
+		}
+		for key, entries := range synced {
+			if out[key] == nil {
+				out[key] = make(map[string]esapi.PushSecretStatusData, len(entries))
+			}
+			maps.Copy(out[key], entries)
+		}
+	}
+	return nil
+}
+
+// snapshotRemoteValues reads the remote values of the entries to push to a secret store into snapshots,
+// skipping the remote refs already read. A remote value missing in the provider, or a property missing
+// in an existing remote secret, is recorded as such, to be deleted on rollback. The values are read from the provider, never from the value cache,
+// as the value restored on rollback must be the one the provider holds.
+func snapshotRemoteValues(ctx context.Context, sp *storePush, snapshots map[string]remoteSnapshot) error {
+	client := secretstore.Uncached(sp.client)
+	for _, params := range sp.entries {
+		if _, ok := snapshots[statusRef(params.data)]; ok {
+			continue
+		}
+		ref := remoteDataRef(params.data)
+		var snapshot remoteSnapshot
+		var err error
+		if params.data.GetSecretKey() == "" {
+			snapshot.values, err = client.GetSecretMap(ctx, ref)
+		} else {
+			snapshot.value, err = client.GetSecret(ctx, ref)
+		}
+		if remoteValueMissing(params.data, err) {
+			snapshots[statusRef(params.data)] = remoteSnapshot{}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf(errSnapshotRemoteValue, statusRef(params.data), sp.info.Name, err)
+		}
+		snapshot.exists = true
+		snapshots[statusRef(params.data)] = snapshot
+	}
+	return nil
+}
+
+// rollbackStore restores the previous remote values of the entries pushed to a secret store.
+// Every entry is restored even if another one fails, and the errors are joined.
+func rollbackStore(ctx context.Context, tx *storeTransaction) error {
+	var errs []error
+	for i := len(tx.pushed) - 1; i >= 0; i-- {
+		push, params := tx.pushed[i].push, tx.pushed[i].params
+		snapshot := tx.snapshots[statusRef(params.data)]
+		// the IfNotExists update policy does not push over an existing value
+		if snapshot.exists && params.updatePolicy == esapi.PushSecretUpdatePolicyIfNotExists {
+			continue
+		}
+		if err := restoreRemoteValue(ctx, push, params, snapshot); err != nil {
+			errs = append(errs, fmt.Errorf(errRestoreRemoteValue, statusRef(params.data), tx.info.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restoreRemoteValue pushes the previous value of an entry again, or deletes it if it did not exist.
+// The remote ref of an entry pushing a property deletes only that property, not the remote secret.
+func restoreRemoteValue(ctx context.Context, sp *storePush, params pushEntryParams, snapshot remoteSnapshot) error {
+	if !snapshot.exists {
+		return sp.client.DeleteSecret(ctx, params.data.Match.RemoteRef)
+	}
+	previous := sp.secret.DeepCopy()
+	if key := params.data.GetSecretKey(); key != "" {
+		previous.Data = map[string][]byte{key: snapshot.value}
+	} else {
+		previous.Data = snapshot.values
+	}
+	return sp.client.PushSecret(ctx, previous, params.data)
+}
+
+// handleAtomicPush records the outcome of an atomic push in status, and reports the rolled back pushes.
+func (r *Reconciler) handleAtomicPush(ps *esapi.PushSecret, statuses []esapi.PushSecretStoreStatus) {
+	ps.Status.Stores = statuses
+
+	var rolledBack, rollbackFailed []string
+	for _, status := range statuses {
+		switch status.Outcome {
+		case esapi.PushSecretStoreRolledBack:
+			rolledBack = append(rolledBack, status.Name)
+		case esapi.PushSecretStoreRollbackFailed:
+			rollbackFailed = append(rollbackFailed, status.Name)
+		default:
+		}
+	}
+	if len(rollbackFailed) > 0 {
+		r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonRollbackFailed, fmt.Sprintf(msgRollbackFailed, strings.Join(rollbackFailed, ", ")))
+		return
+	}
+	if len(rolledBack) > 0 {
+		r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonPushRolledBack, fmt.Sprintf(msgPushRolledBack, strings.Join(rolledBack, ", ")))
+	}
+}
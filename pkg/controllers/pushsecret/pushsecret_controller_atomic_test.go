@@ -0,0 +1,238 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func TestReconcileAtomicPush(t *testing.T) {
+	errProvider := errors.New("provider unavailable")
+	ps := newAtomicTestPushSecret()
+	source := newTestSource(map[string][]byte{"foo": []byte("new")})
+
+	tests := []struct {
+		name     string
+		property string
+		// remote is the value of remote-foo before the push, nil if it does not exist
+		remote      []byte
+		getErr      error
+		failPush    int
+		deleteErr   error
+		wantErr     bool
+		wantRemote  string
+		wantDeletes int
+		want        []esapi.PushSecretStoreOutcome
+		wantEvent   string
+	}{
+		{
+			name:       "pushed to all stores",
+			remote:     []byte("old"),
+			wantRemote: "new",
+			want:       []esapi.PushSecretStoreOutcome{esapi.PushSecretStorePushed, esapi.PushSecretStorePushed},
+			wantEvent:  esapi.ReasonSynced,
+		},
+		{
+			name:       "previous value restored",
+			remote:     []byte("old"),
+			failPush:   2,
+			wantErr:    true,
+			wantRemote: "old",
+			want:       []esapi.PushSecretStoreOutcome{esapi.PushSecretStoreRolledBack, esapi.PushSecretStoreFailed},
+			wantEvent:  esapi.ReasonPushRolledBack,
+		},
+		{
+			name:        "created value deleted",
+			getErr:      esv1.NoSecretErr,
+			failPush:    2,
+			wantErr:     true,
+			wantRemote:  "new",
+			wantDeletes: 2,
+			want:        []esapi.PushSecretStoreOutcome{esapi.PushSecretStoreRolledBack, esapi.PushSecretStoreFailed},
+			wantEvent:   esapi.ReasonPushRolledBack,
+		},
+		{
+			name:        "created property deleted",
+			property:    "bar",
+			getErr:      errors.New("key bar does not exist in secret remote-foo"),
+			failPush:    2,
+			wantErr:     true,
+			wantRemote:  "new",
+			wantDeletes: 2,
+			want:        []esapi.PushSecretStoreOutcome{esapi.PushSecretStoreRolledBack, esapi.PushSecretStoreFailed},
+			wantEvent:   esapi.ReasonPushRolledBack,
+		},
+		{
+			name:        "rollback failed",
+			getErr:      esv1.NoSecretErr,
+			failPush:    2,
+			deleteErr:   errProvider,
+			wantErr:     true,
+			wantRemote:  "new",
+			wantDeletes: 2,
+			want:        []esapi.PushSecretStoreOutcome{esapi.PushSecretStoreRollbackFailed, esapi.PushSecretStoreRollbackFailed},
+			wantEvent:   esapi.ReasonRollbackFailed,
+		},
+		{
+			name:    "nothing pushed when previous values cannot be read",
+			getErr:  errProvider,
+			wantErr: true,
+			want:    []esapi.PushSecretStoreOutcome{esapi.PushSecretStoreFailed, esapi.PushSecretStoreNotPushed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := ps.DeepCopy()
+			ps.Spec.Data[0].Match.RemoteRef.Property = tt.property
+			h := newTestReconciler(t, ps, source.DeepCopy(), newTestStore("a"), newTestStore("b"))
+			fakeProvider.WithGetSecret(tt.remote, tt.getErr)
+			pushes := 0
+			fakeProvider.WithSetSecretFn(func() error {
+				pushes++
+				if pushes == tt.failPush {
+					return errProvider
+				}
+				return nil
+			})
+			deletes := 0
+			fakeProvider.WithDeleteSecretFn(func() error {
+				deletes++
+				return tt.deleteErr
+			})
+
+			_, got, err := h.reconcile(ps)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			// the fake provider keeps the value last pushed to a remote key when it is deleted
+			assert.Equal(t, tt.wantRemote, remoteValue("remote-foo"))
+			assert.Equal(t, tt.wantDeletes, deletes)
+
+			require.Len(t, got.Status.Stores, 2)
+			assert.Equal(t, "SecretStore/a", got.Status.Stores[0].Name)
+			assert.Equal(t, "SecretStore/b", got.Status.Stores[1].Name)
+			for i, outcome := range tt.want {
+				assert.Equal(t, outcome, got.Status.Stores[i].Outcome, got.Status.Stores[i].Name)
+			}
+			if tt.wantErr {
+				// the entries of a failed push are not recorded as synced
+				assert.Empty(t, got.Status.SyncedPushSecrets)
+			} else {
+				assert.Len(t, got.Status.SyncedPushSecrets, 2)
+			}
+			if tt.wantEvent != "" {
+				assert.Contains(t, h.events(), tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestRestoreRemoteValue(t *testing.T) {
+	fakeProvider.Reset()
+	t.Cleanup(fakeProvider.Reset)
+	data := newTestData("foo")
+	data.Match.RemoteRef.Property = "bar"
+	client := &deleteRecorder{SecretsClient: fakeProvider}
+	sp := &storePush{client: client, secret: &v1.Secret{}}
+
+	require.NoError(t, restoreRemoteValue(context.Background(), sp, pushEntryParams{data: data}, remoteSnapshot{}))
+	// only the property pushed is deleted from the remote secret
+	require.Len(t, client.deleted, 1)
+	assert.Equal(t, "remote-foo", client.deleted[0].GetRemoteKey())
+	assert.Equal(t, "bar", client.deleted[0].GetProperty())
+}
+
+// deleteRecorder records the remote refs deleted through the fake provider.
+type deleteRecorder struct {
+	esv1.SecretsClient
+	deleted []esv1.PushSecretRemoteRef
+}
+
+func (c *deleteRecorder) DeleteSecret(ctx context.Context, ref esv1.PushSecretRemoteRef) error {
+	c.deleted = append(c.deleted, ref)
+	return c.SecretsClient.DeleteSecret(ctx, ref)
+}
+
+func TestReconcileAtomicPushDisabled(t *testing.T) {
+	ps := newTestPushSecret(newTestData("foo"))
+	ps.Status.Stores = []esapi.PushSecretStoreStatus{{Name: "SecretStore/store", Outcome: esapi.PushSecretStoreFailed}}
+	h := newTestReconciler(t, ps, newTestSource(map[string][]byte{"foo": []byte("bar")}))
+
+	got := h.mustReconcile(ps)
+	// the outcome of a previous atomic push is not kept
+	assert.Empty(t, got.Status.Stores)
+	assert.Equal(t, "bar", remoteValue("remote-foo"))
+}
+
+func TestReconcileAtomicPushSecrets(t *testing.T) {
+	ps := newAtomicTestPushSecret()
+	ps.Spec.Selector.Secret = &esapi.PushSecretSecret{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"push": "true"}},
+	}
+	newSource := func(name string) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"push": "true"}},
+			Data:       map[string][]byte{"foo": []byte(name)},
+		}
+	}
+	h := newTestReconciler(t, ps, newSource("one"), newSource("two"), newTestStore("a"), newTestStore("b"))
+	fakeProvider.WithGetSecret([]byte("old"), nil)
+	pushes := 0
+	fakeProvider.WithSetSecretFn(func() error {
+		pushes++
+		// store a takes both secrets, store b fails on the first one
+		if pushes == 3 {
+			return errors.New("provider unavailable")
+		}
+		return nil
+	})
+
+	_, got, err := h.reconcile(ps)
+	require.Error(t, err)
+	// both secrets pushed to store a are rolled back, and the push stops at the failing store
+	assert.Equal(t, "old", remoteValue("remote-foo"))
+	assert.Equal(t, 6, pushes)
+
+	require.Len(t, got.Status.Stores, 2)
+	assert.Equal(t, esapi.PushSecretStoreRolledBack, got.Status.Stores[0].Outcome)
+	assert.Equal(t, esapi.PushSecretStoreFailed, got.Status.Stores[1].Outcome)
+	assert.Empty(t, got.Status.SyncedPushSecrets)
+}
+
+// newAtomicTestPushSecret returns a PushSecret which atomically pushes foo to the stores a and b.
+func newAtomicTestPushSecret() *esapi.PushSecret {
+	ps := newTestPushSecret(newTestData("foo"))
+	ps.Spec.SecretStoreRefs = []esapi.PushSecretStoreRef{
+		{Name: "a", Kind: esv1.SecretStoreKind},
+		{Name: "b", Kind: esv1.SecretStoreKind},
+	}
+	ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyReplace
+	ps.Spec.Atomic = true
+	return ps
+}
@@ -0,0 +1,173 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+const (
+	errCompareRemoteValue = "could not read remote ref %v from secretstore %v to compare it with the value last pushed: %w"
+
+	// errMissingProperty is part of the error returned by several providers, e.g. AWS and GCP,
+	// when the property of a remote ref does not exist in the remote secret.
+	errMissingProperty = "does not exist in secret"
+
+	msgRemoteConflict   = "remote values were changed outside of the PushSecret and were not updated: %s"
+	msgNoRemoteConflict = "remote values match the values last pushed by the PushSecret"
+)
+
+// remoteUnchanged reads the remote value of an entry before it is pushed with the IfUnchanged update policy,
+// and returns true if it may be updated: the remote value, or its property in the remote secret, does not exist,
+// or it still matches the value last pushed. The versions are compared when the provider reports them, the hashes of the values otherwise.
+// The remote value is never read from the value cache of the store.
+// synced is the entry recorded in status.syncedPushSecrets, nil if the entry was never pushed by the PushSecret:
+// its remote value is not updated if it exists. An entry synced before the update policy was set,
+// without a recorded value, is updated and recorded.
+func remoteUnchanged(ctx context.Context, secretClient esv1.SecretsClient, data esapi.PushSecretData, synced *esapi.PushSecretStatusData, storeName string) (bool, error) {
+	value, version, err := secretstore.GetSecretVersion(ctx, secretstore.Uncached(secretClient), remoteDataRef(data))
+	if remoteValueMissing(data, err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf(errCompareRemoteValue, statusRef(data), storeName, err)
+	}
+	if synced == nil {
+		return false, nil
+	}
+	if synced.PushedVersion != "" && version.ID != "" {
+		return version.ID == synced.PushedVersion, nil
+	}
+	if synced.PushedHash == "" {
+		return true, nil
+	}
+	return esutils.ObjectHash(value) == synced.PushedHash, nil
+}
+
+// recordPushedValue reads the value of an entry back from the provider right after it was pushed, and records
+// its hash and version, as the provider stores it, to compare them with the remote value before the next push.
+func recordPushedValue(ctx context.Context, secretClient esv1.SecretsClient, data *esapi.PushSecretStatusData, storeName string) error {
+	value, version, err := secretstore.GetSecretVersion(ctx, secretstore.Uncached(secretClient), remoteDataRef(data.PushSecretData))
+	if err != nil {
+		return fmt.Errorf(errGetRemoteValue, statusRef(data.PushSecretData), storeName, err)
+	}
+	data.PushedHash = esutils.ObjectHash(value)
+	data.PushedVersion = version.ID
+	return nil
+}
+
+// pushEntryIfUnchanged pushes a data entry with the IfUnchanged update policy, and returns false, without
+// pushing it, when its remote value was changed outside of the PushSecret. When the provider enforces it through
+// esv1.ConditionalPusher, the push is conditioned on the version last pushed. Otherwise, and for an entry without
+// a recorded version, the remote value is compared with the value last pushed right before the push.
+func (r *Reconciler) pushEntryIfUnchanged(
+	ctx context.Context,
+	sp *storePush,
+	params pushEntryParams,
+	synced *esapi.PushSecretStatusData,
+	beforePush func(params pushEntryParams) func(),
+) (esapi.PushSecretStatusData, bool, error) {
+	entry := esapi.PushSecretStatusData{PushSecretData: params.data}
+	if synced != nil && synced.PushedVersion != "" {
+		undo := notifyBeforePush(beforePush, params)
+		pushedHash, version, err := r.pushSecretEntryIfVersion(ctx, sp.client, sp.secret, params, synced.PushedVersion)
+		switch {
+		case errors.Is(err, esv1.VersionConflictErr):
+			// nothing was pushed, the remote value of another writer must not be restored by a rollback
+			undo()
+			return entry, false, nil
+		case errors.Is(err, esv1.ConditionalPushUnsupportedErr):
+			undo()
+		case err != nil:
+			return entry, false, err
+		default:
+			entry.PushedHash, entry.PushedVersion = pushedHash, version.ID
+			return entry, true, nil
+		}
+	}
+
+	unchanged, err := remoteUnchanged(ctx, sp.client, params.data, synced, sp.info.Name)
+	if err != nil || !unchanged {
+		return entry, false, err
+	}
+	notifyBeforePush(beforePush, params)
+	if _, err := r.pushSecretEntry(ctx, sp.client, sp.secret, params); err != nil {
+		return entry, false, err
+	}
+	if err := recordPushedValue(ctx, sp.client, &entry, sp.info.Name); err != nil {
+		return entry, false, err
+	}
+	return entry, true, nil
+}
+
+// notifyBeforePush calls beforePush with an entry about to be pushed, when non-nil, and returns the func undoing it.
+func notifyBeforePush(beforePush func(params pushEntryParams) func(), params pushEntryParams) func() {
+	if beforePush == nil {
+		return func() {}
+	}
+	return beforePush(params)
+}
+
+// remoteDataRef returns the ref reading the remote value of a data entry.
+func remoteDataRef(data esapi.PushSecretData) esv1.ExternalSecretDataRemoteRef {
+	return esv1.ExternalSecretDataRemoteRef{
+		Key:      data.GetRemoteKey(),
+		Property: data.GetProperty(),
+	}
+}
+
+// remoteValueMissing returns true if err reports that the remote value of a data entry does not exist:
+// the remote secret does not exist, or the property of the entry does not exist in the remote secret.
+func remoteValueMissing(data esapi.PushSecretData, err error) bool {
+	if errors.Is(err, esv1.NoSecretErr) {
+		return true
+	}
+	return err != nil && data.GetProperty() != "" && strings.Contains(err.Error(), errMissingProperty)
+}
+
+// handleRemoteConflicts reports the remote values which were not updated by the IfUnchanged update policy
+// in the Conflict condition. The condition is removed with the other update policies.
+func (r *Reconciler) handleRemoteConflicts(ps *esapi.PushSecret, conflicts []string) {
+	if ps.Spec.UpdatePolicy != esapi.PushSecretUpdatePolicyIfUnchanged {
+		ps.Status.Conditions = FilterOutCondition(ps.Status.Conditions, esapi.PushSecretConflict)
+		return
+	}
+	if len(conflicts) == 0 {
+		SetPushSecretCondition(ps, *NewPushSecretCondition(esapi.PushSecretConflict, v1.ConditionFalse, esapi.ReasonNoRemoteConflict, msgNoRemoteConflict))
+		return
+	}
+
+	slices.Sort(conflicts)
+	msg := fmt.Sprintf(msgRemoteConflict, strings.Join(conflicts, ", "))
+	// a conflict is reported once, not on every refresh until it is resolved
+	cond := GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretConflict)
+	if cond == nil || cond.Status != v1.ConditionTrue || cond.Message != msg {
+		r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonRemoteConflict, msg)
+	}
+	SetPushSecretCondition(ps, *NewPushSecretCondition(esapi.PushSecretConflict, v1.ConditionTrue, esapi.ReasonRemoteConflict, msg))
+}
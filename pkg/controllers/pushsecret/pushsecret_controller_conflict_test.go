@@ -0,0 +1,277 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+// versionedClient reports a fixed version along the values of the fake provider.
+type versionedClient struct {
+	esv1.SecretsClient
+	version string
+}
+
+func (c *versionedClient) GetSecretVersion(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, esv1.SecretVersion, error) {
+	value, err := c.GetSecret(ctx, ref)
+	return value, esv1.SecretVersion{ID: c.version}, err
+}
+
+func TestRemoteUnchanged(t *testing.T) {
+	ctx := context.Background()
+	fakeProvider.Reset()
+	t.Cleanup(fakeProvider.Reset)
+
+	data := esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: "foo", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "foo"}}}
+	unrecorded := esapi.PushSecretStatusData{PushSecretData: data}
+	synced := esapi.PushSecretStatusData{PushSecretData: data, PushedHash: esutils.ObjectHash([]byte("bar"))}
+
+	tests := []struct {
+		name     string
+		client   esv1.SecretsClient
+		property string
+		remote   []byte
+		getErr   error
+		synced   *esapi.PushSecretStatusData
+		want     bool
+		wantErr  string
+	}{
+		{name: "remote value unchanged", client: fakeProvider, remote: []byte("bar"), synced: &synced, want: true},
+		{name: "remote value changed", client: fakeProvider, remote: []byte("changed"), synced: &synced, want: false},
+		{name: "remote value missing", client: fakeProvider, getErr: esv1.NoSecretErr, want: true},
+		{name: "remote property missing", client: fakeProvider, property: "bar", getErr: errors.New("key bar does not exist in secret foo"), want: true},
+		{name: "remote value not pushed by the PushSecret", client: fakeProvider, remote: []byte("bar"), want: false},
+		{name: "remote value not recorded", client: fakeProvider, remote: []byte("changed"), synced: &unrecorded, want: true},
+		{name: "read error", client: fakeProvider, getErr: errors.New("boom"), synced: &synced, wantErr: "to compare it with the value last pushed"},
+		{name: "read error without property", client: fakeProvider, getErr: errors.New("key bar does not exist in secret foo"), synced: &synced, wantErr: "to compare it with the value last pushed"},
+		{
+			name:   "remote version unchanged",
+			client: &versionedClient{SecretsClient: fakeProvider, version: "2"},
+			remote: []byte("changed"),
+			synced: &esapi.PushSecretStatusData{PushSecretData: esapi.PushSecretData{Match: data.Match}, PushedHash: synced.PushedHash, PushedVersion: "2"},
+			want:   true,
+		},
+		{
+			name:   "remote version changed",
+			client: &versionedClient{SecretsClient: fakeProvider, version: "3"},
+			remote: []byte("bar"),
+			synced: &esapi.PushSecretStatusData{PushSecretData: esapi.PushSecretData{Match: data.Match}, PushedHash: synced.PushedHash, PushedVersion: "2"},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeProvider.WithGetSecret(tt.remote, tt.getErr)
+			data := data
+			data.Match.RemoteRef.Property = tt.property
+			got, err := remoteUnchanged(ctx, tt.client, data, tt.synced, "store")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// conditionalClient pushes values to the fake provider only if their version is still the expected one,
+// the version being incremented on every push.
+type conditionalClient struct {
+	versionedClient
+}
+
+func (c *conditionalClient) PushSecretIfVersion(ctx context.Context, secret *v1.Secret, data esv1.PushSecretData, version esv1.SecretVersion) (esv1.SecretVersion, error) {
+	if version.ID != c.version {
+		return esv1.SecretVersion{}, esv1.VersionConflictErr
+	}
+	if err := c.PushSecret(ctx, secret, data); err != nil {
+		return esv1.SecretVersion{}, err
+	}
+	current, _ := strconv.Atoi(c.version)
+	c.version = strconv.Itoa(current + 1)
+	return esv1.SecretVersion{ID: c.version}, nil
+}
+
+func TestPushEntryIfUnchanged(t *testing.T) {
+	ctx := context.Background()
+	data := esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: "foo", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "remote-foo"}}}
+	synced := func(version string) *esapi.PushSecretStatusData {
+		return &esapi.PushSecretStatusData{PushSecretData: data, PushedHash: esutils.ObjectHash([]byte("old")), PushedVersion: version}
+	}
+
+	tests := []struct {
+		name        string
+		client      esv1.SecretsClient
+		synced      *esapi.PushSecretStatusData
+		wantPushed  bool
+		wantVersion string
+		wantRemote  string
+		wantRecords int
+	}{
+		{
+			name:        "version enforced by the provider",
+			client:      &conditionalClient{versionedClient{version: "2"}},
+			synced:      synced("2"),
+			wantPushed:  true,
+			wantVersion: "3",
+			wantRemote:  "new",
+			wantRecords: 1,
+		},
+		{
+			name:       "version conflict reported by the provider",
+			client:     &conditionalClient{versionedClient{version: "3"}},
+			synced:     synced("2"),
+			wantRemote: "old",
+		},
+		{
+			name:        "remote value compared without conditional pushes",
+			client:      &versionedClient{version: "2"},
+			synced:      synced("2"),
+			wantPushed:  true,
+			wantVersion: "2",
+			wantRemote:  "new",
+			wantRecords: 1,
+		},
+		{
+			name:        "remote value compared without a recorded version",
+			client:      &conditionalClient{versionedClient{version: "2"}},
+			synced:      synced(""),
+			wantPushed:  true,
+			wantVersion: "2",
+			wantRemote:  "new",
+			wantRecords: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeProvider.Reset()
+			t.Cleanup(fakeProvider.Reset)
+			switch c := tt.client.(type) {
+			case *conditionalClient:
+				c.SecretsClient = fakeProvider
+			case *versionedClient:
+				c.SecretsClient = fakeProvider
+			}
+			require.NoError(t, fakeProvider.PushSecret(ctx, &v1.Secret{Data: map[string][]byte{"foo": []byte("old")}}, data))
+			fakeProvider.GetSecretFn = func(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+				return fakeProvider.GetPushSecretData()[ref.Key].Value, nil
+			}
+			params := pushEntryParams{
+				data:         data,
+				updatePolicy: esapi.PushSecretUpdatePolicyIfUnchanged,
+				originalData: map[string][]byte{"foo": []byte("new")},
+				storeName:    "store",
+			}
+			sp := &storePush{key: "SecretStore/store", info: storeInfo{Name: "store"}, client: tt.client, secret: &v1.Secret{}, entries: []pushEntryParams{params}}
+			var records int
+			beforePush := func(pushEntryParams) func() {
+				records++
+				return func() { records-- }
+			}
+
+			r := &Reconciler{}
+			entry, pushed, err := r.pushEntryIfUnchanged(ctx, sp, params, tt.synced, beforePush)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPushed, pushed)
+			assert.Equal(t, tt.wantRemote, remoteValue("remote-foo"))
+			// an entry rejected by the provider is not restored by a rollback
+			assert.Equal(t, tt.wantRecords, records)
+			if tt.wantPushed {
+				assert.Equal(t, tt.wantVersion, entry.PushedVersion)
+				assert.Equal(t, esutils.ObjectHash([]byte("new")), entry.PushedHash)
+			}
+		})
+	}
+}
+
+func TestReconcileIfUnchanged(t *testing.T) {
+	data := newTestData("foo")
+	ps := newTestPushSecret(data)
+	ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyIfUnchanged
+	source := newTestSource(map[string][]byte{"foo": []byte("v1")})
+	h := newTestReconciler(t, ps, source)
+	fakeProvider.GetSecretFn = lastPushed
+	changeSourceValue := func(value string) {
+		t.Helper()
+		got := &v1.Secret{}
+		require.NoError(t, h.kube.Get(context.Background(), client.ObjectKeyFromObject(source), got))
+		got.Data["foo"] = []byte(value)
+		require.NoError(t, h.kube.Update(context.Background(), got))
+	}
+	assertConflict := func(got *esapi.PushSecret, status v1.ConditionStatus, reason string) *esapi.PushSecretStatusCondition {
+		t.Helper()
+		cond := GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretConflict)
+		require.NotNil(t, cond)
+		assert.Equal(t, status, cond.Status)
+		assert.Equal(t, reason, cond.Reason)
+		return cond
+	}
+
+	// a remote value which was not pushed by the PushSecret is not overwritten
+	pushRemoteValue(t, data, "foreign")
+	got := h.mustReconcile(ps)
+	assert.Equal(t, "foreign", remoteValue("remote-foo"))
+	assert.Empty(t, got.Status.SyncedPushSecrets["SecretStore/store"])
+	cond := assertConflict(got, v1.ConditionTrue, esapi.ReasonRemoteConflict)
+	assert.Contains(t, cond.Message, "SecretStore/store:remote-foo")
+	ready := GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, v1.ConditionTrue, ready.Status)
+
+	// a missing remote value is created, and the value pushed is recorded
+	// (resetting the fake provider deletes the remote value)
+	fakeProvider.Reset()
+	fakeProvider.GetSecretFn = lastPushed
+	got = h.mustReconcile(ps)
+	assert.Equal(t, "v1", remoteValue("remote-foo"))
+	assert.Equal(t, esutils.ObjectHash([]byte("v1")), got.Status.SyncedPushSecrets["SecretStore/store"]["remote-foo"].PushedHash)
+	assertConflict(got, v1.ConditionFalse, esapi.ReasonNoRemoteConflict)
+
+	// the remote value still matches the value last pushed
+	changeSourceValue("v2")
+	got = h.mustReconcile(ps)
+	assert.Equal(t, "v2", remoteValue("remote-foo"))
+	assert.Equal(t, esutils.ObjectHash([]byte("v2")), got.Status.SyncedPushSecrets["SecretStore/store"]["remote-foo"].PushedHash)
+
+	// the remote value was changed by another writer
+	pushRemoteValue(t, data, "other")
+	changeSourceValue("v3")
+	got = h.mustReconcile(ps)
+	assert.Equal(t, "other", remoteValue("remote-foo"))
+	assert.Equal(t, esutils.ObjectHash([]byte("v2")), got.Status.SyncedPushSecrets["SecretStore/store"]["remote-foo"].PushedHash)
+	assertConflict(got, v1.ConditionTrue, esapi.ReasonRemoteConflict)
+
+	// the condition is removed with the other update policies
+	h.update(ps, func(ps *esapi.PushSecret) { ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyReplace })
+	got = h.mustReconcile(ps)
+	assert.Equal(t, "v3", remoteValue("remote-foo"))
+	assert.Nil(t, GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretConflict))
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+const (
+	msgDeletionScheduled = "remote secrets are deleted at the end of the deletion grace period: %s"
+	msgDeletionCancelled = "pending deletions of remote secrets were cancelled: %s"
+)
+
+// deletionSchedule defers the deletion of remote secrets until the end of the deletion grace period.
+// The deletions are recorded in status.pendingDeletions, and the time they are due is kept across reconciles.
+type deletionSchedule struct {
+	now         time.Time
+	gracePeriod time.Duration
+	// scheduled are the deletions pending before the reconcile, by store and remote ref.
+	scheduled map[string]metav1.Time
+	pending   []esapi.PushSecretPendingDeletion
+	added     []string
+	cancelled []string
+}
+
+func newDeletionSchedule(ps *esapi.PushSecret, now time.Time) *deletionSchedule {
+	s := &deletionSchedule{
+		now:       now,
+		scheduled: make(map[string]metav1.Time, len(ps.Status.PendingDeletions)),
+	}
+	if ps.Spec.DeletionGracePeriod != nil {
+		s.gracePeriod = ps.Spec.DeletionGracePeriod.Duration
+	}
+	for _, pending := range ps.Status.PendingDeletions {
+		s.scheduled[pendingDeletionKey(pending.Store, pending.RemoteRef)] = pending.DeleteAfter
+	}
+	return s
+}
+
+func pendingDeletionKey(store, remoteRef string) string {
+	return store + ":" + remoteRef
+}
+
+// due returns true if the remote secret is deleted now, otherwise its deletion is kept pending.
+// The remote secrets are deleted right away without a grace period.
+func (s *deletionSchedule) due(store, remoteRef string) bool {
+	if s.gracePeriod <= 0 {
+		return true
+	}
+	key := pendingDeletionKey(store, remoteRef)
+	deleteAfter, ok := s.scheduled[key]
+	if !ok {
+		deleteAfter = metav1.NewTime(s.now.Add(s.gracePeriod))
+		s.added = append(s.added, key)
+	}
+	if !s.now.Before(deleteAfter.Time) {
+		return true
+	}
+	s.pending = append(s.pending, esapi.PushSecretPendingDeletion{Store: store, RemoteRef: remoteRef, DeleteAfter: deleteAfter})
+	return false
+}
+
+// keep cancels the pending deletion of a remote secret which is pushed again.
+func (s *deletionSchedule) keep(store, remoteRef string) {
+	key := pendingDeletionKey(store, remoteRef)
+	if _, ok := s.scheduled[key]; ok {
+		s.cancelled = append(s.cancelled, key)
+	}
+}
+
+// handleDeletionSchedule records the pending deletions in status, and reports the scheduled and cancelled ones.
+func (r *Reconciler) handleDeletionSchedule(ps *esapi.PushSecret, s *deletionSchedule) {
+	ps.Status.PendingDeletions = s.pending
+	if len(s.added) > 0 {
+		slices.Sort(s.added)
+		r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonDeletionScheduled, fmt.Sprintf(msgDeletionScheduled, strings.Join(s.added, ", ")))
+	}
+	if len(s.cancelled) > 0 {
+		slices.Sort(s.cancelled)
+		r.recorder.Event(ps, v1.EventTypeNormal, esapi.ReasonDeletionCancelled, fmt.Sprintf(msgDeletionCancelled, strings.Join(s.cancelled, ", ")))
+	}
+}
+
+// deletionsDue returns true if a pending deletion is due, so the PushSecret is reconciled before its next refresh.
+func deletionsDue(ps *esapi.PushSecret, now time.Time) bool {
+	for _, pending := range ps.Status.PendingDeletions {
+		if !now.Before(pending.DeleteAfter.Time) {
+			return true
+		}
+	}
+	return false
+}
+
+// requeueForPendingDeletions requeues the PushSecret when its next pending deletion is due, if it is due earlier.
+func requeueForPendingDeletions(ps *esapi.PushSecret, result ctrl.Result, now time.Time) ctrl.Result {
+	for _, pending := range ps.Status.PendingDeletions {
+		// a second later, so the deletion is due when the PushSecret is reconciled
+		after := max(pending.DeleteAfter.Sub(now), 0) + time.Second
+		if result.RequeueAfter == 0 || after < result.RequeueAfter {
+			result.RequeueAfter = after
+		}
+	}
+	return result
+}
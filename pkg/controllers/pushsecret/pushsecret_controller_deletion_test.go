@@ -0,0 +1,147 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func TestDeletionSchedule(t *testing.T) {
+	now := time.Now()
+	ps := &esapi.PushSecret{}
+	schedule := newDeletionSchedule(ps, now)
+	assert.True(t, schedule.due("SecretStore/store", "foo"))
+	assert.Empty(t, schedule.pending)
+
+	ps.Spec.DeletionGracePeriod = &metav1.Duration{Duration: time.Hour}
+	schedule = newDeletionSchedule(ps, now)
+	assert.False(t, schedule.due("SecretStore/store", "foo"))
+	require.Len(t, schedule.pending, 1)
+	assert.Equal(t, now.Add(time.Hour).Unix(), schedule.pending[0].DeleteAfter.Unix())
+	assert.Equal(t, []string{"SecretStore/store:foo"}, schedule.added)
+
+	// the time a deletion is due is kept across reconciles
+	ps.Status.PendingDeletions = []esapi.PushSecretPendingDeletion{
+		{Store: "SecretStore/store", RemoteRef: "foo", DeleteAfter: metav1.NewTime(now.Add(time.Minute))},
+		{Store: "SecretStore/store", RemoteRef: "bar", DeleteAfter: metav1.NewTime(now.Add(-time.Minute))},
+		{Store: "SecretStore/store", RemoteRef: "baz", DeleteAfter: metav1.NewTime(now.Add(time.Minute))},
+	}
+	schedule = newDeletionSchedule(ps, now)
+	assert.False(t, schedule.due("SecretStore/store", "foo"))
+	assert.Equal(t, now.Add(time.Minute).Unix(), schedule.pending[0].DeleteAfter.Unix())
+	assert.True(t, schedule.due("SecretStore/store", "bar"))
+	schedule.keep("SecretStore/store", "baz")
+	schedule.keep("SecretStore/store", "other")
+	assert.Len(t, schedule.pending, 1)
+	assert.Empty(t, schedule.added)
+	assert.Equal(t, []string{"SecretStore/store:baz"}, schedule.cancelled)
+
+	assert.True(t, deletionsDue(ps, now))
+	result := requeueForPendingDeletions(ps, ctrl.Result{RequeueAfter: time.Hour}, now)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+}
+
+func TestReconcileDeletionGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	foo := newTestData("foo")
+	bar := newTestData("bar")
+	ps := newTestPushSecret(foo, bar)
+	ps.Finalizers = []string{pushSecretFinalizer}
+	ps.Spec.DeletionPolicy = esapi.PushSecretDeletionPolicyDelete
+	ps.Spec.DeletionGracePeriod = &metav1.Duration{Duration: time.Hour}
+	h := newTestReconciler(t, ps, newTestSource(map[string][]byte{"foo": []byte("1"), "bar": []byte("2")}))
+
+	deletes := 0
+	fakeProvider.WithDeleteSecretFn(func() error {
+		deletes++
+		return nil
+	})
+	reconcile := func() (ctrl.Result, *esapi.PushSecret) {
+		t.Helper()
+		result, got, err := h.reconcile(ps)
+		require.NoError(t, err)
+		return result, got
+	}
+	setData := func(data ...esapi.PushSecretData) {
+		t.Helper()
+		h.update(ps, func(ps *esapi.PushSecret) { ps.Spec.Data = data })
+	}
+	expirePendingDeletions := func() {
+		t.Helper()
+		got := &esapi.PushSecret{}
+		require.NoError(t, h.kube.Get(ctx, client.ObjectKeyFromObject(ps), got))
+		for i := range got.Status.PendingDeletions {
+			got.Status.PendingDeletions[i].DeleteAfter = metav1.NewTime(time.Now().Add(-time.Second))
+		}
+		require.NoError(t, h.kube.Status().Update(ctx, got))
+	}
+
+	_, got := reconcile()
+	assert.Len(t, got.Status.SyncedPushSecrets["SecretStore/store"], 2)
+	assert.Empty(t, got.Status.PendingDeletions)
+
+	// the deletion of a removed entry is pending, and the entry is kept synced
+	setData(foo)
+	result, got := reconcile()
+	assert.Equal(t, 0, deletes)
+	assert.Contains(t, got.Status.SyncedPushSecrets["SecretStore/store"], "remote-bar")
+	require.Len(t, got.Status.PendingDeletions, 1)
+	assert.Equal(t, "SecretStore/store", got.Status.PendingDeletions[0].Store)
+	assert.Equal(t, "remote-bar", got.Status.PendingDeletions[0].RemoteRef)
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
+	assert.Contains(t, h.events(), esapi.ReasonDeletionScheduled)
+
+	// the deletion is cancelled when the entry is pushed again
+	setData(foo, bar)
+	_, got = reconcile()
+	assert.Equal(t, 0, deletes)
+	assert.Empty(t, got.Status.PendingDeletions)
+	assert.Contains(t, h.events(), esapi.ReasonDeletionCancelled)
+
+	// the remote secret is deleted at the end of the grace period
+	setData(foo)
+	reconcile()
+	expirePendingDeletions()
+	_, got = reconcile()
+	assert.Equal(t, 1, deletes)
+	assert.NotContains(t, got.Status.SyncedPushSecrets["SecretStore/store"], "remote-bar")
+	assert.Empty(t, got.Status.PendingDeletions)
+
+	// the finalizer of a deleted PushSecret is kept until the end of the grace period
+	require.NoError(t, h.kube.Delete(ctx, got))
+	result, got = reconcile()
+	require.NotNil(t, got)
+	assert.Equal(t, 1, deletes)
+	assert.Contains(t, got.Finalizers, pushSecretFinalizer)
+	require.Len(t, got.Status.PendingDeletions, 1)
+	assert.Greater(t, result.RequeueAfter, 59*time.Minute)
+
+	expirePendingDeletions()
+	_, got = reconcile()
+	assert.Equal(t, 2, deletes)
+	assert.Nil(t, got)
+}
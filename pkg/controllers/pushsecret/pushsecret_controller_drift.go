@@ -0,0 +1,111 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret/psmetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+const (
+	errGetRemoteValue = "could not read remote ref %v back from secretstore %v: %w"
+
+	msgRemoteDrifted       = "remote values were changed outside of the controller: %s"
+	msgRemoteDriftReverted = "remote values changed outside of the controller were pushed again: %s"
+	msgNoRemoteDrift       = "remote values match the values pushed by the controller"
+)
+
+// checksRemoteDrift returns true if the drift policy reads the remote values back, it defaults to Ignore.
+func checksRemoteDrift(ps *esapi.PushSecret) bool {
+	return ps.Spec.DriftPolicy == esapi.PushSecretDriftPolicyReport || ps.Spec.DriftPolicy == esapi.PushSecretDriftPolicyRevert
+}
+
+// detectsRemoteDrift returns true if the remote value of a data entry is read back to detect drift.
+// Only the entries pushing a single key of the Secret can be compared with the value the provider returns,
+// the values of the IfNotExists update policy are not owned by the PushSecret, and the IfUnchanged update
+// policy reports the changed values as conflicts.
+func detectsRemoteDrift(ps *esapi.PushSecret, data esapi.PushSecretData, dataOverride map[string][]byte) bool {
+	if !checksRemoteDrift(ps) {
+		return false
+	}
+	if ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfNotExists || ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfUnchanged {
+		return false
+	}
+	return data.GetSecretKey() != "" && dataOverride == nil
+}
+
+// remoteDrifted reads the remote value of a synced entry back from the provider and returns true
+// if it does not match the hash of the value last pushed. A remote value deleted from the provider drifted as well.
+// The value is never read from the value cache of the store, which would hide a change until its ttl.
+func remoteDrifted(ctx context.Context, secretClient esv1.SecretsClient, synced esapi.PushSecretStatusData, storeName string) (bool, error) {
+	if synced.PushedHash == "" {
+		return false, nil
+	}
+	value, err := secretstore.Uncached(secretClient).GetSecret(ctx, remoteDataRef(synced.PushSecretData))
+	if errors.Is(err, esv1.NoSecretErr) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf(errGetRemoteValue, statusRef(synced.PushSecretData), storeName, err)
+	}
+	return esutils.ObjectHash(value) != synced.PushedHash, nil
+}
+
+// handleRemoteDrift reports the remote values which drifted from the values last pushed in the RemoteDrift condition.
+// The condition is removed when drift detection is disabled.
+func (r *Reconciler) handleRemoteDrift(ps *esapi.PushSecret, drifted []string, resourceLabels prometheus.Labels) {
+	cond := GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretRemoteDrift)
+	if !checksRemoteDrift(ps) {
+		ps.Status.Conditions = FilterOutCondition(ps.Status.Conditions, esapi.PushSecretRemoteDrift)
+		return
+	}
+
+	if len(drifted) == 0 {
+		SetPushSecretCondition(ps, *NewPushSecretCondition(esapi.PushSecretRemoteDrift, v1.ConditionFalse, esapi.ReasonNoRemoteDrift, msgNoRemoteDrift))
+		return
+	}
+
+	slices.Sort(drifted)
+	refs := strings.Join(drifted, ", ")
+	if ps.Spec.DriftPolicy == esapi.PushSecretDriftPolicyReport {
+		msg := fmt.Sprintf(msgRemoteDrifted, refs)
+		// a drift is reported once, not on every refresh until it is resolved
+		if cond == nil || cond.Status != v1.ConditionTrue || cond.Message != msg {
+			r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonRemoteDrifted, msg)
+			psmetrics.GetCounterVec(psmetrics.RemoteDriftDetectedKey).With(resourceLabels).Add(float64(len(drifted)))
+		}
+		SetPushSecretCondition(ps, *NewPushSecretCondition(esapi.PushSecretRemoteDrift, v1.ConditionTrue, esapi.ReasonRemoteDrifted, msg))
+		return
+	}
+
+	msg := fmt.Sprintf(msgRemoteDriftReverted, refs)
+	r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonRemoteDriftReverted, msg)
+	psmetrics.GetCounterVec(psmetrics.RemoteDriftDetectedKey).With(resourceLabels).Add(float64(len(drifted)))
+	SetPushSecretCondition(ps, *NewPushSecretCondition(esapi.PushSecretRemoteDrift, v1.ConditionFalse, esapi.ReasonRemoteDriftReverted, msg))
+}
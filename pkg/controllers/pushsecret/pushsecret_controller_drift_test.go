@@ -0,0 +1,138 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+func TestDetectsRemoteDrift(t *testing.T) {
+	data := esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: "foo", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "foo"}}}
+	ps := &esapi.PushSecret{Spec: esapi.PushSecretSpec{DriftPolicy: esapi.PushSecretDriftPolicyReport}}
+	assert.True(t, detectsRemoteDrift(ps, data, nil))
+
+	// the whole secret and bundles are pushed in a provider specific format
+	assert.False(t, detectsRemoteDrift(ps, esapi.PushSecretData{Match: esapi.PushSecretMatch{RemoteRef: data.Match.RemoteRef}}, nil))
+	assert.False(t, detectsRemoteDrift(ps, data, map[string][]byte{"foo": []byte("bar")}))
+
+	ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyIfNotExists
+	assert.False(t, detectsRemoteDrift(ps, data, nil))
+	ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyIfUnchanged
+	assert.False(t, detectsRemoteDrift(ps, data, nil))
+
+	ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyReplace
+	ps.Spec.DriftPolicy = ""
+	assert.False(t, detectsRemoteDrift(ps, data, nil))
+	ps.Spec.DriftPolicy = esapi.PushSecretDriftPolicyIgnore
+	assert.False(t, detectsRemoteDrift(ps, data, nil))
+}
+
+func TestRemoteDrifted(t *testing.T) {
+	ctx := context.Background()
+	fakeProvider.Reset()
+	t.Cleanup(fakeProvider.Reset)
+
+	synced := esapi.PushSecretStatusData{
+		PushSecretData: esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: "foo", RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "foo"}}},
+		PushedHash:     esutils.ObjectHash([]byte("bar")),
+	}
+	fakeProvider.WithGetSecret([]byte("bar"), nil)
+	drifted, err := remoteDrifted(ctx, fakeProvider, synced, "store")
+	require.NoError(t, err)
+	assert.False(t, drifted)
+
+	fakeProvider.WithGetSecret([]byte("changed"), nil)
+	drifted, err = remoteDrifted(ctx, fakeProvider, synced, "store")
+	require.NoError(t, err)
+	assert.True(t, drifted)
+
+	fakeProvider.WithGetSecret(nil, esv1.NoSecretErr)
+	drifted, err = remoteDrifted(ctx, fakeProvider, synced, "store")
+	require.NoError(t, err)
+	assert.True(t, drifted)
+
+	// nothing is read back for the entries pushed without drift detection
+	drifted, err = remoteDrifted(ctx, fakeProvider, esapi.PushSecretStatusData{PushSecretData: synced.PushSecretData}, "store")
+	require.NoError(t, err)
+	assert.False(t, drifted)
+}
+
+func TestReconcileRemoteDrift(t *testing.T) {
+	data := newTestData("foo")
+	ps := newTestPushSecret(data)
+	ps.Spec.UpdatePolicy = esapi.PushSecretUpdatePolicyReplace
+	ps.Spec.DriftPolicy = esapi.PushSecretDriftPolicyReport
+	h := newTestReconciler(t, ps, newTestSource(map[string][]byte{"foo": []byte("v1")}))
+	fakeProvider.GetSecretFn = lastPushed
+	setDriftPolicy := func(policy esapi.PushSecretDriftPolicy) {
+		t.Helper()
+		h.update(ps, func(ps *esapi.PushSecret) { ps.Spec.DriftPolicy = policy })
+	}
+
+	got := h.mustReconcile(ps)
+	assert.Equal(t, "v1", remoteValue("remote-foo"))
+	assert.Equal(t, esutils.ObjectHash([]byte("v1")), got.Status.SyncedPushSecrets["SecretStore/store"]["remote-foo"].PushedHash)
+	cond := GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretRemoteDrift)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionFalse, cond.Status)
+	assert.Equal(t, esapi.ReasonNoRemoteDrift, cond.Reason)
+
+	// the drift is reported and the remote value is left untouched
+	pushRemoteValue(t, data, "changed")
+	got = h.mustReconcile(ps)
+	assert.Equal(t, "changed", remoteValue("remote-foo"))
+	assert.Equal(t, esutils.ObjectHash([]byte("v1")), got.Status.SyncedPushSecrets["SecretStore/store"]["remote-foo"].PushedHash)
+	cond = GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretRemoteDrift)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionTrue, cond.Status)
+	assert.Equal(t, esapi.ReasonRemoteDrifted, cond.Reason)
+	assert.Contains(t, cond.Message, "SecretStore/store:remote-foo")
+	ready := GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, v1.ConditionTrue, ready.Status)
+
+	// the drift is reverted by pushing the value again
+	setDriftPolicy(esapi.PushSecretDriftPolicyRevert)
+	got = h.mustReconcile(ps)
+	assert.Equal(t, "v1", remoteValue("remote-foo"))
+	cond = GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretRemoteDrift)
+	require.NotNil(t, cond)
+	assert.Equal(t, v1.ConditionFalse, cond.Status)
+	assert.Equal(t, esapi.ReasonRemoteDriftReverted, cond.Reason)
+
+	got = h.mustReconcile(ps)
+	cond = GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretRemoteDrift)
+	require.NotNil(t, cond)
+	assert.Equal(t, esapi.ReasonNoRemoteDrift, cond.Reason)
+
+	// the remote values are not read back anymore
+	setDriftPolicy(esapi.PushSecretDriftPolicyIgnore)
+	pushRemoteValue(t, data, "changed")
+	got = h.mustReconcile(ps)
+	assert.Equal(t, "v1", remoteValue("remote-foo"))
+	assert.Empty(t, got.Status.SyncedPushSecrets["SecretStore/store"]["remote-foo"].PushedHash)
+	assert.Nil(t, GetPushSecretCondition(got.Status.Conditions, esapi.PushSecretRemoteDrift))
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+// testStoreName is the name of the SecretStore added to the client of every testReconciler.
+const testStoreName = "store"
+
+// newTestStore returns a SecretStore served by fakeProvider.
+func newTestStore(name string) *esv1.SecretStore {
+	return &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{Fake: &esv1.FakeProvider{}}},
+	}
+}
+
+// newTestSource returns the Secret named source, which holds data.
+func newTestSource(data map[string][]byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "default"},
+		Data:       data,
+	}
+}
+
+// newTestData returns an entry pushing the key of the source to the remote key remote-<key>.
+func newTestData(key string) esapi.PushSecretData {
+	return esapi.PushSecretData{Match: esapi.PushSecretMatch{SecretKey: key, RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "remote-" + key}}}
+}
+
+// newTestPushSecret returns a PushSecret named ps, which pushes data from the source
+// to the test store at every reconcile.
+func newTestPushSecret(data ...esapi.PushSecretData) *esapi.PushSecret {
+	return &esapi.PushSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "default"},
+		Spec: esapi.PushSecretSpec{
+			RefreshInterval: &metav1.Duration{Duration: time.Nanosecond},
+			SecretStoreRefs: []esapi.PushSecretStoreRef{{Name: testStoreName, Kind: esv1.SecretStoreKind}},
+			Selector:        esapi.PushSecretSelector{Secret: &esapi.PushSecretSecret{Name: "source"}},
+			Data:            data,
+		},
+	}
+}
+
+// testReconciler runs a Reconciler against a fake client.
+type testReconciler struct {
+	t        *testing.T
+	r        *Reconciler
+	kube     client.Client
+	recorder *record.FakeRecorder
+}
+
+// newTestReconciler returns a reconciler on a fake client holding the test store and objs.
+// fakeProvider is reset, so it has to be configured afterwards.
+func newTestReconciler(t *testing.T, objs ...client.Object) *testReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(esv1.AddToScheme(scheme))
+	utilruntime.Must(esapi.AddToScheme(scheme))
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append(objs, newTestStore(testStoreName))...).
+		WithStatusSubresource(&esapi.PushSecret{}).
+		Build()
+
+	fakeProvider.Reset()
+	t.Cleanup(fakeProvider.Reset)
+
+	recorder := record.NewFakeRecorder(100)
+	return &testReconciler{
+		t:        t,
+		r:        &Reconciler{Client: kube, Log: logr.Discard(), Scheme: scheme, recorder: recorder},
+		kube:     kube,
+		recorder: recorder,
+	}
+}
+
+// reconcile reconciles the PushSecret, and returns it as stored afterwards, or nil if it was deleted.
+func (h *testReconciler) reconcile(ps *esapi.PushSecret) (ctrl.Result, *esapi.PushSecret, error) {
+	h.t.Helper()
+	result, err := h.r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ps)})
+	got := &esapi.PushSecret{}
+	getErr := h.kube.Get(context.Background(), client.ObjectKeyFromObject(ps), got)
+	if apierrors.IsNotFound(getErr) {
+		return result, nil, err
+	}
+	require.NoError(h.t, getErr)
+	return result, got, err
+}
+
+// mustReconcile reconciles the PushSecret, which must succeed, and returns it as stored afterwards.
+func (h *testReconciler) mustReconcile(ps *esapi.PushSecret) *esapi.PushSecret {
+	h.t.Helper()
+	_, got, err := h.reconcile(ps)
+	require.NoError(h.t, err)
+	return got
+}
+
+// update applies mutate to the stored PushSecret.
+func (h *testReconciler) update(ps *esapi.PushSecret, mutate func(*esapi.PushSecret)) {
+	h.t.Helper()
+	got := &esapi.PushSecret{}
+	require.NoError(h.t, h.kube.Get(context.Background(), client.ObjectKeyFromObject(ps), got))
+	mutate(got)
+	require.NoError(h.t, h.kube.Update(context.Background(), got))
+}
+
+// events returns the events recorded since the last call.
+func (h *testReconciler) events() string {
+	return drainEvents(h.recorder)
+}
+
+// drainEvents returns the events recorded so far.
+func drainEvents(recorder *record.FakeRecorder) string {
+	var events string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events += event + "\n"
+		default:
+			return events
+		}
+	}
+}
+
+// lastPushed makes fakeProvider return the value last pushed to a remote key.
+func lastPushed(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	pushed, ok := fakeProvider.GetPushSecretData()[ref.Key]
+	if !ok {
+		return nil, esv1.NoSecretErr
+	}
+	return pushed.Value, nil
+}
+
+// pushRemoteValue changes the remote value of data, as another writer would.
+func pushRemoteValue(t *testing.T, data esapi.PushSecretData, value string) {
+	t.Helper()
+	changed := &v1.Secret{Data: map[string][]byte{data.Match.SecretKey: []byte(value)}}
+	require.NoError(t, fakeProvider.PushSecret(context.Background(), changed, data))
+}
+
+// remoteValue returns the value last pushed to the remote key.
+func remoteValue(key string) string {
+	return string(fakeProvider.GetPushSecretData()[key].Value)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/runtime/decoding"
+)
+
+const (
+	errGetSourceObject      = "could not get source object %s %q: %w"
+	errSourceNotNamespaced  = "source object %s %q is not namespaced"
+	errExtractObjectField   = "could not extract field %q of source object %s %q: %w"
+	errParseObjectFieldPath = "could not parse jsonPath %q: %w"
+	errObjectFieldNotFound  = "jsonPath %q did not match any value"
+	errDecodeObjectField    = "could not decode field %q: %w"
+)
+
+// resolveSecretsFromConfigMaps returns the ConfigMaps selected by the PushSecret as Secrets.
+func (r *Reconciler) resolveSecretsFromConfigMaps(ctx context.Context, namespace string, selector *esapi.PushSecretConfigMap) ([]v1.Secret, error) {
+	if selector.Name != "" {
+		configMap := &v1.ConfigMap{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, configMap); err != nil {
+			return nil, err
+		}
+		return []v1.Secret{configMapToSecret(configMap)}, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector.Selector)
+	if err != nil {
+		return nil, err
+	}
+	var configMapList v1.ConfigMapList
+	if err := r.List(ctx, &configMapList, &client.ListOptions{LabelSelector: labelSelector, Namespace: namespace}); err != nil {
+		return nil, err
+	}
+	secrets := make([]v1.Secret, 0, len(configMapList.Items))
+	for i := range configMapList.Items {
+		secrets = append(secrets, configMapToSecret(&configMapList.Items[i]))
+	}
+	return secrets, nil
+}
+
+// configMapToSecret returns a Secret holding the data and binaryData of the ConfigMap.
+func configMapToSecret(configMap *v1.ConfigMap) v1.Secret {
+	secret := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        configMap.Name,
+			Namespace:   configMap.Namespace,
+			Labels:      maps.Clone(configMap.Labels),
+			Annotations: maps.Clone(configMap.Annotations),
+		},
+		Data: make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData)),
+	}
+	for key, value := range configMap.Data {
+		secret.Data[key] = []byte(value)
+	}
+	maps.Copy(secret.Data, configMap.BinaryData)
+	return secret
+}
+
+// resolveSecretFromObject returns a Secret holding the fields extracted from the object referenced by the PushSecret.
+func (r *Reconciler) resolveSecretFromObject(ctx context.Context, namespace string, ref *esapi.PushSecretObjectRef) (*v1.Secret, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ref.APIVersion)
+	obj.SetKind(ref.Kind)
+	// a cluster-scoped object would not be confined to the namespace of the PushSecret
+	namespaced, err := r.Client.IsObjectNamespaced(obj)
+	if err != nil {
+		return nil, fmt.Errorf(errGetSourceObject, ref.Kind, ref.Name, err)
+	}
+	if !namespaced {
+		return nil, fmt.Errorf(errSourceNotNamespaced, ref.Kind, ref.Name)
+	}
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, obj); err != nil {
+		return nil, fmt.Errorf(errGetSourceObject, ref.Kind, ref.Name, err)
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        obj.GetName(),
+			Namespace:   obj.GetNamespace(),
+			Labels:      obj.GetLabels(),
+			Annotations: obj.GetAnnotations(),
+		},
+		Data: make(map[string][]byte, len(ref.Fields)),
+	}
+	for _, field := range ref.Fields {
+		value, err := extractObjectField(obj.Object, field)
+		if err != nil {
+			return nil, fmt.Errorf(errExtractObjectField, field.Key, ref.Kind, ref.Name, err)
+		}
+		secret.Data[field.Key] = value
+	}
+	return secret, nil
+}
+
+// extractObjectField returns the value of the field of the object matched by its JSONPath.
+// A single string is returned as-is, other values and multiple matches are encoded as JSON.
+func extractObjectField(obj map[string]any, field esapi.PushSecretObjectField) ([]byte, error) {
+	path := field.JSONPath
+	// the braces of the kubectl syntax are optional
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New(field.Key)
+	if err := jp.Parse(path); err != nil {
+		return nil, fmt.Errorf(errParseObjectFieldPath, field.JSONPath, err)
+	}
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return nil, err
+	}
+	var values []any
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+
+	var out []byte
+	switch {
+	case len(values) == 0:
+		return nil, fmt.Errorf(errObjectFieldNotFound, field.JSONPath)
+	case len(values) == 1:
+		if s, ok := values[0].(string); ok {
+			out = []byte(s)
+		} else if out, err = json.Marshal(values[0]); err != nil {
+			return nil, err
+		}
+	default:
+		if out, err = json.Marshal(values); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err = decoding.Decode(field.DecodingStrategy, out)
+	if err != nil {
+		return nil, fmt.Errorf(errDecodeObjectField, field.Key, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func TestExtractObjectField(t *testing.T) {
+	obj := map[string]any{
+		"metadata": map[string]any{"name": "cert"},
+		"data":     map[string]any{"token": "c2VjcmV0"},
+		"status": map[string]any{
+			"notAfter": "2030-01-01T00:00:00Z",
+			"revision": int64(3),
+			"ready":    true,
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "True"},
+				map[string]any{"type": "Issuing", "status": "False"},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		field   esapi.PushSecretObjectField
+		want    string
+		wantErr string
+	}{
+		{name: "string", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.status.notAfter}"}, want: "2030-01-01T00:00:00Z"},
+		{name: "without braces", field: esapi.PushSecretObjectField{Key: "k", JSONPath: ".metadata.name"}, want: "cert"},
+		{name: "number", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.status.revision}"}, want: "3"},
+		{name: "bool", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.status.ready}"}, want: "true"},
+		{name: "object", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.status.conditions[0]}"}, want: `{"status":"True","type":"Ready"}`},
+		{name: "filter", field: esapi.PushSecretObjectField{Key: "k", JSONPath: `{.status.conditions[?(@.type=="Issuing")].status}`}, want: "False"},
+		{name: "multiple matches", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.status.conditions[*].type}"}, want: `["Ready","Issuing"]`},
+		{name: "decoded", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.data.token}", DecodingStrategy: esv1.ExternalSecretDecodeBase64}, want: "secret"},
+		{name: "missing", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.status.missing}"}, wantErr: "is not found"},
+		{name: "no match", field: esapi.PushSecretObjectField{Key: "k", JSONPath: `{.status.conditions[?(@.type=="Other")].status}`}, wantErr: "did not match any value"},
+		{name: "invalid", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.status[}"}, wantErr: "could not parse jsonPath"},
+		{name: "not decodable", field: esapi.PushSecretObjectField{Key: "k", JSONPath: "{.status.notAfter}", DecodingStrategy: esv1.ExternalSecretDecodeBase64}, wantErr: "could not decode field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractObjectField(obj, tt.field)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestResolveSecretsFromSources(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(esapi.AddToScheme(scheme))
+
+	config := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default", Labels: map[string]string{"shared": "true"}},
+		Data:       map[string]string{"url": "https://example.com"},
+		BinaryData: map[string][]byte{"ca.crt": []byte("ca")},
+	}
+	other := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Data:       map[string]string{"url": "https://other.example.com"},
+	}
+	account := &v1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Labels: map[string]string{"team": "a"}},
+		Secrets:    []v1.ObjectReference{{Name: "app-token"}},
+	}
+	// the scope of the kinds tells the namespaced objects apart
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(v1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(v1.SchemeGroupVersion.WithKind("ServiceAccount"), meta.RESTScopeNamespace)
+	mapper.Add(v1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(config, other, account).Build()
+	r := &Reconciler{Client: kube, Log: logr.Discard(), Scheme: scheme}
+	newPushSecret := func(selector esapi.PushSecretSelector) *esapi.PushSecret {
+		return &esapi.PushSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "default"},
+			Spec:       esapi.PushSecretSpec{Selector: selector},
+		}
+	}
+
+	secrets, err := r.resolveSecrets(ctx, newPushSecret(esapi.PushSecretSelector{ConfigMap: &esapi.PushSecretConfigMap{Name: "config"}}))
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, "config", secrets[0].Name)
+	assert.Equal(t, map[string][]byte{"url": []byte("https://example.com"), "ca.crt": []byte("ca")}, secrets[0].Data)
+	assert.Equal(t, "true", secrets[0].Labels["shared"])
+
+	secrets, err = r.resolveSecrets(ctx, newPushSecret(esapi.PushSecretSelector{ConfigMap: &esapi.PushSecretConfigMap{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shared": "true"}},
+	}}))
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, "config", secrets[0].Name)
+
+	_, err = r.resolveSecrets(ctx, newPushSecret(esapi.PushSecretSelector{ConfigMap: &esapi.PushSecretConfigMap{Name: "missing"}}))
+	assert.True(t, apierrors.IsNotFound(err))
+
+	objectRef := &esapi.PushSecretObjectRef{
+		APIVersion: "v1",
+		Kind:       "ServiceAccount",
+		Name:       "app",
+		Fields:     []esapi.PushSecretObjectField{{Key: "token-secret", JSONPath: "{.secrets[0].name}"}},
+	}
+	secrets, err = r.resolveSecrets(ctx, newPushSecret(esapi.PushSecretSelector{ObjectRef: objectRef}))
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, "app", secrets[0].Name)
+	assert.Equal(t, "a", secrets[0].Labels["team"])
+	assert.Equal(t, map[string][]byte{"token-secret": []byte("app-token")}, secrets[0].Data)
+
+	// the deletion of a named source is told apart from other errors
+	objectRef.Name = "missing"
+	_, err = r.resolveSecrets(ctx, newPushSecret(esapi.PushSecretSelector{ObjectRef: objectRef}))
+	assert.True(t, apierrors.IsNotFound(err))
+
+	objectRef.Name = "app"
+	objectRef.Fields[0].JSONPath = "{.automountServiceAccountToken}"
+	_, err = r.resolveSecrets(ctx, newPushSecret(esapi.PushSecretSelector{ObjectRef: objectRef}))
+	require.Error(t, err)
+	assert.False(t, apierrors.IsNotFound(err))
+	assert.Contains(t, err.Error(), `could not extract field "token-secret" of source object ServiceAccount "app"`)
+
+	// cluster-scoped objects are not confined to the namespace of the PushSecret
+	_, err = r.resolveSecrets(ctx, newPushSecret(esapi.PushSecretSelector{ObjectRef: &esapi.PushSecretObjectRef{
+		APIVersion: "v1",
+		Kind:       "Namespace",
+		Name:       "default",
+		Fields:     []esapi.PushSecretObjectField{{Key: "name", JSONPath: "{.metadata.name}"}},
+	}}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not namespaced")
+}
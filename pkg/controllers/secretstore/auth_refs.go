@@ -0,0 +1,333 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlreconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+const (
+	authRefKindSecret         = "Secret"
+	authRefKindServiceAccount = "ServiceAccount"
+
+	// authRefsField indexes stores by the Secrets and ServiceAccounts referenced by their provider config.
+	authRefsField = "spec.provider.authRefs"
+)
+
+// authRef identifies a Secret or ServiceAccount referenced by the provider config of a store.
+type authRef struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (r authRef) String() string {
+	return r.kind + "/" + r.namespace + "/" + r.name
+}
+
+// authRefCollector accumulates the refs of a store, skipping duplicates and refs without a namespace.
+type authRefCollector struct {
+	cluster   bool
+	namespace string
+	seen      map[authRef]bool
+	refs      []authRef
+}
+
+func (c *authRefCollector) add(kind, name string, ns *string) {
+	if name == "" {
+		return
+	}
+	ref := authRef{kind: kind, namespace: c.namespace, name: name}
+	if c.cluster && ns != nil {
+		ref.namespace = *ns
+	}
+	if ref.namespace == "" || c.seen[ref] {
+		return
+	}
+	c.seen[ref] = true
+	c.refs = append(c.refs, ref)
+}
+
+func (c *authRefCollector) secret(sel *esmeta.SecretKeySelector) {
+	if sel != nil {
+		c.add(authRefKindSecret, sel.Name, sel.Namespace)
+	}
+}
+
+func (c *authRefCollector) serviceAccount(sel *esmeta.ServiceAccountSelector) {
+	if sel != nil {
+		c.add(authRefKindServiceAccount, sel.Name, sel.Namespace)
+	}
+}
+
+// authRefs returns the Secrets and ServiceAccounts referenced by the provider config of the store.
+// A SecretStore can only reference objects of its own namespace. The refs of a ClusterSecretStore
+// without a namespace are resolved to the namespace of the consumer, or skipped if it is empty.
+func authRefs(store esv1.GenericStore, namespace string) []authRef {
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil {
+		return nil
+	}
+	c := &authRefCollector{
+		cluster:   store.GetKind() == esv1.ClusterSecretStoreKind,
+		namespace: namespace,
+		seen:      make(map[authRef]bool),
+	}
+	if !c.cluster {
+		c.namespace = store.GetNamespace()
+	}
+	providerAuthRefs(spec.Provider, c)
+
+	slices.SortFunc(c.refs, func(a, b authRef) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return c.refs
+}
+
+// indexAuthRefs is the index function of authRefsField.
+func indexAuthRefs(obj client.Object) []string {
+	store, ok := obj.(esv1.GenericStore)
+	if !ok {
+		return nil
+	}
+	refs := authRefs(store, "")
+	keys := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		keys = append(keys, ref.String())
+	}
+	return keys
+}
+
+// authVersions holds the resourceVersions of the Secrets and ServiceAccounts seen by the store controllers.
+// Managers only live for a single reconcile, so the versions are kept at the process level to be shared between them.
+var authVersions = &authRefVersions{
+	versions: make(map[authRef]string),
+}
+
+type authRefVersions struct {
+	mu       sync.RWMutex
+	versions map[authRef]string
+}
+
+func (v *authRefVersions) observe(ref authRef, version string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.versions[ref] = version
+}
+
+func (v *authRefVersions) forget(ref authRef) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.versions, ref)
+}
+
+// snapshot returns the current versions of the refs. Refs which were not seen have an empty version.
+func (v *authRefVersions) snapshot(refs []authRef) map[authRef]string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	versions := make(map[authRef]string, len(refs))
+	for _, ref := range refs {
+		versions[ref] = v.versions[ref]
+	}
+	return versions
+}
+
+// clientAuthVersions holds the versions of the refs of a store when its last provider client was built.
+// Providers may cache their clients across reconciles, keyed on the store, so they are told
+// to evict them when the refs were rotated since.
+var clientAuthVersions = &storeAuthVersions{
+	versions: make(map[storeConsumer]map[authRef]string),
+}
+
+// storeConsumer identifies a store, and the namespace its client is built for.
+type storeConsumer struct {
+	kind           string
+	storeNamespace string
+	name           string
+	namespace      string
+}
+
+type storeAuthVersions struct {
+	mu       sync.Mutex
+	versions map[storeConsumer]map[authRef]string
+}
+
+// update records the versions of the refs a client of the store is built with,
+// and reports whether they changed since the previous client was built.
+func (v *storeAuthVersions) update(store esv1.GenericStore, namespace string, versions map[authRef]string) bool {
+	key := storeConsumer{kind: store.GetKind(), storeNamespace: store.GetNamespace(), name: store.GetName(), namespace: namespace}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	previous, ok := v.versions[key]
+	if len(versions) == 0 {
+		delete(v.versions, key)
+	} else {
+		v.versions[key] = versions
+	}
+	return ok && !maps.Equal(previous, versions)
+}
+
+// drop forgets the versions of a deleted store, for all namespaces.
+func (v *storeAuthVersions) drop(kind, namespace, name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for key := range v.versions {
+		if key.kind == kind && key.storeNamespace == namespace && key.name == name {
+			delete(v.versions, key)
+		}
+	}
+}
+
+// credentialsMetadata returns the metadata-only object of the Secrets or ServiceAccounts watched as credentials.
+func credentialsMetadata(kind string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: kind}}
+}
+
+// NewCredentialsCache returns a cache of the metadata of the Secrets and ServiceAccounts
+// labelled with esv1.LabelStoreCredentials, and adds it to the manager.
+// The cache records their resourceVersions, so provider clients are rebuilt after a rotation
+// even when the store controllers are disabled, and it is the source of the credentials watches of the store controllers.
+func NewCredentialsCache(ctx context.Context, mgr ctrl.Manager, namespace string) (cache.Cache, error) {
+	// NOTE: this means that credentials without the label are not watched
+	credentialsCacheOpts := cache.Options{
+		HTTPClient:           mgr.GetHTTPClient(),
+		Scheme:               mgr.GetScheme(),
+		Mapper:               mgr.GetRESTMapper(),
+		DefaultLabelSelector: labels.SelectorFromSet(labels.Set{esv1.LabelStoreCredentials: esv1.LabelStoreCredentialsValue}),
+		// this requires us to explicitly start an informer for each object type
+		ReaderFailOnMissingInformer: true,
+	}
+	if namespace != "" {
+		credentialsCacheOpts.DefaultNamespaces = map[string]cache.Config{
+			namespace: {},
+		}
+	}
+
+	credentialsCache, err := cache.New(mgr.GetConfig(), credentialsCacheOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range []string{authRefKindSecret, authRefKindServiceAccount} {
+		informer, err := credentialsCache.GetInformer(ctx, credentialsMetadata(kind))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := informer.AddEventHandler(recordAuthVersions(kind)); err != nil {
+			return nil, err
+		}
+	}
+
+	// add the credentials cache to the manager, so that it starts at the same time
+	if err := mgr.Add(credentialsCache); err != nil {
+		return nil, err
+	}
+	return credentialsCache, nil
+}
+
+// recordAuthVersions returns an informer event handler recording the resourceVersions of Secrets or ServiceAccounts.
+func recordAuthVersions(kind string) toolscache.ResourceEventHandler {
+	observe := func(obj any) {
+		if o, ok := obj.(client.Object); ok {
+			authVersions.observe(authRef{kind: kind, namespace: o.GetNamespace(), name: o.GetName()}, o.GetResourceVersion())
+		}
+	}
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    observe,
+		UpdateFunc: func(_, obj any) { observe(obj) },
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if o, ok := obj.(client.Object); ok {
+				authVersions.forget(authRef{kind: kind, namespace: o.GetNamespace(), name: o.GetName()})
+			}
+		},
+	}
+}
+
+// enqueueStoresForAuthRef returns an event handler recording the resourceVersions of Secrets or ServiceAccounts,
+// and enqueueing the stores referencing them, so the stores are validated again with the rotated credentials.
+func enqueueStoresForAuthRef(cl client.Client, kind string, storeList func() client.ObjectList) handler.EventHandler {
+	enqueue := func(ctx context.Context, ref authRef, q workqueue.TypedRateLimitingInterface[ctrlreconcile.Request]) {
+		for _, req := range findStoresForAuthRef(ctx, cl, ref, storeList()) {
+			q.Add(req)
+		}
+	}
+	refFor := func(obj client.Object) authRef {
+		return authRef{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+	}
+
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[ctrlreconcile.Request]) {
+			ref := refFor(e.Object)
+			authVersions.observe(ref, e.Object.GetResourceVersion())
+			enqueue(ctx, ref, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[ctrlreconcile.Request]) {
+			if e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion() {
+				return
+			}
+			ref := refFor(e.ObjectNew)
+			authVersions.observe(ref, e.ObjectNew.GetResourceVersion())
+			enqueue(ctx, ref, q)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[ctrlreconcile.Request]) {
+			ref := refFor(e.Object)
+			authVersions.forget(ref)
+			enqueue(ctx, ref, q)
+		},
+	}
+}
+
+// findStoresForAuthRef finds the SecretStores or ClusterSecretStores whose provider config references the object.
+func findStoresForAuthRef(ctx context.Context, cl client.Client, ref authRef, storeList client.ObjectList) []ctrlreconcile.Request {
+	if err := cl.List(ctx, storeList, client.MatchingFields{authRefsField: ref.String()}); err != nil {
+		return nil
+	}
+
+	var requests []ctrlreconcile.Request
+	switch sl := storeList.(type) {
+	case *esv1.SecretStoreList:
+		for i := range sl.Items {
+			requests = append(requests, ctrlreconcile.Request{NamespacedName: types.NamespacedName{Namespace: sl.Items[i].Namespace, Name: sl.Items[i].Name}})
+		}
+	case *esv1.ClusterSecretStoreList:
+		for i := range sl.Items {
+			requests = append(requests, ctrlreconcile.Request{NamespacedName: types.NamespacedName{Name: sl.Items[i].Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,450 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// providerAuthRefs collects the Secrets and ServiceAccounts referenced by the provider config.
+// A provider referencing new credentials must be added here, TestProviderAuthRefs fails otherwise.
+func providerAuthRefs(p *esv1.SecretStoreProvider, c *authRefCollector) {
+	if aws := p.AWS; aws != nil {
+		if secretRef := aws.Auth.SecretRef; secretRef != nil {
+			c.secret(&secretRef.AccessKeyID)
+			c.secret(&secretRef.SecretAccessKey)
+			c.secret(secretRef.SessionToken)
+		}
+		if jwtAuth := aws.Auth.JWTAuth; jwtAuth != nil {
+			c.serviceAccount(jwtAuth.ServiceAccountRef)
+		}
+	}
+	if azureKV := p.AzureKV; azureKV != nil {
+		if authSecretRef := azureKV.AuthSecretRef; authSecretRef != nil {
+			c.secret(authSecretRef.ClientID)
+			c.secret(authSecretRef.TenantID)
+			c.secret(authSecretRef.ClientSecret)
+			c.secret(authSecretRef.ClientCertificate)
+		}
+		c.serviceAccount(azureKV.ServiceAccountRef)
+	}
+	if akeyless := p.Akeyless; akeyless != nil {
+		if auth := akeyless.Auth; auth != nil {
+			c.secret(&auth.SecretRef.AccessID)
+			c.secret(&auth.SecretRef.AccessType)
+			c.secret(&auth.SecretRef.AccessTypeParam)
+			if kubernetesAuth := auth.KubernetesAuth; kubernetesAuth != nil {
+				c.serviceAccount(kubernetesAuth.ServiceAccountRef)
+				c.secret(kubernetesAuth.SecretRef)
+			}
+			c.serviceAccount(auth.ServiceAccountRef)
+		}
+	}
+	if bitwardenSecretsManager := p.BitwardenSecretsManager; bitwardenSecretsManager != nil {
+		c.secret(&bitwardenSecretsManager.Auth.SecretRef.Credentials)
+	}
+	if vault := p.Vault; vault != nil {
+		if auth := vault.Auth; auth != nil {
+			c.secret(auth.TokenSecretRef)
+			if appRole := auth.AppRole; appRole != nil {
+				c.secret(appRole.RoleRef)
+				c.secret(&appRole.SecretRef)
+			}
+			if kubernetes := auth.Kubernetes; kubernetes != nil {
+				c.serviceAccount(kubernetes.ServiceAccountRef)
+				c.secret(kubernetes.SecretRef)
+			}
+			if ldap := auth.Ldap; ldap != nil {
+				c.secret(&ldap.SecretRef)
+			}
+			if jwt := auth.Jwt; jwt != nil {
+				c.secret(jwt.SecretRef)
+				if kubernetesServiceAccountToken := jwt.KubernetesServiceAccountToken; kubernetesServiceAccountToken != nil {
+					c.serviceAccount(&kubernetesServiceAccountToken.ServiceAccountRef)
+				}
+			}
+			if cert := auth.Cert; cert != nil {
+				c.secret(&cert.ClientCert)
+				c.secret(&cert.SecretRef)
+			}
+			if iam := auth.Iam; iam != nil {
+				if secretRef := iam.SecretRef; secretRef != nil {
+					c.secret(&secretRef.AccessKeyID)
+					c.secret(&secretRef.SecretAccessKey)
+					c.secret(secretRef.SessionToken)
+				}
+				if jwtAuth := iam.JWTAuth; jwtAuth != nil {
+					c.serviceAccount(jwtAuth.ServiceAccountRef)
+				}
+			}
+			if userPass := auth.UserPass; userPass != nil {
+				c.secret(&userPass.SecretRef)
+			}
+			if gcp := auth.GCP; gcp != nil {
+				if secretRef := gcp.SecretRef; secretRef != nil {
+					c.secret(&secretRef.SecretAccessKey)
+				}
+				if workloadIdentity := gcp.WorkloadIdentity; workloadIdentity != nil {
+					c.serviceAccount(&workloadIdentity.ServiceAccountRef)
+				}
+				c.serviceAccount(gcp.ServiceAccountRef)
+			}
+		}
+		c.secret(vault.ClientTLS.CertSecretRef)
+		c.secret(vault.ClientTLS.KeySecretRef)
+	}
+	if ovh := p.OVHcloud; ovh != nil {
+		if clientMTLS := ovh.Auth.ClientMTLS; clientMTLS != nil {
+			c.secret(&clientMTLS.ClientCertificate)
+			c.secret(&clientMTLS.ClientKey)
+		}
+		if clientToken := ovh.Auth.ClientToken; clientToken != nil {
+			c.secret(&clientToken.ClientTokenSecret)
+		}
+	}
+	if gcpsm := p.GCPSM; gcpsm != nil {
+		if secretRef := gcpsm.Auth.SecretRef; secretRef != nil {
+			c.secret(&secretRef.SecretAccessKey)
+		}
+		if workloadIdentity := gcpsm.Auth.WorkloadIdentity; workloadIdentity != nil {
+			c.serviceAccount(&workloadIdentity.ServiceAccountRef)
+		}
+		if workloadIdentityFederation := gcpsm.Auth.WorkloadIdentityFederation; workloadIdentityFederation != nil {
+			c.serviceAccount(workloadIdentityFederation.ServiceAccountRef)
+		}
+	}
+	if oracle := p.Oracle; oracle != nil {
+		if auth := oracle.Auth; auth != nil {
+			c.secret(&auth.SecretRef.PrivateKey)
+			c.secret(&auth.SecretRef.Fingerprint)
+		}
+		c.serviceAccount(oracle.ServiceAccountRef)
+	}
+	if ibm := p.IBM; ibm != nil {
+		if secretRef := ibm.Auth.SecretRef; secretRef != nil {
+			c.secret(&secretRef.SecretAPIKey)
+		}
+	}
+	if yandexCertificateManager := p.YandexCertificateManager; yandexCertificateManager != nil {
+		c.secret(&yandexCertificateManager.Auth.AuthorizedKey)
+		if caProvider := yandexCertificateManager.CAProvider; caProvider != nil {
+			c.secret(&caProvider.Certificate)
+		}
+	}
+	if yandexLockbox := p.YandexLockbox; yandexLockbox != nil {
+		c.secret(&yandexLockbox.Auth.AuthorizedKey)
+		if caProvider := yandexLockbox.CAProvider; caProvider != nil {
+			c.secret(&caProvider.Certificate)
+		}
+	}
+	if github := p.Github; github != nil {
+		c.secret(&github.Auth.PrivateKey)
+	}
+	if gitlab := p.Gitlab; gitlab != nil {
+		c.secret(&gitlab.Auth.SecretRef.AccessToken)
+	}
+	if onePassword := p.OnePassword; onePassword != nil {
+		if auth := onePassword.Auth; auth != nil {
+			if secretRef := auth.SecretRef; secretRef != nil {
+				c.secret(&secretRef.ConnectToken)
+			}
+		}
+	}
+	if onePasswordSDK := p.OnePasswordSDK; onePasswordSDK != nil {
+		if auth := onePasswordSDK.Auth; auth != nil {
+			c.secret(&auth.ServiceAccountSecretRef)
+		}
+	}
+	if webhook := p.Webhook; webhook != nil {
+		if auth := webhook.Auth; auth != nil {
+			if ntlm := auth.NTLM; ntlm != nil {
+				c.secret(&ntlm.UserName)
+				c.secret(&ntlm.Password)
+			}
+		}
+		for i := range webhook.Secrets {
+			c.secret(&webhook.Secrets[i].SecretRef)
+		}
+	}
+	if kubernetes := p.Kubernetes; kubernetes != nil {
+		if auth := kubernetes.Auth; auth != nil {
+			if cert := auth.Cert; cert != nil {
+				c.secret(&cert.ClientCert)
+				c.secret(&cert.ClientKey)
+			}
+			if token := auth.Token; token != nil {
+				c.secret(&token.BearerToken)
+			}
+			c.serviceAccount(auth.ServiceAccount)
+		}
+		c.secret(kubernetes.AuthRef)
+	}
+	if crd := p.CRD; crd != nil {
+		if auth := crd.Auth; auth != nil {
+			if cert := auth.Cert; cert != nil {
+				c.secret(&cert.ClientCert)
+				c.secret(&cert.ClientKey)
+			}
+			if token := auth.Token; token != nil {
+				c.secret(&token.BearerToken)
+			}
+			c.serviceAccount(auth.ServiceAccount)
+		}
+		c.secret(crd.AuthRef)
+	}
+	if senhasegura := p.Senhasegura; senhasegura != nil {
+		c.secret(&senhasegura.Auth.ClientSecret)
+	}
+	if scaleway := p.Scaleway; scaleway != nil {
+		if accessKey := scaleway.AccessKey; accessKey != nil {
+			c.secret(accessKey.SecretRef)
+		}
+		if secretKey := scaleway.SecretKey; secretKey != nil {
+			c.secret(secretKey.SecretRef)
+		}
+	}
+	if doppler := p.Doppler; doppler != nil {
+		if auth := doppler.Auth; auth != nil {
+			if secretRef := auth.SecretRef; secretRef != nil {
+				c.secret(&secretRef.DopplerToken)
+			}
+			if oidcConfig := auth.OIDCConfig; oidcConfig != nil {
+				c.serviceAccount(&oidcConfig.ServiceAccountRef)
+			}
+		}
+	}
+	if previder := p.Previder; previder != nil {
+		if secretRef := previder.Auth.SecretRef; secretRef != nil {
+			c.secret(&secretRef.AccessToken)
+		}
+	}
+	if onboardbase := p.Onboardbase; onboardbase != nil {
+		if auth := onboardbase.Auth; auth != nil {
+			c.secret(&auth.OnboardbaseAPIKeyRef)
+			c.secret(&auth.OnboardbasePasscodeRef)
+		}
+	}
+	if keeperSecurity := p.KeeperSecurity; keeperSecurity != nil {
+		c.secret(&keeperSecurity.Auth)
+	}
+	if conjur := p.Conjur; conjur != nil {
+		if apiKey := conjur.Auth.APIKey; apiKey != nil {
+			c.secret(apiKey.UserRef)
+			c.secret(apiKey.APIKeyRef)
+		}
+		if jwt := conjur.Auth.Jwt; jwt != nil {
+			c.secret(jwt.SecretRef)
+			c.serviceAccount(jwt.ServiceAccountRef)
+		}
+		if cert := conjur.Auth.Cert; cert != nil {
+			c.secret(cert.ClientCertRef)
+			c.secret(cert.ClientKeyRef)
+		}
+	}
+	if delinea := p.Delinea; delinea != nil {
+		if clientID := delinea.ClientID; clientID != nil {
+			c.secret(clientID.SecretRef)
+		}
+		if clientSecret := delinea.ClientSecret; clientSecret != nil {
+			c.secret(clientSecret.SecretRef)
+		}
+	}
+	if secretServer := p.SecretServer; secretServer != nil {
+		if username := secretServer.Username; username != nil {
+			c.secret(username.SecretRef)
+		}
+		if password := secretServer.Password; password != nil {
+			c.secret(password.SecretRef)
+		}
+		if token := secretServer.Token; token != nil {
+			c.secret(token.SecretRef)
+		}
+	}
+	if chef := p.Chef; chef != nil {
+		if auth := chef.Auth; auth != nil {
+			c.secret(&auth.SecretRef.SecretKey)
+		}
+	}
+	if pulumi := p.Pulumi; pulumi != nil {
+		if auth := pulumi.Auth; auth != nil {
+			if accessToken := auth.AccessToken; accessToken != nil {
+				c.secret(accessToken.SecretRef)
+			}
+			if oidcConfig := auth.OIDCConfig; oidcConfig != nil {
+				c.serviceAccount(&oidcConfig.ServiceAccountRef)
+			}
+		}
+		if accessToken := pulumi.AccessToken; accessToken != nil {
+			c.secret(accessToken.SecretRef)
+		}
+	}
+	if fortanix := p.Fortanix; fortanix != nil {
+		if apiKey := fortanix.APIKey; apiKey != nil {
+			c.secret(apiKey.SecretRef)
+		}
+	}
+	if passwordDepot := p.PasswordDepot; passwordDepot != nil {
+		c.secret(&passwordDepot.Auth.SecretRef.Credentials)
+	}
+	if passbolt := p.Passbolt; passbolt != nil {
+		if auth := passbolt.Auth; auth != nil {
+			c.secret(auth.PasswordSecretRef)
+			c.secret(auth.PrivateKeySecretRef)
+		}
+	}
+	if dvls := p.DVLS; dvls != nil {
+		c.secret(&dvls.Auth.SecretRef.AppID)
+		c.secret(&dvls.Auth.SecretRef.AppSecret)
+	}
+	if infisical := p.Infisical; infisical != nil {
+		if universalAuthCredentials := infisical.Auth.UniversalAuthCredentials; universalAuthCredentials != nil {
+			c.secret(&universalAuthCredentials.ClientID)
+			c.secret(&universalAuthCredentials.ClientSecret)
+		}
+		if azureAuthCredentials := infisical.Auth.AzureAuthCredentials; azureAuthCredentials != nil {
+			c.secret(&azureAuthCredentials.IdentityID)
+			c.secret(&azureAuthCredentials.Resource)
+		}
+		if gcpIDTokenAuthCredentials := infisical.Auth.GcpIDTokenAuthCredentials; gcpIDTokenAuthCredentials != nil {
+			c.secret(&gcpIDTokenAuthCredentials.IdentityID)
+		}
+		if gcpIamAuthCredentials := infisical.Auth.GcpIamAuthCredentials; gcpIamAuthCredentials != nil {
+			c.secret(&gcpIamAuthCredentials.IdentityID)
+			c.secret(&gcpIamAuthCredentials.ServiceAccountKeyFilePath)
+		}
+		if jwtAuthCredentials := infisical.Auth.JwtAuthCredentials; jwtAuthCredentials != nil {
+			c.secret(&jwtAuthCredentials.IdentityID)
+			c.secret(&jwtAuthCredentials.JWT)
+		}
+		if ldapAuthCredentials := infisical.Auth.LdapAuthCredentials; ldapAuthCredentials != nil {
+			c.secret(&ldapAuthCredentials.IdentityID)
+			c.secret(&ldapAuthCredentials.LDAPPassword)
+			c.secret(&ldapAuthCredentials.LDAPUsername)
+		}
+		if ociAuthCredentials := infisical.Auth.OciAuthCredentials; ociAuthCredentials != nil {
+			c.secret(&ociAuthCredentials.IdentityID)
+			c.secret(&ociAuthCredentials.PrivateKey)
+			c.secret(&ociAuthCredentials.PrivateKeyPassphrase)
+			c.secret(&ociAuthCredentials.Fingerprint)
+			c.secret(&ociAuthCredentials.UserID)
+			c.secret(&ociAuthCredentials.TenancyID)
+			c.secret(&ociAuthCredentials.Region)
+		}
+		if kubernetesAuthCredentials := infisical.Auth.KubernetesAuthCredentials; kubernetesAuthCredentials != nil {
+			c.secret(&kubernetesAuthCredentials.IdentityID)
+			c.secret(&kubernetesAuthCredentials.ServiceAccountTokenPath)
+		}
+		if awsAuthCredentials := infisical.Auth.AwsAuthCredentials; awsAuthCredentials != nil {
+			c.secret(&awsAuthCredentials.IdentityID)
+		}
+		if tokenAuthCredentials := infisical.Auth.TokenAuthCredentials; tokenAuthCredentials != nil {
+			c.secret(&tokenAuthCredentials.AccessToken)
+		}
+	}
+	if beyondtrust := p.Beyondtrust; beyondtrust != nil {
+		if auth := beyondtrust.Auth; auth != nil {
+			if apiKey := auth.APIKey; apiKey != nil {
+				c.secret(apiKey.SecretRef)
+			}
+			if clientID := auth.ClientID; clientID != nil {
+				c.secret(clientID.SecretRef)
+			}
+			if clientSecret := auth.ClientSecret; clientSecret != nil {
+				c.secret(clientSecret.SecretRef)
+			}
+			if certificate := auth.Certificate; certificate != nil {
+				c.secret(certificate.SecretRef)
+			}
+			if certificateKey := auth.CertificateKey; certificateKey != nil {
+				c.secret(certificateKey.SecretRef)
+			}
+		}
+	}
+	if beyondtrustWorkloadCredentials := p.BeyondtrustWorkloadCredentials; beyondtrustWorkloadCredentials != nil {
+		if auth := beyondtrustWorkloadCredentials.Auth; auth != nil {
+			c.secret(&auth.APIKey.Token)
+		}
+	}
+	if cloudruSM := p.CloudruSM; cloudruSM != nil {
+		if secretRef := cloudruSM.Auth.SecretRef; secretRef != nil {
+			c.secret(&secretRef.AccessKeyID)
+			c.secret(&secretRef.AccessKeySecret)
+		}
+	}
+	if volcengine := p.Volcengine; volcengine != nil {
+		if auth := volcengine.Auth; auth != nil {
+			if secretRef := auth.SecretRef; secretRef != nil {
+				c.secret(&secretRef.AccessKeyID)
+				c.secret(&secretRef.SecretAccessKey)
+				c.secret(secretRef.Token)
+			}
+		}
+	}
+	if ngrok := p.Ngrok; ngrok != nil {
+		if apiKey := ngrok.Auth.APIKey; apiKey != nil {
+			c.secret(apiKey.SecretRef)
+		}
+	}
+	if barbican := p.Barbican; barbican != nil {
+		c.secret(barbican.Auth.Username.SecretRef)
+		c.secret(barbican.Auth.Password.SecretRef)
+	}
+	if nebiusMysterybox := p.NebiusMysterybox; nebiusMysterybox != nil {
+		c.secret(&nebiusMysterybox.Auth.ServiceAccountCreds)
+		c.secret(&nebiusMysterybox.Auth.Token)
+		if caProvider := nebiusMysterybox.CAProvider; caProvider != nil {
+			c.secret(&caProvider.Certificate)
+		}
+	}
+	if openBao := p.OpenBao; openBao != nil {
+		if auth := openBao.Auth; auth != nil {
+			if appRole := auth.AppRole; appRole != nil {
+				c.secret(appRole.RoleRef)
+				c.secret(&appRole.SecretRef)
+			}
+			if kubernetes := auth.Kubernetes; kubernetes != nil {
+				c.serviceAccount(kubernetes.ServiceAccountRef)
+				c.secret(kubernetes.SecretRef)
+			}
+			c.secret(auth.TokenSecretRef)
+			if userPass := auth.UserPass; userPass != nil {
+				c.secret(&userPass.SecretRef)
+			}
+		}
+	}
+	if sops := p.Sops; sops != nil {
+		for i := range sops.Auth.Age {
+			c.secret(&sops.Auth.Age[i])
+		}
+		for i := range sops.Auth.PGP {
+			c.secret(&sops.Auth.PGP[i])
+		}
+	}
+	if consul := p.Consul; consul != nil {
+		if auth := consul.Auth; auth != nil {
+			c.secret(&auth.TokenSecretRef)
+		}
+	}
+	if etcd := p.Etcd; etcd != nil {
+		if auth := etcd.Auth; auth != nil {
+			c.secret(&auth.UsernameSecretRef)
+			c.secret(&auth.PasswordSecretRef)
+		}
+		c.secret(etcd.ClientTLS.CertSecretRef)
+		c.secret(etcd.ClientTLS.KeySecretRef)
+	}
+}
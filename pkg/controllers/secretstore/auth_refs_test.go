@@ -0,0 +1,368 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlreconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func newAuthRefsTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(esv1.AddToScheme(scheme))
+	return scheme
+}
+
+func TestAuthRefs(t *testing.T) {
+	other := "other"
+	vault := &esv1.SecretStoreProvider{
+		Vault: &esv1.VaultProvider{
+			Auth: &esv1.VaultAuth{
+				TokenSecretRef: &esmeta.SecretKeySelector{Name: "vault-token", Key: "token"},
+				Kubernetes: &esv1.VaultKubernetesAuth{
+					ServiceAccountRef: &esmeta.ServiceAccountSelector{Name: "vault-sa", Namespace: &other},
+				},
+				Cert: &esv1.VaultCertAuth{
+					ClientCert: esmeta.SecretKeySelector{Name: "vault-tls", Key: "tls.crt"},
+					SecretRef:  esmeta.SecretKeySelector{Name: "vault-tls", Key: "tls.key"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		store     esv1.GenericStore
+		namespace string
+		want      []string
+	}{
+		{
+			name:  "SecretStore refs are in the namespace of the store",
+			store: &esv1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "team"}, Spec: esv1.SecretStoreSpec{Provider: vault}},
+			want:  []string{"Secret/team/vault-tls", "Secret/team/vault-token", "ServiceAccount/team/vault-sa"},
+		},
+		{
+			name:      "ClusterSecretStore refs without a namespace are in the namespace of the consumer",
+			store:     &esv1.ClusterSecretStore{ObjectMeta: metav1.ObjectMeta{Name: "vault"}, Spec: esv1.SecretStoreSpec{Provider: vault}},
+			namespace: "consumer",
+			want:      []string{"Secret/consumer/vault-tls", "Secret/consumer/vault-token", "ServiceAccount/other/vault-sa"},
+		},
+		{
+			name:  "ClusterSecretStore refs without a namespace are skipped without a consumer",
+			store: &esv1.ClusterSecretStore{ObjectMeta: metav1.ObjectMeta{Name: "vault"}, Spec: esv1.SecretStoreSpec{Provider: vault}},
+			want:  []string{"ServiceAccount/other/vault-sa"},
+		},
+		{
+			name:  "store without credentials",
+			store: &esv1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "fake", Namespace: "team"}, Spec: esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{Fake: &esv1.FakeProvider{}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ref := range authRefs(tt.store, tt.namespace) {
+				got = append(got, ref.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestProviderAuthRefs fills every selector of the provider config, and checks that they are all collected.
+func TestProviderAuthRefs(t *testing.T) {
+	var want []string
+	var fill func(v reflect.Value, path string, parents []reflect.Type)
+	fill = func(v reflect.Value, path string, parents []reflect.Type) {
+		switch v.Kind() {
+		case reflect.Pointer:
+			if slices.Contains(parents, v.Type().Elem()) {
+				return
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+			fill(v.Elem(), path, parents)
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+			fill(v.Index(0), path+"[0]", parents)
+		case reflect.Struct:
+			// the path of the selector names it, so a missing one is easy to spot
+			name := strings.TrimPrefix(path, ".")
+			switch sel := v.Addr().Interface().(type) {
+			case *esmeta.SecretKeySelector:
+				sel.Name = name
+				want = append(want, authRef{kind: authRefKindSecret, namespace: "team", name: name}.String())
+				return
+			case *esmeta.ServiceAccountSelector:
+				sel.Name = name
+				want = append(want, authRef{kind: authRefKindServiceAccount, namespace: "team", name: name}.String())
+				return
+			}
+			parents = append(parents, v.Type())
+			for i := range v.NumField() {
+				if v.Type().Field(i).IsExported() {
+					fill(v.Field(i), path+"."+v.Type().Field(i).Name, parents)
+				}
+			}
+		default:
+		}
+	}
+	provider := &esv1.SecretStoreProvider{}
+	fill(reflect.ValueOf(provider).Elem(), "", nil)
+	require.NotEmpty(t, want)
+
+	var got []string
+	store := &esv1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "all", Namespace: "team"}, Spec: esv1.SecretStoreSpec{Provider: provider}}
+	for _, ref := range authRefs(store, "") {
+		got = append(got, ref.String())
+	}
+	assert.ElementsMatch(t, want, got, "every selector of the provider config must be collected by providerAuthRefs")
+}
+
+func TestManagerRebuildsClientOnRotation(t *testing.T) {
+	ctx := context.Background()
+	scheme := newAuthRefsTestScheme()
+
+	var created []*MockFakeClient
+	esv1.ForceRegister(&WrapProvider{
+		newClientFunc: func(context.Context, esv1.GenericStore, client.Client, string) (esv1.SecretsClient, error) {
+			c := &MockFakeClient{}
+			created = append(created, c)
+			return c, nil
+		},
+	}, &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}}, esv1.MaintenanceStatusMaintained)
+
+	store := &esv1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "rotation", Namespace: "rotation"},
+		Spec: esv1.SecretStoreSpec{
+			Provider: &esv1.SecretStoreProvider{
+				AWS: &esv1.AWSProvider{
+					Auth: esv1.AWSAuth{SecretRef: &esv1.AWSAuthSecretRef{
+						AccessKeyID:     esmeta.SecretKeySelector{Name: "aws-credentials", Key: "access-key"},
+						SecretAccessKey: esmeta.SecretKeySelector{Name: "aws-credentials", Key: "secret-key"},
+					}},
+				},
+			},
+		},
+	}
+	ref := authRef{kind: authRefKindSecret, namespace: "rotation", name: "aws-credentials"}
+	authVersions.observe(ref, "1")
+	t.Cleanup(func() { authVersions.forget(ref) })
+
+	mgr := &Manager{
+		log:       logr.Discard(),
+		client:    fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
+		clientMap: make(map[clientKey]*clientVal),
+	}
+
+	_, err := mgr.GetFromStore(ctx, store, store.Namespace)
+	require.NoError(t, err)
+	_, err = mgr.GetFromStore(ctx, store, store.Namespace)
+	require.NoError(t, err)
+	require.Len(t, created, 1, "the client should be reused while the credentials are unchanged")
+
+	// rotating the credentials closes the stale client and builds a new one
+	authVersions.observe(ref, "2")
+	_, err = mgr.GetFromStore(ctx, store, store.Namespace)
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.True(t, created[0].closeCalled)
+	assert.False(t, created[1].closeCalled)
+}
+
+// CachingWrapProvider caches its clients across managers, keyed on the store and its resourceVersion,
+// like the Vault provider does.
+type CachingWrapProvider struct {
+	WrapProvider
+	cached  map[string]esv1.SecretsClient
+	evicted int
+}
+
+func (f *CachingWrapProvider) NewClient(ctx context.Context, store esv1.GenericStore, kube client.Client, namespace string) (esv1.SecretsClient, error) {
+	key := store.GetNamespace() + "/" + store.GetName() + "@" + store.GetResourceVersion()
+	if c, ok := f.cached[key]; ok {
+		return c, nil
+	}
+	c, err := f.WrapProvider.NewClient(ctx, store, kube, namespace)
+	if err == nil {
+		f.cached[key] = c
+	}
+	return c, err
+}
+
+func (f *CachingWrapProvider) EvictClients(store esv1.GenericStore) {
+	prefix := store.GetNamespace() + "/" + store.GetName() + "@"
+	for key := range f.cached {
+		if strings.HasPrefix(key, prefix) {
+			delete(f.cached, key)
+		}
+	}
+	f.evicted++
+}
+
+func TestManagerEvictsCachedClientsOnRotation(t *testing.T) {
+	ctx := context.Background()
+	scheme := newAuthRefsTestScheme()
+
+	var created int
+	provider := &CachingWrapProvider{
+		WrapProvider: WrapProvider{
+			newClientFunc: func(context.Context, esv1.GenericStore, client.Client, string) (esv1.SecretsClient, error) {
+				created++
+				return &MockFakeClient{}, nil
+			},
+		},
+		cached: make(map[string]esv1.SecretsClient),
+	}
+	esv1.ForceRegister(provider, &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}}, esv1.MaintenanceStatusMaintained)
+
+	store := &esv1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "evict", Namespace: "evict", ResourceVersion: "1"},
+		Spec: esv1.SecretStoreSpec{
+			Provider: &esv1.SecretStoreProvider{
+				AWS: &esv1.AWSProvider{
+					Auth: esv1.AWSAuth{SecretRef: &esv1.AWSAuthSecretRef{
+						AccessKeyID:     esmeta.SecretKeySelector{Name: "aws-credentials", Key: "access-key"},
+						SecretAccessKey: esmeta.SecretKeySelector{Name: "aws-credentials", Key: "secret-key"},
+					}},
+				},
+			},
+		},
+	}
+	ref := authRef{kind: authRefKindSecret, namespace: "evict", name: "aws-credentials"}
+	authVersions.observe(ref, "1")
+	t.Cleanup(func() {
+		authVersions.forget(ref)
+		clientAuthVersions.drop(esv1.SecretStoreKind, "evict", "evict")
+	})
+
+	// every reconcile uses a new manager
+	reconcile := func() {
+		t.Helper()
+		mgr := &Manager{
+			log:       logr.Discard(),
+			client:    fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
+			clientMap: make(map[clientKey]*clientVal),
+		}
+		_, err := mgr.GetFromStore(ctx, store, store.Namespace)
+		require.NoError(t, err)
+		mgr.Close(ctx)
+	}
+
+	reconcile()
+	reconcile()
+	assert.Equal(t, 1, created, "the provider should reuse its cached client while the credentials are unchanged")
+	assert.Zero(t, provider.evicted)
+
+	// rotating the Secret does not change the resourceVersion of the store
+	authVersions.observe(ref, "2")
+	reconcile()
+	assert.Equal(t, 1, provider.evicted)
+	assert.Equal(t, 2, created, "a new client should be built with the rotated credentials")
+
+	reconcile()
+	assert.Equal(t, 1, provider.evicted)
+	assert.Equal(t, 2, created)
+}
+
+func TestEnqueueStoresForAuthRef(t *testing.T) {
+	ctx := context.Background()
+	scheme := newAuthRefsTestScheme()
+
+	tokenRef := func(name string) *esv1.SecretStoreProvider {
+		return &esv1.SecretStoreProvider{Vault: &esv1.VaultProvider{Auth: &esv1.VaultAuth{
+			TokenSecretRef: &esmeta.SecretKeySelector{Name: name, Key: "token"},
+		}}}
+	}
+	kube := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithIndex(&esv1.SecretStore{}, authRefsField, indexAuthRefs).
+		WithObjects(
+			&esv1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "uses-token", Namespace: "enqueue"}, Spec: esv1.SecretStoreSpec{Provider: tokenRef("token")}},
+			&esv1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "other-token", Namespace: "enqueue"}, Spec: esv1.SecretStoreSpec{Provider: tokenRef("other")}},
+			&esv1.SecretStore{ObjectMeta: metav1.ObjectMeta{Name: "uses-token", Namespace: "elsewhere"}, Spec: esv1.SecretStoreSpec{Provider: tokenRef("token")}},
+		).
+		Build()
+
+	h, ok := enqueueStoresForAuthRef(kube, authRefKindSecret, func() client.ObjectList { return &esv1.SecretStoreList{} }).(handler.Funcs)
+	require.True(t, ok)
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[ctrlreconcile.Request]())
+	t.Cleanup(q.ShutDown)
+
+	ref := authRef{kind: authRefKindSecret, namespace: "enqueue", name: "token"}
+	t.Cleanup(func() { authVersions.forget(ref) })
+	secret := func(version string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "enqueue", ResourceVersion: version}}
+	}
+
+	// a resync without a new resourceVersion does nothing
+	h.UpdateFunc(ctx, event.UpdateEvent{ObjectOld: secret("1"), ObjectNew: secret("1")}, q)
+	assert.Equal(t, 0, q.Len())
+
+	h.UpdateFunc(ctx, event.UpdateEvent{ObjectOld: secret("1"), ObjectNew: secret("2")}, q)
+	require.Equal(t, 1, q.Len())
+	req, _ := q.Get()
+	assert.Equal(t, types.NamespacedName{Namespace: "enqueue", Name: "uses-token"}, req.NamespacedName)
+	q.Done(req)
+	assert.Equal(t, "2", authVersions.snapshot([]authRef{ref})[ref])
+
+	h.DeleteFunc(ctx, event.DeleteEvent{Object: secret("3")}, q)
+	assert.Equal(t, 1, q.Len())
+	assert.Empty(t, authVersions.snapshot([]authRef{ref})[ref])
+}
+
+func TestRecordAuthVersions(t *testing.T) {
+	h, ok := recordAuthVersions(authRefKindServiceAccount).(toolscache.ResourceEventHandlerFuncs)
+	require.True(t, ok)
+	ref := authRef{kind: authRefKindServiceAccount, namespace: "record", name: "vault"}
+	t.Cleanup(func() { authVersions.forget(ref) })
+	sa := func(version string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "record", ResourceVersion: version}}
+	}
+
+	h.AddFunc(sa("1"))
+	assert.Equal(t, "1", authVersions.snapshot([]authRef{ref})[ref])
+	h.UpdateFunc(sa("1"), sa("2"))
+	assert.Equal(t, "2", authVersions.snapshot([]authRef{ref})[ref])
+
+	// deletions missed by the informer are delivered as tombstones
+	h.DeleteFunc(toolscache.DeletedFinalStateUnknown{Key: "record/vault", Obj: sa("2")})
+	assert.Empty(t, authVersions.snapshot([]authRef{ref})[ref])
+}
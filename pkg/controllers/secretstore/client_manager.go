@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"regexp"
 	"strings"
 
@@ -41,6 +42,7 @@ const (
 	errGetSecretStore        = "could not get SecretStore %q, %w"
 	errSecretStoreNotReady   = "%s %q is not ready"
 	errClusterStoreMismatch  = "using cluster store %q is not allowed from namespace %q: denied by spec.condition"
+	errBatchResultCount      = "batch returned %d results for %d refs"
 )
 
 // ErrProviderResolution marks a failure to resolve the provider named in the
@@ -48,6 +50,15 @@ const (
 // surface in the store status.
 var ErrProviderResolution = errors.New("could not resolve store provider")
 
+// CheckBatchResults returns an error if a batch call returned a different
+// number of results than the refs it was asked for.
+func CheckBatchResults(results []esv1.SecretResult, refs int) error {
+	if len(results) != refs {
+		return fmt.Errorf(errBatchResultCount, len(results), refs)
+	}
+	return nil
+}
+
 // Manager stores instances of provider clients
 // At any given time we must have no more than one instance
 // of a client (due to limitations in GCP / see mutexlock there)
@@ -70,6 +81,9 @@ type clientKey struct {
 type clientVal struct {
 	client esv1.SecretsClient
 	store  esv1.GenericStore
+	// authVersions are the resourceVersions of the Secrets and ServiceAccounts
+	// referenced by the store when the client was created
+	authVersions map[authRef]string
 }
 
 // NewManager constructs a new manager with defaults.
@@ -92,13 +106,20 @@ func (m *Manager) GetFromStore(ctx context.Context, store esv1.GenericStore, nam
 	if err != nil {
 		return nil, ctrlutil.Safe(fmt.Errorf("%w: %w", ErrProviderResolution, err))
 	}
-	secretClient := m.getStoredClient(ctx, storeProvider, store)
+	secretClient := m.getStoredClient(ctx, storeProvider, store, namespace)
 	if secretClient != nil {
-		return secretClient, nil
+		return newCachingClient(newGuardedClient(secretClient, store), store, namespace), nil
 	}
 	m.log.V(1).Info("creating new client",
 		"provider", fmt.Sprintf("%T", storeProvider),
 		"store", fmt.Sprintf("%s/%s", store.GetNamespace(), store.GetName()))
+	// the provider may have cached a client of the store built with credentials rotated since
+	versions := authVersions.snapshot(authRefs(store, namespace))
+	if clientAuthVersions.update(store, namespace, versions) {
+		if cachingProvider, ok := storeProvider.(esv1.ClientCachingProvider); ok {
+			cachingProvider.EvictClients(store)
+		}
+	}
 	// secret client is created only if we are going to refresh
 	// this skip an unnecessary check/request in the case we are not going to do anything
 	secretClient, err = storeProvider.NewClient(ctx, store, m.client, namespace)
@@ -107,10 +128,11 @@ func (m *Manager) GetFromStore(ctx context.Context, store esv1.GenericStore, nam
 	}
 	idx := storeKey(storeProvider)
 	m.clientMap[idx] = &clientVal{
-		client: secretClient,
-		store:  store,
+		client:       secretClient,
+		store:        store,
+		authVersions: versions,
 	}
-	return secretClient, nil
+	return newCachingClient(newGuardedClient(secretClient, store), store, namespace), nil
 }
 
 // Get returns a provider client from the given storeRef or sourceRef.secretStoreRef
@@ -148,9 +170,10 @@ func (m *Manager) Get(ctx context.Context, storeRef esv1.SecretStoreRef, namespa
 }
 
 // returns a previously stored client from the cache if store and store-version match
-// if a client exists for the same provider which points to a different store or store version
-// it will be cleaned up.
-func (m *Manager) getStoredClient(ctx context.Context, storeProvider esv1.Provider, store esv1.GenericStore) esv1.SecretsClient {
+// and the Secrets and ServiceAccounts referenced by the store were not rotated since.
+// if a client exists for the same provider which points to a different store, store version
+// or stale credentials it will be cleaned up.
+func (m *Manager) getStoredClient(ctx context.Context, storeProvider esv1.Provider, store esv1.GenericStore, namespace string) esv1.SecretsClient {
 	idx := storeKey(storeProvider)
 	val, ok := m.clientMap[idx]
 	if !ok {
@@ -169,7 +192,8 @@ func (m *Manager) getStoredClient(ctx context.Context, storeProvider esv1.Provid
 	if val.store.GetObjectMeta().Generation == store.GetGeneration() &&
 		valGVK == storeGVK &&
 		val.store.GetName() == store.GetName() &&
-		val.store.GetNamespace() == store.GetNamespace() {
+		val.store.GetNamespace() == store.GetNamespace() &&
+		maps.Equal(val.authVersions, authVersions.snapshot(authRefs(store, namespace))) {
 		m.log.V(1).Info("reusing stored client",
 			"provider", fmt.Sprintf("%T", storeProvider),
 			"store", storeName)
@@ -295,3 +319,23 @@ func assertStoreIsUsable(store esv1.GenericStore) error {
 	}
 	return nil
 }
+
+// GetSecretVersion fetches a secret along with its version,
+// if the client reports versions through esv1.SecretVersionClient.
+func GetSecretVersion(ctx context.Context, client esv1.SecretsClient, ref esv1.ExternalSecretDataRemoteRef) ([]byte, esv1.SecretVersion, error) {
+	if versionClient, ok := client.(esv1.SecretVersionClient); ok {
+		return versionClient.GetSecretVersion(ctx, ref)
+	}
+	secret, err := client.GetSecret(ctx, ref)
+	return secret, esv1.SecretVersion{}, err
+}
+
+// PushSecretIfVersion pushes a secret only if its remote value is still at version,
+// if the client supports it through esv1.ConditionalPusher. It returns
+// esv1.ConditionalPushUnsupportedErr otherwise, so that the caller falls back to PushSecret.
+func PushSecretIfVersion(ctx context.Context, client esv1.SecretsClient, secret *v1.Secret, data esv1.PushSecretData, version esv1.SecretVersion) (esv1.SecretVersion, error) {
+	if pusher, ok := client.(esv1.ConditionalPusher); ok {
+		return pusher.PushSecretIfVersion(ctx, secret, data, version)
+	}
+	return esv1.SecretVersion{}, esv1.ConditionalPushUnsupportedErr
+}
@@ -25,10 +25,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrlreconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
@@ -48,6 +50,8 @@ type ClusterStoreReconciler struct {
 	RequeueInterval   time.Duration
 	recorder          record.EventRecorder
 	PushSecretEnabled bool
+	// Credentials is the cache of the Secrets and ServiceAccounts watched to validate stores again after a rotation.
+	Credentials cache.Cache
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -67,6 +71,8 @@ func (r *ClusterStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	err := r.Get(ctx, req.NamespacedName, &css)
 	if apierrors.IsNotFound(err) {
 		cssmetrics.RemoveMetrics(req.Namespace, req.Name)
+		DropValues(esapi.ClusterSecretStoreKind, req.Namespace, req.Name)
+		clientAuthVersions.drop(esapi.ClusterSecretStoreKind, req.Namespace, req.Name)
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to get ClusterSecretStore")
@@ -82,24 +88,39 @@ func (r *ClusterStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request
 }
 
 // SetupWithManager returns a new controller builder that will be started by the provided Manager.
-func (r *ClusterStoreReconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
+func (r *ClusterStoreReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts controller.Options) error {
 	r.recorder = mgr.GetEventRecorderFor("cluster-secret-store")
 
-	builder := ctrl.NewControllerManagedBy(mgr)
+	// index stores by the Secrets and ServiceAccounts referenced by their provider config,
+	// this lets us quickly find the stores to validate again when credentials are rotated
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &esapi.ClusterSecretStore{}, authRefsField, indexAuthRefs); err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		For(&esapi.ClusterSecretStore{})
+
+	// the credentials cache only holds the metadata of the labelled Secrets and ServiceAccounts,
+	// so rotating them validates the store again without caching every Secret of the cluster
+	if r.Credentials != nil {
+		newStoreList := func() client.ObjectList { return &esapi.ClusterSecretStoreList{} }
+		for _, kind := range []string{authRefKindSecret, authRefKindServiceAccount} {
+			builder = builder.WatchesRawSource(source.Kind[client.Object](r.Credentials, credentialsMetadata(kind), enqueueStoresForAuthRef(r.Client, kind, newStoreList)))
+		}
+	}
+
+	// circuit breaker transitions of the store update its CircuitOpen condition
+	builder = builder.WatchesRawSource(source.Channel(storeEvents[esapi.ClusterSecretStoreKind], &handler.EnqueueRequestForObject{}))
 
 	if r.PushSecretEnabled {
-		return builder.WithOptions(opts).
-			For(&esapi.ClusterSecretStore{}).
-			Watches(
-				&esv1alpha1.PushSecret{},
-				handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []ctrlreconcile.Request {
-					return findStoresForPushSecret(ctx, r.Client, obj, &esapi.ClusterSecretStoreList{})
-				}),
-			).
-			Complete(r)
+		builder = builder.Watches(
+			&esv1alpha1.PushSecret{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []ctrlreconcile.Request {
+				return findStoresForPushSecret(ctx, r.Client, obj, &esapi.ClusterSecretStoreList{})
+			}),
+		)
 	}
 
-	return builder.WithOptions(opts).
-		For(&esapi.ClusterSecretStore{}).
-		Complete(r)
+	return builder.Complete(r)
 }
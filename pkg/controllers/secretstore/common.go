@@ -51,6 +51,9 @@ const (
 	errUnableValidateStore  = "unable to validate store"
 
 	msgStoreValidated     = "store validated"
+	msgCircuitOpen        = "calls to the provider are suspended until %s after consecutive errors"
+	msgCircuitHalfOpen    = "the next call to the provider probes whether it recovered"
+	msgCircuitClosed      = "calls to the provider are allowed"
 	msgStoreNotMaintained = "store isn't currently maintained. Please plan and prepare accordingly."
 	msgStoreDeprecated    = "store is deprecated and will be removed on the next minor release. Please plan and prepare accordingly."
 
@@ -89,6 +92,8 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 		}
 	}
 
+	valueCaches.prune(ss)
+
 	requeueInterval := opts.RequeueInterval
 
 	refreshInterval, refreshErr := ss.GetSpec().GetRefreshInterval()
@@ -122,6 +127,8 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 		}
 	}()
 
+	setCircuitCondition(ss, opts.GaugeVecGetter)
+
 	// validateStore modifies the store conditions
 	// we have to patch the status
 	log.V(1).Info("validating")
@@ -173,6 +180,32 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 	}, err
 }
 
+// setCircuitCondition reflects the state of the circuit breaker of the store in its CircuitOpen condition.
+// The condition is removed from stores without a circuit breaker.
+func setCircuitCondition(ss esapi.GenericStore, gaugeVecGetter metrics.GaugeVevGetter) {
+	state, openUntil, ok := circuitStateOf(ss)
+	if !ok {
+		status := ss.GetStatus()
+		if GetSecretStoreCondition(status, esapi.SecretStoreCircuitOpen) != nil {
+			status.Conditions = filterOutCondition(status.Conditions, esapi.SecretStoreCircuitOpen)
+			ss.SetStatus(status)
+		}
+		return
+	}
+
+	var cond *esapi.SecretStoreStatusCondition
+	switch state {
+	case circuitOpen:
+		cond = NewSecretStoreCondition(esapi.SecretStoreCircuitOpen, v1.ConditionTrue, esapi.ReasonCircuitOpen,
+			fmt.Sprintf(msgCircuitOpen, openUntil.UTC().Format(time.RFC3339)))
+	case circuitHalfOpen:
+		cond = NewSecretStoreCondition(esapi.SecretStoreCircuitOpen, v1.ConditionTrue, esapi.ReasonCircuitOpen, msgCircuitHalfOpen)
+	default:
+		cond = NewSecretStoreCondition(esapi.SecretStoreCircuitOpen, v1.ConditionFalse, esapi.ReasonCircuitClosed, msgCircuitClosed)
+	}
+	SetExternalSecretCondition(ss, *cond, gaugeVecGetter)
+}
+
 // validateStore tries to construct a new client
 // if it fails sets a condition and writes events.
 func validateStore(ctx context.Context, namespace, controllerClass string, store esapi.GenericStore,
@@ -0,0 +1,465 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore/metrics"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+const (
+	defaultCircuitErrorThreshold = 5
+	defaultCircuitOpenDuration   = 30 * time.Second
+
+	// maxRateLimitWait is the longest a call waits for the rate limit of its store,
+	// calls which would wait longer are rejected so that they do not hold a worker.
+	maxRateLimitWait = 250 * time.Millisecond
+
+	storeEventsBuffer = 1024
+)
+
+var (
+	// ErrRateLimited is returned for calls rejected by the rate limit of the store.
+	ErrRateLimited = errors.New("rate limit of the store exceeded")
+	// ErrCircuitOpen is returned for calls rejected by the open circuit breaker of the store.
+	ErrCircuitOpen = errors.New("circuit breaker of the store is open")
+)
+
+// RateLimitedError is returned for calls rejected by the rate limit of the store.
+// It matches ErrRateLimited.
+type RateLimitedError struct {
+	// RetryAfter is the time until the rate limit allows a call.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return ErrRateLimited.Error()
+}
+
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RateLimitDelay returns the time to wait before retrying a call rejected by the rate limit of its store.
+func RateLimitDelay(err error) (time.Duration, bool) {
+	var rateLimited *RateLimitedError
+	if !errors.As(err, &rateLimited) || rateLimited.RetryAfter <= 0 {
+		return 0, false
+	}
+	return rateLimited.RetryAfter, true
+}
+
+// RequeueRateLimited requeues a reconcile which failed on the rate limit of a store
+// once the rate limit allows a call, instead of retrying it with the error backoff.
+func RequeueRateLimited(result ctrl.Result, err error) (ctrl.Result, error) {
+	if delay, ok := RateLimitDelay(err); ok {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+	return result, err
+}
+
+// storeGuards holds the rate limiter and the circuit breaker of every store
+// with spec.rateLimit or spec.circuitBreaker set. Like the value caches, they
+// are kept at the process level to be shared by all the managers using a store.
+var storeGuards = &storeGuardRegistry{
+	guards: make(map[valueCacheStoreKey]*storeGuard),
+}
+
+// storeEvents notifies the store controllers, by store kind, when a circuit
+// breaker changes state so that the CircuitOpen condition is updated.
+var storeEvents = map[string]chan event.GenericEvent{
+	esv1.SecretStoreKind:        make(chan event.GenericEvent, storeEventsBuffer),
+	esv1.ClusterSecretStoreKind: make(chan event.GenericEvent, storeEventsBuffer),
+}
+
+type storeGuardRegistry struct {
+	mu     sync.Mutex
+	guards map[valueCacheStoreKey]*storeGuard
+}
+
+// storeGuard limits the calls to the provider of one generation of a store.
+type storeGuard struct {
+	uid        types.UID
+	generation int64
+	limiter    *rate.Limiter
+	breaker    *circuitBreaker
+}
+
+// forStore returns the guard of the store, or nil if the store has neither
+// a rate limit nor a circuit breaker. A new store or a new store generation
+// starts with a full rate limit and a closed circuit.
+func (r *storeGuardRegistry) forStore(store esv1.GenericStore) *storeGuard {
+	key := valueCacheStoreKey{
+		kind:      store.GetKind(),
+		namespace: store.GetNamespace(),
+		name:      store.GetName(),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spec := store.GetSpec()
+	if spec.RateLimit == nil && spec.CircuitBreaker == nil {
+		if g, ok := r.guards[key]; ok && g.breaker != nil {
+			metrics.RemoveCircuitBreakerState(store)
+		}
+		delete(r.guards, key)
+		return nil
+	}
+	prev, ok := r.guards[key]
+	if ok && prev.uid == store.GetUID() && prev.generation == store.GetGeneration() {
+		return prev
+	}
+
+	g := &storeGuard{
+		uid:        store.GetUID(),
+		generation: store.GetGeneration(),
+	}
+	if cfg := spec.RateLimit; cfg != nil {
+		burst := int(cfg.Burst)
+		if burst <= 0 {
+			burst = int(cfg.RequestsPerSecond)
+		}
+		g.limiter = rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), burst)
+	}
+	if cfg := spec.CircuitBreaker; cfg != nil {
+		g.breaker = newCircuitBreaker(store.Copy(), cfg)
+		metrics.SetCircuitBreakerState(store, float64(circuitClosed))
+	} else if ok && prev.breaker != nil {
+		metrics.RemoveCircuitBreakerState(store)
+	}
+	r.guards[key] = g
+	return g
+}
+
+// circuitStateOf returns the state of the circuit breaker of the store,
+// and false if the store has no circuit breaker.
+func circuitStateOf(store esv1.GenericStore) (circuitState, time.Time, bool) {
+	g := storeGuards.forStore(store)
+	if g == nil || g.breaker == nil {
+		return circuitClosed, time.Time{}, false
+	}
+	state, openUntil := g.breaker.current()
+	return state, openUntil, true
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+// circuitBreaker opens after errorThreshold consecutive failed calls and
+// rejects calls until openDuration has passed. A single probe is then let
+// through: its success closes the circuit and its failure opens it again.
+type circuitBreaker struct {
+	mu           sync.Mutex
+	store        esv1.GenericStore
+	threshold    int
+	openDuration time.Duration
+	state        circuitState
+	failures     int
+	openUntil    time.Time
+	probing      bool
+	now          func() time.Time
+}
+
+func newCircuitBreaker(store esv1.GenericStore, cfg *esv1.SecretStoreCircuitBreaker) *circuitBreaker {
+	threshold := defaultCircuitErrorThreshold
+	if cfg.ErrorThreshold > 0 {
+		threshold = int(cfg.ErrorThreshold)
+	}
+	openDuration := defaultCircuitOpenDuration
+	if cfg.OpenDuration.Duration > 0 {
+		openDuration = cfg.OpenDuration.Duration
+	}
+	return &circuitBreaker{
+		store:        store,
+		threshold:    threshold,
+		openDuration: openDuration,
+		now:          time.Now,
+	}
+}
+
+func (b *circuitBreaker) current() (circuitState, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state, b.openUntil
+}
+
+// allow returns ErrCircuitOpen if the call must not reach the provider.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if b.now().Before(b.openUntil) {
+			return ErrCircuitOpen
+		}
+		b.setState(circuitHalfOpen)
+		b.probing = true
+	case circuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	case circuitClosed:
+	}
+	return nil
+}
+
+// done records the outcome of a call let through by allow.
+func (b *circuitBreaker) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	failed := isProviderFailure(err)
+	switch b.state {
+	case circuitClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+	case circuitHalfOpen:
+		b.probing = false
+		if failed {
+			b.open()
+			return
+		}
+		b.failures = 0
+		b.setState(circuitClosed)
+	case circuitOpen:
+		// a call started before the circuit opened, its outcome is already accounted for
+	}
+}
+
+func (b *circuitBreaker) open() {
+	b.openUntil = b.now().Add(b.openDuration)
+	b.setState(circuitOpen)
+}
+
+func (b *circuitBreaker) setState(state circuitState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	metrics.SetCircuitBreakerState(b.store, float64(state))
+	notifyStoreController(b.store)
+}
+
+// isProviderFailure returns true if the error counts as a failure of the provider.
+// Missing secrets, version conflicts and canceled calls say nothing about the health of the provider.
+func isProviderFailure(err error) bool {
+	return err != nil &&
+		!errors.Is(err, esv1.NoSecretErr) &&
+		!errors.Is(err, esv1.NotModifiedErr) &&
+		!errors.Is(err, esv1.VersionConflictErr) &&
+		!errors.Is(err, esv1.ConditionalPushUnsupportedErr) &&
+		!errors.Is(err, context.Canceled)
+}
+
+// notifyStoreController enqueues the store in its controller without blocking the call.
+func notifyStoreController(store esv1.GenericStore) {
+	ch, ok := storeEvents[store.GetKind()]
+	if !ok {
+		return
+	}
+	select {
+	case ch <- event.GenericEvent{Object: store}:
+	default:
+	}
+}
+
+// guardedClient enforces the rate limit and the circuit breaker of its store
+// on every call to the provider. Validate and Close are not guarded.
+type guardedClient struct {
+	esv1.SecretsClient
+	store esv1.GenericStore
+	guard *storeGuard
+}
+
+var (
+	_ esv1.BatchSecretsClient  = &guardedClient{}
+	_ esv1.SecretVersionClient = &guardedClient{}
+	_ esv1.ConditionalPusher   = &guardedClient{}
+)
+
+func newGuardedClient(client esv1.SecretsClient, store esv1.GenericStore) esv1.SecretsClient {
+	g := storeGuards.forStore(store)
+	if g == nil {
+		return client
+	}
+	return &guardedClient{
+		SecretsClient: client,
+		store:         store,
+		guard:         g,
+	}
+}
+
+// call waits for the rate limit, then runs fn if the circuit allows it.
+func (c *guardedClient) call(ctx context.Context, fn func() error) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	if c.guard.breaker == nil {
+		return fn()
+	}
+	if err := c.guard.breaker.allow(); err != nil {
+		metrics.ObserveThrottledCall(c.store, metrics.ThrottleCircuitOpen)
+		return ctrlutil.Safe(err)
+	}
+	err := fn()
+	c.guard.breaker.done(err)
+	return err
+}
+
+// wait blocks until the rate limit allows a call. It rejects the call
+// instead if it would have to wait longer than maxRateLimitWait, with
+// the delay after which the caller should retry.
+func (c *guardedClient) wait(ctx context.Context) error {
+	if c.guard.limiter == nil {
+		return nil
+	}
+	reservation := c.guard.limiter.Reserve()
+	delay := reservation.Delay()
+	if !reservation.OK() || delay > maxRateLimitWait {
+		reservation.Cancel()
+		metrics.ObserveThrottledCall(c.store, metrics.ThrottleRejected)
+		if !reservation.OK() {
+			return ctrlutil.Safe(ErrRateLimited)
+		}
+		return ctrlutil.Safe(&RateLimitedError{RetryAfter: delay})
+	}
+	if delay == 0 {
+		return nil
+	}
+	metrics.ObserveThrottledCall(c.store, metrics.ThrottleDelayed)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+		return ctx.Err()
+	}
+}
+
+func (c *guardedClient) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (secret []byte, err error) {
+	err = c.call(ctx, func() error {
+		secret, err = c.SecretsClient.GetSecret(ctx, ref)
+		return err
+	})
+	return secret, err
+}
+
+func (c *guardedClient) GetSecretVersion(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (secret []byte, version esv1.SecretVersion, err error) {
+	err = c.call(ctx, func() error {
+		secret, version, err = GetSecretVersion(ctx, c.SecretsClient, ref)
+		return err
+	})
+	return secret, version, err
+}
+
+// GetSecrets counts a batch call as a single call to the provider.
+// Without batch support, every ref is a call of its own.
+func (c *guardedClient) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([]esv1.SecretResult, error) {
+	batchClient, ok := c.SecretsClient.(esv1.BatchSecretsClient)
+	if !ok {
+		results := make([]esv1.SecretResult, len(refs))
+		for i, ref := range refs {
+			results[i].Value, results[i].Version, results[i].Err = c.GetSecretVersion(ctx, ref)
+		}
+		return results, nil
+	}
+	var results []esv1.SecretResult
+	err := c.call(ctx, func() error {
+		var err error
+		results, err = batchClient.GetSecrets(ctx, refs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckBatchResults(results, len(refs)); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *guardedClient) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (secretMap map[string][]byte, err error) {
+	err = c.call(ctx, func() error {
+		secretMap, err = c.SecretsClient.GetSecretMap(ctx, ref)
+		return err
+	})
+	return secretMap, err
+}
+
+func (c *guardedClient) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (secretMap map[string][]byte, err error) {
+	err = c.call(ctx, func() error {
+		secretMap, err = c.SecretsClient.GetAllSecrets(ctx, ref)
+		return err
+	})
+	return secretMap, err
+}
+
+func (c *guardedClient) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	return c.call(ctx, func() error {
+		return c.SecretsClient.PushSecret(ctx, secret, data)
+	})
+}
+
+func (c *guardedClient) PushSecretIfVersion(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData, version esv1.SecretVersion) (pushed esv1.SecretVersion, err error) {
+	if _, ok := c.SecretsClient.(esv1.ConditionalPusher); !ok {
+		return esv1.SecretVersion{}, esv1.ConditionalPushUnsupportedErr
+	}
+	err = c.call(ctx, func() error {
+		pushed, err = PushSecretIfVersion(ctx, c.SecretsClient, secret, data, version)
+		return err
+	})
+	return pushed, err
+}
+
+func (c *guardedClient) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	return c.call(ctx, func() error {
+		return c.SecretsClient.DeleteSecret(ctx, remoteRef)
+	})
+}
+
+func (c *guardedClient) SecretExists(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) (exists bool, err error) {
+	err = c.call(ctx, func() error {
+		exists, err = c.SecretsClient.SecretExists(ctx, remoteRef)
+		return err
+	})
+	return exists, err
+}
@@ -0,0 +1,239 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+var errGuardTestUnavailable = errors.New("connection refused")
+
+func newGuardTestStore(name string, rateLimit *esv1.SecretStoreRateLimit, breaker *esv1.SecretStoreCircuitBreaker) *esv1.SecretStore {
+	return &esv1.SecretStore{
+		TypeMeta: metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "default",
+			UID:        types.UID(name),
+			Generation: 1,
+		},
+		Spec: esv1.SecretStoreSpec{RateLimit: rateLimit, CircuitBreaker: breaker},
+	}
+}
+
+func TestGuardDisabled(t *testing.T) {
+	inner := fake.New()
+	client := newGuardedClient(inner, newGuardTestStore("guard-disabled", nil, nil))
+	assert.Same(t, inner, client)
+}
+
+func TestGuardRateLimit(t *testing.T) {
+	var calls int
+	store := newGuardTestStore("guard-rate-limit", &esv1.SecretStoreRateLimit{RequestsPerSecond: 5, Burst: 2}, nil)
+	client := newGuardedClient(countingClient(&calls, nil), store)
+
+	// the burst is served at once
+	start := time.Now()
+	for range 2 {
+		_, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+		require.NoError(t, err)
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// the next call waits for a token, or fails when the caller gives up first
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, calls)
+
+	// the limit is shared by every client of the store
+	other := newGuardedClient(countingClient(&calls, nil), store)
+	start = time.Now()
+	_, err = other.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	assert.Greater(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, 3, calls)
+}
+
+func TestGuardRateLimitRejectsLongWaits(t *testing.T) {
+	store := newGuardTestStore("guard-rate-limit-reject", &esv1.SecretStoreRateLimit{RequestsPerSecond: 1}, nil)
+	guard := storeGuards.forStore(store)
+	// drain more tokens than can be refilled within maxRateLimitWait
+	for range int(maxRateLimitWait/time.Second) + 1 {
+		guard.limiter.Reserve()
+	}
+
+	var calls int
+	client := newGuardedClient(countingClient(&calls, nil), store)
+	start := time.Now()
+	_, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.ErrorIs(t, err, ErrRateLimited)
+	assert.Less(t, time.Since(start), maxRateLimitWait)
+	assert.Equal(t, 0, calls)
+
+	// the caller is told when to retry
+	delay, ok := RateLimitDelay(err)
+	require.True(t, ok)
+	assert.Greater(t, delay, maxRateLimitWait)
+	assert.LessOrEqual(t, delay, time.Second)
+}
+
+func TestRequeueRateLimited(t *testing.T) {
+	result, err := RequeueRateLimited(ctrl.Result{}, fmt.Errorf("could not get secret: %w", &RateLimitedError{RetryAfter: time.Second}))
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Second}, result)
+
+	// other errors are retried with the error backoff
+	result, err = RequeueRateLimited(ctrl.Result{}, errGuardTestUnavailable)
+	require.ErrorIs(t, err, errGuardTestUnavailable)
+	assert.Equal(t, ctrl.Result{}, result)
+}
+
+func TestGuardCircuitBreaker(t *testing.T) {
+	store := newGuardTestStore("guard-circuit", nil, &esv1.SecretStoreCircuitBreaker{
+		ErrorThreshold: 2,
+		OpenDuration:   metav1.Duration{Duration: time.Minute},
+	})
+	now := time.Now()
+	guard := storeGuards.forStore(store)
+	guard.breaker.now = func() time.Time { return now }
+
+	var calls int
+	failing := newGuardedClient(countingClient(&calls, errGuardTestUnavailable), store)
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "foo"}
+
+	// missing secrets are not failures of the provider
+	_, err := newGuardedClient(countingClient(&calls, esv1.NoSecretErr), store).GetSecret(context.Background(), ref)
+	require.ErrorIs(t, err, esv1.NoSecretErr)
+	for range 2 {
+		_, err = failing.GetSecret(context.Background(), ref)
+		require.ErrorIs(t, err, errGuardTestUnavailable)
+	}
+	assert.Equal(t, 3, calls)
+	state, openUntil, ok := circuitStateOf(store)
+	require.True(t, ok)
+	assert.Equal(t, circuitOpen, state)
+	assert.Equal(t, now.Add(time.Minute), openUntil)
+
+	// the open circuit rejects calls without reaching the provider
+	_, err = failing.GetSecret(context.Background(), ref)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	err = failing.PushSecret(context.Background(), &corev1.Secret{}, nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, calls)
+
+	// a failed probe opens the circuit again
+	now = now.Add(time.Minute)
+	_, err = failing.GetSecret(context.Background(), ref)
+	require.ErrorIs(t, err, errGuardTestUnavailable)
+	assert.Equal(t, 4, calls)
+	state, _, _ = circuitStateOf(store)
+	assert.Equal(t, circuitOpen, state)
+
+	// a successful probe closes the circuit
+	now = now.Add(time.Minute)
+	healthy := newGuardedClient(countingClient(&calls, nil), store)
+	_, err = healthy.GetSecret(context.Background(), ref)
+	require.NoError(t, err)
+	state, _, _ = circuitStateOf(store)
+	assert.Equal(t, circuitClosed, state)
+
+	// a new store generation starts with a closed circuit
+	_, err = failing.GetSecret(context.Background(), ref)
+	require.Error(t, err)
+	store.Generation++
+	assert.NotSame(t, guard, storeGuards.forStore(store))
+}
+
+func TestGuardVersionConflicts(t *testing.T) {
+	store := newGuardTestStore("guard-conflict", nil, &esv1.SecretStoreCircuitBreaker{ErrorThreshold: 1})
+	var calls int
+	client := newGuardedClient(conditionalClient{countingClient(&calls, nil)}, store)
+
+	// version conflicts are not failures of the provider
+	_, err := PushSecretIfVersion(context.Background(), client, &corev1.Secret{}, fake.PushSecretData{RemoteKey: "foo"}, esv1.SecretVersion{ID: "3"})
+	require.ErrorIs(t, err, esv1.VersionConflictErr)
+	state, _, ok := circuitStateOf(store)
+	require.True(t, ok)
+	assert.Equal(t, circuitClosed, state)
+
+	// a provider without conditional pushes is not called
+	unsupported := newGuardedClient(countingClient(&calls, nil), store)
+	_, err = PushSecretIfVersion(context.Background(), unsupported, &corev1.Secret{}, fake.PushSecretData{RemoteKey: "foo"}, esv1.SecretVersion{ID: "1"})
+	require.ErrorIs(t, err, esv1.ConditionalPushUnsupportedErr)
+}
+
+func TestGuardHalfOpenAllowsSingleProbe(t *testing.T) {
+	store := newGuardTestStore("guard-half-open", nil, &esv1.SecretStoreCircuitBreaker{ErrorThreshold: 1})
+	now := time.Now()
+	guard := storeGuards.forStore(store)
+	guard.breaker.now = func() time.Time { return now }
+
+	guard.breaker.done(errGuardTestUnavailable)
+	require.ErrorIs(t, guard.breaker.allow(), ErrCircuitOpen)
+
+	now = now.Add(defaultCircuitOpenDuration)
+	require.NoError(t, guard.breaker.allow())
+	require.ErrorIs(t, guard.breaker.allow(), ErrCircuitOpen)
+	state, _, _ := circuitStateOf(store)
+	assert.Equal(t, circuitHalfOpen, state)
+
+	guard.breaker.done(nil)
+	require.NoError(t, guard.breaker.allow())
+}
+
+func TestSetCircuitCondition(t *testing.T) {
+	store := newGuardTestStore("guard-condition", nil, &esv1.SecretStoreCircuitBreaker{ErrorThreshold: 1})
+	guard := storeGuards.forStore(store)
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_condition"}, ctrlmetrics.ConditionMetricLabelNames)
+	conditionGauge := func(string) *prometheus.GaugeVec { return gauge }
+
+	setCircuitCondition(store, conditionGauge)
+	cond := GetSecretStoreCondition(store.Status, esv1.SecretStoreCircuitOpen)
+	require.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+
+	guard.breaker.done(errGuardTestUnavailable)
+	setCircuitCondition(store, conditionGauge)
+	cond = GetSecretStoreCondition(store.Status, esv1.SecretStoreCircuitOpen)
+	require.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, esv1.ReasonCircuitOpen, cond.Reason)
+
+	// the condition is removed with the circuit breaker
+	store.Spec.CircuitBreaker = nil
+	store.Generation++
+	setCircuitCondition(store, conditionGauge)
+	assert.Nil(t, GetSecretStoreCondition(store.Status, esv1.SecretStoreCircuitOpen))
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
@@ -30,6 +31,70 @@ import (
 // StatusConditionKey is the key for the status condition metric.
 const StatusConditionKey = "status_condition"
 
+const (
+	valueCacheRequestsKey = "value_cache_requests_count"
+	valueCacheHit         = "hit"
+	valueCacheMiss        = "miss"
+)
+
+const (
+	providerThrottledCallsKey = "provider_throttled_calls_count"
+	circuitBreakerStateKey    = "circuit_breaker_state"
+
+	// ThrottleDelayed is the reason of a call delayed by the rate limit of a store.
+	ThrottleDelayed = "delayed"
+	// ThrottleRejected is the reason of a call rejected by the rate limit of a store.
+	ThrottleRejected = "rejected"
+	// ThrottleCircuitOpen is the reason of a call rejected by the open circuit breaker of a store.
+	ThrottleCircuitOpen = "circuit_open"
+)
+
+var valueCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Subsystem: "secretstore",
+	Name:      valueCacheRequestsKey,
+	Help:      "Number of lookups in the value cache of a store",
+}, []string{"kind", "namespace", "name", "result"})
+
+var providerThrottledCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Subsystem: "secretstore",
+	Name:      providerThrottledCallsKey,
+	Help:      "Number of provider calls delayed or rejected by the rate limit or the circuit breaker of a store",
+}, []string{"kind", "namespace", "name", "reason"})
+
+var circuitBreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Subsystem: "secretstore",
+	Name:      circuitBreakerStateKey,
+	Help:      "State of the circuit breaker of a store: 0 closed, 1 half-open, 2 open",
+}, []string{"kind", "namespace", "name"})
+
+func init() {
+	metrics.Registry.MustRegister(valueCacheRequests, providerThrottledCalls, circuitBreakerState)
+}
+
+// ObserveValueCacheLookup records a hit or a miss in the value cache of a store.
+func ObserveValueCacheLookup(store esapi.GenericStore, hit bool) {
+	result := valueCacheMiss
+	if hit {
+		result = valueCacheHit
+	}
+	valueCacheRequests.WithLabelValues(store.GetKind(), store.GetNamespace(), store.GetName(), result).Inc()
+}
+
+// ObserveThrottledCall records a provider call delayed or rejected for the reason.
+func ObserveThrottledCall(store esapi.GenericStore, reason string) {
+	providerThrottledCalls.WithLabelValues(store.GetKind(), store.GetNamespace(), store.GetName(), reason).Inc()
+}
+
+// SetCircuitBreakerState records the state of the circuit breaker of a store.
+func SetCircuitBreakerState(store esapi.GenericStore, state float64) {
+	circuitBreakerState.WithLabelValues(store.GetKind(), store.GetNamespace(), store.GetName()).Set(state)
+}
+
+// RemoveCircuitBreakerState removes the state of the circuit breaker of a store.
+func RemoveCircuitBreakerState(store esapi.GenericStore) {
+	circuitBreakerState.DeleteLabelValues(store.GetKind(), store.GetNamespace(), store.GetName())
+}
+
 // GaugeVevGetter is a function type that retrieves a Prometheus GaugeVec based on a provided key.
 type GaugeVevGetter func(key string) *prometheus.GaugeVec
 
@@ -42,18 +107,18 @@ func UpdateStatusCondition(ss esapi.GenericStore, condition esapi.SecretStoreSta
 	conditionLabels := ctrlmetrics.RefineConditionMetricLabels(ssInfo)
 	secretStoreCondition := gaugeVecGetter(StatusConditionKey)
 
-	if condition.Type == esapi.SecretStoreReady {
+	if condition.Type == esapi.SecretStoreReady || condition.Type == esapi.SecretStoreCircuitOpen {
 		switch condition.Status {
 		case v1.ConditionFalse:
 			secretStoreCondition.With(ctrlmetrics.RefineLabels(conditionLabels,
 				map[string]string{
-					"condition": string(esapi.SecretStoreReady),
+					"condition": string(condition.Type),
 					"status":    string(v1.ConditionTrue),
 				})).Set(0)
 		case v1.ConditionTrue:
 			secretStoreCondition.With(ctrlmetrics.RefineLabels(conditionLabels,
 				map[string]string{
-					"condition": string(esapi.SecretStoreReady),
+					"condition": string(condition.Type),
 					"status":    string(v1.ConditionFalse),
 				})).Set(0)
 		case v1.ConditionUnknown:
@@ -93,7 +93,7 @@ var _ = BeforeSuite(func() {
 		Log:               ctrl.Log.WithName("controllers").WithName("SecretStore"),
 		ControllerClass:   defaultControllerClass,
 		PushSecretEnabled: true, // enable PushSecret feature for testing
-	}).SetupWithManager(k8sManager, controller.Options{
+	}).SetupWithManager(ctx, k8sManager, controller.Options{
 		MaxConcurrentReconciles: 1,
 		RateLimiter:             ctrlcommon.BuildRateLimiter(),
 	})
@@ -131,7 +131,7 @@ var _ = BeforeSuite(func() {
 		ControllerClass:   defaultControllerClass,
 		Log:               ctrl.Log.WithName("controllers").WithName("ClusterSecretStore"),
 		PushSecretEnabled: true, // enable PushSecret feature for testing
-	}).SetupWithManager(k8sManager, controller.Options{
+	}).SetupWithManager(ctx, k8sManager, controller.Options{
 		MaxConcurrentReconciles: 1,
 		RateLimiter:             ctrlcommon.BuildRateLimiter(),
 	})
@@ -0,0 +1,358 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore/metrics"
+)
+
+const (
+	defaultValueCacheTTL  = 5 * time.Minute
+	defaultValueCacheSize = 100
+)
+
+// valueCaches holds the value cache of every store with spec.cache set.
+// Managers only live for a single reconcile, so the caches are kept
+// at the process level to be shared between them. The caches hold no
+// goroutine: expired values are dropped when they are read, or when the
+// store is reconciled, and the caches of a deleted store are dropped by
+// its reconciler.
+var valueCaches = &storeValueCaches{
+	caches: make(map[valueCacheStoreKey]*storeValueCache),
+}
+
+type storeValueCaches struct {
+	mu     sync.Mutex
+	caches map[valueCacheStoreKey]*storeValueCache
+}
+
+type valueCacheStoreKey struct {
+	kind      string
+	namespace string
+	name      string
+	// consumer is the namespace the values are fetched for. A ClusterSecretStore may resolve
+	// its credentials in the namespace of the ExternalSecret (referent authentication),
+	// so its values are never shared between namespaces.
+	consumer string
+}
+
+// storeValueCache caches the values of one generation of a store.
+type storeValueCache struct {
+	uid        types.UID
+	generation int64
+	ttl        time.Duration
+	values     *lru.Cache[string, cachedValue]
+}
+
+type cachedValue struct {
+	expires time.Time
+	// remoteKey is the key of the remote ref, to evict the values of a changed key.
+	remoteKey string
+	secret    []byte
+	version   esv1.SecretVersion
+	secretMap map[string][]byte
+}
+
+// forStore returns the value cache of the store for the namespace, or nil if caching is disabled.
+// A new store or a new store generation starts with an empty cache.
+func (c *storeValueCaches) forStore(store esv1.GenericStore, namespace string) *storeValueCache {
+	key := valueCacheStoreKey{
+		kind:      store.GetKind(),
+		namespace: store.GetNamespace(),
+		name:      store.GetName(),
+		consumer:  namespace,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cfg := store.GetSpec().Cache
+	if cfg == nil {
+		delete(c.caches, key)
+		return nil
+	}
+	vc, ok := c.caches[key]
+	if ok && vc.uid == store.GetUID() && vc.generation == store.GetGeneration() {
+		return vc
+	}
+
+	ttl := defaultValueCacheTTL
+	if cfg.TTL.Duration > 0 {
+		ttl = cfg.TTL.Duration
+	}
+	size := defaultValueCacheSize
+	if cfg.MaxSize > 0 {
+		size = cfg.MaxSize
+	}
+	values, err := lru.New[string, cachedValue](size)
+	if err != nil {
+		return nil
+	}
+	vc = &storeValueCache{
+		uid:        store.GetUID(),
+		generation: store.GetGeneration(),
+		ttl:        ttl,
+		values:     values,
+	}
+	c.caches[key] = vc
+	return vc
+}
+
+// get returns the value of the key, unless it expired.
+func (vc *storeValueCache) get(key string) (cachedValue, bool) {
+	val, ok := vc.values.Get(key)
+	if !ok {
+		return cachedValue{}, false
+	}
+	if time.Now().After(val.expires) {
+		vc.values.Remove(key)
+		return cachedValue{}, false
+	}
+	return val, true
+}
+
+func (vc *storeValueCache) add(key string, val cachedValue) {
+	val.expires = time.Now().Add(vc.ttl)
+	vc.values.Add(key, val)
+}
+
+// pruneExpired drops the expired values, and reports whether the cache is empty.
+func (vc *storeValueCache) pruneExpired() bool {
+	now := time.Now()
+	for _, k := range vc.values.Keys() {
+		if val, ok := vc.values.Peek(k); ok && now.After(val.expires) {
+			vc.values.Remove(k)
+		}
+	}
+	return vc.values.Len() == 0
+}
+
+// prune drops the expired values of the store, and the caches of the store which are empty
+// or belong to another generation of the store. It is called when the store is reconciled.
+func (c *storeValueCaches) prune(store esv1.GenericStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, vc := range c.caches {
+		if key.kind != store.GetKind() || key.namespace != store.GetNamespace() || key.name != store.GetName() {
+			continue
+		}
+		if vc.uid != store.GetUID() || vc.generation != store.GetGeneration() || vc.pruneExpired() {
+			delete(c.caches, key)
+		}
+	}
+}
+
+// DropValues drops the value caches of a deleted store, for all namespaces.
+func DropValues(kind, namespace, name string) {
+	valueCaches.mu.Lock()
+	defer valueCaches.mu.Unlock()
+	for key := range valueCaches.caches {
+		if key.kind == kind && key.namespace == namespace && key.name == name {
+			delete(valueCaches.caches, key)
+		}
+	}
+}
+
+// purgeStore drops the cached values of the store for all namespaces.
+func (c *storeValueCaches) purgeStore(store esv1.GenericStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, vc := range c.caches {
+		if key.kind == store.GetKind() && key.namespace == store.GetNamespace() && key.name == store.GetName() {
+			vc.values.Purge()
+		}
+	}
+}
+
+// EvictValues drops the cached values of the remote key from the value caches of the store, for all namespaces.
+// It is called when a provider notifies a change of the key, which would be hidden by the cache until its ttl.
+func EvictValues(kind, namespace, name, remoteKey string) {
+	valueCaches.mu.Lock()
+	defer valueCaches.mu.Unlock()
+	for key, vc := range valueCaches.caches {
+		if key.kind != kind || key.namespace != namespace || key.name != name {
+			continue
+		}
+		for _, k := range vc.values.Keys() {
+			if val, ok := vc.values.Peek(k); ok && val.remoteKey == remoteKey {
+				vc.values.Remove(k)
+			}
+		}
+	}
+}
+
+// cachingClient serves GetSecret and GetSecretMap from the value cache of
+// its store before asking the provider. Errors are never cached.
+type cachingClient struct {
+	esv1.SecretsClient
+	store esv1.GenericStore
+	cache *storeValueCache
+}
+
+var (
+	_ esv1.BatchSecretsClient  = &cachingClient{}
+	_ esv1.SecretVersionClient = &cachingClient{}
+	_ esv1.ConditionalPusher   = &cachingClient{}
+)
+
+func newCachingClient(client esv1.SecretsClient, store esv1.GenericStore, namespace string) esv1.SecretsClient {
+	vc := valueCaches.forStore(store, namespace)
+	if vc == nil {
+		return client
+	}
+	return &cachingClient{
+		SecretsClient: client,
+		store:         store,
+		cache:         vc,
+	}
+}
+
+// Uncached returns the client without the value cache of its store, for the reads which must see
+// the current remote value, like the checks of a PushSecret before and after a push.
+func Uncached(client esv1.SecretsClient) esv1.SecretsClient {
+	if c, ok := client.(*cachingClient); ok {
+		return c.SecretsClient
+	}
+	return client
+}
+
+// valueCacheKey identifies a value by the call and the complete remote ref,
+// including its version.
+func valueCacheKey(call string, ref esv1.ExternalSecretDataRemoteRef) string {
+	raw, _ := json.Marshal(ref)
+	return call + "/" + string(raw)
+}
+
+func (c *cachingClient) lookup(key string) (cachedValue, bool) {
+	val, ok := c.cache.get(key)
+	metrics.ObserveValueCacheLookup(c.store, ok)
+	return val, ok
+}
+
+func (c *cachingClient) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, _, err := c.GetSecretVersion(ctx, ref)
+	return secret, err
+}
+
+func (c *cachingClient) GetSecretVersion(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, esv1.SecretVersion, error) {
+	if val, ok := c.lookup(valueCacheKey("GetSecret", ref)); ok {
+		return bytes.Clone(val.secret), val.version, nil
+	}
+	return c.fetchSecret(ctx, ref)
+}
+
+// GetSecrets serves the cached refs and fetches the others with a single
+// batch call if the provider supports it.
+func (c *cachingClient) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([]esv1.SecretResult, error) {
+	results := make([]esv1.SecretResult, len(refs))
+	var missing []int
+	for i, ref := range refs {
+		if val, ok := c.lookup(valueCacheKey("GetSecret", ref)); ok {
+			results[i].Value = bytes.Clone(val.secret)
+			results[i].Version = val.version
+			continue
+		}
+		missing = append(missing, i)
+	}
+
+	batchClient, ok := c.SecretsClient.(esv1.BatchSecretsClient)
+	if !ok || len(missing) < 2 {
+		for _, i := range missing {
+			results[i].Value, results[i].Version, results[i].Err = c.fetchSecret(ctx, refs[i])
+		}
+		return results, nil
+	}
+
+	missingRefs := make([]esv1.ExternalSecretDataRemoteRef, 0, len(missing))
+	for _, i := range missing {
+		missingRefs = append(missingRefs, refs[i])
+	}
+	batch, err := batchClient.GetSecrets(ctx, missingRefs)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckBatchResults(batch, len(missingRefs)); err != nil {
+		return nil, err
+	}
+	for j, i := range missing {
+		results[i] = batch[j]
+		if batch[j].Err == nil {
+			c.cache.add(valueCacheKey("GetSecret", refs[i]), cachedValue{remoteKey: refs[i].Key, secret: bytes.Clone(batch[j].Value), version: batch[j].Version})
+		}
+	}
+	return results, nil
+}
+
+func (c *cachingClient) fetchSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, esv1.SecretVersion, error) {
+	secret, version, err := GetSecretVersion(ctx, c.SecretsClient, ref)
+	if err != nil {
+		return nil, esv1.SecretVersion{}, err
+	}
+	c.cache.add(valueCacheKey("GetSecret", ref), cachedValue{remoteKey: ref.Key, secret: bytes.Clone(secret), version: version})
+	return secret, version, nil
+}
+
+func (c *cachingClient) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	key := valueCacheKey("GetSecretMap", ref)
+	if val, ok := c.lookup(key); ok {
+		return cloneSecretMap(val.secretMap), nil
+	}
+	secretMap, err := c.SecretsClient.GetSecretMap(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.add(key, cachedValue{remoteKey: ref.Key, secretMap: cloneSecretMap(secretMap)})
+	return secretMap, nil
+}
+
+// PushSecret, PushSecretIfVersion and DeleteSecret drop all cached values of the store, in all namespaces,
+// so that pushed changes are not hidden by the cache.
+func (c *cachingClient) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	defer valueCaches.purgeStore(c.store)
+	return c.SecretsClient.PushSecret(ctx, secret, data)
+}
+
+func (c *cachingClient) PushSecretIfVersion(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData, version esv1.SecretVersion) (esv1.SecretVersion, error) {
+	defer valueCaches.purgeStore(c.store)
+	return PushSecretIfVersion(ctx, c.SecretsClient, secret, data, version)
+}
+
+func (c *cachingClient) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	defer valueCaches.purgeStore(c.store)
+	return c.SecretsClient.DeleteSecret(ctx, remoteRef)
+}
+
+func cloneSecretMap(in map[string][]byte) map[string][]byte {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string][]byte, len(in))
+	for k, v := range in {
+		out[k] = bytes.Clone(v)
+	}
+	return out
+}
@@ -0,0 +1,298 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+func newValueCacheStore(name string, cache *esv1.CacheConfig) *esv1.SecretStore {
+	return &esv1.SecretStore{
+		TypeMeta: metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  "default",
+			UID:        types.UID(name),
+			Generation: 1,
+		},
+		Spec: esv1.SecretStoreSpec{Cache: cache},
+	}
+}
+
+func countingClient(calls *int, err error) *fake.Client {
+	client := fake.New()
+	client.GetSecretFn = func(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+		*calls++
+		if err != nil {
+			return nil, err
+		}
+		return []byte(ref.Key + "@" + ref.Version), nil
+	}
+	return client
+}
+
+func TestValueCacheDisabled(t *testing.T) {
+	inner := fake.New()
+	client := newCachingClient(inner, newValueCacheStore("disabled", nil), "default")
+	assert.Same(t, inner, client)
+}
+
+func TestValueCacheGetSecret(t *testing.T) {
+	var calls int
+	store := newValueCacheStore("get-secret", &esv1.CacheConfig{})
+	client := newCachingClient(countingClient(&calls, nil), store, "default")
+
+	for range 3 {
+		val, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+		require.NoError(t, err)
+		assert.Equal(t, "foo@", string(val))
+	}
+	assert.Equal(t, 1, calls)
+
+	// a different version is a different entry
+	val, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo", Version: "2"})
+	require.NoError(t, err)
+	assert.Equal(t, "foo@2", string(val))
+	assert.Equal(t, 2, calls)
+
+	// a new client of the same store generation shares the cache
+	client = newCachingClient(countingClient(&calls, nil), store, "default")
+	_, err = client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// a new store generation starts from an empty cache
+	store.Generation++
+	client = newCachingClient(countingClient(&calls, nil), store, "default")
+	_, err = client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestValueCacheClusterStoreNamespaces(t *testing.T) {
+	var calls int
+	store := &esv1.ClusterSecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.ClusterSecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "cluster", Generation: 1},
+		Spec:       esv1.SecretStoreSpec{Cache: &esv1.CacheConfig{}},
+	}
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "foo"}
+
+	// a value fetched for a namespace is not served to another one
+	_, err := newCachingClient(countingClient(&calls, nil), store, "a").GetSecret(context.Background(), ref)
+	require.NoError(t, err)
+	_, err = newCachingClient(countingClient(&calls, nil), store, "b").GetSecret(context.Background(), ref)
+	require.NoError(t, err)
+	_, err = newCachingClient(countingClient(&calls, nil), store, "a").GetSecret(context.Background(), ref)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// a push drops the values cached for every namespace
+	require.NoError(t, newCachingClient(countingClient(&calls, nil), store, "a").PushSecret(context.Background(), &corev1.Secret{}, fake.PushSecretData{RemoteKey: "foo"}))
+	_, err = newCachingClient(countingClient(&calls, nil), store, "b").GetSecret(context.Background(), ref)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestValueCacheDoesNotCacheErrors(t *testing.T) {
+	var calls int
+	client := newCachingClient(countingClient(&calls, esv1.NoSecretErr), newValueCacheStore("errors", &esv1.CacheConfig{}), "default")
+
+	for range 2 {
+		_, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+		assert.True(t, errors.Is(err, esv1.NoSecretErr))
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestValueCacheTTL(t *testing.T) {
+	var calls int
+	client := newCachingClient(countingClient(&calls, nil), newValueCacheStore("ttl", &esv1.CacheConfig{
+		TTL: metav1.Duration{Duration: 10 * time.Millisecond},
+	}), "default")
+
+	_, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	_, err = client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestValueCachePrune(t *testing.T) {
+	store := newValueCacheStore("prune", &esv1.CacheConfig{
+		TTL: metav1.Duration{Duration: 10 * time.Millisecond},
+	})
+	key := valueCacheStoreKey{kind: esv1.SecretStoreKind, namespace: "default", name: "prune", consumer: "default"}
+	cached := func() bool {
+		valueCaches.mu.Lock()
+		defer valueCaches.mu.Unlock()
+		_, ok := valueCaches.caches[key]
+		return ok
+	}
+
+	var calls int
+	_, err := newCachingClient(countingClient(&calls, nil), store, "default").GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	valueCaches.prune(store)
+	assert.True(t, cached(), "a cache with values which did not expire is kept")
+
+	time.Sleep(50 * time.Millisecond)
+	valueCaches.prune(store)
+	assert.False(t, cached(), "a cache with only expired values is dropped")
+
+	_, err = newCachingClient(countingClient(&calls, nil), store, "default").GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	updated := store.DeepCopy()
+	updated.Generation++
+	valueCaches.prune(updated)
+	assert.False(t, cached(), "the cache of a previous generation is dropped")
+
+	_, err = newCachingClient(countingClient(&calls, nil), store, "default").GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	DropValues(esv1.SecretStoreKind, "default", "prune")
+	assert.False(t, cached(), "the caches of a deleted store are dropped")
+}
+
+func TestValueCacheGetSecretMap(t *testing.T) {
+	var calls int
+	inner := fake.New()
+	inner.GetSecretMapFn = func(context.Context, esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+		calls++
+		return map[string][]byte{"foo": []byte("bar")}, nil
+	}
+	client := newCachingClient(inner, newValueCacheStore("get-secret-map", &esv1.CacheConfig{}), "default")
+
+	first, err := client.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	first["foo"][0] = 'X'
+
+	second, err := client.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"foo": []byte("bar")}, second)
+	assert.Equal(t, 1, calls)
+}
+
+func TestValueCachePushSecretPurges(t *testing.T) {
+	var calls int
+	client := newCachingClient(countingClient(&calls, nil), newValueCacheStore("push", &esv1.CacheConfig{}), "default")
+
+	_, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	require.NoError(t, client.PushSecret(context.Background(), &corev1.Secret{}, fake.PushSecretData{RemoteKey: "foo"}))
+	_, err = client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestValueCacheGetSecrets(t *testing.T) {
+	var calls int
+	client := newCachingClient(countingClient(&calls, nil), newValueCacheStore("get-secrets", &esv1.CacheConfig{}), "default")
+	batchClient, ok := client.(esv1.BatchSecretsClient)
+	require.True(t, ok)
+
+	_, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	require.NoError(t, err)
+
+	results, err := batchClient.GetSecrets(context.Background(), []esv1.ExternalSecretDataRemoteRef{{Key: "foo"}, {Key: "bar"}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "foo@", string(results[0].Value))
+	assert.Equal(t, "bar@", string(results[1].Value))
+	assert.Equal(t, 2, calls)
+}
+
+func TestValueCacheEvictValues(t *testing.T) {
+	var calls int
+	store := newValueCacheStore("evict", &esv1.CacheConfig{})
+	client := newCachingClient(countingClient(&calls, nil), store, "default")
+
+	for _, key := range []string{"foo", "bar"} {
+		_, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: key})
+		require.NoError(t, err)
+	}
+
+	// only the values of the changed key are fetched again
+	EvictValues(esv1.SecretStoreKind, "default", "evict", "foo")
+	for _, key := range []string{"foo", "bar"} {
+		_, err := client.GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: key})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, calls)
+}
+
+func TestValueCacheUncached(t *testing.T) {
+	var calls int
+	client := newCachingClient(countingClient(&calls, nil), newValueCacheStore("uncached", &esv1.CacheConfig{}), "default")
+
+	for range 2 {
+		_, err := Uncached(client).GetSecret(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+// conditionalClient accepts the pushes conditioned on the version "1", which are pushed as the version "2".
+type conditionalClient struct {
+	*fake.Client
+}
+
+func (c conditionalClient) PushSecretIfVersion(_ context.Context, _ *corev1.Secret, _ esv1.PushSecretData, version esv1.SecretVersion) (esv1.SecretVersion, error) {
+	if version.ID != "1" {
+		return esv1.SecretVersion{}, esv1.VersionConflictErr
+	}
+	return esv1.SecretVersion{ID: "2"}, nil
+}
+
+func TestValueCachePushSecretIfVersion(t *testing.T) {
+	ctx := context.Background()
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "foo"}
+	data := fake.PushSecretData{RemoteKey: "foo"}
+	var calls int
+	store := newValueCacheStore("push-if-version", &esv1.CacheConfig{})
+
+	// a provider without conditional pushes lets the caller fall back to PushSecret
+	unsupported := newCachingClient(countingClient(&calls, nil), store, "default")
+	_, err := PushSecretIfVersion(ctx, unsupported, &corev1.Secret{}, data, esv1.SecretVersion{ID: "1"})
+	require.ErrorIs(t, err, esv1.ConditionalPushUnsupportedErr)
+
+	client := newCachingClient(conditionalClient{countingClient(&calls, nil)}, store, "default")
+	_, err = client.GetSecret(ctx, ref)
+	require.NoError(t, err)
+	version, err := PushSecretIfVersion(ctx, client, &corev1.Secret{}, data, esv1.SecretVersion{ID: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", version.ID)
+	_, err = PushSecretIfVersion(ctx, client, &corev1.Secret{}, data, esv1.SecretVersion{ID: "3"})
+	require.ErrorIs(t, err, esv1.VersionConflictErr)
+
+	// the pushed value is fetched again
+	_, err = client.GetSecret(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
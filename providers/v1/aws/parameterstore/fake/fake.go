@@ -29,6 +29,7 @@ import (
 // Client implements the aws parameterstore interface.
 type Client struct {
 	GetParameterFn           GetParameterFn
+	GetParametersFn          GetParametersFn
 	GetParametersByPathFn    GetParametersByPathFn
 	PutParameterFn           PutParameterFn
 	PutParameterCalledN      int
@@ -43,6 +44,9 @@ type Client struct {
 // GetParameterFn defines a function type for mocking GetParameter API.
 type GetParameterFn func(context.Context, *ssm.GetParameterInput, ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
 
+// GetParametersFn defines a function type for mocking GetParameters API.
+type GetParametersFn func(context.Context, *ssm.GetParametersInput, ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
+
 // GetParametersByPathFn defines a function type for mocking GetParametersByPath API.
 type GetParametersByPathFn func(context.Context, *ssm.GetParametersByPathInput, ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
 
@@ -98,6 +102,11 @@ func (sm *Client) GetParameter(ctx context.Context, input *ssm.GetParameterInput
 	return sm.GetParameterFn(ctx, input, options...)
 }
 
+// GetParameters executes the mocked GetParametersFn.
+func (sm *Client) GetParameters(ctx context.Context, input *ssm.GetParametersInput, options ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	return sm.GetParametersFn(ctx, input, options...)
+}
+
 // GetParametersByPath executes the mocked GetParametersByPathFn.
 func (sm *Client) GetParametersByPath(ctx context.Context, input *ssm.GetParametersByPathInput, options ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
 	return sm.GetParametersByPathFn(ctx, input, options...)
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -60,10 +61,11 @@ type PushSecretMetadataSpec struct {
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var (
-	_               esv1.SecretsClient = &ParameterStore{}
-	managedBy                          = "managed-by"
-	externalSecrets                    = "external-secrets"
-	logger                             = ctrl.Log.WithName("provider").WithName("parameterstore")
+	_               esv1.SecretsClient      = &ParameterStore{}
+	_               esv1.BatchSecretsClient = &ParameterStore{}
+	managedBy                               = "managed-by"
+	externalSecrets                         = "external-secrets"
+	logger                                  = ctrl.Log.WithName("provider").WithName("parameterstore")
 )
 
 // ParameterStore is a provider for AWS ParameterStore.
@@ -78,6 +80,7 @@ type ParameterStore struct {
 // see: https://docs.aws.amazon.com/sdk-for-go/api/service/ssm/ssmiface/
 type PMInterface interface {
 	GetParameter(ctx context.Context, input *ssm.GetParameterInput, opts ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+	GetParameters(ctx context.Context, input *ssm.GetParametersInput, opts ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
 	GetParametersByPath(ctx context.Context, input *ssm.GetParametersByPathInput, opts ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
 	PutParameter(ctx context.Context, input *ssm.PutParameterInput, opts ...func(*ssm.Options)) (*ssm.PutParameterOutput, error)
 	DescribeParameters(ctx context.Context, input *ssm.DescribeParametersInput, opts ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
@@ -90,6 +93,10 @@ type PMInterface interface {
 const (
 	errUnexpectedFindOperator    = "unexpected find operator"
 	errCodeAccessDeniedException = "AccessDeniedException"
+
+	// getParametersMaxNames is the maximum number of names
+	// accepted by a single GetParameters call.
+	getParametersMaxNames = 10
 )
 
 // New constructs a ParameterStore Provider that is specific to a store.
@@ -537,21 +544,78 @@ func (pm *ParameterStore) GetSecret(ctx context.Context, ref esv1.ExternalSecret
 	if err != nil {
 		return nil, awsutil.SanitizeErr(err)
 	}
+	return parameterValue(out.Parameter, ref)
+}
+
+// GetSecrets returns the secrets referenced by refs.
+// Refs without a version are fetched through GetParameters, the remaining
+// refs and any parameter the batch call could not return fall back to GetSecret.
+func (pm *ParameterStore) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([]esv1.SecretResult, error) {
+	var names []string
+	for _, ref := range refs {
+		name := pm.prefix + ref.Key
+		if isBatchable(ref) && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	params := make(map[string]*ssmTypes.Parameter)
+	invalid := make(map[string]bool)
+	for chunk := range slices.Chunk(names, getParametersMaxNames) {
+		out, err := pm.client.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          chunk,
+			WithDecryption: aws.Bool(true),
+		})
+		metrics.ObserveAPICall(constants.ProviderAWSPS, constants.CallAWSPSGetParameters, err)
+		if err != nil {
+			logger.V(1).Info("batch fetch failed, falling back to GetParameter", "error", awsutil.SanitizeErr(err))
+			break
+		}
+		for i := range out.Parameters {
+			params[aws.ToString(out.Parameters[i].Name)] = &out.Parameters[i]
+		}
+		for _, name := range out.InvalidParameters {
+			invalid[name] = true
+		}
+	}
+
+	results := make([]esv1.SecretResult, len(refs))
+	for i, ref := range refs {
+		name := pm.prefix + ref.Key
+		switch {
+		case isBatchable(ref) && invalid[name]:
+			results[i].Err = esv1.NoSecretErr
+		case isBatchable(ref) && params[name] != nil:
+			results[i].Value, results[i].Err = parameterValue(params[name], ref)
+		default:
+			results[i].Value, results[i].Err = pm.GetSecret(ctx, ref)
+		}
+	}
+	return results, nil
+}
+
+// isBatchable returns true if the ref can be fetched with GetParameters.
+func isBatchable(ref esv1.ExternalSecretDataRemoteRef) bool {
+	return ref.Version == "" && ref.MetadataPolicy != esv1.ExternalSecretMetadataPolicyFetch
+}
+
+// parameterValue returns the value of the parameter or of the property referenced by ref.
+func parameterValue(param *ssmTypes.Parameter, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if ref.Property == "" {
-		if out.Parameter.Value != nil {
-			return []byte(*out.Parameter.Value), nil
+		if param.Value != nil {
+			return []byte(*param.Value), nil
 		}
 		return nil, fmt.Errorf("invalid secret received. parameter value is nil for key: %s", ref.Key)
 	}
 	idx := strings.Index(ref.Property, ".")
 	if idx > -1 {
 		refProperty := strings.ReplaceAll(ref.Property, ".", "\\.")
-		val := gjson.Get(*out.Parameter.Value, refProperty)
+		val := gjson.Get(*param.Value, refProperty)
 		if val.Exists() {
 			return []byte(val.String()), nil
 		}
 	}
-	val := gjson.Get(*out.Parameter.Value, ref.Property)
+	val := gjson.Get(*param.Value, ref.Property)
 	if !val.Exists() {
 		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
@@ -920,6 +920,56 @@ func TestGetSecret(t *testing.T) {
 	}
 }
 
+func TestGetSecrets(t *testing.T) {
+	var (
+		batchNames [][]string
+		getCalls   []string
+	)
+	fakeClient := &fakeps.Client{
+		GetParametersFn: func(_ context.Context, input *ssm.GetParametersInput, _ ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+			batchNames = append(batchNames, input.Names)
+			out := &ssm.GetParametersOutput{}
+			for _, name := range input.Names {
+				if name == "/missing" {
+					out.InvalidParameters = append(out.InvalidParameters, name)
+					continue
+				}
+				out.Parameters = append(out.Parameters, ssmtypes.Parameter{
+					Name:  aws.String(name),
+					Value: aws.String(`{"foo":"batch` + name + `"}`),
+				})
+			}
+			return out, nil
+		},
+		GetParameterFn: func(_ context.Context, input *ssm.GetParameterInput, _ ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+			getCalls = append(getCalls, *input.Name)
+			return &ssm.GetParameterOutput{
+				Parameter: &ssmtypes.Parameter{Value: aws.String("single" + *input.Name)},
+			}, nil
+		},
+	}
+	ps := ParameterStore{client: fakeClient, prefix: "/"}
+
+	results, err := ps.GetSecrets(context.Background(), []esv1.ExternalSecretDataRemoteRef{
+		{Key: "a", Property: "foo"},
+		{Key: "b"},
+		{Key: "a", Version: "2"},
+		{Key: "missing"},
+		{Key: "a", Property: "INVALPROP"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.Equal(t, "batch/a", string(results[0].Value))
+	assert.Equal(t, `{"foo":"batch/b"}`, string(results[1].Value))
+	assert.Equal(t, "single/a:2", string(results[2].Value))
+	assert.ErrorIs(t, results[3].Err, esv1.NoSecretErr)
+	assert.ErrorContains(t, results[4].Err, errInvalidProperty)
+
+	assert.Equal(t, [][]string{{"/a", "/b", "/missing"}}, batchNames)
+	assert.Equal(t, []string{"/a:2"}, getCalls)
+}
+
 func TestGetSecretMap(t *testing.T) {
 	// good case: default version & deserialization
 	simpleJSON := func(pstc *parameterstoreTestCase) {
@@ -48,6 +48,7 @@ type Client struct {
 	DeleteResourcePolicyFn         DeleteResourcePolicyFn
 	ReplicateSecretToRegionsFn     ReplicateSecretToRegionsFn
 	RemoveRegionsFromReplicationFn RemoveRegionsFromReplicationFn
+	UpdateSecretVersionStageFn     UpdateSecretVersionStageFn
 }
 type (
 	CreateSecretFn        func(context.Context, *awssm.CreateSecretInput, ...func(*awssm.Options)) (*awssm.CreateSecretOutput, error)
@@ -70,6 +71,7 @@ type (
 type (
 	ReplicateSecretToRegionsFn     func(context.Context, *awssm.ReplicateSecretToRegionsInput, ...func(*awssm.Options)) (*awssm.ReplicateSecretToRegionsOutput, error)
 	RemoveRegionsFromReplicationFn func(context.Context, *awssm.RemoveRegionsFromReplicationInput, ...func(*awssm.Options)) (*awssm.RemoveRegionsFromReplicationOutput, error)
+	UpdateSecretVersionStageFn     func(context.Context, *awssm.UpdateSecretVersionStageInput, ...func(*awssm.Options)) (*awssm.UpdateSecretVersionStageOutput, error)
 )
 
 func (sm *Client) CreateSecret(ctx context.Context, input *awssm.CreateSecretInput, options ...func(*awssm.Options)) (*awssm.CreateSecretOutput, error) {
@@ -311,3 +313,20 @@ func (sm *Client) RemoveRegionsFromReplication(
 ) (*awssm.RemoveRegionsFromReplicationOutput, error) {
 	return sm.RemoveRegionsFromReplicationFn(ctx, params, optFns...)
 }
+
+func NewUpdateSecretVersionStageFn(output *awssm.UpdateSecretVersionStageOutput, err error, aFunc ...func(input *awssm.UpdateSecretVersionStageInput)) UpdateSecretVersionStageFn {
+	return func(ctx context.Context, params *awssm.UpdateSecretVersionStageInput, optFns ...func(*awssm.Options)) (*awssm.UpdateSecretVersionStageOutput, error) {
+		for _, f := range aFunc {
+			f(params)
+		}
+		return output, err
+	}
+}
+
+func (sm *Client) UpdateSecretVersionStage(
+	ctx context.Context,
+	params *awssm.UpdateSecretVersionStageInput,
+	optFns ...func(*awssm.Options),
+) (*awssm.UpdateSecretVersionStageOutput, error) {
+	return sm.UpdateSecretVersionStageFn(ctx, params, optFns...)
+}
@@ -83,6 +83,9 @@ const (
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1.SecretsClient = &SecretsManager{}
+var _ esv1.BatchSecretsClient = &SecretsManager{}
+var _ esv1.SecretVersionClient = &SecretsManager{}
+var _ esv1.ConditionalPusher = &SecretsManager{}
 
 // SecretsManager is a provider for AWS SecretsManager.
 type SecretsManager struct {
@@ -114,6 +117,7 @@ type SMInterface interface {
 	DeleteResourcePolicy(ctx context.Context, params *awssm.DeleteResourcePolicyInput, optFuncs ...func(*awssm.Options)) (*awssm.DeleteResourcePolicyOutput, error)
 	ReplicateSecretToRegions(ctx context.Context, params *awssm.ReplicateSecretToRegionsInput, optFuncs ...func(*awssm.Options)) (*awssm.ReplicateSecretToRegionsOutput, error)
 	RemoveRegionsFromReplication(ctx context.Context, params *awssm.RemoveRegionsFromReplicationInput, optFuncs ...func(*awssm.Options)) (*awssm.RemoveRegionsFromReplicationOutput, error)
+	UpdateSecretVersionStage(ctx context.Context, params *awssm.UpdateSecretVersionStageInput, optFuncs ...func(*awssm.Options)) (*awssm.UpdateSecretVersionStageOutput, error)
 }
 
 const (
@@ -121,6 +125,15 @@ const (
 	managedBy                 = "managed-by"
 	externalSecrets           = "external-secrets"
 	initialVersion            = "00000000-0000-0000-0000-000000000001"
+	currentStage              = "AWSCURRENT"
+
+	// pendingPushStage labels the version put by PushSecretIfVersion
+	// until AWSCURRENT is moved to it.
+	pendingPushStage = "ESOPENDING"
+
+	// batchGetSecretValueMaxIDs is the maximum number of secret ids
+	// accepted by a single BatchGetSecretValue call.
+	batchGetSecretValueMaxIDs = 20
 )
 
 var log = ctrl.Log.WithName("provider").WithName("aws").WithName("secretsmanager")
@@ -154,7 +167,7 @@ func (sm *SecretsManager) fetch(ctx context.Context, ref esv1.ExternalSecretData
 	key := sm.prefix + ref.Key
 	log.Info("fetching secret value", "key", key, "version", ver, "value", valueFrom)
 
-	cacheKey := fmt.Sprintf("%s#%s#%s", key, ver, valueFrom)
+	cacheKey := secretCacheKey(key, ver, valueFrom)
 	if secretOut, found := sm.cache[cacheKey]; found {
 		log.Info("found secret in cache", "key", key, "version", ver)
 		return secretOut, nil
@@ -173,6 +186,7 @@ func (sm *SecretsManager) fetch(ctx context.Context, ref esv1.ExternalSecretData
 // DeleteSecret deletes a secret from AWS Secrets Manager.
 func (sm *SecretsManager) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
 	secretName := sm.prefix + remoteRef.GetRemoteKey()
+	defer sm.evict(secretName)
 	secretValue := awssm.GetSecretValueInput{
 		SecretId: &secretName,
 	}
@@ -258,6 +272,7 @@ func (sm *SecretsManager) PushSecret(ctx context.Context, secret *corev1.Secret,
 	}
 
 	secretName := sm.prefix + psd.GetRemoteKey()
+	defer sm.evict(secretName)
 	describeSecretInput := awssm.DescribeSecretInput{SecretId: &secretName}
 	describeSecretOutput, err := sm.client.DescribeSecret(ctx, &describeSecretInput)
 	metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMDescribeSecret, err)
@@ -283,7 +298,8 @@ func (sm *SecretsManager) PushSecret(ctx context.Context, secret *corev1.Secret,
 		if err != nil {
 			return err
 		}
-		return sm.putSecretValueWithContext(ctx, secretName, nil, psd, finalValue, describeSecretOutput)
+		_, err = sm.putSecretValueWithContext(ctx, secretName, nil, psd, finalValue, describeSecretOutput, nil)
+		return err
 	}
 
 	getSecretValueInput := awssm.GetSecretValueInput{SecretId: &secretName}
@@ -297,7 +313,90 @@ func (sm *SecretsManager) PushSecret(ctx context.Context, secret *corev1.Secret,
 	if err != nil {
 		return err
 	}
-	return sm.putSecretValueWithContext(ctx, secretName, getSecretValueOutput, psd, finalValue, describeSecretOutput)
+	_, err = sm.putSecretValueWithContext(ctx, secretName, getSecretValueOutput, psd, finalValue, describeSecretOutput, nil)
+	return err
+}
+
+// PushSecretIfVersion pushes a secret like PushSecret, only if the AWSCURRENT version of the secret is still version.
+// The new value is put with a pending staging label, then AWSCURRENT is moved to it from the expected version,
+// which Secrets Manager rejects if AWSCURRENT was attached to another version in the meantime. A rejected version
+// loses its pending label, and is removed by Secrets Manager as deprecated.
+func (sm *SecretsManager) PushSecretIfVersion(ctx context.Context, secret *corev1.Secret, psd esv1.PushSecretData, version esv1.SecretVersion) (esv1.SecretVersion, error) {
+	value, err := esutils.ExtractSecretData(psd, secret)
+	if err != nil {
+		return esv1.SecretVersion{}, fmt.Errorf("failed to extract secret data: %w", err)
+	}
+
+	secretName := sm.prefix + psd.GetRemoteKey()
+	defer sm.evict(secretName)
+	describeSecretOutput, err := sm.client.DescribeSecret(ctx, &awssm.DescribeSecretInput{SecretId: &secretName})
+	metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMDescribeSecret, err)
+	var aerr smithy.APIError
+	if errors.As(err, &aerr) && aerr.ErrorCode() == ResourceNotFoundException {
+		// the secret was deleted since the expected version
+		return esv1.SecretVersion{}, esv1.VersionConflictErr
+	}
+	if err != nil {
+		return esv1.SecretVersion{}, err
+	}
+	if !isManagedByESO(describeSecretOutput) {
+		return esv1.SecretVersion{}, errors.New("secret not managed by external-secrets")
+	}
+	if !slices.Contains(describeSecretOutput.VersionIdsToStages[version.ID], currentStage) {
+		return esv1.SecretVersion{}, esv1.VersionConflictErr
+	}
+
+	getSecretValueOutput, err := sm.client.GetSecretValue(ctx, &awssm.GetSecretValueInput{SecretId: &secretName, VersionId: aws.String(version.ID)})
+	metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMGetSecretValue, err)
+	if err != nil {
+		return esv1.SecretVersion{}, err
+	}
+	finalValue, err := sm.getNewSecretValue(value, psd.GetProperty(), getSecretValueOutput)
+	if err != nil {
+		return esv1.SecretVersion{}, err
+	}
+	newVersion, err := sm.putSecretValueWithContext(ctx, secretName, getSecretValueOutput, psd, finalValue, describeSecretOutput, []string{pendingPushStage})
+	if err != nil {
+		return esv1.SecretVersion{}, err
+	}
+	if newVersion == "" {
+		return version, nil
+	}
+
+	_, err = sm.client.UpdateSecretVersionStage(ctx, &awssm.UpdateSecretVersionStageInput{
+		SecretId:            &secretName,
+		VersionStage:        aws.String(currentStage),
+		MoveToVersionId:     aws.String(newVersion),
+		RemoveFromVersionId: aws.String(version.ID),
+	})
+	metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMUpdateSecretVersionStage, err)
+	if err == nil {
+		return esv1.SecretVersion{ID: newVersion}, nil
+	}
+	_, removeErr := sm.client.UpdateSecretVersionStage(ctx, &awssm.UpdateSecretVersionStageInput{
+		SecretId:            &secretName,
+		VersionStage:        aws.String(pendingPushStage),
+		RemoveFromVersionId: aws.String(newVersion),
+	})
+	metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMUpdateSecretVersionStage, removeErr)
+	if removeErr != nil {
+		log.Error(awsutil.SanitizeErr(removeErr), "could not remove the pending label of a rejected version", "key", secretName, "version", newVersion)
+	}
+	var ie *types.InvalidParameterException
+	if errors.As(err, &ie) {
+		// AWSCURRENT is no longer attached to the expected version
+		return esv1.SecretVersion{}, esv1.VersionConflictErr
+	}
+	return esv1.SecretVersion{}, err
+}
+
+// evict drops the cached values of a secret which was pushed or deleted, so that it is read again.
+func (sm *SecretsManager) evict(secretName string) {
+	for key := range sm.cache {
+		if strings.HasPrefix(key, secretName+"#") {
+			delete(sm.cache, key)
+		}
+	}
 }
 
 func (sm *SecretsManager) getNewSecretValue(value []byte, property string, existingSecret *awssm.GetSecretValueOutput) ([]byte, error) {
@@ -484,6 +583,99 @@ func (sm *SecretsManager) GetSecret(ctx context.Context, ref esv1.ExternalSecret
 	return []byte(val.String()), nil
 }
 
+// GetSecrets returns the secrets referenced by refs.
+// The current version of all referenced secrets is fetched through
+// BatchGetSecretValue and put into the client cache, every ref is then resolved
+// like GetSecret. Refs pinned to a version or fetching metadata, as well as
+// secrets the batch call could not return, fall back to GetSecretValue.
+func (sm *SecretsManager) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([]esv1.SecretResult, error) {
+	var ids []string
+	for _, ref := range refs {
+		if (ref.Version != "" && ref.Version != "AWSCURRENT") || ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
+			continue
+		}
+		key := sm.prefix + ref.Key
+		if _, found := sm.cache[secretCacheKey(key, "AWSCURRENT", "SECRET")]; found || slices.Contains(ids, key) {
+			continue
+		}
+		ids = append(ids, key)
+	}
+
+	for chunk := range slices.Chunk(ids, batchGetSecretValueMaxIDs) {
+		if err := sm.fetchBatchToCache(ctx, chunk); err != nil {
+			// BatchGetSecretValue requires its own IAM permission,
+			// keep going with GetSecretValue if it is not granted.
+			log.V(1).Info("batch fetch failed, falling back to GetSecretValue", "error", awsutil.SanitizeErr(err))
+			break
+		}
+	}
+
+	results := make([]esv1.SecretResult, len(refs))
+	for i, ref := range refs {
+		results[i].Value, results[i].Version, results[i].Err = sm.GetSecretVersion(ctx, ref)
+	}
+	return results, nil
+}
+
+// GetSecretVersion returns a single secret like GetSecret, along with its VersionId.
+// Tags fetched with the Fetch metadata policy have no version.
+func (sm *SecretsManager) GetSecretVersion(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, esv1.SecretVersion, error) {
+	value, err := sm.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, esv1.SecretVersion{}, err
+	}
+	var version esv1.SecretVersion
+	if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
+		return value, version, nil
+	}
+	// the secret is served from the client cache filled by GetSecret
+	if secretOut, err := sm.fetch(ctx, ref); err == nil && secretOut.VersionId != nil {
+		version.ID = *secretOut.VersionId
+	}
+	return value, version, nil
+}
+
+// fetchBatchToCache fetches the current version of the given secret ids
+// and stores them in the client cache.
+func (sm *SecretsManager) fetchBatchToCache(ctx context.Context, ids []string) error {
+	var nextToken *string
+	for {
+		it, err := sm.client.BatchGetSecretValue(ctx, &awssm.BatchGetSecretValueInput{
+			SecretIdList: ids,
+			NextToken:    nextToken,
+		})
+		metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMBatchGetSecretValue, err)
+		if err != nil {
+			return err
+		}
+		for _, secret := range it.SecretValues {
+			secretOut := &awssm.GetSecretValueOutput{
+				ARN:           secret.ARN,
+				CreatedDate:   secret.CreatedDate,
+				Name:          secret.Name,
+				SecretBinary:  secret.SecretBinary,
+				SecretString:  secret.SecretString,
+				VersionId:     secret.VersionId,
+				VersionStages: secret.VersionStages,
+			}
+			// secrets may be referenced by name or by ARN
+			for _, id := range ids {
+				if id == aws.ToString(secret.Name) || id == aws.ToString(secret.ARN) {
+					sm.cache[secretCacheKey(id, "AWSCURRENT", "SECRET")] = secretOut
+				}
+			}
+		}
+		nextToken = it.NextToken
+		if nextToken == nil {
+			return nil
+		}
+	}
+}
+
+func secretCacheKey(key, ver, valueFrom string) string {
+	return fmt.Sprintf("%s#%s#%s", key, ver, valueFrom)
+}
+
 func (sm *SecretsManager) mapSecretToGjson(secretOut *awssm.GetSecretValueOutput, property string) gjson.Result {
 	payload := sm.retrievePayload(secretOut)
 	refProperty := sm.escapeDotsIfRequired(property, payload)
@@ -638,25 +830,26 @@ func (sm *SecretsManager) putSecretValueWithContext(
 	psd esv1.PushSecretData,
 	value []byte,
 	describeSecret *awssm.DescribeSecretOutput,
-) error {
+	stages []string,
+) (string, error) {
 	currentTags := make(map[string]string, len(describeSecret.Tags))
 	for _, tag := range describeSecret.Tags {
 		currentTags[*tag.Key] = *tag.Value
 	}
 	if err := sm.patchTags(ctx, psd.GetMetadata(), &secretArn, currentTags); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := sm.manageResourcePolicy(ctx, psd.GetMetadata(), &secretArn); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := sm.manageRegionReplication(ctx, psd.GetMetadata(), &secretArn, describeSecret.KmsKeyId, describeSecret.ReplicationStatus); err != nil {
-		return err
+		return "", err
 	}
 
 	if awsSecret != nil && (bytes.Equal(awsSecret.SecretBinary, value) || esutils.CompareStringAndByteSlices(awsSecret.SecretString, value)) {
-		return nil
+		return "", nil
 	}
 
 	newVersionNumber := initialVersion
@@ -671,10 +864,11 @@ func (sm *SecretsManager) putSecretValueWithContext(
 		SecretId:           &secretArn,
 		SecretBinary:       value,
 		ClientRequestToken: aws.String(newVersionNumber),
+		VersionStages:      stages,
 	}
 	secretPushFormat, err := esutils.FetchValueFromMetadata(SecretPushFormatKey, psd.GetMetadata(), SecretPushFormatBinary)
 	if err != nil {
-		return fmt.Errorf("failed to parse metadata: %w", err)
+		return "", fmt.Errorf("failed to parse metadata: %w", err)
 	}
 	if secretPushFormat == SecretPushFormatString {
 		input.SecretBinary = nil
@@ -683,7 +877,10 @@ func (sm *SecretsManager) putSecretValueWithContext(
 
 	_, err = sm.client.PutSecretValue(ctx, input)
 	metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMPutSecretValue, err)
-	return err
+	if err != nil {
+		return "", err
+	}
+	return newVersionNumber, nil
 }
 
 func (sm *SecretsManager) patchTags(ctx context.Context, rawMetadata *apiextensionsv1.JSON, secretID *string, tags map[string]string) error {
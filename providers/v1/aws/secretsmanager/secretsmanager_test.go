@@ -21,7 +21,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -30,6 +32,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	awssm "github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/smithy-go"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -377,6 +380,131 @@ func TestCaching(t *testing.T) {
 	}
 }
 
+func TestSecretsManagerGetSecrets(t *testing.T) {
+	batchOutput := func(_ context.Context, input *awssm.BatchGetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.BatchGetSecretValueOutput, error) {
+		out := &awssm.BatchGetSecretValueOutput{}
+		for _, id := range input.SecretIdList {
+			if id == "missing" {
+				continue
+			}
+			out.SecretValues = append(out.SecretValues, types.SecretValueEntry{
+				Name:         aws.String(id),
+				SecretString: aws.String(`{"foo":"batch-` + id + `"}`),
+			})
+		}
+		return out, nil
+	}
+
+	testCases := []struct {
+		name                  string
+		refs                  []esv1.ExternalSecretDataRemoteRef
+		batchGetSecretValueFn fakesm.BatchGetSecretValueFn
+		expectedBatchIDs      [][]string
+		expectedGetCalls      int
+		expectedValues        []string
+		expectedErrors        []error
+	}{
+		{
+			name: "current versions are fetched in a single batch",
+			refs: []esv1.ExternalSecretDataRemoteRef{
+				{Key: "a", Property: "foo"},
+				{Key: "b"},
+				{Key: "a"},
+			},
+			batchGetSecretValueFn: batchOutput,
+			expectedBatchIDs:      [][]string{{"a", "b"}},
+			expectedValues:        []string{"batch-a", `{"foo":"batch-b"}`, `{"foo":"batch-a"}`},
+			expectedErrors:        []error{nil, nil, nil},
+		},
+		{
+			name: "pinned versions and missing secrets fall back to GetSecretValue",
+			refs: []esv1.ExternalSecretDataRemoteRef{
+				{Key: "a", Property: "foo"},
+				{Key: "a", Version: "AWSPREVIOUS"},
+				{Key: "missing"},
+			},
+			batchGetSecretValueFn: batchOutput,
+			expectedBatchIDs:      [][]string{{"a", "missing"}},
+			expectedGetCalls:      2,
+			expectedValues:        []string{"batch-a", "single-a", ""},
+			expectedErrors:        []error{nil, nil, esv1.NoSecretErr},
+		},
+		{
+			name: "batch errors fall back to GetSecretValue",
+			refs: []esv1.ExternalSecretDataRemoteRef{
+				{Key: "a"},
+				{Key: "b"},
+			},
+			batchGetSecretValueFn: func(_ context.Context, _ *awssm.BatchGetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.BatchGetSecretValueOutput, error) {
+				return nil, errors.New("AccessDeniedException")
+			},
+			expectedBatchIDs: [][]string{{"a", "b"}},
+			expectedGetCalls: 2,
+			expectedValues:   []string{"single-a", "single-b"},
+			expectedErrors:   []error{nil, nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var batchIDs [][]string
+			getCalls := 0
+			fc := fakesm.NewClient()
+			fc.BatchGetSecretValueFn = func(ctx context.Context, input *awssm.BatchGetSecretValueInput, opts ...func(*awssm.Options)) (*awssm.BatchGetSecretValueOutput, error) {
+				batchIDs = append(batchIDs, input.SecretIdList)
+				return tc.batchGetSecretValueFn(ctx, input, opts...)
+			}
+			fc.GetSecretValueFn = func(_ context.Context, input *awssm.GetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.GetSecretValueOutput, error) {
+				getCalls++
+				if *input.SecretId == "missing" {
+					return nil, &types.ResourceNotFoundException{}
+				}
+				return &awssm.GetSecretValueOutput{SecretString: aws.String("single-" + *input.SecretId)}, nil
+			}
+			sm := SecretsManager{
+				client: fc,
+				cache:  make(map[string]*awssm.GetSecretValueOutput),
+			}
+
+			results, err := sm.GetSecrets(context.Background(), tc.refs)
+			require.NoError(t, err)
+			require.Len(t, results, len(tc.refs))
+			for i, res := range results {
+				if tc.expectedErrors[i] != nil {
+					assert.ErrorIs(t, res.Err, tc.expectedErrors[i])
+					continue
+				}
+				require.NoError(t, res.Err)
+				assert.Equal(t, tc.expectedValues[i], string(res.Value))
+			}
+			assert.Equal(t, tc.expectedBatchIDs, batchIDs)
+			assert.Equal(t, tc.expectedGetCalls, getCalls)
+		})
+	}
+}
+
+func TestSecretsManagerGetSecretVersion(t *testing.T) {
+	getCalls := 0
+	fc := fakesm.NewClient()
+	fc.GetSecretValueFn = func(_ context.Context, _ *awssm.GetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.GetSecretValueOutput, error) {
+		getCalls++
+		return &awssm.GetSecretValueOutput{
+			SecretString: aws.String(`{"foo":"bar"}`),
+			VersionId:    aws.String("d6a4b5c2-0000-4000-8000-000000000001"),
+		}, nil
+	}
+	sm := SecretsManager{
+		client: fc,
+		cache:  make(map[string]*awssm.GetSecretValueOutput),
+	}
+
+	value, version, err := sm.GetSecretVersion(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "a", Property: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(value))
+	assert.Equal(t, "d6a4b5c2-0000-4000-8000-000000000001", version.ID)
+	assert.Equal(t, 1, getCalls)
+}
+
 func TestGetSecretMap(t *testing.T) {
 	// good case: default version & deserialization
 	setDeserialization := func(smtc *secretsManagerTestCase) {
@@ -1724,6 +1852,158 @@ func TestPushSecretEmptyExistingResourcePolicy(t *testing.T) {
 	assert.True(t, putResourcePolicyCalled, "PutResourcePolicy should be called when existing policy is empty")
 }
 
+func TestPushSecretIfVersion(t *testing.T) {
+	const (
+		expectedVersion = "11111111-1111-1111-1111-111111111111"
+		otherVersion    = "22222222-2222-2222-2222-222222222222"
+		pushedVersion   = "33333333-3333-3333-3333-333333333333"
+	)
+	arn := testARN
+	managedBy := managedBy
+	externalSecrets := externalSecrets
+	fakeSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			fakeSecretKey: []byte("new-value"),
+		},
+	}
+	describe := func(currentVersion string) fakesm.DescribeSecretFn {
+		return fakesm.NewDescribeSecretFn(&awssm.DescribeSecretOutput{
+			ARN:  &arn,
+			Tags: []types.Tag{{Key: &managedBy, Value: &externalSecrets}},
+			VersionIdsToStages: map[string][]string{
+				expectedVersion: {"AWSPREVIOUS"},
+				currentVersion:  {"AWSCURRENT"},
+			},
+		}, nil)
+	}
+
+	tests := map[string]struct {
+		currentVersion  string
+		remoteValue     string
+		stageErr        error
+		expectedErr     error
+		expectedVersion string
+		expectedStages  []*awssm.UpdateSecretVersionStageInput
+	}{
+		"moves AWSCURRENT from the expected version": {
+			currentVersion:  expectedVersion,
+			remoteValue:     "old-value",
+			expectedVersion: pushedVersion,
+			expectedStages: []*awssm.UpdateSecretVersionStageInput{{
+				SecretId:            aws.String(fakeKey),
+				VersionStage:        aws.String("AWSCURRENT"),
+				MoveToVersionId:     aws.String(pushedVersion),
+				RemoveFromVersionId: aws.String(expectedVersion),
+			}},
+		},
+		"conflicts when AWSCURRENT is on another version": {
+			currentVersion: otherVersion,
+			remoteValue:    "old-value",
+			expectedErr:    esv1.VersionConflictErr,
+		},
+		"conflicts when AWSCURRENT was moved concurrently": {
+			currentVersion: expectedVersion,
+			remoteValue:    "old-value",
+			stageErr:       &types.InvalidParameterException{Message: aws.String("AWSCURRENT is not attached to the version")},
+			expectedErr:    esv1.VersionConflictErr,
+			expectedStages: []*awssm.UpdateSecretVersionStageInput{
+				{
+					SecretId:            aws.String(fakeKey),
+					VersionStage:        aws.String("AWSCURRENT"),
+					MoveToVersionId:     aws.String(pushedVersion),
+					RemoveFromVersionId: aws.String(expectedVersion),
+				},
+				{
+					SecretId:            aws.String(fakeKey),
+					VersionStage:        aws.String(pendingPushStage),
+					RemoveFromVersionId: aws.String(pushedVersion),
+				},
+			},
+		},
+		"keeps the expected version when the value is unchanged": {
+			currentVersion:  expectedVersion,
+			remoteValue:     "new-value",
+			expectedVersion: expectedVersion,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				puts   []*awssm.PutSecretValueInput
+				stages []*awssm.UpdateSecretVersionStageInput
+			)
+			client := fakesm.Client{
+				DescribeSecretFn: describe(tc.currentVersion),
+				GetSecretValueFn: func(_ context.Context, input *awssm.GetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.GetSecretValueOutput, error) {
+					assert.Equal(t, expectedVersion, aws.ToString(input.VersionId))
+					return &awssm.GetSecretValueOutput{ARN: &arn, SecretBinary: []byte(tc.remoteValue), VersionId: input.VersionId}, nil
+				},
+				PutSecretValueFn: func(_ context.Context, input *awssm.PutSecretValueInput, _ ...func(*awssm.Options)) (*awssm.PutSecretValueOutput, error) {
+					puts = append(puts, input)
+					return &awssm.PutSecretValueOutput{}, nil
+				},
+				UpdateSecretVersionStageFn: fakesm.NewUpdateSecretVersionStageFn(&awssm.UpdateSecretVersionStageOutput{}, nil, func(input *awssm.UpdateSecretVersionStageInput) {
+					stages = append(stages, input)
+				}),
+				TagResourceFn:          fakesm.NewTagResourceFn(&awssm.TagResourceOutput{}, nil),
+				UntagResourceFn:        fakesm.NewUntagResourceFn(&awssm.UntagResourceOutput{}, nil),
+				DeleteResourcePolicyFn: fakesm.NewDeleteResourcePolicyFn(&awssm.DeleteResourcePolicyOutput{}, nil),
+			}
+			if tc.stageErr != nil {
+				client.UpdateSecretVersionStageFn = func(_ context.Context, input *awssm.UpdateSecretVersionStageInput, _ ...func(*awssm.Options)) (*awssm.UpdateSecretVersionStageOutput, error) {
+					stages = append(stages, input)
+					if aws.ToString(input.VersionStage) == "AWSCURRENT" {
+						return nil, tc.stageErr
+					}
+					return &awssm.UpdateSecretVersionStageOutput{}, nil
+				}
+			}
+			sm := SecretsManager{
+				client:  &client,
+				newUUID: func() string { return pushedVersion },
+			}
+
+			version, err := sm.PushSecretIfVersion(context.Background(), fakeSecret, fake.PushSecretData{SecretKey: fakeSecretKey, RemoteKey: fakeKey}, esv1.SecretVersion{ID: expectedVersion})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedVersion, version.ID)
+			}
+			assert.Equal(t, tc.expectedStages, stages)
+			for _, put := range puts {
+				assert.Equal(t, []string{pendingPushStage}, put.VersionStages)
+			}
+		})
+	}
+}
+
+func TestPushAndDeleteSecretEvictCache(t *testing.T) {
+	arn := testARN
+	cached := func() map[string]*awssm.GetSecretValueOutput {
+		return map[string]*awssm.GetSecretValueOutput{
+			secretCacheKey(fakeKey, "AWSCURRENT", "SECRET"): {SecretString: aws.String("stale")},
+			secretCacheKey(fakeKey, "AWSCURRENT", "TAG"):    {SecretString: aws.String("stale")},
+			secretCacheKey("other", "AWSCURRENT", "SECRET"): {SecretString: aws.String("kept")},
+		}
+	}
+	notFound := &smithy.GenericAPIError{Code: ResourceNotFoundException}
+	client := fakesm.Client{
+		GetSecretValueFn: fakesm.NewGetSecretValueFn(nil, notFound),
+		DescribeSecretFn: fakesm.NewDescribeSecretFn(nil, notFound),
+		CreateSecretFn:   fakesm.NewCreateSecretFn(&awssm.CreateSecretOutput{ARN: &arn}, nil),
+	}
+	fakeSecret := &corev1.Secret{Data: map[string][]byte{fakeSecretKey: []byte("value")}}
+
+	sm := SecretsManager{client: &client, cache: cached()}
+	require.NoError(t, sm.PushSecret(context.Background(), fakeSecret, fake.PushSecretData{SecretKey: fakeSecretKey, RemoteKey: fakeKey}))
+	assert.Equal(t, []string{secretCacheKey("other", "AWSCURRENT", "SECRET")}, slices.Collect(maps.Keys(sm.cache)))
+
+	sm.cache = cached()
+	require.NoError(t, sm.DeleteSecret(context.Background(), fake.PushSecretData{RemoteKey: fakeKey}))
+	assert.Equal(t, []string{secretCacheKey("other", "AWSCURRENT", "SECRET")}, slices.Collect(maps.Keys(sm.cache)))
+}
+
 func TestDeleteSecret(t *testing.T) {
 	fakeClient := fakesm.Client{}
 	managed := managedBy
@@ -0,0 +1,274 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/tidwall/gjson"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils/kvjson"
+	"github.com/external-secrets/external-secrets/runtime/find"
+)
+
+var errTagsNotSupported = errors.New("find.tags is not supported by the Consul provider")
+
+// managedByFlags marks the keys written by external-secrets.
+// Consul keys have no tags, so the opaque flags of the key are used instead.
+const managedByFlags uint64 = 0x65736f // "eso"
+
+const (
+	errGetKey           = "cannot get key %q: %w"
+	errListKeys         = "cannot list keys with prefix %q: %w"
+	errWriteKey         = "cannot write key %q: %w"
+	errDeleteKey        = "cannot delete key %q: %w"
+	errCASConflict      = "key %q was modified concurrently, it will be retried"
+	errPropertyNotFound = "property %q not found in key %q"
+	errNotManaged       = "key %q is not managed by external-secrets"
+)
+
+// Client reads and writes keys of the Consul KV store.
+// Writes use check-and-set on the modify index of the key, so concurrent changes are never overwritten.
+// Keys written by the client carry managedByFlags, and keys without them are never overwritten or deleted.
+type Client struct {
+	kv     *api.KV
+	prefix string
+}
+
+// GetSecret returns the value of a key, or the value of a property of its JSON value.
+func (c *Client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	pair, err := c.get(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, esv1.NoSecretErr
+	}
+	if ref.Property == "" {
+		return pair.Value, nil
+	}
+
+	result := gjson.GetBytes(pair.Value, ref.Property)
+	if !result.Exists() {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return kvjson.ResultBytes(result), nil
+}
+
+// GetSecretMap returns the top-level keys of the JSON value of a key, or of a property of it.
+// Values which are not strings are returned as JSON.
+func (c *Client) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	pair, err := c.get(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, esv1.NoSecretErr
+	}
+
+	result := gjson.ParseBytes(pair.Value)
+	if ref.Property != "" {
+		result = result.Get(ref.Property)
+		if !result.Exists() {
+			return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+		}
+	}
+	if !result.IsObject() {
+		return nil, kvjson.ErrNotAnObject
+	}
+
+	secretMap := make(map[string][]byte)
+	result.ForEach(func(key, value gjson.Result) bool {
+		secretMap[key.String()] = kvjson.ResultBytes(value)
+		return true
+	})
+	return secretMap, nil
+}
+
+// GetAllSecrets returns the values of the keys below find.path whose name matches find.name.
+// Keys are returned relative to the prefix of the store.
+func (c *Client) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (map[string][]byte, error) {
+	if len(ref.Tags) > 0 {
+		return nil, errTagsNotSupported
+	}
+
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+
+	prefix := c.prefix
+	if ref.Path != nil {
+		prefix = c.key(*ref.Path)
+	}
+	pairs, _, err := c.kv.List(prefix, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf(errListKeys, prefix, err)
+	}
+
+	secrets := make(map[string][]byte)
+	for _, pair := range pairs {
+		// keys ending with a slash are folders
+		if strings.HasSuffix(pair.Key, "/") {
+			continue
+		}
+		name := strings.TrimPrefix(pair.Key, c.prefix)
+		if matcher != nil && !matcher.MatchName(name) {
+			continue
+		}
+		secrets[name] = pair.Value
+	}
+	return secrets, nil
+}
+
+// PushSecret writes a key of the secret, or the whole secret as JSON, to a key of Consul.
+// With a property, the value is set as a property of the JSON object of the key.
+func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	value, err := kvjson.PushValue(secret, data)
+	if err != nil {
+		return err
+	}
+
+	pair, err := c.get(ctx, data.GetRemoteKey())
+	if err != nil {
+		return err
+	}
+	var (
+		current []byte
+		index   uint64
+	)
+	if pair != nil {
+		if pair.Flags != managedByFlags {
+			return fmt.Errorf(errNotManaged, pair.Key)
+		}
+		current = pair.Value
+		index = pair.ModifyIndex
+	}
+
+	if property := data.GetProperty(); property != "" {
+		value, err = kvjson.SetProperty(current, property, value)
+		if err != nil {
+			return err
+		}
+	}
+	if pair != nil && bytes.Equal(current, value) {
+		return nil
+	}
+
+	// a zero index only creates the key if it does not exist
+	return c.cas(ctx, &api.KVPair{Key: c.key(data.GetRemoteKey()), Value: value, Flags: managedByFlags, ModifyIndex: index})
+}
+
+// DeleteSecret deletes a key, or a property of its JSON value.
+// The key is deleted once its last property is removed.
+// Keys not managed by external-secrets are left untouched.
+func (c *Client) DeleteSecret(ctx context.Context, ref esv1.PushSecretRemoteRef) error {
+	pair, err := c.get(ctx, ref.GetRemoteKey())
+	if err != nil {
+		return err
+	}
+	if pair == nil || pair.Flags != managedByFlags {
+		return nil
+	}
+
+	if property := ref.GetProperty(); property != "" {
+		object, err := kvjson.Object(pair.Value)
+		if err != nil {
+			return err
+		}
+		if _, ok := object[property]; !ok {
+			return nil
+		}
+		delete(object, property)
+		if len(object) > 0 {
+			value, err := json.Marshal(object)
+			if err != nil {
+				return err
+			}
+			return c.cas(ctx, &api.KVPair{Key: pair.Key, Value: value, Flags: managedByFlags, ModifyIndex: pair.ModifyIndex})
+		}
+	}
+
+	ok, _, err := c.kv.DeleteCAS(&api.KVPair{Key: pair.Key, ModifyIndex: pair.ModifyIndex}, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf(errDeleteKey, pair.Key, err)
+	}
+	if !ok {
+		return fmt.Errorf(errCASConflict, pair.Key)
+	}
+	return nil
+}
+
+// SecretExists checks if a key, or a property of its JSON value, exists.
+func (c *Client) SecretExists(ctx context.Context, ref esv1.PushSecretRemoteRef) (bool, error) {
+	pair, err := c.get(ctx, ref.GetRemoteKey())
+	if err != nil || pair == nil {
+		return false, err
+	}
+	if ref.GetProperty() == "" {
+		return true, nil
+	}
+	return gjson.GetBytes(pair.Value, ref.GetProperty()).Exists(), nil
+}
+
+// Validate checks that the keys below the prefix of the store can be listed.
+func (c *Client) Validate() (esv1.ValidationResult, error) {
+	if _, _, err := c.kv.Keys(c.prefix, "/", &api.QueryOptions{}); err != nil {
+		return esv1.ValidationResultError, fmt.Errorf(errListKeys, c.prefix, err)
+	}
+	return esv1.ValidationResultReady, nil
+}
+
+// Close implements cleanup operations for the Consul client.
+func (c *Client) Close(_ context.Context) error {
+	return nil
+}
+
+func (c *Client) key(key string) string {
+	return c.prefix + strings.TrimPrefix(key, "/")
+}
+
+func (c *Client) get(ctx context.Context, key string) (*api.KVPair, error) {
+	pair, _, err := c.kv.Get(c.key(key), (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf(errGetKey, c.key(key), err)
+	}
+	return pair, nil
+}
+
+func (c *Client) cas(ctx context.Context, pair *api.KVPair) error {
+	ok, _, err := c.kv.CAS(pair, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf(errWriteKey, pair.Key, err)
+	}
+	if !ok {
+		return fmt.Errorf(errCASConflict, pair.Key)
+	}
+	return nil
+}
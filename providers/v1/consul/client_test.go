@@ -0,0 +1,343 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils/kvjson"
+	testingfake "github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+// fakeConsul is a minimal stand-in for the KV endpoints of the Consul HTTP API.
+type fakeConsul struct {
+	mu    sync.Mutex
+	index uint64
+	keys  map[string]*api.KVPair
+}
+
+func newFakeConsul(t *testing.T, values map[string]string) *Client {
+	t.Helper()
+	fake := &fakeConsul{keys: make(map[string]*api.KVPair)}
+	for key, value := range values {
+		fake.put(key, []byte(value), 0)
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	consul, err := api.NewClient(&api.Config{Address: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{kv: consul.KV(), prefix: "app/"}
+}
+
+func (f *fakeConsul) put(key string, value []byte, flags uint64) {
+	f.index++
+	pair, ok := f.keys[key]
+	if !ok {
+		pair = &api.KVPair{Key: key, CreateIndex: f.index}
+		f.keys[key] = pair
+	}
+	pair.Value = value
+	pair.Flags = flags
+	pair.ModifyIndex = f.index
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	query := r.URL.Query()
+	casIndex, hasCAS := uint64(0), query.Has("cas")
+	if hasCAS {
+		casIndex, _ = strconv.ParseUint(query.Get("cas"), 10, 64)
+	}
+	casMatches := func() bool {
+		pair, ok := f.keys[key]
+		if casIndex == 0 {
+			return !ok
+		}
+		return ok && pair.ModifyIndex == casIndex
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var pairs []*api.KVPair
+		for k, pair := range f.keys {
+			if k == key || ((query.Has("recurse") || query.Has("keys")) && strings.HasPrefix(k, key)) {
+				pairs = append(pairs, pair)
+			}
+		}
+		if len(pairs) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+		if query.Has("keys") {
+			names := make([]string, 0, len(pairs))
+			for _, pair := range pairs {
+				names = append(names, pair.Key)
+			}
+			_ = json.NewEncoder(w).Encode(names)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(pairs)
+	case http.MethodPut:
+		if hasCAS && !casMatches() {
+			_, _ = w.Write([]byte("false"))
+			return
+		}
+		value, _ := io.ReadAll(r.Body)
+		flags, _ := strconv.ParseUint(query.Get("flags"), 10, 64)
+		f.put(key, value, flags)
+		_, _ = w.Write([]byte("true"))
+	case http.MethodDelete:
+		if hasCAS && !casMatches() {
+			_, _ = w.Write([]byte("false"))
+			return
+		}
+		delete(f.keys, key)
+		_, _ = w.Write([]byte("true"))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGetSecret(t *testing.T) {
+	c := newFakeConsul(t, map[string]string{
+		"app/db":     `{"user": "admin", "port": 5432, "tls": {"enabled": true}}`,
+		"app/plain":  "value",
+		"other/key":  "outside of the prefix",
+		"app/folder": "",
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		ref      esv1.ExternalSecretDataRemoteRef
+		want     string
+		notFound bool
+		wantErr  bool
+	}{
+		{name: "value", ref: esv1.ExternalSecretDataRemoteRef{Key: "plain"}, want: "value"},
+		{name: "string property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "user"}, want: "admin"},
+		{name: "number property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "port"}, want: "5432"},
+		{name: "nested property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "tls.enabled"}, want: "true"},
+		{name: "missing property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "password"}, wantErr: true},
+		{name: "missing key", ref: esv1.ExternalSecretDataRemoteRef{Key: "missing"}, wantErr: true, notFound: true},
+		{name: "key outside of the prefix", ref: esv1.ExternalSecretDataRemoteRef{Key: "key"}, wantErr: true, notFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetSecret(ctx, tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				if tt.notFound && !errors.Is(err, esv1.NoSecretErr) {
+					t.Fatalf("expected NoSecretErr, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetSecretMap(t *testing.T) {
+	c := newFakeConsul(t, map[string]string{
+		"app/db":    `{"user": "admin", "port": 5432, "tls": {"enabled": true}}`,
+		"app/plain": "value",
+	})
+
+	got, err := c.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"user": "admin", "port": "5432", "tls": `{"enabled": true}`}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for key, value := range want {
+		if string(got[key]) != value {
+			t.Fatalf("expected %q for %q, got %q", value, key, got[key])
+		}
+	}
+
+	if _, err := c.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "plain"}); !errors.Is(err, kvjson.ErrNotAnObject) {
+		t.Fatalf("expected ErrNotAnObject, got %v", err)
+	}
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	c := newFakeConsul(t, map[string]string{
+		"app/db/user":     "admin",
+		"app/db/password": "s3cr3t",
+		"app/db/":         "",
+		"app/cache/url":   "redis://cache",
+		"other/db/user":   "root",
+	})
+	ctx := context.Background()
+
+	got, err := c.GetAllSecrets(ctx, esv1.ExternalSecretFind{Path: new("db/")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || string(got["db/user"]) != "admin" || string(got["db/password"]) != "s3cr3t" {
+		t.Fatalf("unexpected secrets: %q", got)
+	}
+
+	got, err = c.GetAllSecrets(ctx, esv1.ExternalSecretFind{Name: &esv1.FindName{RegExp: "url$"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || string(got["cache/url"]) != "redis://cache" {
+		t.Fatalf("unexpected secrets: %q", got)
+	}
+
+	if _, err := c.GetAllSecrets(ctx, esv1.ExternalSecretFind{Tags: map[string]string{"a": "b"}}); !errors.Is(err, errTagsNotSupported) {
+		t.Fatalf("expected errTagsNotSupported, got %v", err)
+	}
+}
+
+func TestPushAndDeleteSecret(t *testing.T) {
+	c := newFakeConsul(t, nil)
+	ctx := context.Background()
+	secret := &corev1.Secret{Data: map[string][]byte{"user": []byte("admin"), "password": []byte("s3cr3t")}}
+
+	get := func(key string) string {
+		t.Helper()
+		value, err := c.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: key})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(value)
+	}
+
+	// a secret key to a plain key
+	if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "user", RemoteKey: "user"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := get("user"); got != "admin" {
+		t.Fatalf("expected admin, got %q", got)
+	}
+
+	// secret keys to properties of the same key
+	for _, key := range []string{"user", "password"} {
+		if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: key, RemoteKey: "db", Property: key}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := get("db"); got != `{"password":"s3cr3t","user":"admin"}` {
+		t.Fatalf("unexpected value: %q", got)
+	}
+
+	// the whole secret as JSON
+	if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{RemoteKey: "all"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := get("all"); got != `{"password":"s3cr3t","user":"admin"}` {
+		t.Fatalf("unexpected value: %q", got)
+	}
+
+	exists, err := c.SecretExists(ctx, &testingfake.PushSecretData{RemoteKey: "db", Property: "password"})
+	if err != nil || !exists {
+		t.Fatalf("expected the property to exist, got %v, %v", exists, err)
+	}
+
+	// deleting a property keeps the other ones
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "db", Property: "password"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := get("db"); got != `{"user":"admin"}` {
+		t.Fatalf("unexpected value: %q", got)
+	}
+
+	// deleting the last property deletes the key
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "db", Property: "user"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exists, err = c.SecretExists(ctx, &testingfake.PushSecretData{RemoteKey: "db"})
+	if err != nil || exists {
+		t.Fatalf("expected the key to be deleted, got %v, %v", exists, err)
+	}
+
+	// deleting a missing key is a no-op
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "db"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPushAndDeleteUnmanagedSecret(t *testing.T) {
+	c := newFakeConsul(t, map[string]string{"app/db": `{"user":"root"}`})
+	ctx := context.Background()
+	secret := &corev1.Secret{Data: map[string][]byte{"user": []byte("admin")}}
+
+	// a key not written by external-secrets is never overwritten
+	err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "user", RemoteKey: "db", Property: "user"})
+	if err == nil || !strings.Contains(err.Error(), "not managed by external-secrets") {
+		t.Fatalf("expected an unmanaged key error, got %v", err)
+	}
+
+	// nor deleted
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "db"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	value, err := c.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: "db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(value) != `{"user":"root"}` {
+		t.Fatalf("expected the key to be untouched, got %q", value)
+	}
+}
+
+func TestPushSecretConflict(t *testing.T) {
+	c := newFakeConsul(t, nil)
+	ctx := context.Background()
+
+	// the key is created between the read and the write of the push
+	err := c.cas(ctx, &api.KVPair{Key: "app/user", Value: []byte("admin")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = c.cas(ctx, &api.KVPair{Key: "app/user", Value: []byte("root")})
+	if err == nil || !strings.Contains(err.Error(), "modified concurrently") {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+}
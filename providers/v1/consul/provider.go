@@ -0,0 +1,169 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package consul implements a provider for the HashiCorp Consul KV store.
+package consul
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+	"github.com/external-secrets/external-secrets/runtime/esutils/resolvers"
+)
+
+var (
+	errMissingStore          = errors.New("missing store")
+	errMissingConsulProvider = errors.New("missing Consul provider configuration")
+	errMissingServer         = errors.New("server is required")
+	errMissingTokenName      = errors.New("auth.tokenSecretRef.name cannot be empty")
+	errCANamespaceRequired   = errors.New("caProvider.namespace is required for ClusterSecretStore")
+	errCANamespaceNotAllowed = errors.New("caProvider.namespace must be empty with SecretStore")
+)
+
+const (
+	errInvalidStore = "invalid store: %w"
+	errFetchCA      = "failed to read ca bundle: %w"
+	errNewClient    = "unable to create Consul client: %w"
+)
+
+// Provider is a Consul KV provider implementing esv1.Provider.
+type Provider struct{}
+
+var _ esv1.Provider = &Provider{}
+var _ esv1.SecretsClient = &Client{}
+
+// Capabilities returns the provider's supported capabilities.
+func (p *Provider) Capabilities() esv1.SecretStoreCapabilities {
+	return esv1.SecretStoreReadWrite
+}
+
+// NewClient creates a new Consul client with the provided store configuration.
+func (p *Provider) NewClient(ctx context.Context, store esv1.GenericStore, kube kclient.Client, namespace string) (esv1.SecretsClient, error) {
+	spec, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+
+	config := &api.Config{
+		Address:    spec.Server,
+		Datacenter: spec.Datacenter,
+		Namespace:  spec.Namespace,
+		Partition:  spec.Partition,
+	}
+
+	if spec.Auth != nil {
+		token, err := resolvers.SecretKeyRef(ctx, kube, storeKind, namespace, &spec.Auth.TokenSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		config.Token = token
+	}
+
+	ca, err := esutils.FetchCACertFromSource(ctx, esutils.CreateCertOpts{
+		CABundle:   spec.CABundle,
+		CAProvider: spec.CAProvider,
+		StoreKind:  storeKind,
+		Namespace:  namespace,
+		Client:     kube,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errFetchCA, err)
+	}
+	config.TLSConfig.CAPem = ca
+
+	consul, err := api.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf(errNewClient, err)
+	}
+
+	return &Client{
+		kv:     consul.KV(),
+		prefix: spec.Prefix,
+	}, nil
+}
+
+// ValidateStore validates the Consul SecretStore configuration.
+func (p *Provider) ValidateStore(store esv1.GenericStore) (admission.Warnings, error) {
+	spec, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Server == "" {
+		return nil, fmt.Errorf(errInvalidStore, errMissingServer)
+	}
+	if spec.Auth != nil {
+		if spec.Auth.TokenSecretRef.Name == "" {
+			return nil, fmt.Errorf(errInvalidStore, errMissingTokenName)
+		}
+		if err := esutils.ValidateSecretSelector(store, spec.Auth.TokenSecretRef); err != nil {
+			return nil, fmt.Errorf(errInvalidStore, err)
+		}
+	}
+	if err := validateCAProvider(store, spec.CAProvider); err != nil {
+		return nil, fmt.Errorf(errInvalidStore, err)
+	}
+	return nil, nil
+}
+
+func validateCAProvider(store esv1.GenericStore, caProvider *esv1.CAProvider) error {
+	if caProvider == nil {
+		return nil
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	if storeKind == esv1.ClusterSecretStoreKind && caProvider.Namespace == nil {
+		return errCANamespaceRequired
+	}
+	if storeKind == esv1.SecretStoreKind && caProvider.Namespace != nil {
+		return errCANamespaceNotAllowed
+	}
+	return nil
+}
+
+func getProvider(store esv1.GenericStore) (*esv1.ConsulProvider, error) {
+	if store == nil {
+		return nil, errMissingStore
+	}
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Consul == nil {
+		return nil, errMissingConsulProvider
+	}
+	return spec.Provider.Consul, nil
+}
+
+// NewProvider creates a new Provider instance.
+func NewProvider() esv1.Provider {
+	return &Provider{}
+}
+
+// ProviderSpec returns the provider specification for registration.
+func ProviderSpec() *esv1.SecretStoreProvider {
+	return &esv1.SecretStoreProvider{
+		Consul: &esv1.ConsulProvider{},
+	}
+}
+
+// MaintenanceStatus returns the maintenance status of the provider.
+func MaintenanceStatus() esv1.MaintenanceStatus {
+	return esv1.MaintenanceStatusMaintained
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consul
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func makeStore(kind string, spec *esv1.ConsulProvider) esv1.GenericStore {
+	storeSpec := esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{Consul: spec}}
+	if kind == esv1.ClusterSecretStoreKind {
+		return &esv1.ClusterSecretStore{
+			TypeMeta: metav1.TypeMeta{Kind: esv1.ClusterSecretStoreKind},
+			Spec:     storeSpec,
+		}
+	}
+	return &esv1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+		Spec:       storeSpec,
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	ns := "default"
+	tests := []struct {
+		name    string
+		kind    string
+		spec    *esv1.ConsulProvider
+		wantErr error
+	}{
+		{
+			name: "anonymous",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.ConsulProvider{Server: "http://consul:8500"},
+		},
+		{
+			name: "token",
+			kind: esv1.ClusterSecretStoreKind,
+			spec: &esv1.ConsulProvider{
+				Server: "https://consul:8501",
+				Auth:   &esv1.ConsulAuth{TokenSecretRef: esmeta.SecretKeySelector{Name: "consul", Key: "token", Namespace: &ns}},
+			},
+		},
+		{
+			name:    "missing provider",
+			kind:    esv1.SecretStoreKind,
+			wantErr: errMissingConsulProvider,
+		},
+		{
+			name:    "missing server",
+			kind:    esv1.SecretStoreKind,
+			spec:    &esv1.ConsulProvider{},
+			wantErr: errMissingServer,
+		},
+		{
+			name: "token without name",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.ConsulProvider{
+				Server: "http://consul:8500",
+				Auth:   &esv1.ConsulAuth{TokenSecretRef: esmeta.SecretKeySelector{Key: "token"}},
+			},
+			wantErr: errMissingTokenName,
+		},
+		{
+			name: "caProvider without namespace in a ClusterSecretStore",
+			kind: esv1.ClusterSecretStoreKind,
+			spec: &esv1.ConsulProvider{
+				Server:     "https://consul:8501",
+				CAProvider: &esv1.CAProvider{Type: esv1.CAProviderTypeConfigMap, Name: "ca", Key: "ca.crt"},
+			},
+			wantErr: errCANamespaceRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&Provider{}).ValidateStore(makeStore(tt.kind, tt.spec))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,429 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils/kvjson"
+	"github.com/external-secrets/external-secrets/runtime/esutils/metadata"
+	"github.com/external-secrets/external-secrets/runtime/find"
+)
+
+// validateTimeout bounds the request made by Validate, which has no context.
+const validateTimeout = 10 * time.Second
+
+// managedByPrefix prefixes the keys marking the keys written by external-secrets.
+// etcd keys have no metadata, so every written key has a marker key, written in the same transaction.
+const managedByPrefix = "/external-secrets.io/managed-by"
+
+var (
+	errTagsNotSupported = errors.New("find.tags is not supported by the etcd provider")
+	errInvalidTTL       = errors.New("ttl must be positive")
+)
+
+const (
+	errGetKey           = "cannot get key %q: %w"
+	errListKeys         = "cannot list keys with prefix %q: %w"
+	errWriteKey         = "cannot write key %q: %w"
+	errDeleteKey        = "cannot delete key %q: %w"
+	errGrantLease       = "cannot grant lease for key %q: %w"
+	errRefreshLease     = "cannot refresh lease of key %q: %w"
+	errTxnConflict      = "key %q was modified concurrently, it will be retried"
+	errPropertyNotFound = "property %q not found in key %q"
+	errParseMetadata    = "cannot parse metadata: %w"
+	errNotManaged       = "key %q is not managed by external-secrets"
+)
+
+// PushSecretMetadataSpec configures how a secret is pushed to etcd.
+type PushSecretMetadataSpec struct {
+	// TTL after which etcd deletes the key, unless the secret is pushed again.
+	// The key never expires when it is not set.
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// kv is the part of clientv3.KV used by the client.
+type kv interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Txn(ctx context.Context) clientv3.Txn
+}
+
+// lease is the part of clientv3.Lease used by the client.
+type lease interface {
+	Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error)
+	TimeToLive(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error)
+	KeepAliveOnce(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseKeepAliveResponse, error)
+	Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error)
+}
+
+// Client reads and writes keys of an etcd cluster.
+// Writes are transactions comparing the mod revision of the key, so concurrent changes are never overwritten.
+// Keys written by the client have a marker key, and keys without one are never overwritten or deleted.
+type Client struct {
+	kv     kv
+	lease  lease
+	close  func() error
+	prefix string
+}
+
+// GetSecret returns the value of a key, or the value of a property of its JSON value.
+func (c *Client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	pair, err := c.get(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, esv1.NoSecretErr
+	}
+	if ref.Property == "" {
+		return pair.Value, nil
+	}
+
+	result := gjson.GetBytes(pair.Value, ref.Property)
+	if !result.Exists() {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return kvjson.ResultBytes(result), nil
+}
+
+// GetSecretMap returns the top-level keys of the JSON value of a key, or of a property of it.
+// Values which are not strings are returned as JSON.
+func (c *Client) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	pair, err := c.get(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, esv1.NoSecretErr
+	}
+
+	result := gjson.ParseBytes(pair.Value)
+	if ref.Property != "" {
+		result = result.Get(ref.Property)
+		if !result.Exists() {
+			return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+		}
+	}
+	if !result.IsObject() {
+		return nil, kvjson.ErrNotAnObject
+	}
+
+	secretMap := make(map[string][]byte)
+	result.ForEach(func(key, value gjson.Result) bool {
+		secretMap[key.String()] = kvjson.ResultBytes(value)
+		return true
+	})
+	return secretMap, nil
+}
+
+// GetAllSecrets returns the values of the keys starting with find.path whose name matches find.name.
+// Keys are returned relative to the prefix of the store.
+func (c *Client) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (map[string][]byte, error) {
+	if len(ref.Tags) > 0 {
+		return nil, errTagsNotSupported
+	}
+
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+
+	prefix := c.prefix
+	if ref.Path != nil {
+		prefix = c.key(*ref.Path)
+	}
+	resp, err := c.kv.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf(errListKeys, prefix, err)
+	}
+
+	secrets := make(map[string][]byte)
+	for _, pair := range resp.Kvs {
+		if strings.HasPrefix(string(pair.Key), managedByPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(string(pair.Key), c.prefix)
+		if matcher != nil && !matcher.MatchName(name) {
+			continue
+		}
+		secrets[name] = pair.Value
+	}
+	return secrets, nil
+}
+
+// PushSecret writes a key of the secret, or the whole secret as JSON, to a key of etcd.
+// With a property, the value is set as a property of the JSON object of the key.
+// With a ttl in the metadata, the key is attached to a lease, which is renewed on every push.
+func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	ttl, err := leaseTTL(data)
+	if err != nil {
+		return err
+	}
+	value, err := kvjson.PushValue(secret, data)
+	if err != nil {
+		return err
+	}
+
+	e, err := c.read(ctx, data.GetRemoteKey())
+	if err != nil {
+		return err
+	}
+	var current []byte
+	if e.pair != nil {
+		if e.marker == nil {
+			return fmt.Errorf(errNotManaged, e.key)
+		}
+		current = e.pair.Value
+	}
+
+	if property := data.GetProperty(); property != "" {
+		value, err = kvjson.SetProperty(current, property, value)
+		if err != nil {
+			return err
+		}
+	}
+	if e.pair != nil && bytes.Equal(current, value) {
+		ok, err := c.keepLease(ctx, e.pair, ttl)
+		if ok || err != nil {
+			return err
+		}
+	}
+
+	var (
+		opts    []clientv3.OpOption
+		granted clientv3.LeaseID
+	)
+	if ttl > 0 {
+		grant, err := c.lease.Grant(ctx, ttl)
+		if err != nil {
+			return fmt.Errorf(errGrantLease, e.key, err)
+		}
+		granted = grant.ID
+		opts = append(opts, clientv3.WithLease(granted))
+	}
+	// a missing key or marker is only created if it still does not exist
+	err = c.commit(ctx, e, errWriteKey,
+		clientv3.OpPut(e.key, string(value), opts...),
+		clientv3.OpPut(managedByKey(e.key), "true", opts...))
+	if err != nil {
+		c.revoke(ctx, granted)
+		return err
+	}
+	if e.pair != nil && clientv3.LeaseID(e.pair.Lease) != granted {
+		// the key and its marker are the only keys attached to the previous lease
+		c.revoke(ctx, clientv3.LeaseID(e.pair.Lease))
+	}
+	return nil
+}
+
+// keepLease reports whether the lease of an unchanged key already matches the ttl,
+// renewing the lease if the key has one.
+func (c *Client) keepLease(ctx context.Context, pair *mvccpb.KeyValue, ttl int64) (bool, error) {
+	if pair.Lease == 0 || ttl == 0 {
+		return pair.Lease == 0 && ttl == 0, nil
+	}
+	id := clientv3.LeaseID(pair.Lease)
+	resp, err := c.lease.TimeToLive(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf(errRefreshLease, pair.Key, err)
+	}
+	// an expired lease has a ttl of -1
+	if resp.TTL <= 0 || resp.GrantedTTL != ttl {
+		return false, nil
+	}
+	if _, err := c.lease.KeepAliveOnce(ctx, id); err != nil {
+		return false, fmt.Errorf(errRefreshLease, pair.Key, err)
+	}
+	return true, nil
+}
+
+// DeleteSecret deletes a key, or a property of its JSON value.
+// The key is deleted once its last property is removed.
+// Keys not managed by external-secrets are left untouched.
+func (c *Client) DeleteSecret(ctx context.Context, ref esv1.PushSecretRemoteRef) error {
+	e, err := c.read(ctx, ref.GetRemoteKey())
+	if err != nil {
+		return err
+	}
+	if e.pair == nil || e.marker == nil {
+		return nil
+	}
+
+	if property := ref.GetProperty(); property != "" {
+		object, err := kvjson.Object(e.pair.Value)
+		if err != nil {
+			return err
+		}
+		if _, ok := object[property]; !ok {
+			return nil
+		}
+		delete(object, property)
+		if len(object) > 0 {
+			value, err := json.Marshal(object)
+			if err != nil {
+				return err
+			}
+			return c.commit(ctx, e, errWriteKey, clientv3.OpPut(e.key, string(value), clientv3.WithIgnoreLease()))
+		}
+	}
+
+	if err := c.commit(ctx, e, errDeleteKey, clientv3.OpDelete(e.key), clientv3.OpDelete(managedByKey(e.key))); err != nil {
+		return err
+	}
+	c.revoke(ctx, clientv3.LeaseID(e.pair.Lease))
+	return nil
+}
+
+// SecretExists checks if a key, or a property of its JSON value, exists.
+func (c *Client) SecretExists(ctx context.Context, ref esv1.PushSecretRemoteRef) (bool, error) {
+	pair, err := c.get(ctx, ref.GetRemoteKey())
+	if err != nil || pair == nil {
+		return false, err
+	}
+	if ref.GetProperty() == "" {
+		return true, nil
+	}
+	return gjson.GetBytes(pair.Value, ref.GetProperty()).Exists(), nil
+}
+
+// Validate checks that the keys starting with the prefix of the store can be read.
+func (c *Client) Validate() (esv1.ValidationResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), validateTimeout)
+	defer cancel()
+	if _, err := c.kv.Get(ctx, c.prefix, clientv3.WithPrefix(), clientv3.WithCountOnly()); err != nil {
+		return esv1.ValidationResultError, fmt.Errorf(errListKeys, c.prefix, err)
+	}
+	return esv1.ValidationResultReady, nil
+}
+
+// Close closes the connection to the cluster.
+func (c *Client) Close(_ context.Context) error {
+	if c.close == nil {
+		return nil
+	}
+	return c.close()
+}
+
+func (c *Client) key(key string) string {
+	if strings.HasSuffix(c.prefix, "/") {
+		key = strings.TrimPrefix(key, "/")
+	}
+	return c.prefix + key
+}
+
+func (c *Client) get(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
+	return c.getKey(ctx, c.key(key))
+}
+
+func (c *Client) getKey(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
+	resp, err := c.kv.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf(errGetKey, key, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	return resp.Kvs[0], nil
+}
+
+// entry is a key and its marker, as read before writing them.
+type entry struct {
+	key    string
+	pair   *mvccpb.KeyValue
+	marker *mvccpb.KeyValue
+}
+
+// read returns a key and its marker, which are nil if they do not exist.
+func (c *Client) read(ctx context.Context, key string) (entry, error) {
+	e := entry{key: c.key(key)}
+	var err error
+	if e.pair, err = c.getKey(ctx, e.key); err != nil {
+		return e, err
+	}
+	e.marker, err = c.getKey(ctx, managedByKey(e.key))
+	return e, err
+}
+
+// commit applies the operations if neither the key nor its marker were modified since they were read.
+func (c *Client) commit(ctx context.Context, e entry, errFormat string, ops ...clientv3.Op) error {
+	resp, err := c.kv.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(e.key), "=", modRevision(e.pair)),
+			clientv3.Compare(clientv3.ModRevision(managedByKey(e.key)), "=", modRevision(e.marker))).
+		Then(ops...).
+		Commit()
+	if err != nil {
+		return fmt.Errorf(errFormat, e.key, err)
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf(errTxnConflict, e.key)
+	}
+	return nil
+}
+
+// revoke revokes a lease no key is attached to anymore.
+// Errors are ignored, as a lease which is not revoked still expires after its ttl.
+func (c *Client) revoke(ctx context.Context, id clientv3.LeaseID) {
+	if id != clientv3.NoLease {
+		_, _ = c.lease.Revoke(ctx, id)
+	}
+}
+
+// managedByKey returns the key marking a key as written by external-secrets.
+func managedByKey(key string) string {
+	return managedByPrefix + "/" + strings.TrimPrefix(key, "/")
+}
+
+// modRevision returns the mod revision of a key, zero meaning that it does not exist.
+func modRevision(pair *mvccpb.KeyValue) int64 {
+	if pair == nil {
+		return 0
+	}
+	return pair.ModRevision
+}
+
+// leaseTTL returns the ttl of the metadata in seconds, rounded up, or zero when it is not set.
+func leaseTTL(data esv1.PushSecretData) (int64, error) {
+	meta, err := metadata.ParseMetadataParameters[PushSecretMetadataSpec](data.GetMetadata())
+	if err != nil {
+		return 0, fmt.Errorf(errParseMetadata, err)
+	}
+	if meta == nil || meta.Spec.TTL == nil {
+		return 0, nil
+	}
+	if meta.Spec.TTL.Duration <= 0 {
+		return 0, errInvalidTTL
+	}
+	return int64(math.Ceil(meta.Spec.TTL.Seconds())), nil
+}
@@ -0,0 +1,477 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils/kvjson"
+	testingfake "github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+// newTestEtcd starts an embedded single-member etcd cluster with the given keys,
+// and returns a client of the cluster and a Client with the prefix "/app/".
+func newTestEtcd(t *testing.T, values map[string]string) (*clientv3.Client, *Client) {
+	t.Helper()
+	local, err := url.Parse("http://127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	cfg.ListenClientUrls = []url.URL{*local}
+	cfg.AdvertiseClientUrls = []url.URL{*local}
+	cfg.ListenPeerUrls = []url.URL{*local}
+	cfg.AdvertisePeerUrls = []url.URL{*local}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(time.Minute):
+		t.Fatal("etcd did not start")
+	}
+
+	etcd, err := clientv3.New(clientv3.Config{Endpoints: []string{server.Clients[0].Addr().String()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = etcd.Close() })
+	for key, value := range values {
+		if _, err := etcd.Put(context.Background(), key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return etcd, &Client{kv: etcd, lease: etcd, prefix: "/app/"}
+}
+
+// getKey returns the key of the cluster, or nil if it does not exist.
+func getKey(t *testing.T, etcd *clientv3.Client, key string) *mvccpb.KeyValue {
+	t.Helper()
+	resp, err := etcd.Get(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil
+	}
+	return resp.Kvs[0]
+}
+
+// grantedTTL returns the ttl a lease was granted with, or -1 if it expired.
+func grantedTTL(t *testing.T, etcd *clientv3.Client, lease int64) int64 {
+	t.Helper()
+	resp, err := etcd.TimeToLive(context.Background(), clientv3.LeaseID(lease))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.TTL <= 0 {
+		return -1
+	}
+	return resp.GrantedTTL
+}
+
+func TestGetSecret(t *testing.T) {
+	_, c := newTestEtcd(t, map[string]string{
+		"/app/db":    `{"user": "admin", "port": 5432, "tls": {"enabled": true}}`,
+		"/app/plain": "value",
+		"/other/key": "outside of the prefix",
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		ref      esv1.ExternalSecretDataRemoteRef
+		want     string
+		notFound bool
+		wantErr  bool
+	}{
+		{name: "value", ref: esv1.ExternalSecretDataRemoteRef{Key: "plain"}, want: "value"},
+		{name: "leading slash", ref: esv1.ExternalSecretDataRemoteRef{Key: "/plain"}, want: "value"},
+		{name: "string property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "user"}, want: "admin"},
+		{name: "number property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "port"}, want: "5432"},
+		{name: "nested property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "tls.enabled"}, want: "true"},
+		{name: "missing property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "password"}, wantErr: true},
+		{name: "missing key", ref: esv1.ExternalSecretDataRemoteRef{Key: "missing"}, wantErr: true, notFound: true},
+		{name: "key outside of the prefix", ref: esv1.ExternalSecretDataRemoteRef{Key: "key"}, wantErr: true, notFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetSecret(ctx, tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				if tt.notFound && !errors.Is(err, esv1.NoSecretErr) {
+					t.Fatalf("expected NoSecretErr, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetSecretMap(t *testing.T) {
+	_, c := newTestEtcd(t, map[string]string{
+		"/app/db":    `{"user": "admin", "port": 5432, "tls": {"enabled": true}}`,
+		"/app/plain": "value",
+	})
+
+	got, err := c.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "db"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"user": "admin", "port": "5432", "tls": `{"enabled": true}`}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for key, value := range want {
+		if string(got[key]) != value {
+			t.Fatalf("expected %q for %q, got %q", value, key, got[key])
+		}
+	}
+
+	if _, err := c.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "plain"}); !errors.Is(err, kvjson.ErrNotAnObject) {
+		t.Fatalf("expected ErrNotAnObject, got %v", err)
+	}
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	_, c := newTestEtcd(t, map[string]string{
+		"/app/db/user":     "admin",
+		"/app/db/password": "s3cr3t",
+		"/app/cache/url":   "redis://cache",
+		"/other/db/user":   "root",
+	})
+	ctx := context.Background()
+
+	got, err := c.GetAllSecrets(ctx, esv1.ExternalSecretFind{Path: new("db/")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || string(got["db/user"]) != "admin" || string(got["db/password"]) != "s3cr3t" {
+		t.Fatalf("unexpected secrets: %q", got)
+	}
+
+	got, err = c.GetAllSecrets(ctx, esv1.ExternalSecretFind{Name: &esv1.FindName{RegExp: "url$"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || string(got["cache/url"]) != "redis://cache" {
+		t.Fatalf("unexpected secrets: %q", got)
+	}
+
+	// without a prefix, the whole keyspace is listed
+	c.prefix = ""
+	got, err = c.GetAllSecrets(ctx, esv1.ExternalSecretFind{Name: &esv1.FindName{RegExp: "db/user$"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || string(got["/other/db/user"]) != "root" {
+		t.Fatalf("unexpected secrets: %q", got)
+	}
+
+	if _, err := c.GetAllSecrets(ctx, esv1.ExternalSecretFind{Tags: map[string]string{"a": "b"}}); !errors.Is(err, errTagsNotSupported) {
+		t.Fatalf("expected errTagsNotSupported, got %v", err)
+	}
+}
+
+func TestPushAndDeleteSecret(t *testing.T) {
+	_, c := newTestEtcd(t, nil)
+	ctx := context.Background()
+	secret := &corev1.Secret{Data: map[string][]byte{"user": []byte("admin"), "password": []byte("s3cr3t")}}
+
+	get := func(key string) string {
+		t.Helper()
+		value, err := c.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: key})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(value)
+	}
+
+	// a secret key to a plain key
+	if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "user", RemoteKey: "user"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := get("user"); got != "admin" {
+		t.Fatalf("expected admin, got %q", got)
+	}
+
+	// secret keys to properties of the same key
+	for _, key := range []string{"user", "password"} {
+		if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: key, RemoteKey: "db", Property: key}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := get("db"); got != `{"password":"s3cr3t","user":"admin"}` {
+		t.Fatalf("unexpected value: %q", got)
+	}
+
+	// the whole secret as JSON
+	if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{RemoteKey: "all"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := get("all"); got != `{"password":"s3cr3t","user":"admin"}` {
+		t.Fatalf("unexpected value: %q", got)
+	}
+
+	exists, err := c.SecretExists(ctx, &testingfake.PushSecretData{RemoteKey: "db", Property: "password"})
+	if err != nil || !exists {
+		t.Fatalf("expected the property to exist, got %v, %v", exists, err)
+	}
+
+	// deleting a property keeps the other ones
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "db", Property: "password"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := get("db"); got != `{"user":"admin"}` {
+		t.Fatalf("unexpected value: %q", got)
+	}
+
+	// deleting the last property deletes the key
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "db", Property: "user"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exists, err = c.SecretExists(ctx, &testingfake.PushSecretData{RemoteKey: "db"})
+	if err != nil || exists {
+		t.Fatalf("expected the key to be deleted, got %v, %v", exists, err)
+	}
+
+	// deleting a missing key is a no-op
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "db"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPushSecretTTL(t *testing.T) {
+	etcd, c := newTestEtcd(t, nil)
+	ctx := context.Background()
+	secret := &corev1.Secret{Data: map[string][]byte{"token": []byte("t0k3n")}}
+	withTTL := func(ttl string) *apiextensionsv1.JSON {
+		if ttl == "" {
+			return nil
+		}
+		return &apiextensionsv1.JSON{Raw: []byte(`{"apiVersion": "kubernetes.external-secrets.io/v1alpha1", "kind": "PushSecretMetadata", "spec": {"ttl": "` + ttl + `"}}`)}
+	}
+	push := func(ttl string) *mvccpb.KeyValue {
+		t.Helper()
+		data := &testingfake.PushSecretData{SecretKey: "token", RemoteKey: "token", Metadata: withTTL(ttl)}
+		if err := c.PushSecret(ctx, secret, data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return getKey(t, etcd, "/app/token")
+	}
+
+	// the key is attached to a lease of the ttl, rounded up to the second
+	pair := push("90500ms")
+	lease := pair.Lease
+	if lease == 0 || grantedTTL(t, etcd, lease) != 91 {
+		t.Fatalf("expected a lease of 91s, got %d", pair.Lease)
+	}
+
+	// an unchanged value renews the lease without writing the key
+	revision := pair.ModRevision
+	pair = push("90500ms")
+	if pair.ModRevision != revision || pair.Lease != lease {
+		t.Fatalf("expected the lease to be renewed, got revision %d, lease %d", pair.ModRevision, pair.Lease)
+	}
+
+	// another ttl grants a new lease, and revokes the previous one
+	pair = push("1h")
+	if pair.Lease == lease || grantedTTL(t, etcd, pair.Lease) != 3600 {
+		t.Fatalf("expected a new lease of 1h, got %d", pair.Lease)
+	}
+	if grantedTTL(t, etcd, lease) != -1 {
+		t.Fatalf("expected lease %d to be revoked", lease)
+	}
+	if marker := getKey(t, etcd, managedByKey("/app/token")); marker == nil || marker.Lease != pair.Lease {
+		t.Fatalf("expected the marker to be attached to the lease of the key, got %v", marker)
+	}
+
+	// an expired lease deletes the key, which is pushed again with a new lease
+	if _, err := etcd.Revoke(ctx, clientv3.LeaseID(pair.Lease)); err != nil {
+		t.Fatal(err)
+	}
+	if getKey(t, etcd, "/app/token") != nil {
+		t.Fatal("expected the key to be deleted with its lease")
+	}
+	pair = push("1h")
+	if grantedTTL(t, etcd, pair.Lease) != 3600 {
+		t.Fatalf("expected a new lease, got %d", pair.Lease)
+	}
+
+	// deleting a property keeps the lease
+	if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "token", RemoteKey: "object", Property: "a", Metadata: withTTL("1h")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "token", RemoteKey: "object", Property: "b", Metadata: withTTL("1h")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	objectLease := getKey(t, etcd, "/app/object").Lease
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "object", Property: "a"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := getKey(t, etcd, "/app/object").Lease; got != objectLease {
+		t.Fatalf("expected lease %d to be kept, got %d", objectLease, got)
+	}
+
+	// removing the ttl detaches the key from its lease, which is revoked
+	lease = pair.Lease
+	if pair = push(""); pair.Lease != 0 {
+		t.Fatalf("expected no lease, got %d", pair.Lease)
+	}
+	if grantedTTL(t, etcd, lease) != -1 {
+		t.Fatalf("expected lease %d to be revoked", lease)
+	}
+
+	err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "token", RemoteKey: "token", Metadata: withTTL("0s")})
+	if !errors.Is(err, errInvalidTTL) {
+		t.Fatalf("expected errInvalidTTL, got %v", err)
+	}
+}
+
+func TestPushSecretConflict(t *testing.T) {
+	_, c := newTestEtcd(t, nil)
+	ctx := context.Background()
+
+	// the key is created between the read and the write of the push
+	err := c.commit(ctx, entry{key: "/app/user"}, errWriteKey, clientv3.OpPut("/app/user", "admin"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = c.commit(ctx, entry{key: "/app/user"}, errWriteKey, clientv3.OpPut("/app/user", "root"))
+	if err == nil || !strings.Contains(err.Error(), "modified concurrently") {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+}
+
+// racingKV writes a key between the read and the write of the client.
+type racingKV struct {
+	*clientv3.Client
+	race func()
+}
+
+func (r *racingKV) Txn(ctx context.Context) clientv3.Txn {
+	if r.race != nil {
+		r.race()
+		r.race = nil
+	}
+	return r.Client.Txn(ctx)
+}
+
+func TestPushSecretConflictRevokesLease(t *testing.T) {
+	etcd, c := newTestEtcd(t, nil)
+	ctx := context.Background()
+	c.kv = &racingKV{Client: etcd, race: func() {
+		if _, err := etcd.Put(ctx, "/app/token", "other"); err != nil {
+			t.Fatal(err)
+		}
+	}}
+
+	metadata := &apiextensionsv1.JSON{Raw: []byte(`{"apiVersion": "kubernetes.external-secrets.io/v1alpha1", "kind": "PushSecretMetadata", "spec": {"ttl": "1h"}}`)}
+	secret := &corev1.Secret{Data: map[string][]byte{"token": []byte("t0k3n")}}
+	err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "token", RemoteKey: "token", Metadata: metadata})
+	if err == nil || !strings.Contains(err.Error(), "modified concurrently") {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	leases, err := etcd.Leases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases.Leases) != 0 {
+		t.Fatalf("expected the granted lease to be revoked, got %v", leases.Leases)
+	}
+}
+
+func TestUnmanagedKeys(t *testing.T) {
+	etcd, c := newTestEtcd(t, map[string]string{"/app/db": `{"user": "admin"}`})
+	ctx := context.Background()
+	secret := &corev1.Secret{Data: map[string][]byte{"password": []byte("s3cr3t")}}
+
+	// a key written by someone else is never overwritten
+	err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "password", RemoteKey: "db", Property: "password"})
+	if err == nil || !strings.Contains(err.Error(), "not managed by external-secrets") {
+		t.Fatalf("expected an unmanaged key error, got %v", err)
+	}
+
+	// nor deleted
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "db"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pair := getKey(t, etcd, "/app/db"); pair == nil || string(pair.Value) != `{"user": "admin"}` {
+		t.Fatalf("expected the key to be kept, got %v", pair)
+	}
+
+	// a key written by external-secrets has a marker, which is deleted with it
+	if err := c.PushSecret(ctx, secret, &testingfake.PushSecretData{SecretKey: "password", RemoteKey: "password"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if getKey(t, etcd, managedByKey("/app/password")) == nil {
+		t.Fatal("expected the key to be marked as managed")
+	}
+
+	// markers are not listed as secrets
+	c.prefix = ""
+	got, err := c.GetAllSecrets(ctx, esv1.ExternalSecretFind{Name: &esv1.FindName{RegExp: "password"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || string(got["/app/password"]) != "s3cr3t" {
+		t.Fatalf("unexpected secrets: %q", got)
+	}
+	c.prefix = "/app/"
+
+	if err := c.DeleteSecret(ctx, &testingfake.PushSecretData{RemoteKey: "password"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if getKey(t, etcd, "/app/password") != nil || getKey(t, etcd, managedByKey("/app/password")) != nil {
+		t.Fatal("expected the key and its marker to be deleted")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	_, c := newTestEtcd(t, map[string]string{"/app/db": "value"})
+	result, err := c.Validate()
+	if err != nil || result != esv1.ValidationResultReady {
+		t.Fatalf("expected the store to be ready, got %v, %v", result, err)
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package etcd implements a provider for etcd v3 clusters.
+package etcd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+	"github.com/external-secrets/external-secrets/runtime/esutils/resolvers"
+)
+
+// dialTimeout bounds the time to connect to the cluster, and to authenticate when auth is set.
+const dialTimeout = 10 * time.Second
+
+var (
+	errMissingStore          = errors.New("missing store")
+	errMissingEtcdProvider   = errors.New("missing etcd provider configuration")
+	errMissingEndpoints      = errors.New("at least one endpoint is required")
+	errEmptyEndpoint         = errors.New("endpoints cannot contain an empty endpoint")
+	errMissingUsernameName   = errors.New("auth.usernameSecretRef.name cannot be empty")
+	errMissingPasswordName   = errors.New("auth.passwordSecretRef.name cannot be empty")
+	errInvalidClientTLS      = errors.New("tls.certSecretRef and tls.keySecretRef must be set together")
+	errInvalidCABundle       = errors.New("no certificate found in the ca bundle")
+	errCANamespaceRequired   = errors.New("caProvider.namespace is required for ClusterSecretStore")
+	errCANamespaceNotAllowed = errors.New("caProvider.namespace must be empty with SecretStore")
+)
+
+const (
+	errInvalidStore = "invalid store: %w"
+	errFetchCA      = "failed to read ca bundle: %w"
+	errClientCert   = "failed to load client certificate: %w"
+	errNewClient    = "unable to create etcd client: %w"
+)
+
+// Provider is an etcd v3 provider implementing esv1.Provider.
+type Provider struct{}
+
+var _ esv1.Provider = &Provider{}
+var _ esv1.SecretsClient = &Client{}
+
+// Capabilities returns the provider's supported capabilities.
+func (p *Provider) Capabilities() esv1.SecretStoreCapabilities {
+	return esv1.SecretStoreReadWrite
+}
+
+// NewClient creates a new etcd client with the provided store configuration.
+func (p *Provider) NewClient(ctx context.Context, store esv1.GenericStore, kube kclient.Client, namespace string) (esv1.SecretsClient, error) {
+	spec, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+
+	config := clientv3.Config{
+		Endpoints:   spec.Endpoints,
+		DialTimeout: dialTimeout,
+	}
+
+	if spec.Auth != nil {
+		config.Username, err = resolvers.SecretKeyRef(ctx, kube, storeKind, namespace, &spec.Auth.UsernameSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		config.Password, err = resolvers.SecretKeyRef(ctx, kube, storeKind, namespace, &spec.Auth.PasswordSecretRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config.TLS, err = tlsConfig(ctx, spec, kube, storeKind, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	etcd, err := clientv3.New(config)
+	if err != nil {
+		return nil, fmt.Errorf(errNewClient, err)
+	}
+
+	return &Client{
+		kv:     etcd.KV,
+		lease:  etcd.Lease,
+		close:  etcd.Close,
+		prefix: spec.Prefix,
+	}, nil
+}
+
+// tlsConfig returns the TLS configuration of the connection to the cluster,
+// or nil when the endpoints are plain HTTP and no certificate is configured.
+func tlsConfig(ctx context.Context, spec *esv1.EtcdProvider, kube kclient.Client, storeKind, namespace string) (*tls.Config, error) {
+	ca, err := esutils.FetchCACertFromSource(ctx, esutils.CreateCertOpts{
+		CABundle:   spec.CABundle,
+		CAProvider: spec.CAProvider,
+		StoreKind:  storeKind,
+		Namespace:  namespace,
+		Client:     kube,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errFetchCA, err)
+	}
+
+	clientTLS := spec.ClientTLS
+	secure := len(ca) > 0 || clientTLS.CertSecretRef != nil
+	for _, endpoint := range spec.Endpoints {
+		secure = secure || strings.HasPrefix(endpoint, "https://")
+	}
+	if !secure {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(ca) > 0 {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf(errFetchCA, errInvalidCABundle)
+		}
+	}
+
+	if clientTLS.CertSecretRef != nil && clientTLS.KeySecretRef != nil {
+		cert, err := secretKeyRefOrDefault(ctx, kube, storeKind, namespace, *clientTLS.CertSecretRef, corev1.TLSCertKey)
+		if err != nil {
+			return nil, err
+		}
+		key, err := secretKeyRefOrDefault(ctx, kube, storeKind, namespace, *clientTLS.KeySecretRef, corev1.TLSPrivateKeyKey)
+		if err != nil {
+			return nil, err
+		}
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf(errClientCert, err)
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+	return config, nil
+}
+
+// secretKeyRefOrDefault resolves a secret key selector, using defaultKey when the key is not set.
+func secretKeyRefOrDefault(ctx context.Context, kube kclient.Client, storeKind, namespace string, ref esmeta.SecretKeySelector, defaultKey string) (string, error) {
+	if ref.Key == "" {
+		ref.Key = defaultKey
+	}
+	return resolvers.SecretKeyRef(ctx, kube, storeKind, namespace, &ref)
+}
+
+// ValidateStore validates the etcd SecretStore configuration.
+func (p *Provider) ValidateStore(store esv1.GenericStore) (admission.Warnings, error) {
+	spec, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec.Endpoints) == 0 {
+		return nil, fmt.Errorf(errInvalidStore, errMissingEndpoints)
+	}
+	for _, endpoint := range spec.Endpoints {
+		if endpoint == "" {
+			return nil, fmt.Errorf(errInvalidStore, errEmptyEndpoint)
+		}
+	}
+
+	if spec.Auth != nil {
+		if spec.Auth.UsernameSecretRef.Name == "" {
+			return nil, fmt.Errorf(errInvalidStore, errMissingUsernameName)
+		}
+		if spec.Auth.PasswordSecretRef.Name == "" {
+			return nil, fmt.Errorf(errInvalidStore, errMissingPasswordName)
+		}
+		if err := esutils.ValidateSecretSelector(store, spec.Auth.UsernameSecretRef); err != nil {
+			return nil, fmt.Errorf(errInvalidStore, err)
+		}
+		if err := esutils.ValidateSecretSelector(store, spec.Auth.PasswordSecretRef); err != nil {
+			return nil, fmt.Errorf(errInvalidStore, err)
+		}
+	}
+
+	clientTLS := spec.ClientTLS
+	if (clientTLS.CertSecretRef == nil) != (clientTLS.KeySecretRef == nil) {
+		return nil, fmt.Errorf(errInvalidStore, errInvalidClientTLS)
+	}
+	if clientTLS.CertSecretRef != nil {
+		if err := esutils.ValidateSecretSelector(store, *clientTLS.CertSecretRef); err != nil {
+			return nil, fmt.Errorf(errInvalidStore, err)
+		}
+		if err := esutils.ValidateSecretSelector(store, *clientTLS.KeySecretRef); err != nil {
+			return nil, fmt.Errorf(errInvalidStore, err)
+		}
+	}
+
+	if err := validateCAProvider(store, spec.CAProvider); err != nil {
+		return nil, fmt.Errorf(errInvalidStore, err)
+	}
+	return nil, nil
+}
+
+func validateCAProvider(store esv1.GenericStore, caProvider *esv1.CAProvider) error {
+	if caProvider == nil {
+		return nil
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+	if storeKind == esv1.ClusterSecretStoreKind && caProvider.Namespace == nil {
+		return errCANamespaceRequired
+	}
+	if storeKind == esv1.SecretStoreKind && caProvider.Namespace != nil {
+		return errCANamespaceNotAllowed
+	}
+	return nil
+}
+
+func getProvider(store esv1.GenericStore) (*esv1.EtcdProvider, error) {
+	if store == nil {
+		return nil, errMissingStore
+	}
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Etcd == nil {
+		return nil, errMissingEtcdProvider
+	}
+	return spec.Provider.Etcd, nil
+}
+
+// NewProvider creates a new Provider instance.
+func NewProvider() esv1.Provider {
+	return &Provider{}
+}
+
+// ProviderSpec returns the provider specification for registration.
+func ProviderSpec() *esv1.SecretStoreProvider {
+	return &esv1.SecretStoreProvider{
+		Etcd: &esv1.EtcdProvider{},
+	}
+}
+
+// MaintenanceStatus returns the maintenance status of the provider.
+func MaintenanceStatus() esv1.MaintenanceStatus {
+	return esv1.MaintenanceStatusMaintained
+}
@@ -0,0 +1,219 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func makeStore(kind string, spec *esv1.EtcdProvider) esv1.GenericStore {
+	storeSpec := esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{Etcd: spec}}
+	if kind == esv1.ClusterSecretStoreKind {
+		return &esv1.ClusterSecretStore{
+			TypeMeta: metav1.TypeMeta{Kind: esv1.ClusterSecretStoreKind},
+			Spec:     storeSpec,
+		}
+	}
+	return &esv1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+		Spec:       storeSpec,
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	ns := "default"
+	endpoints := []string{"https://etcd-0.etcd:2379"}
+	tests := []struct {
+		name    string
+		kind    string
+		spec    *esv1.EtcdProvider
+		wantErr error
+	}{
+		{
+			name: "anonymous",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.EtcdProvider{Endpoints: []string{"http://etcd:2379"}},
+		},
+		{
+			name: "user and client certificate",
+			kind: esv1.ClusterSecretStoreKind,
+			spec: &esv1.EtcdProvider{
+				Endpoints: endpoints,
+				Auth: &esv1.EtcdAuth{
+					UsernameSecretRef: esmeta.SecretKeySelector{Name: "etcd", Key: "username", Namespace: &ns},
+					PasswordSecretRef: esmeta.SecretKeySelector{Name: "etcd", Key: "password", Namespace: &ns},
+				},
+				ClientTLS: esv1.EtcdClientTLS{
+					CertSecretRef: &esmeta.SecretKeySelector{Name: "etcd-client", Namespace: &ns},
+					KeySecretRef:  &esmeta.SecretKeySelector{Name: "etcd-client", Namespace: &ns},
+				},
+			},
+		},
+		{
+			name:    "missing provider",
+			kind:    esv1.SecretStoreKind,
+			wantErr: errMissingEtcdProvider,
+		},
+		{
+			name:    "missing endpoints",
+			kind:    esv1.SecretStoreKind,
+			spec:    &esv1.EtcdProvider{},
+			wantErr: errMissingEndpoints,
+		},
+		{
+			name:    "empty endpoint",
+			kind:    esv1.SecretStoreKind,
+			spec:    &esv1.EtcdProvider{Endpoints: []string{"http://etcd:2379", ""}},
+			wantErr: errEmptyEndpoint,
+		},
+		{
+			name: "password without name",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.EtcdProvider{
+				Endpoints: endpoints,
+				Auth: &esv1.EtcdAuth{
+					UsernameSecretRef: esmeta.SecretKeySelector{Name: "etcd", Key: "username"},
+					PasswordSecretRef: esmeta.SecretKeySelector{Key: "password"},
+				},
+			},
+			wantErr: errMissingPasswordName,
+		},
+		{
+			name: "client certificate without key",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.EtcdProvider{
+				Endpoints: endpoints,
+				ClientTLS: esv1.EtcdClientTLS{CertSecretRef: &esmeta.SecretKeySelector{Name: "etcd-client"}},
+			},
+			wantErr: errInvalidClientTLS,
+		},
+		{
+			name: "caProvider without namespace in a ClusterSecretStore",
+			kind: esv1.ClusterSecretStoreKind,
+			spec: &esv1.EtcdProvider{
+				Endpoints:  endpoints,
+				CAProvider: &esv1.CAProvider{Type: esv1.CAProviderTypeConfigMap, Name: "ca", Key: "ca.crt"},
+			},
+			wantErr: errCANamespaceRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&Provider{}).ValidateStore(makeStore(tt.kind, tt.spec))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	ctx := context.Background()
+	certPEM, keyPEM := selfSignedCert(t)
+	kube := fakeclient.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-client", Namespace: "default"},
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+	}).Build()
+
+	config, err := tlsConfig(ctx, &esv1.EtcdProvider{Endpoints: []string{"http://etcd:2379"}}, kube, esv1.SecretStoreKind, "default")
+	if err != nil || config != nil {
+		t.Fatalf("expected no TLS for plain endpoints, got %v, %v", config, err)
+	}
+
+	config, err = tlsConfig(ctx, &esv1.EtcdProvider{Endpoints: []string{"https://etcd:2379"}}, kube, esv1.SecretStoreKind, "default")
+	if err != nil || config == nil || len(config.Certificates) != 0 {
+		t.Fatalf("expected TLS without client certificate, got %v, %v", config, err)
+	}
+
+	// the keys of the client certificate default to the keys of a kubernetes.io/tls Secret
+	config, err = tlsConfig(ctx, &esv1.EtcdProvider{
+		Endpoints: []string{"etcd:2379"},
+		CABundle:  certPEM,
+		ClientTLS: esv1.EtcdClientTLS{
+			CertSecretRef: &esmeta.SecretKeySelector{Name: "etcd-client"},
+			KeySecretRef:  &esmeta.SecretKeySelector{Name: "etcd-client"},
+		},
+	}, kube, esv1.SecretStoreKind, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.Certificates) != 1 || config.RootCAs == nil {
+		t.Fatalf("expected a client certificate and a CA, got %+v", config)
+	}
+
+	_, err = tlsConfig(ctx, &esv1.EtcdProvider{Endpoints: []string{"etcd:2379"}, CABundle: []byte("not a certificate")}, kube, esv1.SecretStoreKind, "default")
+	if err == nil {
+		t.Fatal("expected an error for an invalid ca bundle")
+	}
+}
+
+func selfSignedCert(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "external-secrets"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		t.Fatal(err)
+	}
+	return certPEM, keyPEM
+}
@@ -22,9 +22,11 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"path"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
@@ -54,6 +56,7 @@ const (
 	CloudPlatformRole = "https://www.googleapis.com/auth/cloud-platform"
 
 	defaultVersion                  = "latest"
+	getSecretsConcurrency           = 10
 	errGCPSMStore                   = "received invalid GCPSM SecretStore resource"
 	errUnableGetCredentials         = "unable to get credentials: %w"
 	errClientClose                  = "unable to close SecretManager client: %w"
@@ -74,6 +77,9 @@ const (
 	managedByKey   = "managed-by"
 	managedByValue = "external-secrets"
 
+	// pushedVersionAnnotation records the version a conditional push was made against.
+	pushedVersionAnnotation = "external-secrets.io/pushed-version"
+
 	providerName               = "GCPSecretManager"
 	topicsKey                  = "topics"
 	globalSecretPath           = "projects/%s/secrets/%s"
@@ -161,6 +167,24 @@ func (c *Client) SecretExists(ctx context.Context, ref esv1.PushSecretRemoteRef)
 
 // PushSecret pushes a kubernetes secret key into gcp provider Secret.
 func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecretData esv1.PushSecretData) error {
+	_, err := c.pushSecret(ctx, secret, pushSecretData, nil)
+	return err
+}
+
+// PushSecretIfVersion pushes a secret like PushSecret, only if the latest version
+// of the secret is still version. Secret Manager has no conditional
+// AddSecretVersion, so the push first claims the secret with an etag-conditioned
+// update of its annotations, which fails if another conditional push or a metadata
+// update happened since the secret was read, then checks the latest version.
+// A version added directly between the check and the add is not detected.
+func (c *Client) PushSecretIfVersion(ctx context.Context, secret *corev1.Secret, pushSecretData esv1.PushSecretData, version esv1.SecretVersion) (esv1.SecretVersion, error) {
+	return c.pushSecret(ctx, secret, pushSecretData, &version)
+}
+
+// pushSecret pushes the secret, and returns the version added, or the latest
+// version if the value is unchanged. If expected is set, the push only happens
+// if the latest version of the secret is still expected.
+func (c *Client) pushSecret(ctx context.Context, secret *corev1.Secret, pushSecretData esv1.PushSecretData, expected *esv1.SecretVersion) (esv1.SecretVersion, error) {
 	var (
 		payload []byte
 		err     error
@@ -173,7 +197,7 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 		}
 		payload, err = esutils.JSONMarshal(secretStringVal)
 		if err != nil {
-			return fmt.Errorf("failed to serialize secret content as JSON: %w", err)
+			return esv1.SecretVersion{}, fmt.Errorf("failed to serialize secret content as JSON: %w", err)
 		}
 	} else {
 		payload = secret.Data[pushSecretData.GetSecretKey()]
@@ -186,7 +210,11 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 
 	if err != nil {
 		if status.Code(err) != codes.NotFound {
-			return err
+			return esv1.SecretVersion{}, err
+		}
+		if expected != nil {
+			// the secret was deleted since the expected version
+			return esv1.SecretVersion{}, esv1.VersionConflictErr
 		}
 
 		replication := &secretmanagerpb.Replication{
@@ -198,7 +226,7 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 		if pushSecretData.GetMetadata() != nil {
 			meta, err := metadata.ParseMetadataParameters[PushSecretMetadataSpec](pushSecretData.GetMetadata())
 			if err != nil {
-				return fmt.Errorf("failed to parse PushSecret metadata: %w", err)
+				return esv1.SecretVersion{}, fmt.Errorf("failed to parse PushSecret metadata: %w", err)
 			}
 			if meta != nil {
 				if r := buildReplication(meta.Spec); r != nil {
@@ -219,13 +247,13 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 
 		topics, err := esutils.FetchValueFromMetadata(topicsKey, pushSecretData.GetMetadata(), []any{})
 		if err != nil {
-			return fmt.Errorf("failed to fetch topics from metadata: %w", err)
+			return esv1.SecretVersion{}, fmt.Errorf("failed to fetch topics from metadata: %w", err)
 		}
 
 		for _, t := range topics {
 			name, ok := t.(string)
 			if !ok {
-				return fmt.Errorf("invalid topic type")
+				return esv1.SecretVersion{}, fmt.Errorf("invalid topic type")
 			}
 
 			scrt.Topics = append(scrt.Topics, &secretmanagerpb.Topic{
@@ -240,18 +268,28 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 		})
 		metrics.ObserveAPICall(constants.ProviderGCPSM, constants.CallGCPSMCreateSecret, err)
 		if err != nil {
-			return err
+			return esv1.SecretVersion{}, err
 		}
 	}
 
 	builder, err := newPushSecretBuilder(payload, pushSecretData)
 	if err != nil {
-		return err
+		return esv1.SecretVersion{}, err
 	}
 
 	annotations, labels, topics, err := builder.buildMetadata(gcpSecret.Annotations, gcpSecret.Labels, gcpSecret.Topics)
 	if err != nil {
-		return err
+		return esv1.SecretVersion{}, err
+	}
+	if expected != nil {
+		// claim the secret for the expected version: the update below is
+		// conditioned on the etag read above.
+		claimed := maps.Clone(annotations)
+		if claimed == nil {
+			claimed = map[string]string{}
+		}
+		claimed[pushedVersionAnnotation] = expected.ID
+		annotations = claimed
 	}
 
 	// Comparing with a pointer based slice doesn't work so we are converting
@@ -291,14 +329,17 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 			},
 		})
 		metrics.ObserveAPICall(constants.ProviderGCPSM, constants.CallGCPSMUpdateSecret, err)
+		if expected != nil && (status.Code(err) == codes.Aborted || status.Code(err) == codes.FailedPrecondition) {
+			return esv1.SecretVersion{}, esv1.VersionConflictErr
+		}
 		if err != nil {
-			return err
+			return esv1.SecretVersion{}, err
 		}
 	}
 
 	unlock, err := locks.TryLock(providerName, secretName)
 	if err != nil {
-		return err
+		return esv1.SecretVersion{}, err
 	}
 	defer unlock()
 
@@ -308,11 +349,18 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 	metrics.ObserveAPICall(constants.ProviderGCPSM, constants.CallGCPSMAccessSecretVersion, err)
 
 	if err != nil && status.Code(err) != codes.NotFound {
-		return err
+		return esv1.SecretVersion{}, err
+	}
+	var latest esv1.SecretVersion
+	if gcpVersion != nil {
+		latest.ID = path.Base(gcpVersion.Name)
+	}
+	if expected != nil && latest.ID != expected.ID {
+		return esv1.SecretVersion{}, esv1.VersionConflictErr
 	}
 
 	if gcpVersion != nil && gcpVersion.Payload != nil && !builder.needUpdate(gcpVersion.Payload.Data) {
-		return nil
+		return latest, nil
 	}
 
 	var original []byte
@@ -322,7 +370,7 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 
 	data, err := builder.buildData(original)
 	if err != nil {
-		return err
+		return esv1.SecretVersion{}, err
 	}
 
 	parent := getName(c.store.ProjectID, c.store.Location, pushSecretData.GetRemoteKey())
@@ -334,9 +382,12 @@ func (c *Client) PushSecret(ctx context.Context, secret *corev1.Secret, pushSecr
 		},
 	}
 
-	_, err = c.smClient.AddSecretVersion(ctx, addSecretVersionReq)
+	added, err := c.smClient.AddSecretVersion(ctx, addSecretVersionReq)
 	metrics.ObserveAPICall(constants.ProviderGCPSM, constants.CallGCPSMAddSecretVersion, err)
-	return err
+	if err != nil {
+		return esv1.SecretVersion{}, err
+	}
+	return esv1.SecretVersion{ID: path.Base(added.GetName())}, nil
 }
 
 // GetAllSecrets syncs multiple secrets from gcp provider into a single Kubernetes Secret.
@@ -477,13 +528,94 @@ func (c *Client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemot
 		return c.getSecretMetadata(ctx, ref)
 	}
 
-	version := ref.Version
+	result, err := c.accessSecretVersion(ctx, ref.Key, ref.Version)
+	if err != nil {
+		return nil, err
+	}
+	return payloadValue(result, ref)
+}
+
+// GetSecretVersion follows GetSecret, and returns the version of the value.
+// Metadata has no version.
+func (c *Client) GetSecretVersion(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, esv1.SecretVersion, error) {
+	if esutils.IsNil(c.smClient) || c.store.ProjectID == "" {
+		return nil, esv1.SecretVersion{}, errors.New(errUninitalizedGCPProvider)
+	}
+
+	if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
+		value, err := c.getSecretMetadata(ctx, ref)
+		return value, esv1.SecretVersion{}, err
+	}
+
+	result, err := c.accessSecretVersion(ctx, ref.Key, ref.Version)
+	if err != nil {
+		return nil, esv1.SecretVersion{}, err
+	}
+	value, err := payloadValue(result, ref)
+	if err != nil {
+		return nil, esv1.SecretVersion{}, err
+	}
+	return value, esv1.SecretVersion{ID: path.Base(result.Name)}, nil
+}
+
+// GetSecrets returns the secrets referenced by refs.
+// Secret Manager has no batch read API: refs pointing to the same secret
+// version share a single AccessSecretVersion call and distinct versions
+// are accessed concurrently.
+func (c *Client) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([]esv1.SecretResult, error) {
+	if esutils.IsNil(c.smClient) || c.store.ProjectID == "" {
+		return nil, errors.New(errUninitalizedGCPProvider)
+	}
+
+	type secretVersion struct {
+		key, version string
+	}
+	type accessResult struct {
+		res *secretmanagerpb.AccessSecretVersionResponse
+		err error
+	}
+	accessed := make(map[secretVersion]*accessResult)
+	for _, ref := range refs {
+		if ref.MetadataPolicy != esv1.ExternalSecretMetadataPolicyFetch {
+			accessed[secretVersion{ref.Key, ref.Version}] = &accessResult{}
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, getSecretsConcurrency)
+	for sv, ar := range accessed {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			ar.res, ar.err = c.accessSecretVersion(ctx, sv.key, sv.version)
+		})
+	}
+	wg.Wait()
+
+	results := make([]esv1.SecretResult, len(refs))
+	for i, ref := range refs {
+		if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
+			results[i].Value, results[i].Err = c.getSecretMetadata(ctx, ref)
+			continue
+		}
+		ar := accessed[secretVersion{ref.Key, ref.Version}]
+		if ar.err != nil {
+			results[i].Err = ar.err
+			continue
+		}
+		results[i].Value, results[i].Err = payloadValue(ar.res, ref)
+	}
+	return results, nil
+}
+
+func (c *Client) accessSecretVersion(ctx context.Context, key, version string) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	requestedVersion := version
 	if version == "" {
 		version = defaultVersion
 	}
-	name := fmt.Sprintf(globalSecretVersionsPath, c.store.ProjectID, ref.Key, version)
+	name := fmt.Sprintf(globalSecretVersionsPath, c.store.ProjectID, key, version)
 	if c.store.Location != "" {
-		name = fmt.Sprintf(regionalSecretVersionsPath, c.store.ProjectID, c.store.Location, ref.Key, version)
+		name = fmt.Sprintf(regionalSecretVersionsPath, c.store.ProjectID, c.store.Location, key, version)
 	}
 	req := &secretmanagerpb.AccessSecretVersionRequest{
 		Name: name,
@@ -491,13 +623,13 @@ func (c *Client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemot
 	result, err := c.smClient.AccessSecretVersion(ctx, req)
 	metrics.ObserveAPICall(constants.ProviderGCPSM, constants.CallGCPSMAccessSecretVersion, err)
 	if err != nil && c.store.SecretVersionSelectionPolicy == esv1.SecretVersionSelectionPolicyLatestOrFetch &&
-		ref.Version == "" && isErrSecretDestroyedOrDisabled(err) {
+		requestedVersion == "" && isErrSecretDestroyedOrDisabled(err) {
 		// if the secret is destroyed or disabled, and we are configured to get the latest enabled secret,
 		// we need to get the latest enabled secret
 		// Extract the secret name from the version name for ListSecretVersions
-		secretName := fmt.Sprintf(globalSecretPath, c.store.ProjectID, ref.Key)
+		secretName := fmt.Sprintf(globalSecretPath, c.store.ProjectID, key)
 		if c.store.Location != "" {
-			secretName = fmt.Sprintf(regionalSecretPath, c.store.ProjectID, c.store.Location, ref.Key)
+			secretName = fmt.Sprintf(regionalSecretPath, c.store.ProjectID, c.store.Location, key)
 		}
 		result, err = getLatestEnabledVersion(ctx, c.smClient, secretName)
 	}
@@ -505,7 +637,11 @@ func (c *Client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemot
 		err = parseError(err)
 		return nil, fmt.Errorf(errClientGetSecretAccess, err)
 	}
+	return result, nil
+}
 
+// payloadValue returns the payload of the secret version or the property referenced by ref.
+func payloadValue(result *secretmanagerpb.AccessSecretVersionResponse, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if ref.Property == "" {
 		if result.Payload.Data != nil {
 			return result.Payload.Data, nil
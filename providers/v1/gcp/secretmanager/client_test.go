@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -225,6 +226,50 @@ func TestSecretManagerGetSecret(t *testing.T) {
 	}
 }
 
+func TestSecretManagerGetSecrets(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		accessed []string
+	)
+	mc := &fakesm.MockSMClient{}
+	mc.WithAccessSecretVersionFn(func(_ context.Context, req *secretmanagerpb.AccessSecretVersionRequest, _ ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+		mu.Lock()
+		accessed = append(accessed, req.Name)
+		mu.Unlock()
+		if strings.Contains(req.Name, "/missing/") {
+			notFoundError, _ := apierror.FromError(status.Error(codes.NotFound, "failed"))
+			return nil, notFoundError
+		}
+		return &secretmanagerpb.AccessSecretVersionResponse{
+			Name:    req.Name,
+			Payload: &secretmanagerpb.SecretPayload{Data: []byte(`{"name":"` + req.Name + `"}`)},
+		}, nil
+	})
+	sm := Client{
+		smClient: mc,
+		store:    &esv1.GCPSMProvider{ProjectID: "default"},
+	}
+
+	results, err := sm.GetSecrets(t.Context(), []esv1.ExternalSecretDataRemoteRef{
+		{Key: "foo", Property: "name"},
+		{Key: "foo"},
+		{Key: "foo", Version: "2", Property: "name"},
+		{Key: "missing"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, "projects/default/secrets/foo/versions/latest", string(results[0].Value))
+	assert.Equal(t, `{"name":"projects/default/secrets/foo/versions/latest"}`, string(results[1].Value))
+	assert.Equal(t, "projects/default/secrets/foo/versions/2", string(results[2].Value))
+	assert.ErrorIs(t, results[3].Err, esv1.NoSecretErr)
+	assert.ElementsMatch(t, []string{
+		"projects/default/secrets/foo/versions/latest",
+		"projects/default/secrets/foo/versions/2",
+		"projects/default/secrets/missing/versions/latest",
+	}, accessed)
+}
+
 func TestGetSecretMetadataPolicyFetch(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -1012,6 +1057,129 @@ func TestPushSecret(t *testing.T) {
 	}
 }
 
+func TestPushSecretIfVersion(t *testing.T) {
+	secretKey := "secret-key"
+	secretName := "projects/default/secrets/bar"
+	managedSecret := &secretmanagerpb.Secret{
+		Name:   secretName,
+		Etag:   "etag-1",
+		Labels: map[string]string{managedByKey: managedByValue},
+	}
+
+	tests := map[string]struct {
+		getSecret     fakesm.SecretMockReturn
+		updateErr     error
+		latestVersion string
+		latestValue   string
+		expectedErr   error
+		expected      esv1.SecretVersion
+		expectUpdate  bool
+		expectAdd     bool
+	}{
+		"claims the secret and adds a version": {
+			getSecret:     fakesm.SecretMockReturn{Secret: managedSecret},
+			latestVersion: "3",
+			latestValue:   "old-value",
+			expected:      esv1.SecretVersion{ID: "4"},
+			expectUpdate:  true,
+			expectAdd:     true,
+		},
+		"conflicts when a newer version was added": {
+			getSecret:     fakesm.SecretMockReturn{Secret: managedSecret},
+			latestVersion: "4",
+			latestValue:   "old-value",
+			expectedErr:   esv1.VersionConflictErr,
+			expectUpdate:  true,
+		},
+		"conflicts when the claim is rejected": {
+			getSecret:     fakesm.SecretMockReturn{Secret: managedSecret},
+			updateErr:     status.Error(codes.Aborted, "etag mismatch"),
+			latestVersion: "3",
+			latestValue:   "old-value",
+			expectedErr:   esv1.VersionConflictErr,
+			expectUpdate:  true,
+		},
+		"conflicts when the secret was deleted": {
+			getSecret:   fakesm.SecretMockReturn{Err: status.Error(codes.NotFound, "failed to find a Secret")},
+			expectedErr: esv1.VersionConflictErr,
+		},
+		"keeps the expected version when the value is unchanged": {
+			getSecret:     fakesm.SecretMockReturn{Secret: managedSecret},
+			latestVersion: "3",
+			latestValue:   "new-value",
+			expected:      esv1.SecretVersion{ID: "3"},
+			expectUpdate:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				updates []*secretmanagerpb.UpdateSecretRequest
+				added   bool
+			)
+			mc := &fakesm.MockSMClient{}
+			mc.NewGetSecretFn(tc.getSecret)
+			mc.WithUpdateSecretFn(func(_ context.Context, req *secretmanagerpb.UpdateSecretRequest, _ ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+				updates = append(updates, req)
+				return req.Secret, tc.updateErr
+			})
+			mc.WithAccessSecretVersionFn(func(_ context.Context, req *secretmanagerpb.AccessSecretVersionRequest, _ ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+				return &secretmanagerpb.AccessSecretVersionResponse{
+					Name:    secretName + "/versions/" + tc.latestVersion,
+					Payload: &secretmanagerpb.SecretPayload{Data: []byte(tc.latestValue)},
+				}, nil
+			})
+			mc.AddSecretFn = func(_ context.Context, req *secretmanagerpb.AddSecretVersionRequest, _ ...gax.CallOption) (*secretmanagerpb.SecretVersion, error) {
+				added = true
+				assert.Equal(t, "new-value", string(req.Payload.Data))
+				return &secretmanagerpb.SecretVersion{Name: secretName + "/versions/4"}, nil
+			}
+			client := Client{
+				smClient: mc,
+				store:    &esv1.GCPSMProvider{ProjectID: "default"},
+			}
+
+			s := &corev1.Secret{Data: map[string][]byte{secretKey: []byte("new-value")}}
+			data := testingfake.PushSecretData{SecretKey: secretKey, RemoteKey: "bar"}
+			version, err := client.PushSecretIfVersion(t.Context(), s, data, esv1.SecretVersion{ID: "3"})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, version)
+			}
+			assert.Equal(t, tc.expectAdd, added)
+			if !tc.expectUpdate {
+				assert.Empty(t, updates)
+				return
+			}
+			require.Len(t, updates, 1)
+			assert.Equal(t, "etag-1", updates[0].Secret.Etag)
+			assert.Equal(t, "3", updates[0].Secret.Annotations[pushedVersionAnnotation])
+		})
+	}
+}
+
+func TestSecretManagerGetSecretVersion(t *testing.T) {
+	mc := &fakesm.MockSMClient{}
+	mc.WithAccessSecretVersionFn(func(_ context.Context, req *secretmanagerpb.AccessSecretVersionRequest, _ ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+		assert.Equal(t, "projects/default/secrets/foo/versions/latest", req.Name)
+		return &secretmanagerpb.AccessSecretVersionResponse{
+			Name:    "projects/123/secrets/foo/versions/7",
+			Payload: &secretmanagerpb.SecretPayload{Data: []byte(`{"name":"value"}`)},
+		}, nil
+	})
+	sm := Client{
+		smClient: mc,
+		store:    &esv1.GCPSMProvider{ProjectID: "default"},
+	}
+
+	value, version, err := sm.GetSecretVersion(t.Context(), esv1.ExternalSecretDataRemoteRef{Key: "foo", Property: "name"})
+	require.NoError(t, err)
+	assert.Equal(t, "value", string(value))
+	assert.Equal(t, esv1.SecretVersion{ID: "7"}, version)
+}
+
 func TestSecretExists(t *testing.T) {
 	tests := []struct {
 		name           string
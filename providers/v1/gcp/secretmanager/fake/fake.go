@@ -177,6 +177,11 @@ func (mc *MockSMClient) NewAccessSecretVersionFn(mock AccessSecretVersionMockRet
 	}
 }
 
+// WithAccessSecretVersionFn sets the function answering AccessSecretVersion calls.
+func (mc *MockSMClient) WithAccessSecretVersionFn(fn func(context.Context, *secretmanagerpb.AccessSecretVersionRequest, ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error)) {
+	mc.accessSecretFn = fn
+}
+
 func (mc *MockSMClient) ListSecrets(ctx context.Context, req *secretmanagerpb.ListSecretsRequest, _ ...gax.CallOption) *secretmanager.SecretIterator {
 	return mc.ListSecretsFn(ctx, req)
 }
@@ -289,6 +294,11 @@ func (mc *MockSMClient) NewUpdateSecretFn(mock SecretMockReturn) {
 	}
 }
 
+// WithUpdateSecretFn sets the function answering UpdateSecret calls.
+func (mc *MockSMClient) WithUpdateSecretFn(fn func(context.Context, *secretmanagerpb.UpdateSecretRequest, ...gax.CallOption) (*secretmanagerpb.Secret, error)) {
+	mc.updateSecretFn = fn
+}
+
 func (mc *MockSMClient) ListSecretVersions(ctx context.Context, req *secretmanagerpb.ListSecretVersionsRequest, _ ...gax.CallOption) *secretmanager.SecretVersionIterator {
 	return mc.ListSecretVersionsFn(ctx, req)
 }
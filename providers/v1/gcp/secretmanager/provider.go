@@ -38,6 +38,9 @@ type Provider struct{}
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1.SecretsClient = &Client{}
+var _ esv1.BatchSecretsClient = &Client{}
+var _ esv1.SecretVersionClient = &Client{}
+var _ esv1.ConditionalPusher = &Client{}
 var _ esv1.Provider = &Provider{}
 
 /*
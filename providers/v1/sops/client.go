@@ -0,0 +1,314 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/tidwall/gjson"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils/kvjson"
+	"github.com/external-secrets/external-secrets/runtime/find"
+)
+
+var (
+	errNotImplemented   = errors.New("not implemented")
+	errInvalidKey       = errors.New("key must be the name of a document")
+	errTagsNotSupported = errors.New("find.tags is not supported by the SOPS provider")
+	errNotAnObject      = errors.New("document or property is not an object")
+)
+
+const (
+	errGetSource        = "cannot get %s %q from namespace %q: %w"
+	errReadDir          = "cannot read directory %q: %w"
+	errDecryptDocument  = "cannot decrypt document %q: %w"
+	errConvertDocument  = "cannot convert document %q to JSON: %w"
+	errPropertyNotFound = "property %q not found in document %q"
+)
+
+// Client serves the documents of a SOPS store.
+// Documents are read and decrypted once per client.
+type Client struct {
+	kube      kclient.Client
+	store     *esv1.SopsProvider
+	namespace string
+	storeKind string
+	decrypt   func(data []byte, format formats.Format) ([]byte, error)
+
+	mu        sync.Mutex
+	documents map[string][]byte
+	plain     map[string][]byte
+}
+
+// GetSecret returns the cleartext of a document, or the value of a property of it.
+func (c *Client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if ref.Property == "" {
+		return c.document(ctx, ref.Key)
+	}
+
+	doc, err := c.jsonDocument(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	result := gjson.GetBytes(doc, ref.Property)
+	if !result.Exists() {
+		return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+	}
+	return kvjson.ResultBytes(result), nil
+}
+
+// GetSecretMap returns the top-level keys of a document, or of a property of it.
+// Values which are not strings are returned as JSON.
+func (c *Client) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	doc, err := c.jsonDocument(ctx, ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	result := gjson.ParseBytes(doc)
+	if ref.Property != "" {
+		result = result.Get(ref.Property)
+		if !result.Exists() {
+			return nil, fmt.Errorf(errPropertyNotFound, ref.Property, ref.Key)
+		}
+	}
+	if !result.IsObject() {
+		return nil, errNotAnObject
+	}
+
+	secretMap := make(map[string][]byte)
+	result.ForEach(func(key, value gjson.Result) bool {
+		secretMap[key.String()] = kvjson.ResultBytes(value)
+		return true
+	})
+	return secretMap, nil
+}
+
+// GetAllSecrets returns the cleartext of every document whose name matches find.name or find.path.
+func (c *Client) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (map[string][]byte, error) {
+	if len(ref.Tags) > 0 {
+		return nil, errTagsNotSupported
+	}
+
+	var matcher *find.Matcher
+	if ref.Name != nil {
+		m, err := find.New(*ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		matcher = m
+	}
+
+	documents, err := c.loadDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string][]byte)
+	for name := range documents {
+		if (matcher != nil && !matcher.MatchName(name)) || (ref.Path != nil && !strings.HasPrefix(name, *ref.Path)) {
+			continue
+		}
+		plain, err := c.document(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		secrets[name] = plain
+	}
+	return secrets, nil
+}
+
+// PushSecret is not supported, the documents are read-only.
+func (c *Client) PushSecret(_ context.Context, _ *corev1.Secret, _ esv1.PushSecretData) error {
+	return errNotImplemented
+}
+
+// DeleteSecret is not supported, the documents are read-only.
+func (c *Client) DeleteSecret(_ context.Context, _ esv1.PushSecretRemoteRef) error {
+	return errNotImplemented
+}
+
+// SecretExists is not supported, the documents are read-only.
+func (c *Client) SecretExists(_ context.Context, _ esv1.PushSecretRemoteRef) (bool, error) {
+	return false, errNotImplemented
+}
+
+// Validate checks that the documents of the store can be read.
+func (c *Client) Validate() (esv1.ValidationResult, error) {
+	if _, err := c.loadDocuments(context.Background()); err != nil {
+		return esv1.ValidationResultError, err
+	}
+	return esv1.ValidationResultReady, nil
+}
+
+// Close implements cleanup operations for the SOPS client.
+func (c *Client) Close(_ context.Context) error {
+	return nil
+}
+
+// document returns the decrypted document in its own format.
+func (c *Client) document(ctx context.Context, name string) ([]byte, error) {
+	if !validDocumentName(name) {
+		return nil, errInvalidKey
+	}
+	documents, err := c.loadDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if plain, ok := c.plain[name]; ok {
+		return plain, nil
+	}
+	data, ok := documents[name]
+	if !ok {
+		return nil, esv1.NoSecretErr
+	}
+	plain, err := c.decrypt(data, documentFormat(c.store.Format, name))
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptDocument, name, err)
+	}
+	if c.plain == nil {
+		c.plain = make(map[string][]byte)
+	}
+	c.plain[name] = plain
+	return plain, nil
+}
+
+// jsonDocument returns the decrypted document as JSON.
+func (c *Client) jsonDocument(ctx context.Context, name string) ([]byte, error) {
+	plain, err := c.document(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := toJSON(plain, documentFormat(c.store.Format, name))
+	if err != nil {
+		return nil, fmt.Errorf(errConvertDocument, name, err)
+	}
+	return doc, nil
+}
+
+// loadDocuments returns the encrypted documents of the store by name.
+func (c *Client) loadDocuments(ctx context.Context) (map[string][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.documents != nil {
+		return c.documents, nil
+	}
+
+	var (
+		documents map[string][]byte
+		err       error
+	)
+	switch {
+	case c.store.ConfigMap != nil:
+		documents, err = c.configMapDocuments(ctx)
+	case c.store.Secret != nil:
+		documents, err = c.secretDocuments(ctx)
+	case c.store.Path != "":
+		documents, err = readDirDocuments(c.store.Path)
+	default:
+		err = errSourceCount
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.documents = documents
+	return documents, nil
+}
+
+func (c *Client) configMapDocuments(ctx context.Context) (map[string][]byte, error) {
+	key := c.objectKey(c.store.ConfigMap)
+	cm := &corev1.ConfigMap{}
+	if err := c.kube.Get(ctx, key, cm); err != nil {
+		return nil, fmt.Errorf(errGetSource, "ConfigMap", key.Name, key.Namespace, err)
+	}
+	documents := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for name, value := range cm.Data {
+		documents[name] = []byte(value)
+	}
+	for name, value := range cm.BinaryData {
+		documents[name] = value
+	}
+	return documents, nil
+}
+
+func (c *Client) secretDocuments(ctx context.Context) (map[string][]byte, error) {
+	key := c.objectKey(c.store.Secret)
+	secret := &corev1.Secret{}
+	if err := c.kube.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf(errGetSource, "Secret", key.Name, key.Namespace, err)
+	}
+	documents := make(map[string][]byte, len(secret.Data))
+	for name, value := range secret.Data {
+		documents[name] = value
+	}
+	return documents, nil
+}
+
+// objectKey returns the key of the source object.
+// Only a ClusterSecretStore may read it from another namespace.
+func (c *Client) objectKey(ref *esv1.SopsObjectReference) types.NamespacedName {
+	key := types.NamespacedName{Name: ref.Name, Namespace: c.namespace}
+	if c.storeKind == esv1.ClusterSecretStoreKind && ref.Namespace != nil {
+		key.Namespace = *ref.Namespace
+	}
+	return key
+}
+
+// readDirDocuments reads the regular files of a directory.
+// Hidden files are skipped, like the "..data" links of a mounted ConfigMap.
+func readDirDocuments(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf(errReadDir, dir, err)
+	}
+	documents := make(map[string][]byte)
+	for _, entry := range entries {
+		if !validDocumentName(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		// mounted files are symlinks, so the target is checked
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(errReadDir, dir, err)
+		}
+		documents[entry.Name()] = data
+	}
+	return documents, nil
+}
+
+// validDocumentName rejects names which could escape the directory of the documents.
+func validDocumentName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, ".") && !strings.ContainsAny(name, `/\`)
+}
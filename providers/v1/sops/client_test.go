@@ -0,0 +1,190 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// plainDecrypt stands in for the keyring, so tests can use cleartext documents.
+func plainDecrypt(data []byte, _ formats.Format) ([]byte, error) {
+	return data, nil
+}
+
+func newTestClient(t *testing.T, objs ...*corev1.ConfigMap) *Client {
+	t.Helper()
+	builder := fake.NewClientBuilder()
+	for _, obj := range objs {
+		builder = builder.WithObjects(obj)
+	}
+	return &Client{
+		kube:      builder.Build(),
+		store:     &esv1.SopsProvider{ConfigMap: &esv1.SopsObjectReference{Name: "docs"}, Format: esv1.SopsFormatAuto},
+		namespace: "default",
+		storeKind: esv1.SecretStoreKind,
+		decrypt:   plainDecrypt,
+	}
+}
+
+func docsConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "docs", Namespace: "default"},
+		Data: map[string]string{
+			"db.yaml":  "user: admin\npassword: s3cr3t\nreplicas:\n  - a\n  - b\n",
+			"api.json": `{"token": "abc", "nested": {"id": 1}}`,
+			"app.env":  "LOG_LEVEL=debug\n",
+		},
+	}
+}
+
+func TestGetSecret(t *testing.T) {
+	c := newTestClient(t, docsConfigMap())
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		ref      esv1.ExternalSecretDataRemoteRef
+		want     string
+		wantErr  bool
+		notFound bool
+	}{
+		{name: "document", ref: esv1.ExternalSecretDataRemoteRef{Key: "app.env"}, want: "LOG_LEVEL=debug\n"},
+		{name: "yaml property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db.yaml", Property: "password"}, want: "s3cr3t"},
+		{name: "nested json property", ref: esv1.ExternalSecretDataRemoteRef{Key: "api.json", Property: "nested.id"}, want: "1"},
+		{name: "dotenv property", ref: esv1.ExternalSecretDataRemoteRef{Key: "app.env", Property: "LOG_LEVEL"}, want: "debug"},
+		{name: "missing property", ref: esv1.ExternalSecretDataRemoteRef{Key: "db.yaml", Property: "missing"}, wantErr: true},
+		{name: "missing document", ref: esv1.ExternalSecretDataRemoteRef{Key: "missing.yaml"}, wantErr: true, notFound: true},
+		{name: "path traversal", ref: esv1.ExternalSecretDataRemoteRef{Key: "../etc/passwd"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetSecret(ctx, tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				if tt.notFound && !errors.Is(err, esv1.NoSecretErr) {
+					t.Fatalf("expected NoSecretErr, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetSecretMap(t *testing.T) {
+	c := newTestClient(t, docsConfigMap())
+
+	got, err := c.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "db.yaml"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got["user"]) != "admin" || string(got["password"]) != "s3cr3t" || string(got["replicas"]) == "" {
+		t.Fatalf("unexpected secret map: %q", got)
+	}
+
+	got, err = c.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "api.json", Property: "nested"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || string(got["id"]) != "1" {
+		t.Fatalf("unexpected secret map: %q", got)
+	}
+
+	if _, err := c.GetSecretMap(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "db.yaml", Property: "user"}); !errors.Is(err, errNotAnObject) {
+		t.Fatalf("expected errNotAnObject, got %v", err)
+	}
+}
+
+func TestGetAllSecrets(t *testing.T) {
+	c := newTestClient(t, docsConfigMap())
+
+	got, err := c.GetAllSecrets(context.Background(), esv1.ExternalSecretFind{Name: &esv1.FindName{RegExp: `\.(yaml|json)$`}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got["db.yaml"] == nil || got["api.json"] == nil {
+		t.Fatalf("unexpected secrets: %q", got)
+	}
+
+	if _, err := c.GetAllSecrets(context.Background(), esv1.ExternalSecretFind{Tags: map[string]string{"a": "b"}}); !errors.Is(err, errTagsNotSupported) {
+		t.Fatalf("expected errTagsNotSupported, got %v", err)
+	}
+}
+
+func TestReadDirDocuments(t *testing.T) {
+	dir := t.TempDir()
+	// mimic the layout of a mounted ConfigMap
+	data := filepath.Join(dir, "..2026_01_01")
+	if err := os.Mkdir(data, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(data, "db.yaml"), []byte("user: admin\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(data, filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "db.yaml"), filepath.Join(dir, "db.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readDirDocuments(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || string(got["db.yaml"]) != "user: admin\n" {
+		t.Fatalf("unexpected documents: %q", got)
+	}
+}
+
+func TestDocumentFormat(t *testing.T) {
+	tests := []struct {
+		format esv1.SopsFormat
+		name   string
+		want   formats.Format
+	}{
+		{format: esv1.SopsFormatAuto, name: "db.json", want: formats.Json},
+		{format: esv1.SopsFormatAuto, name: "app.env", want: formats.Dotenv},
+		{format: esv1.SopsFormatAuto, name: "app.ini", want: formats.Ini},
+		{format: esv1.SopsFormatAuto, name: "db", want: formats.Yaml},
+		{format: "", name: "db.json", want: formats.Json},
+		{format: esv1.SopsFormatJSON, name: "db", want: formats.Json},
+	}
+	for _, tt := range tests {
+		if got := documentFormat(tt.format, tt.name); got != tt.want {
+			t.Errorf("documentFormat(%q, %q) = %v, want %v", tt.format, tt.name, got, tt.want)
+		}
+	}
+}
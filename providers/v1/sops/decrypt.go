@@ -0,0 +1,264 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgparmor "github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	sopsage "github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/config"
+	sopspgp "github.com/getsops/sops/v3/pgp"
+	"github.com/getsops/sops/v3/shamir"
+	"github.com/getsops/sops/v3/stores/dotenv"
+	"github.com/getsops/sops/v3/stores/ini"
+	"github.com/getsops/sops/v3/stores/json"
+	"github.com/getsops/sops/v3/stores/yaml"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils/resolvers"
+)
+
+var (
+	errNoMatchingKey = errors.New("none of the configured keys can decrypt the data key")
+	errMACMismatch   = errors.New("failed to verify the integrity of the document")
+)
+
+const (
+	errParseAgeKey   = "cannot parse age identities of secret %q: %w"
+	errParsePGPKey   = "cannot parse pgp key of secret %q: %w"
+	errLoadDocument  = "cannot load SOPS document: %w"
+	errDecryptTree   = "cannot decrypt SOPS document: %w"
+	errShamirParts   = "only %d of %d key groups could be decrypted"
+	errCombineShamir = "cannot combine the key groups: %w"
+)
+
+// store is the subset of the SOPS stores used to read documents.
+type store interface {
+	LoadEncryptedFile(in []byte) (sops.Tree, error)
+	LoadPlainFile(in []byte) (sops.TreeBranches, error)
+	EmitPlainFile(in sops.TreeBranches) ([]byte, error)
+}
+
+func storeFor(format formats.Format) store {
+	switch format {
+	case formats.Json:
+		return json.NewStore(&config.JSONStoreConfig{})
+	case formats.Dotenv:
+		return dotenv.NewStore(&config.DotenvStoreConfig{})
+	case formats.Ini:
+		return ini.NewStore(&config.INIStoreConfig{})
+	default:
+		return yaml.NewStore(&config.YAMLStoreConfig{})
+	}
+}
+
+// documentFormat returns the format of a document,
+// detecting it from the extension of its name for esv1.SopsFormatAuto.
+func documentFormat(format esv1.SopsFormat, name string) formats.Format {
+	switch format {
+	case esv1.SopsFormatYAML:
+		return formats.Yaml
+	case esv1.SopsFormatJSON:
+		return formats.Json
+	case esv1.SopsFormatDotenv:
+		return formats.Dotenv
+	case esv1.SopsFormatINI:
+		return formats.Ini
+	case esv1.SopsFormatAuto:
+	}
+
+	switch {
+	case formats.IsJSONFile(name):
+		return formats.Json
+	case formats.IsEnvFile(name):
+		return formats.Dotenv
+	case formats.IsIniFile(name):
+		return formats.Ini
+	default:
+		return formats.Yaml
+	}
+}
+
+// keyring holds the private keys of a store.
+type keyring struct {
+	age []age.Identity
+	pgp openpgp.EntityList
+}
+
+func loadKeys(ctx context.Context, kube kclient.Client, storeKind, namespace string, auth esv1.SopsAuth) (*keyring, error) {
+	keys := &keyring{}
+	for i := range auth.Age {
+		value, err := resolvers.SecretKeyRef(ctx, kube, storeKind, namespace, &auth.Age[i])
+		if err != nil {
+			return nil, err
+		}
+		identities, err := age.ParseIdentities(strings.NewReader(value))
+		if err != nil {
+			return nil, fmt.Errorf(errParseAgeKey, auth.Age[i].Name, err)
+		}
+		keys.age = append(keys.age, identities...)
+	}
+	for i := range auth.PGP {
+		value, err := resolvers.SecretKeyRef(ctx, kube, storeKind, namespace, &auth.PGP[i])
+		if err != nil {
+			return nil, err
+		}
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(value))
+		if err != nil {
+			return nil, fmt.Errorf(errParsePGPKey, auth.PGP[i].Name, err)
+		}
+		keys.pgp = append(keys.pgp, entities...)
+	}
+	return keys, nil
+}
+
+// decrypt decrypts a SOPS document and returns its cleartext in the same format.
+// The integrity of the document is verified against its MAC.
+func (k *keyring) decrypt(data []byte, format formats.Format) ([]byte, error) {
+	s := storeFor(format)
+	tree, err := s.LoadEncryptedFile(data)
+	if err != nil {
+		return nil, fmt.Errorf(errLoadDocument, err)
+	}
+
+	dataKey, err := k.dataKey(tree.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher := aes.NewCipher()
+	mac, err := tree.Decrypt(dataKey, cipher)
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptTree, err)
+	}
+	originalMAC, err := cipher.Decrypt(tree.Metadata.MessageAuthenticationCode, dataKey, tree.Metadata.LastModified.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf(errDecryptTree, err)
+	}
+	if originalMAC != mac {
+		return nil, errMACMismatch
+	}
+
+	return s.EmitPlainFile(tree.Branches)
+}
+
+// dataKey decrypts the data key of a document.
+// With several key groups, the data key is split with Shamir's secret sharing
+// and every group contributes a part.
+func (k *keyring) dataKey(metadata sops.Metadata) ([]byte, error) {
+	var parts [][]byte
+	for _, group := range metadata.KeyGroups {
+		if part, ok := k.decryptGroup(group); ok {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(metadata.KeyGroups) <= 1 {
+		if len(parts) == 0 {
+			return nil, errNoMatchingKey
+		}
+		return parts[0], nil
+	}
+
+	threshold := metadata.ShamirThreshold
+	if threshold == 0 {
+		threshold = len(metadata.KeyGroups)
+	}
+	if len(parts) < threshold {
+		return nil, fmt.Errorf(errShamirParts, len(parts), threshold)
+	}
+	dataKey, err := shamir.Combine(parts)
+	if err != nil {
+		return nil, fmt.Errorf(errCombineShamir, err)
+	}
+	return dataKey, nil
+}
+
+// decryptGroup returns the part of the data key held by the first key of the group
+// which can be decrypted with the keyring.
+func (k *keyring) decryptGroup(group sops.KeyGroup) ([]byte, bool) {
+	for _, key := range group {
+		var (
+			part []byte
+			err  error
+		)
+		switch key := key.(type) {
+		case *sopsage.MasterKey:
+			part, err = k.decryptAge(key.EncryptedKey)
+		case *sopspgp.MasterKey:
+			part, err = k.decryptPGP(key.EncryptedKey)
+		default:
+			continue
+		}
+		if err == nil {
+			return part, true
+		}
+	}
+	return nil, false
+}
+
+func (k *keyring) decryptAge(encryptedKey string) ([]byte, error) {
+	if len(k.age) == 0 {
+		return nil, errNoMatchingKey
+	}
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(encryptedKey)), k.age...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func (k *keyring) decryptPGP(encryptedKey string) ([]byte, error) {
+	if len(k.pgp) == 0 {
+		return nil, errNoMatchingKey
+	}
+	block, err := pgparmor.Decode(bytes.NewReader([]byte(encryptedKey)))
+	if err != nil {
+		return nil, err
+	}
+	md, err := openpgp.ReadMessage(block.Body, k.pgp, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(md.UnverifiedBody)
+}
+
+// toJSON converts the cleartext of a document to JSON.
+func toJSON(plain []byte, format formats.Format) ([]byte, error) {
+	if format == formats.Json {
+		return plain, nil
+	}
+	branches, err := storeFor(format).LoadPlainFile(plain)
+	if err != nil {
+		return nil, err
+	}
+	return storeFor(formats.Json).EmitPlainFile(branches)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sops implements a provider reading SOPS-encrypted documents
+// from a ConfigMap, a Secret or a directory of the controller.
+package sops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+var (
+	errMissingStore        = errors.New("missing store")
+	errMissingSopsProvider = errors.New("missing SOPS provider configuration")
+	errSourceCount         = errors.New("exactly one of configMap, secret or path must be specified")
+	errMissingSourceName   = errors.New("name of the source object cannot be empty")
+	errSourceNamespace     = errors.New("cluster scope requires the namespace of the source object")
+	errPathNotAllowed      = errors.New("path is only allowed in a ClusterSecretStore")
+	errPathNotAbsolute     = errors.New("path must be absolute")
+	errMissingKeys         = errors.New("at least one age or pgp key is required")
+	errKeyNameEmpty        = errors.New("name of the key secret cannot be empty")
+	errInvalidStore        = "invalid store: %w"
+)
+
+// Provider reads SOPS-encrypted documents. It implements esv1.Provider.
+type Provider struct{}
+
+var _ esv1.Provider = &Provider{}
+var _ esv1.SecretsClient = &Client{}
+
+// Capabilities returns the provider's supported capabilities.
+func (p *Provider) Capabilities() esv1.SecretStoreCapabilities {
+	return esv1.SecretStoreReadOnly
+}
+
+// NewClient resolves the decryption keys of the store and returns a client reading its documents.
+func (p *Provider) NewClient(ctx context.Context, store esv1.GenericStore, kube kclient.Client, namespace string) (esv1.SecretsClient, error) {
+	spec, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	storeKind := store.GetObjectKind().GroupVersionKind().Kind
+
+	keys, err := loadKeys(ctx, kube, storeKind, namespace, spec.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		kube:      kube,
+		store:     spec,
+		namespace: namespace,
+		storeKind: storeKind,
+		decrypt:   keys.decrypt,
+	}, nil
+}
+
+// ValidateStore validates the SOPS SecretStore configuration.
+func (p *Provider) ValidateStore(store esv1.GenericStore) (admission.Warnings, error) {
+	spec, err := getProvider(store)
+	if err != nil {
+		return nil, err
+	}
+	clusterScope := store.GetObjectKind().GroupVersionKind().Kind == esv1.ClusterSecretStoreKind
+
+	sources := 0
+	for _, ref := range []*esv1.SopsObjectReference{spec.ConfigMap, spec.Secret} {
+		if ref == nil {
+			continue
+		}
+		sources++
+		if ref.Name == "" {
+			return nil, fmt.Errorf(errInvalidStore, errMissingSourceName)
+		}
+		if clusterScope && ref.Namespace == nil {
+			return nil, fmt.Errorf(errInvalidStore, errSourceNamespace)
+		}
+	}
+	if spec.Path != "" {
+		sources++
+		if !clusterScope {
+			return nil, fmt.Errorf(errInvalidStore, errPathNotAllowed)
+		}
+		if !filepath.IsAbs(spec.Path) {
+			return nil, fmt.Errorf(errInvalidStore, errPathNotAbsolute)
+		}
+	}
+	if sources != 1 {
+		return nil, fmt.Errorf(errInvalidStore, errSourceCount)
+	}
+
+	if len(spec.Auth.Age)+len(spec.Auth.PGP) == 0 {
+		return nil, fmt.Errorf(errInvalidStore, errMissingKeys)
+	}
+	for _, ref := range slices.Concat(spec.Auth.Age, spec.Auth.PGP) {
+		if ref.Name == "" {
+			return nil, fmt.Errorf(errInvalidStore, errKeyNameEmpty)
+		}
+		if err := esutils.ValidateSecretSelector(store, ref); err != nil {
+			return nil, fmt.Errorf(errInvalidStore, err)
+		}
+	}
+
+	return nil, nil
+}
+
+func getProvider(store esv1.GenericStore) (*esv1.SopsProvider, error) {
+	if store == nil {
+		return nil, errMissingStore
+	}
+	spec := store.GetSpec()
+	if spec == nil || spec.Provider == nil || spec.Provider.Sops == nil {
+		return nil, errMissingSopsProvider
+	}
+	return spec.Provider.Sops, nil
+}
+
+// NewProvider creates a new Provider instance.
+func NewProvider() esv1.Provider {
+	return &Provider{}
+}
+
+// ProviderSpec returns the provider specification for registration.
+func ProviderSpec() *esv1.SecretStoreProvider {
+	return &esv1.SecretStoreProvider{
+		Sops: &esv1.SopsProvider{},
+	}
+}
+
+// MaintenanceStatus returns the maintenance status of the provider.
+func MaintenanceStatus() esv1.MaintenanceStatus {
+	return esv1.MaintenanceStatusMaintained
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sops
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+func makeStore(kind string, spec *esv1.SopsProvider) esv1.GenericStore {
+	storeSpec := esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{Sops: spec}}
+	if kind == esv1.ClusterSecretStoreKind {
+		return &esv1.ClusterSecretStore{
+			TypeMeta: metav1.TypeMeta{Kind: esv1.ClusterSecretStoreKind},
+			Spec:     storeSpec,
+		}
+	}
+	return &esv1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+		Spec:       storeSpec,
+	}
+}
+
+func TestValidateStore(t *testing.T) {
+	ns := "default"
+	ageKey := []esmeta.SecretKeySelector{{Name: "sops-keys", Key: "age"}}
+	clusterKey := []esmeta.SecretKeySelector{{Name: "sops-keys", Key: "age", Namespace: &ns}}
+
+	tests := []struct {
+		name    string
+		kind    string
+		spec    *esv1.SopsProvider
+		wantErr error
+	}{
+		{
+			name: "configMap",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.SopsProvider{
+				ConfigMap: &esv1.SopsObjectReference{Name: "docs"},
+				Auth:      esv1.SopsAuth{Age: ageKey},
+			},
+		},
+		{
+			name: "path in a ClusterSecretStore",
+			kind: esv1.ClusterSecretStoreKind,
+			spec: &esv1.SopsProvider{
+				Path: "/etc/sops",
+				Auth: esv1.SopsAuth{PGP: clusterKey},
+			},
+		},
+		{
+			name:    "missing provider",
+			kind:    esv1.SecretStoreKind,
+			wantErr: errMissingSopsProvider,
+		},
+		{
+			name: "no source",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.SopsProvider{
+				Auth: esv1.SopsAuth{Age: ageKey},
+			},
+			wantErr: errSourceCount,
+		},
+		{
+			name: "several sources",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.SopsProvider{
+				ConfigMap: &esv1.SopsObjectReference{Name: "docs"},
+				Secret:    &esv1.SopsObjectReference{Name: "docs"},
+				Auth:      esv1.SopsAuth{Age: ageKey},
+			},
+			wantErr: errSourceCount,
+		},
+		{
+			name: "path in a SecretStore",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.SopsProvider{
+				Path: "/etc/sops",
+				Auth: esv1.SopsAuth{Age: ageKey},
+			},
+			wantErr: errPathNotAllowed,
+		},
+		{
+			name: "relative path",
+			kind: esv1.ClusterSecretStoreKind,
+			spec: &esv1.SopsProvider{
+				Path: "sops",
+				Auth: esv1.SopsAuth{Age: clusterKey},
+			},
+			wantErr: errPathNotAbsolute,
+		},
+		{
+			name: "source namespace required in a ClusterSecretStore",
+			kind: esv1.ClusterSecretStoreKind,
+			spec: &esv1.SopsProvider{
+				Secret: &esv1.SopsObjectReference{Name: "docs"},
+				Auth:   esv1.SopsAuth{Age: clusterKey},
+			},
+			wantErr: errSourceNamespace,
+		},
+		{
+			name: "no keys",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.SopsProvider{
+				ConfigMap: &esv1.SopsObjectReference{Name: "docs"},
+			},
+			wantErr: errMissingKeys,
+		},
+		{
+			name: "key without name",
+			kind: esv1.SecretStoreKind,
+			spec: &esv1.SopsProvider{
+				ConfigMap: &esv1.SopsObjectReference{Name: "docs"},
+				Auth:      esv1.SopsAuth{Age: []esmeta.SecretKeySelector{{Key: "age"}}},
+			},
+			wantErr: errKeyNameEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&Provider{}).ValidateStore(makeStore(tt.kind, tt.spec))
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -38,6 +38,8 @@ import (
 )
 
 var _ esv1.SecretsClient = &client{}
+var _ esv1.SecretVersionClient = &client{}
+var _ esv1.ConditionalPusher = &client{}
 
 type client struct {
 	kube            kclient.Client
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tidwall/gjson"
 
@@ -39,6 +40,7 @@ const (
 	errUnsupportedMetadataKvVersion = "cannot perform metadata fetch operations with kv version v1"
 	errNotFound                     = "secret not found"
 	errSecretKeyFmt                 = "cannot find secret data for key: %q"
+	errCASMismatch                  = "check-and-set parameter did not match the current version"
 )
 
 var systemMetadataKeys = []string{"created_time", "current_version", "delete_version_after"}
@@ -77,6 +79,25 @@ func (c *client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemot
 	return getSecretValue(data, ref.Property)
 }
 
+// GetSecretVersion follows GetSecret, and returns the KV v2 version of the
+// secret, and the end of its lease for leased secrets like dynamic credentials.
+// Metadata and KV v1 secrets have no version.
+func (c *client) GetSecretVersion(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, esv1.SecretVersion, error) {
+	if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
+		value, err := c.GetSecret(ctx, ref)
+		return value, esv1.SecretVersion{}, err
+	}
+	data, version, err := c.readSecretVersion(ctx, ref.Key, ref.Version)
+	if err != nil {
+		return nil, esv1.SecretVersion{}, err
+	}
+	value, err := getSecretValue(data, ref.Property)
+	if err != nil {
+		return nil, esv1.SecretVersion{}, err
+	}
+	return value, version, nil
+}
+
 // GetSecretMap supports two modes of operation:
 // 1. get the full secret from the vault data payload (by leaving .property empty).
 // 2. extract key/value pairs from a (nested) object.
@@ -122,6 +143,12 @@ func (c *client) SecretExists(ctx context.Context, ref esv1.PushSecretRemoteRef)
 }
 
 func (c *client) readSecret(ctx context.Context, path, version string) (map[string]any, error) {
+	secretData, _, err := c.readSecretVersion(ctx, path, version)
+	return secretData, err
+}
+
+// readSecretVersion reads a secret like readSecret, along with its KV v2 version and the end of its lease.
+func (c *client) readSecretVersion(ctx context.Context, path, version string) (map[string]any, esv1.SecretVersion, error) {
 	dataPath := c.buildPath(path)
 
 	// path formated according to vault docs for v1 and v2 API
@@ -135,10 +162,14 @@ func (c *client) readSecret(ctx context.Context, path, version string) (map[stri
 	vaultSecret, err := c.logical.ReadWithDataWithContext(ctx, dataPath, params)
 	metrics.ObserveAPICall(constants.ProviderHCVault, constants.CallHCVaultReadSecretData, err)
 	if err != nil {
-		return nil, fmt.Errorf(errReadSecret, err)
+		return nil, esv1.SecretVersion{}, fmt.Errorf(errReadSecret, err)
 	}
 	if vaultSecret == nil {
-		return nil, esv1.NoSecretError{}
+		return nil, esv1.SecretVersion{}, esv1.NoSecretError{}
+	}
+	var expiry time.Time
+	if vaultSecret.LeaseDuration > 0 {
+		expiry = time.Now().Add(time.Duration(vaultSecret.LeaseDuration) * time.Second)
 	}
 	secretData := vaultSecret.Data
 	if c.store.Version == esv1.VaultKVStoreV2 {
@@ -146,18 +177,25 @@ func (c *client) readSecret(ctx context.Context, path, version string) (map[stri
 		// reference - https://www.vaultproject.io/api/secret/kv/kv-v2#read-secret-version
 		dataInt, ok := vaultSecret.Data["data"]
 		if !ok {
-			return nil, errors.New(errDataField)
+			return nil, esv1.SecretVersion{}, errors.New(errDataField)
 		}
 		if dataInt == nil {
-			return nil, esv1.NoSecretError{}
+			return nil, esv1.SecretVersion{}, esv1.NoSecretError{}
 		}
 		secretData, ok = dataInt.(map[string]any)
 		if !ok {
-			return nil, errors.New(errJSONUnmarshall)
+			return nil, esv1.SecretVersion{}, errors.New(errJSONUnmarshall)
+		}
+		metadata, _ := vaultSecret.Data["metadata"].(map[string]any)
+		v, err := secretVersion(metadata["version"])
+		if err != nil {
+			return nil, esv1.SecretVersion{}, err
 		}
+		v.Expiry = expiry
+		return secretData, v, nil
 	}
 
-	return secretData, nil
+	return secretData, esv1.SecretVersion{Expiry: expiry}, nil
 }
 
 func getSecretValue(data map[string]any, property string) ([]byte, error) {
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	vault "github.com/hashicorp/vault/api"
@@ -416,6 +417,74 @@ func TestGetSecret(t *testing.T) {
 	}
 }
 
+func TestGetSecretVersion(t *testing.T) {
+	cases := map[string]struct {
+		reason        string
+		store         esv1.VaultKVStoreVersion
+		secret        map[string]any
+		leaseDuration int
+		want          []byte
+		version       esv1.SecretVersion
+	}{
+		"ReadVersionKV2": {
+			reason: "Should return the version from the metadata of a KV v2 secret",
+			store:  esv1.VaultKVStoreV2,
+			secret: map[string]any{
+				"data":     map[string]any{"access_key": "access_key"},
+				"metadata": map[string]any{"version": json.Number("7")},
+			},
+			want:    []byte("access_key"),
+			version: esv1.SecretVersion{ID: "7"},
+		},
+		"NoVersionKV1": {
+			reason: "Should return no version for a KV v1 secret",
+			store:  esv1.VaultKVStoreV1,
+			secret: map[string]any{"access_key": "access_key"},
+			want:   []byte("access_key"),
+		},
+		"LeasedSecret": {
+			reason:        "Should return the end of the lease of a dynamic secret",
+			store:         esv1.VaultKVStoreV1,
+			secret:        map[string]any{"access_key": "access_key"},
+			leaseDuration: 3600,
+			want:          []byte("access_key"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			read := fake.NewReadWithContextFn(tc.secret, nil)
+			if tc.leaseDuration > 0 {
+				read = func(context.Context, string, map[string][]string) (*vault.Secret, error) {
+					return &vault.Secret{Data: tc.secret, LeaseDuration: tc.leaseDuration}, nil
+				}
+			}
+			vStore := &client{
+				store:   makeValidSecretStoreWithVersion(tc.store).Spec.Provider.Vault,
+				logical: &fake.Logical{ReadWithDataWithContextFn: read},
+			}
+			before := time.Now()
+			val, version, err := vStore.GetSecretVersion(context.Background(), esv1.ExternalSecretDataRemoteRef{Key: "secret", Property: "access_key"})
+			if err != nil {
+				t.Errorf("\n%s\nvault.GetSecretVersion(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(string(tc.want), string(val)); diff != "" {
+				t.Errorf("\n%s\nvault.GetSecretVersion(...): -want val, +got val:\n%s", tc.reason, diff)
+			}
+			if version.ID != tc.version.ID {
+				t.Errorf("\n%s\nvault.GetSecretVersion(...): want version %v, got %v", tc.reason, tc.version, version)
+			}
+			lease := time.Duration(tc.leaseDuration) * time.Second
+			if tc.leaseDuration == 0 && !version.Expiry.IsZero() {
+				t.Errorf("\n%s\nvault.GetSecretVersion(...): want no expiry, got %v", tc.reason, version.Expiry)
+			}
+			if tc.leaseDuration > 0 && (version.Expiry.Before(before.Add(lease)) || version.Expiry.After(time.Now().Add(lease))) {
+				t.Errorf("\n%s\nvault.GetSecretVersion(...): want an expiry in %v, got %v", tc.reason, lease, version.Expiry)
+			}
+		})
+	}
+}
+
 func TestGetSecretMap(t *testing.T) {
 	errBoom := errors.New("boom")
 	secret := map[string]any{
@@ -23,6 +23,8 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -33,6 +35,28 @@ import (
 )
 
 func (c *client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	_, err := c.pushSecret(ctx, secret, data, nil)
+	return err
+}
+
+// PushSecretIfVersion pushes a secret like PushSecret, with the check-and-set
+// parameter of KV v2 set to version, so that Vault rejects the write if the
+// secret was written since. KV v1 has no versions to check.
+func (c *client) PushSecretIfVersion(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData, version esv1.SecretVersion) (esv1.SecretVersion, error) {
+	if c.store.Version != esv1.VaultKVStoreV2 {
+		return esv1.SecretVersion{}, esv1.ConditionalPushUnsupportedErr
+	}
+	cas, err := strconv.Atoi(version.ID)
+	if err != nil {
+		return esv1.SecretVersion{}, fmt.Errorf("invalid secret version %q: %w", version.ID, err)
+	}
+	return c.pushSecret(ctx, secret, data, &cas)
+}
+
+// pushSecret writes the secret, and returns the version written, or the current
+// version if the value is unchanged. If cas is set, the write only succeeds if
+// the current version of the secret is still cas.
+func (c *client) pushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData, cas *int) (esv1.SecretVersion, error) {
 	var (
 		value []byte
 		err   error
@@ -46,7 +70,7 @@ func (c *client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv
 		}
 		value, err = esutils.JSONMarshal(secretStringVal)
 		if err != nil {
-			return fmt.Errorf("failed to serialize secret content as JSON: %w", err)
+			return esv1.SecretVersion{}, fmt.Errorf("failed to serialize secret content as JSON: %w", err)
 		}
 	} else {
 		value = secret.Data[key]
@@ -60,26 +84,31 @@ func (c *client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv
 	path := c.buildPath(data.GetRemoteKey())
 	metaPath, err := c.buildMetadataPath(data.GetRemoteKey())
 	if err != nil {
-		return err
+		return esv1.SecretVersion{}, err
 	}
 
 	// Retrieve the secret map from vault and convert the secret value in string form.
-	vaultSecret, err := c.readSecret(ctx, path, "")
+	vaultSecret, currentVersion, err := c.readSecretVersion(ctx, path, "")
 	// If error is not of type secret not found, we should error
 	if err != nil && !errors.Is(err, esv1.NoSecretError{}) {
-		return err
+		return esv1.SecretVersion{}, err
 	}
 
 	secretExists := err == nil
+	// A secret deleted or written since the expected version is a conflict,
+	// which the check-and-set parameter would only report on write.
+	if cas != nil && (!secretExists || currentVersion.ID != strconv.Itoa(*cas)) {
+		return esv1.SecretVersion{}, esv1.VersionConflictErr
+	}
 	// If the secret exists, we should check if it is managed by external-secrets
 	if secretExists {
 		metadata, err := c.readSecretMetadata(ctx, data.GetRemoteKey())
 		if err != nil {
-			return err
+			return esv1.SecretVersion{}, err
 		}
 		manager, ok := metadata["managed-by"]
 		if !ok || manager != "external-secrets" {
-			return errors.New("secret not managed by external-secrets")
+			return esv1.SecretVersion{}, errors.New("secret not managed by external-secrets")
 		}
 		// Remove the metadata map to check the reconcile difference
 		if c.store.Version == esv1.VaultKVStoreV1 {
@@ -93,11 +122,11 @@ func (c *client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv
 			if err != nil {
 				// Do not wrap the original error with %w as json.Unmarshal errors
 				// may contain sensitive secret data in the error message
-				return errors.New("error unmarshalling incoming secret value: invalid JSON format")
+				return esv1.SecretVersion{}, errors.New("error unmarshalling incoming secret value: invalid JSON format")
 			}
 			// Compare maps instead of raw bytes to handle JSON field ordering and formatting
 			if maps.Equal(vaultSecret, incomingSecretMap) {
-				return nil
+				return currentVersion, nil
 			}
 		}
 	}
@@ -106,11 +135,11 @@ func (c *client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv
 		if _, ok := vaultSecret[data.GetProperty()]; ok {
 			d, ok := vaultSecret[data.GetProperty()].(string)
 			if !ok {
-				return fmt.Errorf("error converting %s to string", data.GetProperty())
+				return esv1.SecretVersion{}, fmt.Errorf("error converting %s to string", data.GetProperty())
 			}
 			// If the property has the same value, don't update the secret
 			if bytes.Equal([]byte(d), value) {
-				return nil
+				return currentVersion, nil
 			}
 		}
 		maps.Insert(secretVal, maps.All(vaultSecret))
@@ -121,7 +150,7 @@ func (c *client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv
 		if err != nil {
 			// Do not wrap the original error with %w as json.Unmarshal errors
 			// may contain sensitive secret data in the error message
-			return errors.New("error unmarshalling vault secret: invalid JSON format")
+			return esv1.SecretVersion{}, errors.New("error unmarshalling vault secret: invalid JSON format")
 		}
 	}
 	secretToPush := secretVal
@@ -135,10 +164,14 @@ func (c *client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv
 		}
 
 		// Add CAS options if required
-		if c.store.CheckAndSet != nil && c.store.CheckAndSet.Required {
+		if cas != nil {
+			secretToPush["options"] = map[string]any{
+				"cas": *cas,
+			}
+		} else if c.store.CheckAndSet != nil && c.store.CheckAndSet.Required {
 			casVersion, casErr := c.getCASVersion(ctx, data.GetRemoteKey(), secretExists)
 			if casErr != nil {
-				return fmt.Errorf("failed to get CAS version: %w", casErr)
+				return esv1.SecretVersion{}, fmt.Errorf("failed to get CAS version: %w", casErr)
 			}
 
 			secretToPush["options"] = map[string]any{
@@ -151,13 +184,22 @@ func (c *client) PushSecret(ctx context.Context, secret *corev1.Secret, data esv
 		_, err = c.logical.WriteWithContext(ctx, metaPath, label)
 		metrics.ObserveAPICall(constants.ProviderHCVault, constants.CallHCVaultWriteSecretData, err)
 		if err != nil {
-			return err
+			return esv1.SecretVersion{}, err
 		}
 	}
 	// Otherwise, create or update the version.
-	_, err = c.logical.WriteWithContext(ctx, path, secretToPush)
+	written, err := c.logical.WriteWithContext(ctx, path, secretToPush)
 	metrics.ObserveAPICall(constants.ProviderHCVault, constants.CallHCVaultWriteSecretData, err)
-	return err
+	if err != nil {
+		if cas != nil && strings.Contains(err.Error(), errCASMismatch) {
+			return esv1.SecretVersion{}, esv1.VersionConflictErr
+		}
+		return esv1.SecretVersion{}, err
+	}
+	if c.store.Version != esv1.VaultKVStoreV2 || written == nil {
+		return esv1.SecretVersion{}, nil
+	}
+	return secretVersion(written.Data["version"])
 }
 
 func (c *client) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
@@ -249,21 +291,12 @@ func (c *client) getCASVersion(ctx context.Context, remoteKey string, secretExis
 }
 
 func getCurrentVersionFromMetadata(data map[string]any) (int, error) {
-	var err error
 	if currentVersion, ok := data["current_version"]; ok {
-		switch v := currentVersion.(type) {
-		case int:
-			return v, nil
-		case float64:
-			return int(v), nil
-		case json.Number:
-			if intVal, err := v.Int64(); err == nil {
-				return int(intVal), nil
-			}
-			return 0, fmt.Errorf("failed to convert json.Number to int: %w", err)
-		default:
-			return 0, fmt.Errorf("unexpected type for current_version: %T", currentVersion)
+		version, err := versionNumber(currentVersion)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected current_version: %w", err)
 		}
+		return version, nil
 	}
 
 	// If metadata exists but no current_version found, assume this is version 1.
@@ -271,3 +304,34 @@ func getCurrentVersionFromMetadata(data map[string]any) (int, error) {
 	// Vault KV v2 secrets start at version 1, so this is the safest assumption.
 	return 1, nil
 }
+
+// secretVersion returns the version of a KV v2 secret, as reported in the
+// metadata of a read or the response of a write.
+func secretVersion(version any) (esv1.SecretVersion, error) {
+	if version == nil {
+		return esv1.SecretVersion{}, nil
+	}
+	n, err := versionNumber(version)
+	if err != nil {
+		return esv1.SecretVersion{}, fmt.Errorf("unexpected secret version: %w", err)
+	}
+	return esv1.SecretVersion{ID: strconv.Itoa(n)}, nil
+}
+
+// versionNumber converts a version number as decoded from a Vault response.
+func versionNumber(version any) (int, error) {
+	switch v := version.(type) {
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return 0, fmt.Errorf("failed to convert json.Number to int: %w", err)
+		}
+		return int(n), nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T", version)
+	}
+}
@@ -18,11 +18,14 @@ package vault
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
+	vault "github.com/hashicorp/vault/api"
 	corev1 "k8s.io/api/core/v1"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
@@ -894,6 +897,131 @@ func TestPushSecret(t *testing.T) {
 	}
 }
 
+func TestPushSecretIfVersion(t *testing.T) {
+	secretKey := "secret-key"
+	readVersion := func(value string, version json.Number) fake.ReadWithDataWithContextFn {
+		return fake.NewReadWithDataAndMetadataFn(
+			map[string]any{
+				"data":     map[string]any{fakeKey: value},
+				"metadata": map[string]any{"version": version},
+			},
+			map[string]any{
+				"custom_metadata": map[string]any{
+					managedBy: managedByESO,
+				},
+				"current_version": version,
+			},
+			nil, nil,
+		)
+	}
+	writeVersion := func(cas int, version json.Number, err error) fake.WriteWithContextFn {
+		return func(_ context.Context, path string, data map[string]any) (*vault.Secret, error) {
+			if strings.Contains(path, "metadata") {
+				return &vault.Secret{Data: data}, nil
+			}
+			expected := map[string]any{
+				"options": map[string]any{"cas": cas},
+				"data":    map[string]any{fakeKey: fakeValue},
+			}
+			if !reflect.DeepEqual(expected, data) {
+				return nil, fmt.Errorf("expected: %v, got: %v", expected, data)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &vault.Secret{Data: map[string]any{"version": version}}, nil
+		}
+	}
+
+	tests := map[string]struct {
+		reason  string
+		store   esv1.VaultKVStoreVersion
+		logical *fake.Logical
+		version string
+		want    esv1.SecretVersion
+		wantErr error
+	}{
+		"WritesWithCAS": {
+			reason: "Should write with the expected version as check-and-set parameter, and return the new version",
+			store:  esv1.VaultKVStoreV2,
+			logical: &fake.Logical{
+				ReadWithDataWithContextFn: readVersion("old-value", "3"),
+				WriteWithContextFn:        writeVersion(3, "4", nil),
+			},
+			version: "3",
+			want:    esv1.SecretVersion{ID: "4"},
+		},
+		"ConflictOnNewerVersion": {
+			reason: "Should not write if the secret was written since the expected version",
+			store:  esv1.VaultKVStoreV2,
+			logical: &fake.Logical{
+				ReadWithDataWithContextFn: readVersion("old-value", "4"),
+				WriteWithContextFn:        fake.ExpectWriteWithContextNoCall(),
+			},
+			version: "3",
+			wantErr: esv1.VersionConflictErr,
+		},
+		"ConflictOnDeletedSecret": {
+			reason: "Should not write if the secret was deleted since the expected version",
+			store:  esv1.VaultKVStoreV2,
+			logical: &fake.Logical{
+				ReadWithDataWithContextFn: fake.NewReadWithContextFn(nil, nil),
+				WriteWithContextFn:        fake.ExpectWriteWithContextNoCall(),
+			},
+			version: "3",
+			wantErr: esv1.VersionConflictErr,
+		},
+		"ConflictOnRejectedCAS": {
+			reason: "Should report a conflict if Vault rejects the check-and-set parameter",
+			store:  esv1.VaultKVStoreV2,
+			logical: &fake.Logical{
+				ReadWithDataWithContextFn: readVersion("old-value", "3"),
+				WriteWithContextFn:        writeVersion(3, "", errors.New("Code: 400. Errors:\n\n* check-and-set parameter did not match the current version")),
+			},
+			version: "3",
+			wantErr: esv1.VersionConflictErr,
+		},
+		"UnchangedValue": {
+			reason: "Should return the expected version without writing if the value is unchanged",
+			store:  esv1.VaultKVStoreV2,
+			logical: &fake.Logical{
+				ReadWithDataWithContextFn: readVersion(fakeValue, "3"),
+				WriteWithContextFn:        fake.ExpectWriteWithContextNoCall(),
+			},
+			version: "3",
+			want:    esv1.SecretVersion{ID: "3"},
+		},
+		"UnsupportedKV1": {
+			reason: "Should report KV v1 as unsupported, as it has no versions",
+			store:  esv1.VaultKVStoreV1,
+			logical: &fake.Logical{
+				ReadWithDataWithContextFn: fake.NewReadWithContextFn(nil, nil),
+				WriteWithContextFn:        fake.ExpectWriteWithContextNoCall(),
+			},
+			version: "3",
+			wantErr: esv1.ConditionalPushUnsupportedErr,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &client{
+				logical: tc.logical,
+				store:   makeValidSecretStoreWithVersion(tc.store).Spec.Provider.Vault,
+			}
+			s := &corev1.Secret{Data: map[string][]byte{secretKey: []byte(`{"fake-key":"fake-value"}`)}}
+			data := testingfake.PushSecretData{SecretKey: secretKey, RemoteKey: "secret"}
+			got, err := client.PushSecretIfVersion(context.Background(), s, data, esv1.SecretVersion{ID: tc.version})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("\nTesting PushSecretIfVersion:\nName: %v\nReason: %v\nWant error: %v\nGot error: %v", name, tc.reason, tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("\nTesting PushSecretIfVersion:\nName: %v\nReason: %v\nWant version: %v\nGot version: %v", name, tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
 func makeValidSecretStoreWithCASRequired(version esv1.VaultKVStoreVersion) *esv1.SecretStore {
 	store := makeValidSecretStoreWithVersion(version)
 	store.Spec.Provider.Vault.CheckAndSet = &esv1.VaultCheckAndSet{
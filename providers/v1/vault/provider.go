@@ -38,7 +38,8 @@ import (
 )
 
 var (
-	_           esv1.Provider = &Provider{}
+	_           esv1.Provider              = &Provider{}
+	_           esv1.ClientCachingProvider = &Provider{}
 	enableCache bool
 	logger      = ctrl.Log.WithName("provider").WithName("vault")
 	clientCache *cache.Cache[vaultutil.Client]
@@ -269,6 +270,20 @@ func getVaultClient(p *Provider, store esv1.GenericStore, cfg *vault.Config, nam
 	return client, nil
 }
 
+// EvictClients evicts the cached clients of the store, so the next client logs in with the rotated credentials.
+// The clients of a ClusterSecretStore are evicted for all the namespaces they were created for.
+func (p *Provider) EvictClients(store esv1.GenericStore) {
+	if !enableCache || clientCache == nil {
+		return
+	}
+	clientCache.RemoveFunc(func(key cache.Key) bool {
+		if key.Kind != store.GetTypeMeta().Kind || key.Name != store.GetObjectMeta().Name {
+			return false
+		}
+		return key.Kind == esv1.ClusterSecretStoreKind || key.Namespace == store.GetObjectMeta().Namespace
+	})
+}
+
 func isReferentSpec(prov *esv1.VaultProvider) bool {
 	if prov.Auth == nil {
 		return false
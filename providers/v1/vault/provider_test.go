@@ -879,6 +879,48 @@ func TestCacheWithReferentSpec(t *testing.T) {
 	}
 }
 
+func TestEvictClients(t *testing.T) {
+	t.Cleanup(resetCache)
+	enableCache = true
+	initCache(defaultCacheSize)
+
+	prov := &Provider{
+		NewVaultClient: fake.ClientWithLoginMock,
+	}
+	store := makeClusterSecretStore(func(s *esv1.SecretStore) {
+		s.Spec.Provider.Vault.Auth.Kubernetes.ServiceAccountRef = &esmeta.ServiceAccountSelector{
+			Name: "vault-sa",
+		}
+	})
+
+	c1, err := getVaultClient(prov, store, nil, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := getVaultClient(prov, store, nil, "another-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the store references the same ServiceAccount after the rotation, so its resourceVersion is unchanged
+	prov.EvictClients(store)
+
+	c3, err := getVaultClient(prov, store, nil, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c3 == c1 {
+		t.Fatal("Expected a new client instance")
+	}
+	c4, err := getVaultClient(prov, store, nil, "another-namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c4 == c2 {
+		t.Fatal("Expected a new client instance")
+	}
+}
+
 func resetCache() {
 	enableCache = false
 	clientCache = nil
@@ -107,6 +107,15 @@ func (c *Cache[T]) ContainsOrAdd(version string, key Key, client T) bool {
 	return exists
 }
 
+// RemoveFunc removes the values whose key matches, calling the cleanup function for each of them.
+func (c *Cache[T]) RemoveFunc(match func(Key) bool) {
+	for _, k := range c.lru.Keys() {
+		if key, ok := k.(Key); ok && match(key) {
+			c.lru.Remove(key)
+		}
+	}
+}
+
 // Contains returns true if a value with the given key exists.
 func (c *Cache[T]) Contains(key Key) bool {
 	return c.lru.Contains(key)
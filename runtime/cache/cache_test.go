@@ -168,3 +168,19 @@ func TestCacheEvict(t *testing.T) {
 	c.Add("", Key{Name: "bar"}, client{})
 	assert.True(t, cleanupCalled)
 }
+
+func TestCacheRemoveFunc(t *testing.T) {
+	var cleaned []client
+	c := Must(3, func(value client) {
+		cleaned = append(cleaned, value)
+	})
+	c.Add("", Key{Name: "foo", Namespace: "a"}, client{id: 1})
+	c.Add("", Key{Name: "foo", Namespace: "b"}, client{id: 2})
+	c.Add("", Key{Name: "bar", Namespace: "a"}, client{id: 3})
+
+	c.RemoveFunc(func(key Key) bool { return key.Name == "foo" })
+
+	assert.ElementsMatch(t, []client{{id: 1}, {id: 2}}, cleaned)
+	assert.False(t, c.Contains(Key{Name: "foo", Namespace: "a"}))
+	assert.True(t, c.Contains(Key{Name: "bar", Namespace: "a"}))
+}
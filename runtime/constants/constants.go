@@ -47,9 +47,11 @@ const (
 	CallAWSSMDeleteResourcePolicy         = "DeleteResourcePolicy"
 	CallAWSSMReplicateSecretToRegions     = "ReplicateSecretToRegions"
 	CallAWSSMRemoveRegionsFromReplication = "RemoveRegionsFromReplication"
+	CallAWSSMUpdateSecretVersionStage     = "UpdateSecretVersionStage"
 
 	ProviderAWSPS                = "AWS/ParameterStore"
 	CallAWSPSGetParameter        = "GetParameter"
+	CallAWSPSGetParameters       = "GetParameters"
 	CallAWSPSPutParameter        = "PutParameter"
 	CallAWSPSDeleteParameter     = "DeleteParameter"
 	CallAWSPSDescribeParameter   = "DescribeParameter"
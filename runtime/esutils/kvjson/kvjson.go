@@ -0,0 +1,88 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kvjson provides helpers for providers storing secrets as plain or JSON values of keys.
+package kvjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/runtime/esutils"
+)
+
+// ErrNotAnObject is returned when a value is expected to be a JSON object.
+var ErrNotAnObject = errors.New("value is not a JSON object")
+
+const errMissingSecretKey = "secret key %q not found in secret"
+
+// PushValue returns the value of the secret key to push, or the whole secret as JSON.
+func PushValue(secret *corev1.Secret, data esv1.PushSecretData) ([]byte, error) {
+	if data.GetSecretKey() == "" {
+		values := make(map[string]string, len(secret.Data))
+		for key, value := range secret.Data {
+			values[key] = string(value)
+		}
+		return esutils.JSONMarshal(values)
+	}
+	value, ok := secret.Data[data.GetSecretKey()]
+	if !ok {
+		return nil, fmt.Errorf(errMissingSecretKey, data.GetSecretKey())
+	}
+	return value, nil
+}
+
+// SetProperty sets a top-level property of a JSON object to a string value.
+// An empty value is an empty object.
+func SetProperty(current []byte, property string, value []byte) ([]byte, error) {
+	object, err := Object(current)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(string(value))
+	if err != nil {
+		return nil, err
+	}
+	object[property] = encoded
+	return json.Marshal(object)
+}
+
+// Object returns the top-level properties of a JSON object.
+// An empty value is an empty object.
+func Object(value []byte) (map[string]json.RawMessage, error) {
+	object := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(value)) == 0 {
+		return object, nil
+	}
+	if err := json.Unmarshal(value, &object); err != nil {
+		return nil, ErrNotAnObject
+	}
+	return object, nil
+}
+
+// ResultBytes returns a string result as is, and any other result as JSON.
+func ResultBytes(result gjson.Result) []byte {
+	if result.Type == gjson.String {
+		return []byte(result.Str)
+	}
+	return []byte(result.Raw)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © The ESO Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvjson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tidwall/gjson"
+	corev1 "k8s.io/api/core/v1"
+
+	testingfake "github.com/external-secrets/external-secrets/runtime/testing/fake"
+)
+
+func TestPushValue(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{"user": []byte("admin"), "password": []byte("s3cr3t")}}
+
+	got, err := PushValue(secret, &testingfake.PushSecretData{SecretKey: "user"})
+	if err != nil || string(got) != "admin" {
+		t.Fatalf("expected admin, got %q, %v", got, err)
+	}
+	got, err = PushValue(secret, &testingfake.PushSecretData{})
+	if err != nil || string(got) != `{"password":"s3cr3t","user":"admin"}` {
+		t.Fatalf("expected the whole secret, got %q, %v", got, err)
+	}
+	if _, err := PushValue(secret, &testingfake.PushSecretData{SecretKey: "missing"}); err == nil {
+		t.Fatal("expected an error for a missing secret key")
+	}
+}
+
+func TestSetProperty(t *testing.T) {
+	got, err := SetProperty(nil, "user", []byte("admin"))
+	if err != nil || string(got) != `{"user":"admin"}` {
+		t.Fatalf("expected a new object, got %q, %v", got, err)
+	}
+	got, err = SetProperty([]byte(`{"port": 5432, "user": "root"}`), "user", []byte("admin"))
+	if err != nil || string(got) != `{"port":5432,"user":"admin"}` {
+		t.Fatalf("expected the other properties to be kept, got %q, %v", got, err)
+	}
+	if _, err := SetProperty([]byte("plain"), "user", []byte("admin")); !errors.Is(err, ErrNotAnObject) {
+		t.Fatalf("expected ErrNotAnObject, got %v", err)
+	}
+}
+
+func TestResultBytes(t *testing.T) {
+	value := []byte(`{"user": "admin", "port": 5432, "tls": {"enabled": true}}`)
+	for property, want := range map[string]string{"user": "admin", "port": "5432", "tls": `{"enabled": true}`} {
+		if got := ResultBytes(gjson.GetBytes(value, property)); string(got) != want {
+			t.Fatalf("expected %q for %q, got %q", want, property, got)
+		}
+	}
+}